@@ -0,0 +1,128 @@
+package tracingx
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"runtime/trace"
+	"strconv"
+)
+
+// errInvalidSampleRateRequest is returned for unparseable sample-rate requests
+var errInvalidSampleRateRequest = errors.New(`expected {"sample_rate": <0.0-1.0>} or ?rate=<0.0-1.0>`)
+
+// sampleRateRequest is the body accepted by the admin sample-rate endpoint
+type sampleRateRequest struct {
+	SampleRate *float64 `json:"sample_rate"`
+}
+
+// AdminOption configures the admin handler
+type AdminOption func(*adminConfig)
+
+type adminConfig struct {
+	flightRecorder *trace.FlightRecorder
+}
+
+// WithFlightRecorder serves snapshots of recorder, started and stopped by
+// the application, as GET /flight-recorder
+func WithFlightRecorder(recorder *trace.FlightRecorder) AdminOption {
+	return func(c *adminConfig) {
+		c.flightRecorder = recorder
+	}
+}
+
+// AdminHandler returns an HTTP handler for operating the tracing subsystem,
+// meant to be mounted on an internal admin mux, e.g.
+// mux.Handle("/admin/tracing/", http.StripPrefix("/admin/tracing", tracingx.AdminHandler(provider))).
+//
+// Routes:
+//
+//	GET  /diagnostics      provider diagnostics as JSON
+//	POST /sample-rate      set the sample rate from {"sample_rate": 0.1} or ?rate=0.1
+//	POST /flush            export all ended spans
+//	GET  /ready            204 once the exporters are connected, 503 before
+//	GET  /flight-recorder  execution trace snapshot, with WithFlightRecorder
+func AdminHandler(provider Provider, opts ...AdminOption) http.Handler {
+	var config adminConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /diagnostics", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, provider.Diagnostics())
+	})
+
+	mux.HandleFunc("POST /sample-rate", func(w http.ResponseWriter, r *http.Request) {
+		rate, err := parseSampleRate(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := provider.SetSampleRate(rate); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		writeJSON(w, http.StatusOK, provider.Diagnostics())
+	})
+
+	mux.HandleFunc("POST /flush", func(w http.ResponseWriter, r *http.Request) {
+		if err := provider.ForceFlush(r.Context()); err != nil {
+			http.Error(w, "failed to flush spans: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("GET /ready", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-provider.Ready():
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.Error(w, "tracing exporters not connected", http.StatusServiceUnavailable)
+		}
+	})
+
+	if recorder := config.flightRecorder; recorder != nil {
+		mux.HandleFunc("GET /flight-recorder", func(w http.ResponseWriter, r *http.Request) {
+			if !recorder.Enabled() {
+				http.Error(w, "flight recorder not running", http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Header().Set("Content-Disposition", `attachment; filename="flight-recorder.trace"`)
+			// WriteTo fails before writing when another snapshot is in progress;
+			// later errors come from the client connection
+			if n, err := recorder.WriteTo(w); err != nil && n == 0 {
+				w.Header().Del("Content-Disposition")
+				http.Error(w, "failed to snapshot flight recorder: "+err.Error(), http.StatusConflict)
+			}
+		})
+	}
+
+	return mux
+}
+
+// parseSampleRate reads the requested sample rate from the query or JSON body
+func parseSampleRate(r *http.Request) (float64, error) {
+	if value := r.URL.Query().Get("rate"); value != "" {
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, errInvalidSampleRateRequest
+		}
+		return rate, nil
+	}
+
+	var req sampleRateRequest
+	if err := json.NewDecoder(http.MaxBytesReader(nil, r.Body, 1024)).Decode(&req); err != nil || req.SampleRate == nil {
+		return 0, errInvalidSampleRateRequest
+	}
+	return *req.SampleRate, nil
+}
+
+// writeJSON writes v as a JSON response
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,120 @@
+package tracingx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime/trace"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandler(t *testing.T) {
+	collector := newFakeCollector(t)
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "test-service",
+		SampleRate:  1.0,
+		OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+	}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	handler := AdminHandler(provider)
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rec
+	}
+
+	t.Run("reports diagnostics", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/diagnostics", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+		var diag Diagnostics
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &diag))
+		assert.Equal(t, "otlp", diag.Provider)
+		assert.Equal(t, "test-service", diag.ServiceName)
+	})
+
+	t.Run("changes sample rate", func(t *testing.T) {
+		rec := serve(http.MethodPost, "/sample-rate", `{"sample_rate": 0.25}`)
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 0.25, provider.Diagnostics().SampleRate)
+
+		rec = serve(http.MethodPost, "/sample-rate?rate=0.5", "")
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, 0.5, provider.Diagnostics().SampleRate)
+	})
+
+	t.Run("rejects invalid sample rates", func(t *testing.T) {
+		for _, tc := range []struct{ target, body string }{
+			{"/sample-rate", `{"sample_rate": 2}`},
+			{"/sample-rate", `{}`},
+			{"/sample-rate", `garbage`},
+			{"/sample-rate?rate=abc", ""},
+		} {
+			rec := serve(http.MethodPost, tc.target, tc.body)
+			assert.Equal(t, http.StatusBadRequest, rec.Code, tc)
+		}
+		assert.Equal(t, 0.5, provider.Diagnostics().SampleRate)
+	})
+
+	t.Run("flushes spans", func(t *testing.T) {
+		require.NoError(t, provider.SetSampleRate(1))
+		_, span := provider.Start(context.Background(), "pending")
+		span.End()
+
+		rec := serve(http.MethodPost, "/flush", "")
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.NotEmpty(t, collector.received())
+	})
+
+	t.Run("reports readiness", func(t *testing.T) {
+		assert.Equal(t, http.StatusNoContent, serve(http.MethodGet, "/ready", "").Code)
+
+		unready := AdminHandler(failingProvider{Provider: newNoopProvider()})
+		rec := httptest.NewRecorder()
+		unready.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+
+	t.Run("rejects wrong methods", func(t *testing.T) {
+		assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodPost, "/diagnostics", "").Code)
+		assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodGet, "/flush", "").Code)
+	})
+}
+
+func TestAdminHandlerFlightRecorder(t *testing.T) {
+	provider := newNoopProvider()
+
+	t.Run("not served without a recorder", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		AdminHandler(provider).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/flight-recorder", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	recorder := trace.NewFlightRecorder(trace.FlightRecorderConfig{})
+	handler := AdminHandler(provider, WithFlightRecorder(recorder))
+
+	t.Run("unavailable while stopped", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/flight-recorder", nil))
+		assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	})
+
+	t.Run("serves snapshots", func(t *testing.T) {
+		require.NoError(t, recorder.Start())
+		defer recorder.Stop()
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/flight-recorder", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/octet-stream", rec.Header().Get("Content-Type"))
+		assert.True(t, strings.HasPrefix(rec.Body.String(), "go 1."), "snapshot should start with the trace header")
+	})
+}
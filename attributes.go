@@ -0,0 +1,161 @@
+package tracingx
+
+import (
+	"github.com/gostratum/core/logx"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Modes for reserved attributes set by instrumentation
+const (
+	// ReservedNamespace records reserved keys under ReservedAttributePrefix
+	ReservedNamespace = "namespace"
+
+	// ReservedReject drops reserved keys
+	ReservedReject = "reject"
+
+	// ReservedAllow sets reserved keys as given
+	ReservedAllow = "allow"
+)
+
+// ReservedAttributePrefix is prepended to reserved keys set by
+// instrumentation in namespace mode, so service.name becomes tag.service.name
+const ReservedAttributePrefix = "tag."
+
+// AttributesConfig configures how span attributes set more than once are
+// resolved, and the default attributes of each span kind. By default the
+// last value set for a key wins, and a span exports each key once.
+type AttributesConfig struct {
+	// SystemFirstWins keeps the first value of attributes set by tracingx
+	// itself (correlation.id, request.id, tracingx.synthetic,
+	// trace.truncated), so instrumentation cannot overwrite them via SetTag
+	SystemFirstWins bool `mapstructure:"system_first_wins" default:"false"`
+
+	// Reserved controls span attributes that would shadow resource or trace
+	// identity attributes (service.name, trace_id, ...): "namespace" (the
+	// default) prefixes them with tag., "reject" drops them and "allow" sets
+	// them as given
+	Reserved string `mapstructure:"reserved" default:"namespace"`
+
+	// Profiles are default attributes applied to spans by kind
+	Profiles []AttributeProfile `mapstructure:"profiles"`
+}
+
+// reservedAttributes are resource and trace identity keys instrumentation
+// must not set on spans
+var reservedAttributes = map[string]bool{
+	"service.name":           true,
+	"service.namespace":      true,
+	"service.version":        true,
+	"service.instance.id":    true,
+	"telemetry.sdk.name":     true,
+	"telemetry.sdk.language": true,
+	"telemetry.sdk.version":  true,
+	"trace_id":               true,
+	"span_id":                true,
+	"parent_id":              true,
+	"trace.id":               true,
+	"span.id":                true,
+}
+
+// userKey maps a key set by instrumentation to the key recorded on the
+// span, reporting false when the key is rejected
+func (c AttributesConfig) userKey(key string) (string, bool) {
+	if !reservedAttributes[key] {
+		return key, true
+	}
+	switch c.Reserved {
+	case ReservedAllow:
+		return key, true
+	case ReservedReject:
+		return "", false
+	default:
+		return reservedKeys.get(key), true
+	}
+}
+
+// systemAttributes are the attributes tracingx sets on spans itself, each
+// with its bit in otlpSpan.system
+var systemAttributes = map[string]uint8{
+	CorrelationIDAttribute:  1 << 0,
+	RequestIDAttribute:      1 << 1,
+	SyntheticAttribute:      1 << 2,
+	TraceTruncatedAttribute: 1 << 3,
+}
+
+// systemBit returns the bit of key when the first value set for it is kept,
+// else 0
+func (c AttributesConfig) systemBit(key string) uint8 {
+	if !c.SystemFirstWins {
+		return 0
+	}
+	return systemAttributes[key]
+}
+
+// setSystemTag records a system attribute set by tracingx itself. Its value
+// replaces any set by instrumentation, and with SystemFirstWins is kept from
+// then on.
+func (s *otlpSpan) setSystemTag(key string, value attribute.Value) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.end.IsZero() {
+		return
+	}
+	s.system |= s.pipeline.config.Attributes.systemBit(key)
+	s.span.SetAttributes(attribute.KeyValue{Key: attribute.Key(key), Value: value})
+}
+
+// setTag records key on the span and reports whether it was set. Reserved
+// keys are namespaced or rejected first. An already set key is kept when
+// ifAbsent is set or key is a first-wins system attribute; otherwise the new
+// value replaces it.
+func (s *otlpSpan) setTag(key string, value attribute.Value, ifAbsent bool) bool {
+	recorded, ok := s.pipeline.config.Attributes.userKey(key)
+	if !ok {
+		if s.debug != nil {
+			s.debug.Debug("tracing: rejected reserved attribute",
+				logx.String("key", key),
+				logx.String("span", s.name),
+			)
+		}
+		return false
+	}
+	key = recorded
+
+	s.mu.Lock()
+	if !s.end.IsZero() {
+		s.mu.Unlock()
+		return false
+	}
+	bit := s.pipeline.config.Attributes.systemBit(key)
+	firstWins := s.system&bit != 0
+	if firstWins || ifAbsent && s.hasAttribute(key) {
+		s.mu.Unlock()
+		if firstWins && !ifAbsent && s.debug != nil {
+			s.debug.Debug("tracing: kept first value of system attribute",
+				logx.String("key", key),
+				logx.String("span", s.name),
+			)
+		}
+		return false
+	}
+	s.system |= bit
+	s.span.SetAttributes(attribute.KeyValue{Key: attribute.Key(key), Value: value})
+	s.mu.Unlock()
+	return true
+}
+
+// hasAttribute reports whether key is set on the span. Spans that are not
+// recording keep no attributes, so none is ever set on them.
+func (s *otlpSpan) hasAttribute(key string) bool {
+	recorded, ok := s.span.(sdktrace.ReadOnlySpan)
+	if !ok {
+		return false
+	}
+	for _, kv := range recorded.Attributes() {
+		if string(kv.Key) == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gostratum/core/logx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSetTagOverride(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("last value wins", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "work")
+		defer span.End()
+
+		span.SetTag("tier", "free")
+		span.SetTag("tier", "paid")
+		assert.Equal(t, "paid", attributesOf(t, span)["tier"])
+	})
+
+	t.Run("SetTagIfAbsent keeps existing values", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "work", WithAttributes(map[string]any{"tier": "free"}))
+		defer span.End()
+
+		assert.False(t, span.SetTagIfAbsent("tier", "paid"))
+		assert.True(t, span.SetTagIfAbsent("region", "eu"))
+		assert.False(t, span.SetTagIfAbsent("region", "us"))
+
+		attrs := attributesOf(t, span)
+		assert.Equal(t, "free", attrs["tier"])
+		assert.Equal(t, "eu", attrs["region"])
+	})
+
+	t.Run("system attributes are overridable by default", func(t *testing.T) {
+		_, span := provider.Start(ContextWithCorrelationID(context.Background(), "upstream"), "work")
+		defer span.End()
+
+		span.SetTag(CorrelationIDAttribute, "other")
+		assert.Equal(t, "other", attributesOf(t, span)[CorrelationIDAttribute])
+	})
+
+	t.Run("SetTagIfAbsent after End", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "work")
+		span.End()
+		assert.False(t, span.SetTagIfAbsent("late", true))
+		assert.NotContains(t, attributesOf(t, span), "late")
+	})
+}
+
+func TestSystemAttributesFirstWins(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	config := Config{
+		ServiceName: "test-service",
+		SampleRate:  1.0,
+		Debug:       true,
+		Attributes:  AttributesConfig{SystemFirstWins: true},
+	}
+	provider, err := newOTLPProvider(config, logx.ProvideAdapter(zap.New(core)))
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	_, span := provider.Start(ContextWithCorrelationID(context.Background(), "upstream"), "work")
+	defer span.End()
+
+	span.SetTag(CorrelationIDAttribute, "other")
+	span.SetTag("tier", "free")
+	span.SetTag("tier", "paid")
+
+	attrs := attributesOf(t, span)
+	assert.Equal(t, "upstream", attrs[CorrelationIDAttribute])
+	assert.Equal(t, "paid", attrs["tier"])
+	assert.Equal(t, 1, logs.FilterMessage("tracing: kept first value of system attribute").Len())
+}
+
+func TestReservedAttributes(t *testing.T) {
+	start := func(t *testing.T, mode string) Span {
+		config := Config{ServiceName: "test-service", SampleRate: 1.0, Attributes: AttributesConfig{Reserved: mode}}
+		provider, err := newOTLPProvider(config, getTestLogger())
+		require.NoError(t, err)
+		t.Cleanup(func() { provider.Shutdown(context.Background()) })
+
+		_, span := provider.Start(context.Background(), "work", WithAttributes(map[string]any{"trace_id": "forged"}))
+		t.Cleanup(span.End)
+		span.SetTag("service.name", "other")
+		span.SetTag("order.id", "ord-1")
+		return span
+	}
+
+	t.Run("namespaces by default", func(t *testing.T) {
+		attrs := attributesOf(t, start(t, ""))
+		assert.Equal(t, "other", attrs["tag.service.name"])
+		assert.Equal(t, "forged", attrs["tag.trace_id"])
+		assert.NotContains(t, attrs, "service.name")
+		assert.NotContains(t, attrs, "trace_id")
+		assert.Equal(t, "ord-1", attrs["order.id"])
+	})
+
+	t.Run("rejects", func(t *testing.T) {
+		span := start(t, ReservedReject)
+		attrs := attributesOf(t, span)
+		assert.NotContains(t, attrs, "service.name")
+		assert.NotContains(t, attrs, "tag.service.name")
+		assert.NotContains(t, attrs, "trace_id")
+		assert.Equal(t, "ord-1", attrs["order.id"])
+		assert.False(t, span.SetTagIfAbsent("span_id", "forged"))
+	})
+
+	t.Run("allows", func(t *testing.T) {
+		attrs := attributesOf(t, start(t, ReservedAllow))
+		assert.Equal(t, "other", attrs["service.name"])
+		assert.Equal(t, "forged", attrs["trace_id"])
+	})
+}
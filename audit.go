@@ -0,0 +1,164 @@
+package tracingx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// AuditConfig configures the audit trail for selected operations
+type AuditConfig struct {
+	// Enabled turns on audit record export
+	Enabled bool `mapstructure:"enabled" default:"false"`
+
+	// Operations lists the span names recorded as audit records (e.g. "user.delete")
+	Operations []string `mapstructure:"operations"`
+
+	// Path is the file audit records are appended to when no AuditSink is provided
+	Path string `mapstructure:"path" default:"tracing-audit.jsonl"`
+}
+
+// AuditRecord is an immutable record of an audited operation
+type AuditRecord struct {
+	TraceID           string         `json:"trace_id"`
+	SpanID            string         `json:"span_id"`
+	ParentSpanID      string         `json:"parent_span_id,omitempty"`
+	Operation         string         `json:"operation"`
+	Kind              string         `json:"kind"`
+	Service           string         `json:"service"`
+	StartTime         time.Time      `json:"start_time"`
+	EndTime           time.Time      `json:"end_time"`
+	Status            string         `json:"status"`
+	StatusDescription string         `json:"status_description,omitempty"`
+	Attributes        map[string]any `json:"attributes,omitempty"`
+}
+
+// AuditSink receives audit records. Implementations can write to files,
+// message topics (e.g. Kafka) or any append-only store.
+type AuditSink interface {
+	// Write persists a single audit record
+	Write(ctx context.Context, record AuditRecord) error
+
+	// Close flushes and releases the sink
+	Close(ctx context.Context) error
+}
+
+// fileAuditSink appends audit records as JSON lines to a file
+type fileAuditSink struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+// NewFileAuditSink creates an AuditSink appending JSON lines to path
+func NewFileAuditSink(path string) (AuditSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file: %w", err)
+	}
+	return &fileAuditSink{file: f, enc: json.NewEncoder(f)}, nil
+}
+
+func (s *fileAuditSink) Write(ctx context.Context, record AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(record)
+}
+
+func (s *fileAuditSink) Close(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.file.Sync(); err != nil {
+		s.file.Close()
+		return err
+	}
+	return s.file.Close()
+}
+
+// auditProcessor forwards finished spans of audited operations to a sink
+type auditProcessor struct {
+	operations map[string]struct{}
+	sink       AuditSink
+	service    string
+	logger     logx.Logger
+}
+
+// newAuditProcessor creates a processor writing audited operations to sink
+func newAuditProcessor(config Config, sink AuditSink, logger logx.Logger) *auditProcessor {
+	ops := make(map[string]struct{}, len(config.Audit.Operations))
+	for _, op := range config.Audit.Operations {
+		ops[op] = struct{}{}
+	}
+	return &auditProcessor{
+		operations: ops,
+		sink:       sink,
+		service:    config.ServiceName,
+		logger:     logger,
+	}
+}
+
+func (p *auditProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {}
+
+func (p *auditProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !p.audits(s) {
+		return
+	}
+	if err := p.sink.Write(context.Background(), p.record(s)); err != nil {
+		p.logger.Error("failed to write audit record",
+			logx.String("operation", s.Name()),
+			logx.String("trace_id", s.SpanContext().TraceID().String()),
+			logx.Err(err),
+		)
+	}
+}
+
+// audits reports whether s is of an audited operation
+func (p *auditProcessor) audits(s sdktrace.ReadOnlySpan) bool {
+	_, ok := p.operations[s.Name()]
+	return ok
+}
+
+// record converts a finished span into an audit record
+func (p *auditProcessor) record(s sdktrace.ReadOnlySpan) AuditRecord {
+	record := AuditRecord{
+		TraceID:           s.SpanContext().TraceID().String(),
+		SpanID:            s.SpanContext().SpanID().String(),
+		Operation:         s.Name(),
+		Kind:              s.SpanKind().String(),
+		Service:           p.service,
+		StartTime:         s.StartTime(),
+		EndTime:           s.EndTime(),
+		Status:            s.Status().Code.String(),
+		StatusDescription: s.Status().Description,
+	}
+	if s.Parent().IsValid() {
+		record.ParentSpanID = s.Parent().SpanID().String()
+	}
+	if attrs := s.Attributes(); len(attrs) > 0 {
+		record.Attributes = make(map[string]any, len(attrs))
+		for _, kv := range attrs {
+			record.Attributes[string(kv.Key)] = kv.Value.AsInterface()
+		}
+	}
+	return record
+}
+
+func (p *auditProcessor) Shutdown(ctx context.Context) error {
+	return p.sink.Close(ctx)
+}
+
+func (p *auditProcessor) ForceFlush(ctx context.Context) error { return nil }
+
+// sharedAuditSink is an injected sink whose lifetime is managed by the
+// provider, so closing an audit processor leaves it open
+type sharedAuditSink struct {
+	AuditSink
+}
+
+func (s sharedAuditSink) Close(ctx context.Context) error { return nil }
@@ -0,0 +1,106 @@
+package tracingx
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryAuditSink collects audit records in memory
+type memoryAuditSink struct {
+	mu      sync.Mutex
+	records []AuditRecord
+	closed  bool
+}
+
+func (s *memoryAuditSink) Write(ctx context.Context, record AuditRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+func (s *memoryAuditSink) Close(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	return nil
+}
+
+func TestAuditProcessor(t *testing.T) {
+	sink := &memoryAuditSink{}
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "billing",
+		SampleRate:  0, // nothing is sampled for export
+		Audit: AuditConfig{
+			Enabled:    true,
+			Operations: []string{"payment.refund"},
+		},
+	}, getTestLogger(), withAuditSink(sink))
+	require.NoError(t, err)
+
+	_, refund := provider.Start(context.Background(), "payment.refund",
+		WithAttributes(map[string]any{"payment.id": "pay-1", "amount": 42}),
+	)
+	refund.End()
+
+	_, other := provider.Start(context.Background(), "payment.lookup")
+	other.End()
+
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	require.Len(t, sink.records, 1)
+	record := sink.records[0]
+	assert.Equal(t, "payment.refund", record.Operation)
+	assert.Equal(t, "billing", record.Service)
+	assert.Equal(t, refund.TraceID(), record.TraceID)
+	assert.Equal(t, refund.SpanID(), record.SpanID)
+	assert.Equal(t, "pay-1", record.Attributes["payment.id"])
+	assert.EqualValues(t, 42, record.Attributes["amount"])
+	assert.False(t, record.EndTime.Before(record.StartTime))
+	assert.True(t, sink.closed)
+}
+
+func TestFileAuditSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := NewFileAuditSink(path)
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	for _, op := range []string{"user.delete", "user.delete"} {
+		require.NoError(t, sink.Write(context.Background(), AuditRecord{
+			TraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+			Operation: op,
+			StartTime: now,
+			EndTime:   now,
+		}))
+	}
+	require.NoError(t, sink.Close(context.Background()))
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var lines int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var record AuditRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		assert.Equal(t, "user.delete", record.Operation)
+		lines++
+	}
+	assert.Equal(t, 2, lines)
+}
+
+func TestNewFileAuditSinkFailure(t *testing.T) {
+	_, err := NewFileAuditSink(filepath.Join(t.TempDir(), "missing", "audit.jsonl"))
+	assert.Error(t, err)
+}
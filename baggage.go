@@ -0,0 +1,96 @@
+package tracingx
+
+import (
+	"context"
+	"sort"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// BaggageConfig configures limits on propagated baggage
+type BaggageConfig struct {
+	// MaxBytes caps the encoded size of outbound baggage; members are dropped
+	// (in key order) once the limit is reached. 0 disables the cap.
+	MaxBytes int `mapstructure:"max_bytes" default:"8192"`
+
+	// AllowedKeys restricts which baggage keys are propagated outbound
+	// (empty allows all keys)
+	AllowedKeys []string `mapstructure:"allowed_keys"`
+
+	// DropInbound discards all baggage received by Extract
+	DropInbound bool `mapstructure:"drop_inbound" default:"false"`
+
+	// InboundAllowedKeys restricts which received baggage keys Extract
+	// accepts (empty accepts all keys)
+	InboundAllowedKeys []string `mapstructure:"inbound_allowed_keys"`
+
+	// InboundMaxBytes caps the encoded size of accepted baggage (0 disables the cap)
+	InboundMaxBytes int `mapstructure:"inbound_max_bytes"`
+}
+
+// sanitizeInbound applies the inbound baggage policy to the baggage that
+// extraction added to ctx; baggage already present before extraction is kept
+func (c BaggageConfig) sanitizeInbound(before, extracted context.Context) context.Context {
+	bag := baggage.FromContext(extracted)
+	if bag.Len() == 0 || bag.String() == baggage.FromContext(before).String() {
+		return extracted
+	}
+	if c.DropInbound {
+		return baggage.ContextWithBaggage(extracted, baggage.FromContext(before))
+	}
+	if len(c.InboundAllowedKeys) == 0 && c.InboundMaxBytes <= 0 {
+		return extracted
+	}
+	return baggage.ContextWithBaggage(extracted, filterBaggage(bag, c.InboundAllowedKeys, c.InboundMaxBytes))
+}
+
+// limitOutbound returns ctx with its baggage reduced to the allowed keys and size cap
+func (c BaggageConfig) limitOutbound(ctx context.Context) context.Context {
+	bag := baggage.FromContext(ctx)
+	if bag.Len() == 0 || (c.MaxBytes <= 0 && len(c.AllowedKeys) == 0) {
+		return ctx
+	}
+	limited := filterBaggage(bag, c.AllowedKeys, c.MaxBytes)
+	if limited.Len() == bag.Len() {
+		return ctx
+	}
+	return baggage.ContextWithBaggage(ctx, limited)
+}
+
+// filterBaggage keeps the members whose keys are allowed (all when allowed is
+// empty) and whose cumulative encoded size stays within maxBytes (no limit when
+// maxBytes <= 0). Members are considered in key order for deterministic results.
+func filterBaggage(bag baggage.Baggage, allowed []string, maxBytes int) baggage.Baggage {
+	allowSet := make(map[string]struct{}, len(allowed))
+	for _, key := range allowed {
+		allowSet[key] = struct{}{}
+	}
+
+	members := bag.Members()
+	sort.Slice(members, func(i, j int) bool { return members[i].Key() < members[j].Key() })
+
+	kept := make([]baggage.Member, 0, len(members))
+	size := 0
+	for _, m := range members {
+		if len(allowSet) > 0 {
+			if _, ok := allowSet[m.Key()]; !ok {
+				continue
+			}
+		}
+		memberSize := len(m.String())
+		if len(kept) > 0 {
+			memberSize++ // list-member separator
+		}
+		if maxBytes > 0 && size+memberSize > maxBytes {
+			continue
+		}
+		size += memberSize
+		kept = append(kept, m)
+	}
+
+	result, err := baggage.New(kept...)
+	if err != nil {
+		return baggage.Baggage{}
+	}
+	return result
+}
@@ -0,0 +1,131 @@
+package tracingx
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// newTestBaggage builds baggage from key/value pairs
+func newTestBaggage(t *testing.T, kv ...string) baggage.Baggage {
+	t.Helper()
+	var members []baggage.Member
+	for i := 0; i < len(kv); i += 2 {
+		m, err := baggage.NewMember(kv[i], kv[i+1])
+		require.NoError(t, err)
+		members = append(members, m)
+	}
+	bag, err := baggage.New(members...)
+	require.NoError(t, err)
+	return bag
+}
+
+func TestFilterBaggage(t *testing.T) {
+	bag := newTestBaggage(t, "tenant", "acme", "user", "42", "internal.secret", "x")
+
+	t.Run("keeps only allowed keys", func(t *testing.T) {
+		filtered := filterBaggage(bag, []string{"tenant", "user"}, 0)
+		assert.Equal(t, 2, filtered.Len())
+		assert.Equal(t, "acme", filtered.Member("tenant").Value())
+		assert.Empty(t, filtered.Member("internal.secret").Key())
+	})
+
+	t.Run("caps the encoded size", func(t *testing.T) {
+		// "internal.secret=x" (17) + ",tenant=acme" (12) = 29
+		filtered := filterBaggage(bag, nil, 29)
+		assert.Equal(t, 2, filtered.Len())
+		assert.LessOrEqual(t, len(filtered.String()), 29)
+	})
+
+	t.Run("passes everything without limits", func(t *testing.T) {
+		assert.Equal(t, 3, filterBaggage(bag, nil, 0).Len())
+	})
+}
+
+func TestLimitOutbound(t *testing.T) {
+	cfg := BaggageConfig{MaxBytes: 8192, AllowedKeys: []string{"tenant"}}
+
+	t.Run("returns ctx unchanged without baggage", func(t *testing.T) {
+		ctx := context.Background()
+		assert.Equal(t, ctx, cfg.limitOutbound(ctx))
+	})
+
+	t.Run("filters baggage on the context", func(t *testing.T) {
+		ctx := baggage.ContextWithBaggage(context.Background(), newTestBaggage(t, "tenant", "acme", "debug", "1"))
+		limited := baggage.FromContext(cfg.limitOutbound(ctx))
+		assert.Equal(t, 1, limited.Len())
+		assert.Equal(t, "acme", limited.Member("tenant").Value())
+	})
+}
+
+func TestInjectLimitsBaggage(t *testing.T) {
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "test-service",
+		SampleRate:  1.0,
+		Baggage:     BaggageConfig{MaxBytes: 64, AllowedKeys: []string{"tenant", "blob"}},
+	}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	bag := newTestBaggage(t, "tenant", "acme", "blob", strings.Repeat("a", 100), "leak", "internal")
+	ctx, span := provider.Start(baggage.ContextWithBaggage(context.Background(), bag), "outbound")
+	defer span.End()
+
+	carrier := make(map[string]string)
+	require.NoError(t, provider.Inject(ctx, MapCarrier(carrier)))
+	assert.Equal(t, "tenant=acme", carrier["baggage"])
+	assert.Contains(t, carrier, "traceparent")
+}
+
+func TestSanitizeInbound(t *testing.T) {
+	incoming := newTestBaggage(t, "tenant", "acme", "role", "admin")
+	extracted := baggage.ContextWithBaggage(context.Background(), incoming)
+
+	t.Run("accepts everything by default", func(t *testing.T) {
+		ctx := BaggageConfig{}.sanitizeInbound(context.Background(), extracted)
+		assert.Equal(t, 2, baggage.FromContext(ctx).Len())
+	})
+
+	t.Run("drops all inbound baggage", func(t *testing.T) {
+		ctx := BaggageConfig{DropInbound: true}.sanitizeInbound(context.Background(), extracted)
+		assert.Equal(t, 0, baggage.FromContext(ctx).Len())
+	})
+
+	t.Run("keeps baggage present before extraction", func(t *testing.T) {
+		before := baggage.ContextWithBaggage(context.Background(), newTestBaggage(t, "local", "1"))
+		ctx := BaggageConfig{DropInbound: true}.sanitizeInbound(before, extracted)
+		assert.Equal(t, "1", baggage.FromContext(ctx).Member("local").Value())
+		assert.Empty(t, baggage.FromContext(ctx).Member("role").Key())
+	})
+
+	t.Run("filters by allowlist", func(t *testing.T) {
+		ctx := BaggageConfig{InboundAllowedKeys: []string{"tenant"}}.sanitizeInbound(context.Background(), extracted)
+		bag := baggage.FromContext(ctx)
+		assert.Equal(t, 1, bag.Len())
+		assert.Equal(t, "acme", bag.Member("tenant").Value())
+	})
+}
+
+func TestExtractSanitizesBaggage(t *testing.T) {
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "test-service",
+		SampleRate:  1.0,
+		Baggage:     BaggageConfig{InboundAllowedKeys: []string{"tenant"}},
+	}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	ctx, err := provider.Extract(context.Background(), MapCarrier(map[string]string{
+		"traceparent": validTraceParent,
+		"baggage":     "tenant=acme,role=admin",
+	}))
+	require.NoError(t, err)
+
+	bag := baggage.FromContext(ctx)
+	assert.Equal(t, 1, bag.Len())
+	assert.Equal(t, "acme", bag.Member("tenant").Value())
+}
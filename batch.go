@@ -0,0 +1,84 @@
+package tracingx
+
+import (
+	"context"
+	"sync"
+)
+
+// BatchSpan is a span covering a batch of items, such as a Kafka poll or a
+// bulk API request, that records per-item outcomes. On End the span is tagged
+// with the number of succeeded and failed items.
+type BatchSpan struct {
+	Span
+	items []SpanContext
+
+	mu        sync.Mutex
+	succeeded int
+	failed    int
+	ended     bool
+}
+
+// StartBatchSpan starts a consumer span linked to the span context of every
+// item in the batch, so each item's originating trace leads to the batch that
+// processed it. Items are addressed by their index in itemContexts when
+// recording outcomes.
+func StartBatchSpan(ctx context.Context, tracer Tracer, name string, itemContexts []SpanContext, opts ...SpanOption) (context.Context, *BatchSpan) {
+	opts = append([]SpanOption{
+		WithSpanKind(SpanKindConsumer),
+		WithLinks(itemContexts...),
+		WithAttributes(map[string]any{messagingBatchMessageCountKey: len(itemContexts)}),
+	}, opts...)
+	ctx, span := tracer.Start(ctx, name, opts...)
+	batch := &BatchSpan{Span: span, items: itemContexts}
+	return ContextWithSpan(ctx, batch), batch
+}
+
+// ItemSucceeded records that the item at index was processed successfully
+func (b *BatchSpan) ItemSucceeded(index int) {
+	b.mu.Lock()
+	b.succeeded++
+	b.mu.Unlock()
+}
+
+// ItemFailed records that the item at index failed, adding an event that
+// identifies the item's originating trace
+func (b *BatchSpan) ItemFailed(index int, err error) {
+	b.mu.Lock()
+	b.failed++
+	b.mu.Unlock()
+
+	fields := []Field{
+		{Key: "event", Value: "batch.item_failed"},
+		{Key: "batch.item.index", Value: index},
+	}
+	if index >= 0 && index < len(b.items) && b.items[index].IsValid() {
+		fields = append(fields,
+			Field{Key: "batch.item.trace_id", Value: b.items[index].TraceID},
+			Field{Key: "batch.item.span_id", Value: b.items[index].SpanID},
+		)
+	}
+	if err != nil {
+		fields = append(fields, Field{Key: "error", Value: err.Error()})
+	}
+	b.LogFields(fields...)
+}
+
+// End tags the span with the item outcome counts and completes it. The span
+// is marked as errored only if every recorded item failed.
+func (b *BatchSpan) End() {
+	b.mu.Lock()
+	succeeded, failed, ended := b.succeeded, b.failed, b.ended
+	b.ended = true
+	b.mu.Unlock()
+	if ended {
+		b.Span.End()
+		return
+	}
+
+	b.SetTag("batch.succeeded", succeeded)
+	b.SetTag("batch.failed", failed)
+	if failed > 0 && succeeded == 0 {
+		b.SetTag("error", true)
+	}
+	b.Span.End()
+}
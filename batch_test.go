@@ -0,0 +1,104 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestStartBatchSpan(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	var items []SpanContext
+	for i := 0; i < 3; i++ {
+		ctx, producer := provider.Start(context.Background(), "produce")
+		headers := map[string]string{}
+		require.NoError(t, provider.Inject(ctx, MapCarrier(headers)))
+		producer.End()
+
+		item, err := SpanContextFromCarrier(MapCarrier(headers))
+		require.NoError(t, err)
+		require.True(t, item.IsValid())
+		assert.Equal(t, producer.TraceID(), item.TraceID)
+		items = append(items, item)
+	}
+
+	t.Run("links every item", func(t *testing.T) {
+		ctx, batch := StartBatchSpan(context.Background(), provider, "consume-batch", items)
+		assert.Same(t, batch, SpanFromContext(ctx))
+		batch.End()
+
+		ro := batch.Span.(*otlpSpan).span.(sdktrace.ReadOnlySpan)
+		assert.Equal(t, trace.SpanKindConsumer, ro.SpanKind())
+		require.Len(t, ro.Links(), 3)
+		for i, link := range ro.Links() {
+			assert.Equal(t, items[i].TraceID, link.SpanContext.TraceID().String())
+			assert.Equal(t, items[i].SpanID, link.SpanContext.SpanID().String())
+		}
+		assert.Equal(t, int64(3), attributesOf(t, batch.Span)[messagingBatchMessageCountKey])
+	})
+
+	t.Run("records per-item outcomes", func(t *testing.T) {
+		_, batch := StartBatchSpan(context.Background(), provider, "consume-batch", items)
+		batch.ItemSucceeded(0)
+		batch.ItemFailed(1, errors.New("poison message"))
+		batch.ItemSucceeded(2)
+		batch.End()
+
+		attrs := attributesOf(t, batch.Span)
+		assert.Equal(t, int64(2), attrs["batch.succeeded"])
+		assert.Equal(t, int64(1), attrs["batch.failed"])
+		assert.NotContains(t, attrs, "error")
+
+		ro := batch.Span.(*otlpSpan).span.(sdktrace.ReadOnlySpan)
+		require.Len(t, ro.Events(), 1)
+		event := map[string]any{}
+		for _, kv := range ro.Events()[0].Attributes {
+			event[string(kv.Key)] = kv.Value.AsInterface()
+		}
+		assert.Equal(t, "batch.item_failed", event["event"])
+		assert.Equal(t, items[1].TraceID, event["batch.item.trace_id"])
+		assert.Equal(t, "poison message", event["error"])
+	})
+
+	t.Run("marks error when every item failed", func(t *testing.T) {
+		_, batch := StartBatchSpan(context.Background(), provider, "consume-batch", items[:1])
+		batch.ItemFailed(0, nil)
+		batch.End()
+		batch.End()
+
+		assert.Equal(t, true, attributesOf(t, batch.Span)["error"])
+	})
+
+	t.Run("ignores invalid item contexts", func(t *testing.T) {
+		_, batch := StartBatchSpan(context.Background(), provider, "consume-batch", []SpanContext{{}, items[0]})
+		batch.ItemFailed(5, nil)
+		batch.End()
+
+		ro := batch.Span.(*otlpSpan).span.(sdktrace.ReadOnlySpan)
+		assert.Len(t, ro.Links(), 1)
+	})
+}
+
+func TestSpanContextFromContext(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	ctx, span := provider.Start(context.Background(), "op")
+	defer span.End()
+
+	sc := SpanContextFromContext(ctx)
+	assert.Equal(t, SpanContext{TraceID: span.TraceID(), SpanID: span.SpanID(), Sampled: true}, sc)
+	assert.False(t, SpanContextFromContext(context.Background()).IsValid())
+
+	_, err = SpanContextFromCarrier(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,94 @@
+package tracingx
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// BudgetBaggageKey is the baggage member carrying the remaining timeout
+// budget of a request, in milliseconds, to downstream services. Inject sets
+// it from the deadline of the context; Extract records when it was received.
+const BudgetBaggageKey = "timeout_budget_ms"
+
+// BudgetAttribute records the remaining timeout budget, in milliseconds, on
+// spans at each hop
+const BudgetAttribute = "timeout.budget_ms"
+
+// receivedBudget is a timeout budget received from upstream
+type receivedBudget struct {
+	remaining time.Duration
+	at        time.Time
+}
+
+type receivedBudgetKey struct{}
+
+// RemainingBudget returns the time left to serve the request in ctx: the
+// earlier of the deadline of ctx and the budget received from upstream, less
+// the time spent since it was received. ok is false when neither is known.
+func RemainingBudget(ctx context.Context) (remaining time.Duration, ok bool) {
+	now := time.Now()
+	if deadline, has := ctx.Deadline(); has {
+		remaining, ok = deadline.Sub(now), true
+	}
+	if received, has := ctx.Value(receivedBudgetKey{}).(receivedBudget); has {
+		if left := received.remaining - now.Sub(received.at); !ok || left < remaining {
+			remaining, ok = left, true
+		}
+	}
+	return max(remaining, 0), ok
+}
+
+// ContextWithBudgetDeadline returns a copy of ctx whose deadline is the
+// budget received from upstream, so local work stops when the caller gives up
+func ContextWithBudgetDeadline(ctx context.Context) (context.Context, context.CancelFunc) {
+	remaining, ok := RemainingBudget(ctx)
+	if !ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, remaining)
+}
+
+// RecordBudget records the remaining timeout budget of ctx on span
+func RecordBudget(ctx context.Context, span Span) {
+	if remaining, ok := RemainingBudget(ctx); ok {
+		span.SetTag(BudgetAttribute, remaining.Milliseconds())
+	}
+}
+
+// withBudgetBaggage returns ctx with the remaining timeout budget in its
+// baggage, replacing a value received from upstream
+func withBudgetBaggage(ctx context.Context) context.Context {
+	remaining, ok := RemainingBudget(ctx)
+	if !ok {
+		return ctx
+	}
+	member, err := baggage.NewMember(BudgetBaggageKey, strconv.FormatInt(remaining.Milliseconds(), 10))
+	if err != nil {
+		return ctx
+	}
+	bag, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+// withReceivedBudget records the timeout budget found in the baggage of an
+// extracted context, and when it was received
+func withReceivedBudget(ctx context.Context) context.Context {
+	value := baggage.FromContext(ctx).Member(BudgetBaggageKey).Value()
+	if value == "" {
+		return ctx
+	}
+	ms, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || ms < 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, receivedBudgetKey{}, receivedBudget{
+		remaining: time.Duration(ms) * time.Millisecond,
+		at:        time.Now(),
+	})
+}
@@ -0,0 +1,120 @@
+package tracingx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+func TestRemainingBudget(t *testing.T) {
+	t.Run("unknown without deadline or budget", func(t *testing.T) {
+		_, ok := RemainingBudget(context.Background())
+		assert.False(t, ok)
+	})
+
+	t.Run("uses the context deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		remaining, ok := RemainingBudget(ctx)
+		assert.True(t, ok)
+		assert.InDelta(t, time.Minute, remaining, float64(time.Second))
+	})
+
+	t.Run("uses the earlier of deadline and received budget", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), receivedBudgetKey{}, receivedBudget{remaining: 2 * time.Second, at: time.Now()})
+		remaining, ok := RemainingBudget(ctx)
+		assert.True(t, ok)
+		assert.InDelta(t, 2*time.Second, remaining, float64(100*time.Millisecond))
+
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+		remaining, _ = RemainingBudget(ctx)
+		assert.LessOrEqual(t, remaining, time.Second)
+	})
+
+	t.Run("never negative", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), receivedBudgetKey{}, receivedBudget{remaining: time.Second, at: time.Now().Add(-time.Minute)})
+		remaining, ok := RemainingBudget(ctx)
+		assert.True(t, ok)
+		assert.Zero(t, remaining)
+	})
+}
+
+func TestBudgetPropagation(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("injects the remaining deadline", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		carrier := map[string]string{}
+		require.NoError(t, provider.Inject(ctx, MapCarrier(carrier)))
+
+		bag, err := baggage.Parse(carrier["baggage"])
+		require.NoError(t, err)
+		ms, err := strconv.ParseInt(bag.Member(BudgetBaggageKey).Value(), 10, 64)
+		require.NoError(t, err)
+		assert.InDelta(t, 5000, ms, 100)
+	})
+
+	t.Run("injects nothing without a deadline", func(t *testing.T) {
+		carrier := map[string]string{}
+		require.NoError(t, provider.Inject(context.Background(), MapCarrier(carrier)))
+		assert.NotContains(t, carrier["baggage"], BudgetBaggageKey)
+	})
+
+	t.Run("extracts and applies the received budget", func(t *testing.T) {
+		ctx, err := provider.Extract(context.Background(), MapCarrier(map[string]string{
+			"traceparent": validTraceParent,
+			"baggage":     BudgetBaggageKey + "=3000",
+		}))
+		require.NoError(t, err)
+		remaining, ok := RemainingBudget(ctx)
+		require.True(t, ok)
+		assert.InDelta(t, 3*time.Second, remaining, float64(100*time.Millisecond))
+
+		ctx, cancel := ContextWithBudgetDeadline(ctx)
+		defer cancel()
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(3*time.Second), deadline, 100*time.Millisecond)
+	})
+
+	t.Run("ignores malformed budgets", func(t *testing.T) {
+		ctx, err := provider.Extract(context.Background(), MapCarrier(map[string]string{"baggage": BudgetBaggageKey + "=soon"}))
+		require.NoError(t, err)
+		_, ok := RemainingBudget(ctx)
+		assert.False(t, ok)
+	})
+
+	t.Run("records the budget at each hop", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		server := httptest.NewServer(NewMiddleware(spy)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+		defer server.Close()
+		client := &http.Client{Transport: NewTransport(spy, nil)}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		spans := spy.started()
+		require.Len(t, spans, 2)
+		for _, span := range spans {
+			budget, ok := attributesOf(t, span)[BudgetAttribute].(int64)
+			require.True(t, ok)
+			assert.InDelta(t, 2000, budget, 200)
+		}
+	})
+}
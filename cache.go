@@ -0,0 +1,77 @@
+package tracingx
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Cache attribute keys recorded by the cache helpers
+const (
+	CacheNameAttribute      = "cache.name"
+	CacheOperationAttribute = "cache.operation"
+	CacheHitAttribute       = "cache.hit"
+	CacheKeyHashAttribute   = "cache.key_hash"
+)
+
+// CacheGet runs fn, a cache lookup reporting whether key was found, in a
+// client span named "cache.get <cacheName>" recording the hit/miss outcome and
+// a hash of the key. Keys are hashed so cached identifiers such as emails or
+// tokens are not exported.
+func CacheGet[T any](ctx context.Context, tracer Tracer, cacheName, key string, fn func(ctx context.Context) (T, bool, error)) (T, bool, error) {
+	ctx, span := startCacheSpan(ctx, tracer, "get", cacheName, key)
+	defer span.End()
+
+	value, hit, err := fn(ctx)
+	if err != nil {
+		span.SetError(err)
+		return value, hit, err
+	}
+	span.SetTag(CacheHitAttribute, hit)
+	return value, hit, nil
+}
+
+// CacheSet runs fn, a cache write, in a client span named
+// "cache.set <cacheName>"
+func CacheSet(ctx context.Context, tracer Tracer, cacheName, key string, fn func(ctx context.Context) error) error {
+	ctx, span := startCacheSpan(ctx, tracer, "set", cacheName, key)
+	defer span.End()
+
+	if err := fn(ctx); err != nil {
+		span.SetError(err)
+		return err
+	}
+	return nil
+}
+
+// RecordCacheLookup records a cache lookup as an event on span, for hot paths
+// where a span per lookup is too expensive
+func RecordCacheLookup(span Span, cacheName, key string, hit bool, latency time.Duration) {
+	span.LogFields(
+		Field{Key: "event", Value: "cache.get"},
+		Field{Key: CacheNameAttribute, Value: cacheName},
+		Field{Key: CacheKeyHashAttribute, Value: hashKey(key)},
+		Field{Key: CacheHitAttribute, Value: hit},
+		Field{Key: "cache.latency_ms", Value: float64(latency) / float64(time.Millisecond)},
+	)
+}
+
+// startCacheSpan starts a client span for a cache operation
+func startCacheSpan(ctx context.Context, tracer Tracer, operation, cacheName, key string) (context.Context, Span) {
+	return tracer.Start(ctx, "cache."+operation+" "+cacheName,
+		WithSpanKind(SpanKindClient),
+		WithAttributes(map[string]any{
+			CacheNameAttribute:      cacheName,
+			CacheOperationAttribute: operation,
+			CacheKeyHashAttribute:   hashKey(key),
+		}),
+	)
+}
+
+// hashKey returns a short, stable hash of a key, for attributes that must
+// not export the key itself
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
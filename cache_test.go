@@ -0,0 +1,90 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestCacheHelpers(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("records hit", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		value, hit, err := CacheGet(context.Background(), spy, "users", "user:42", func(ctx context.Context) (string, bool, error) {
+			return "alice", true, nil
+		})
+		require.NoError(t, err)
+		assert.True(t, hit)
+		assert.Equal(t, "alice", value)
+
+		span := spy.started()[0]
+		ro := span.(*otlpSpan).span.(sdktrace.ReadOnlySpan)
+		assert.Equal(t, "cache.get users", ro.Name())
+		assert.Equal(t, trace.SpanKindClient, ro.SpanKind())
+
+		attrs := attributesOf(t, span)
+		assert.Equal(t, "users", attrs[CacheNameAttribute])
+		assert.Equal(t, "get", attrs[CacheOperationAttribute])
+		assert.Equal(t, true, attrs[CacheHitAttribute])
+		assert.Equal(t, hashKey("user:42"), attrs[CacheKeyHashAttribute])
+		assert.NotContains(t, attrs[CacheKeyHashAttribute], "user:42")
+	})
+
+	t.Run("records miss", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		_, hit, err := CacheGet(context.Background(), spy, "users", "user:43", func(ctx context.Context) (int, bool, error) {
+			return 0, false, nil
+		})
+		require.NoError(t, err)
+		assert.False(t, hit)
+		assert.Equal(t, false, attributesOf(t, spy.started()[0])[CacheHitAttribute])
+	})
+
+	t.Run("records errors", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		_, _, err := CacheGet(context.Background(), spy, "users", "k", func(ctx context.Context) (int, bool, error) {
+			return 0, false, errors.New("redis down")
+		})
+		require.Error(t, err)
+		attrs := attributesOf(t, spy.started()[0])
+		assert.Equal(t, true, attrs["error"])
+		assert.NotContains(t, attrs, CacheHitAttribute)
+
+		err = CacheSet(context.Background(), spy, "users", "k", func(ctx context.Context) error {
+			return errors.New("redis down")
+		})
+		require.Error(t, err)
+		assert.Equal(t, "set", attributesOf(t, spy.started()[1])[CacheOperationAttribute])
+	})
+
+	t.Run("records lookup event", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "handler")
+		RecordCacheLookup(span, "sessions", "abc", false, 2*time.Millisecond)
+		span.End()
+
+		events := span.(*otlpSpan).span.(sdktrace.ReadOnlySpan).Events()
+		require.Len(t, events, 1)
+		event := map[string]any{}
+		for _, kv := range events[0].Attributes {
+			event[string(kv.Key)] = kv.Value.AsInterface()
+		}
+		assert.Equal(t, "sessions", event[CacheNameAttribute])
+		assert.Equal(t, false, event[CacheHitAttribute])
+		assert.Equal(t, 2.0, event["cache.latency_ms"])
+	})
+
+	t.Run("key hash is stable and short", func(t *testing.T) {
+		assert.Equal(t, hashKey("k"), hashKey("k"))
+		assert.NotEqual(t, hashKey("a"), hashKey("b"))
+		assert.Len(t, hashKey("k"), 16)
+	})
+}
@@ -0,0 +1,41 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+)
+
+// CancellationAttribute records why a server request's context ended before
+// the handler returned, separating impatient clients from server timeouts
+const CancellationAttribute = "request.cancellation"
+
+// Values of CancellationAttribute
+const (
+	// CancellationClientDisconnect means the client went away, e.g. closed
+	// the connection or canceled the stream
+	CancellationClientDisconnect = "client_disconnect"
+
+	// CancellationTimeout means a server-side deadline expired
+	CancellationTimeout = "timeout"
+)
+
+// RecordCancellation records on span whether ctx, the context of a server
+// request, was canceled by the client or by a server timeout. It does nothing
+// when ctx is still active. Server integrations call it after the handler
+// returns.
+func RecordCancellation(ctx context.Context, span Span) {
+	err := ctx.Err()
+	if err == nil {
+		return
+	}
+	reason := CancellationClientDisconnect
+	if errors.Is(err, context.DeadlineExceeded) {
+		reason = CancellationTimeout
+	}
+	span.SetTag(CancellationAttribute, reason)
+	span.LogFields(
+		String("event", "request_canceled"),
+		String(CancellationAttribute, reason),
+		String("cause", context.Cause(ctx).Error()),
+	)
+}
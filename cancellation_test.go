@@ -0,0 +1,89 @@
+package tracingx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestRecordCancellation(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("active context", func(t *testing.T) {
+		ctx, span := provider.Start(context.Background(), "request")
+		defer span.End()
+		RecordCancellation(ctx, span)
+		assert.NotContains(t, attributesOf(t, span), CancellationAttribute)
+	})
+
+	t.Run("client disconnect", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		ctx, span := provider.Start(ctx, "request")
+		defer span.End()
+		cancel()
+
+		RecordCancellation(ctx, span)
+		assert.Equal(t, CancellationClientDisconnect, attributesOf(t, span)[CancellationAttribute])
+		events := span.(*otlpSpan).span.(sdktrace.ReadOnlySpan).Events()
+		require.Len(t, events, 1)
+		fields := map[string]any{}
+		for _, kv := range events[0].Attributes {
+			fields[string(kv.Key)] = kv.Value.AsInterface()
+		}
+		assert.Equal(t, "request_canceled", fields["event"])
+		assert.Equal(t, "context canceled", fields["cause"])
+		assert.NotContains(t, attributesOf(t, span), "error")
+	})
+
+	t.Run("server timeout", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+		defer cancel()
+		ctx, span := provider.Start(ctx, "request")
+		defer span.End()
+		<-ctx.Done()
+
+		RecordCancellation(ctx, span)
+		assert.Equal(t, CancellationTimeout, attributesOf(t, span)[CancellationAttribute])
+	})
+}
+
+func TestMiddlewareClientDisconnect(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	var span Span
+	started := make(chan struct{})
+	done := make(chan struct{})
+	handler := NewMiddleware(provider)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		span = SpanFromContext(r.Context())
+		close(started)
+		<-r.Context().Done()
+	}))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+		close(done)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	go func() {
+		<-started
+		cancel()
+	}()
+	_, err = http.DefaultClient.Do(req)
+	require.Error(t, err)
+	<-done
+
+	assert.Equal(t, CancellationClientDisconnect, attributesOf(t, span)[CancellationAttribute])
+}
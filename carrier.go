@@ -0,0 +1,59 @@
+package tracingx
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc/metadata"
+)
+
+// Carrier stores propagated trace context, e.g. request headers or message
+// attributes. It has the method set of OpenTelemetry's TextMapCarrier, so
+// carriers of either package are interchangeable.
+type Carrier interface {
+	// Get returns the value stored for key, or an empty string
+	Get(key string) string
+
+	// Set stores value for key
+	Set(key, value string)
+
+	// Keys lists the keys stored in the carrier
+	Keys() []string
+}
+
+// HeaderCarrier adapts HTTP headers to Carrier
+func HeaderCarrier(h http.Header) Carrier {
+	return propagation.HeaderCarrier(h)
+}
+
+// MapCarrier adapts a string map to Carrier
+func MapCarrier(m map[string]string) Carrier {
+	return propagation.MapCarrier(m)
+}
+
+// MetadataCarrier adapts gRPC metadata to Carrier
+func MetadataCarrier(md metadata.MD) Carrier {
+	return metadataCarrier(md)
+}
+
+// metadataCarrier adapts metadata.MD, whose keys are lower case, to Carrier
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if vals := metadata.MD(c).Get(key); len(vals) > 0 {
+		return vals[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
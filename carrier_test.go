@@ -0,0 +1,55 @@
+package tracingx
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestCarrierAdapters(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	ctx, span := provider.Start(context.Background(), "parent")
+	defer span.End()
+	want := FormatTraceParent(span)
+
+	tests := []struct {
+		name    string
+		carrier Carrier
+	}{
+		{"http header", HeaderCarrier(http.Header{})},
+		{"map", MapCarrier(map[string]string{})},
+		{"grpc metadata", MetadataCarrier(metadata.MD{})},
+		{"env", EnvCarrier{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.NoError(t, provider.Inject(ctx, tt.carrier))
+			assert.Equal(t, want, tt.carrier.Get("traceparent"))
+			assert.NotEmpty(t, tt.carrier.Keys())
+
+			extracted, err := provider.Extract(context.Background(), tt.carrier)
+			require.NoError(t, err)
+			_, child := provider.Start(extracted, "child")
+			defer child.End()
+			assert.Equal(t, span.TraceID(), child.TraceID())
+			assert.Equal(t, span.SpanID(), child.ParentSpanID())
+		})
+	}
+}
+
+func TestMetadataCarrier(t *testing.T) {
+	md := metadata.MD{}
+	carrier := MetadataCarrier(md)
+	carrier.Set("Traceparent", validTraceParent)
+	assert.Equal(t, []string{validTraceParent}, md.Get("traceparent"))
+	assert.Equal(t, validTraceParent, carrier.Get("TRACEPARENT"))
+	assert.Equal(t, "", carrier.Get("tracestate"))
+	assert.Equal(t, []string{"traceparent"}, carrier.Keys())
+}
@@ -0,0 +1,52 @@
+package tracingx
+
+import (
+	"context"
+	"flag"
+	"fmt"
+)
+
+// TraceParentFlag is the command-line flag carrying a W3C traceparent
+// between chained CLIs
+const TraceParentFlag = "traceparent"
+
+// RegisterTraceParentFlag registers the --traceparent flag on fs
+func RegisterTraceParentFlag(fs *flag.FlagSet) *string {
+	return fs.String(TraceParentFlag, "", "W3C traceparent of the calling process")
+}
+
+// StartCLI starts the root span of a CLI invocation. The parent trace is
+// read from traceParent, typically the --traceparent flag, falling back to
+// TRACEPARENT, TRACESTATE and BAGGAGE in the environment, so chained CLIs
+// share one trace.
+func StartCLI(ctx context.Context, tracer Tracer, name, traceParent string, opts ...SpanOption) (context.Context, Span, error) {
+	var err error
+	if traceParent != "" {
+		var normalized string
+		if normalized, err = NormalizeTraceParent(traceParent); err != nil {
+			return ctx, nil, fmt.Errorf("invalid --%s: %w", TraceParentFlag, err)
+		}
+		ctx, err = tracer.Extract(ctx, MapCarrier(map[string]string{"traceparent": normalized}))
+	} else {
+		ctx, err = ExtractEnv(ctx, tracer)
+	}
+	if err != nil {
+		return ctx, nil, err
+	}
+	ctx, span := tracer.Start(ctx, name, opts...)
+	return ctx, span, nil
+}
+
+// TraceParentArgs returns the --traceparent argument passing the trace
+// context of ctx to a chained CLI, or nil when ctx has no span
+func TraceParentArgs(ctx context.Context) []string {
+	span := SpanFromContext(ctx)
+	if span == nil {
+		return nil
+	}
+	traceParent := FormatTraceParent(span)
+	if traceParent == "" {
+		return nil
+	}
+	return []string{"--" + TraceParentFlag + "=" + traceParent}
+}
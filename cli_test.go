@@ -0,0 +1,72 @@
+package tracingx
+
+import (
+	"context"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartCLI(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("continues the trace from the flag", func(t *testing.T) {
+		fs := flag.NewFlagSet("cli", flag.ContinueOnError)
+		traceParent := RegisterTraceParentFlag(fs)
+		require.NoError(t, fs.Parse([]string{"--traceparent", validTraceParent}))
+
+		t.Setenv(TraceParentEnv, "00-11111111111111111111111111111111-2222222222222222-01")
+		_, span, err := StartCLI(context.Background(), provider, "cli.run", *traceParent)
+		require.NoError(t, err)
+		defer span.End()
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.TraceID())
+		assert.Equal(t, "00f067aa0ba902b7", span.ParentSpanID())
+	})
+
+	t.Run("falls back to the environment", func(t *testing.T) {
+		t.Setenv(TraceParentEnv, validTraceParent)
+		_, span, err := StartCLI(context.Background(), provider, "cli.run", "")
+		require.NoError(t, err)
+		defer span.End()
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.TraceID())
+	})
+
+	t.Run("starts a new trace without context", func(t *testing.T) {
+		_, span, err := StartCLI(context.Background(), provider, "cli.run", "")
+		require.NoError(t, err)
+		defer span.End()
+		assert.True(t, span.IsRoot())
+	})
+
+	t.Run("rejects an invalid flag", func(t *testing.T) {
+		_, span, err := StartCLI(context.Background(), provider, "cli.run", "garbage")
+		assert.ErrorIs(t, err, ErrInvalidTraceParent)
+		assert.Nil(t, span)
+	})
+}
+
+func TestTraceParentArgs(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	assert.Nil(t, TraceParentArgs(context.Background()))
+
+	ctx, span := provider.Start(context.Background(), "parent")
+	defer span.End()
+	args := TraceParentArgs(ctx)
+	assert.Equal(t, []string{"--traceparent=" + FormatTraceParent(span)}, args)
+
+	fs := flag.NewFlagSet("next", flag.ContinueOnError)
+	traceParent := RegisterTraceParentFlag(fs)
+	require.NoError(t, fs.Parse(args))
+	_, child, err := StartCLI(context.Background(), provider, "next.run", *traceParent)
+	require.NoError(t, err)
+	defer child.End()
+	assert.Equal(t, span.TraceID(), child.TraceID())
+	assert.Equal(t, span.SpanID(), child.ParentSpanID())
+}
@@ -0,0 +1,20 @@
+package tracingx
+
+import "time"
+
+// Clock provides the current time used for span start, end and event
+// timestamps. Inject a fake clock to make durations deterministic in tests.
+type Clock interface {
+	Now() time.Time
+}
+
+// ClockFunc adapts an ordinary function to the Clock interface
+type ClockFunc func() time.Time
+
+// Now returns f()
+func (f ClockFunc) Now() time.Time { return f() }
+
+// systemClock is the default Clock backed by time.Now
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
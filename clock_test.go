@@ -0,0 +1,107 @@
+package tracingx
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// fakeClock is a manually advanced Clock
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newFakeClock(start time.Time) *fakeClock { return &fakeClock{now: start} }
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestClockFunc(t *testing.T) {
+	fixed := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := ClockFunc(func() time.Time { return fixed })
+	assert.Equal(t, fixed, clock.Now())
+}
+
+func TestApplySpanOptionsWithClock(t *testing.T) {
+	fixed := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	config := applySpanOptionsWithClock(ClockFunc(func() time.Time { return fixed }))
+	assert.Equal(t, fixed, config.Timestamp)
+}
+
+func TestOTLPProviderUsesClock(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := newFakeClock(start)
+
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger(),
+		withClock(clock),
+	)
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	_, span := provider.Start(context.Background(), "timed")
+	clock.Advance(250 * time.Millisecond)
+	span.LogFields(Field{Key: "event", Value: "halfway"})
+	clock.Advance(250 * time.Millisecond)
+	span.End()
+
+	ro := span.(*otlpSpan).span.(sdktrace.ReadOnlySpan)
+	assert.Equal(t, start, ro.StartTime())
+	assert.Equal(t, 500*time.Millisecond, ro.EndTime().Sub(ro.StartTime()))
+	require.Len(t, ro.Events(), 1)
+	assert.Equal(t, start.Add(250*time.Millisecond), ro.Events()[0].Time)
+}
+
+func TestSpanDuration(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := newFakeClock(start)
+
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger(),
+		withClock(clock),
+	)
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("reports elapsed time while running", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "running")
+		defer span.End()
+		clock.Advance(100 * time.Millisecond)
+		assert.Equal(t, 100*time.Millisecond, span.Duration())
+		clock.Advance(50 * time.Millisecond)
+		assert.Equal(t, 150*time.Millisecond, span.Duration())
+	})
+
+	t.Run("freezes at End", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "ended")
+		clock.Advance(time.Second)
+		span.End()
+		clock.Advance(time.Hour)
+		assert.Equal(t, time.Second, span.Duration())
+	})
+
+	t.Run("honours backdated start timestamps", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "backdated", WithTimestamp(clock.Now().Add(-2*time.Second)))
+		span.End()
+		assert.Equal(t, 2*time.Second, span.Duration())
+	})
+
+	t.Run("is zero for noop spans", func(t *testing.T) {
+		_, span := newNoopProvider().Start(context.Background(), "noop")
+		span.End()
+		assert.Zero(t, span.Duration())
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/gostratum/core/configx"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 // Config contains configuration for the tracing module
@@ -14,22 +15,159 @@ type Config struct {
 	// ServiceName identifies this service in traces
 	ServiceName string `mapstructure:"service_name" default:"gostratum-service"`
 
-	// Provider specifies which tracing provider to use (otlp, jaeger, noop)
+	// Provider specifies which tracing provider to use (otlp, zipkin, datadog, xray, file, stdout, tee, jaeger, noop)
 	Provider string `mapstructure:"provider" default:"otlp"`
 
 	// SampleRate determines the sampling rate (0.0 to 1.0)
 	SampleRate float64 `mapstructure:"sample_rate" default:"1.0"`
 
+	// Debug logs diagnostics, including call sites, for span misuse such as
+	// ending a span twice or tagging it after End
+	Debug bool `mapstructure:"debug" default:"false"`
+
+	// MaxSpansPerTrace caps the spans started per trace in this process;
+	// further child spans are recorded as events on their parent and the
+	// trace is marked trace.truncated=true (0 disables the cap)
+	MaxSpansPerTrace int `mapstructure:"max_spans_per_trace" default:"0"`
+
+	// Strict surfaces unsupported carriers and malformed trace context that
+	// Extract and Inject otherwise only report as ignorable errors: "off",
+	// "log" (once per carrier type) or "panic" (for tests and development;
+	// malformed trace context from callers is logged, never panicked on)
+	Strict string `mapstructure:"strict" default:"off"`
+
+	// StartupSpan exports a diagnostic span with the effective configuration
+	// (sampler, propagators, exporter, resource, versions) when the provider starts
+	StartupSpan bool `mapstructure:"startup_span" default:"false"`
+
+	// Propagators lists the trace context formats read and written, in
+	// extraction precedence order (later entries win): tracecontext, baggage,
+	// jaeger, datadog, xray. Defaults to tracecontext and baggage.
+	Propagators []string `mapstructure:"propagators"`
+
+	// IDGenerator selects how new trace IDs are generated: "random" (128-bit),
+	// "64bit" (high 64 bits zero, for backends that only keep 64 bits) or
+	// "xray" (timestamped, as AWS X-Ray requires). Defaults to xray for the
+	// xray provider and random for the others.
+	IDGenerator string `mapstructure:"id_generator"`
+
+	// SchemaURL overrides the semantic conventions schema URL attached to
+	// the resource and tracer (defaults to DefaultSchemaURL)
+	SchemaURL string `mapstructure:"schema_url"`
+
+	// Instrumentation identifies the instrumentation scope spans are attributed to
+	Instrumentation InstrumentationConfig `mapstructure:"instrumentation"`
+
+	// Attributes configures how span attributes set more than once are resolved
+	Attributes AttributesConfig `mapstructure:"attributes"`
+
+	// PeerService maps destination addresses of client spans to the logical
+	// service names recorded as peer.service
+	PeerService PeerServiceConfig `mapstructure:"peer_service"`
+
+	// Correlation configures stitching of orphan traces via correlation IDs
+	Correlation CorrelationConfig `mapstructure:"correlation"`
+
+	// SpanLog mirrors finished spans to the logger
+	SpanLog SpanLogConfig `mapstructure:"span_log"`
+
+	// Audit exports selected operations as audit records, independent of sampling
+	Audit AuditConfig `mapstructure:"audit"`
+
+	// Baggage configures limits on propagated baggage
+	Baggage BaggageConfig `mapstructure:"baggage"`
+
+	// Export configures filtering applied before spans are exported
+	Export ExportConfig `mapstructure:"export"`
+
+	// Pipeline lists the processing steps finished spans go through before
+	// export: redact, drop_health, min_duration and batch (the export itself,
+	// implicit when omitted). redact runs in StageRedact and the others in
+	// StageFilter, in the order listed, after the span processors registered
+	// in code for the same stage and priority.
+	Pipeline []string `mapstructure:"pipeline"`
+
+	// Processors configures the steps listed in Pipeline
+	Processors ProcessorsConfig `mapstructure:"processors"`
+
+	// DryRun evaluates candidate filter and sampling rules without
+	// applying them, reporting their effect in Provider.Diagnostics
+	DryRun DryRunConfig `mapstructure:"dry_run"`
+
+	// Pipelines adds export pipelines with their own filter and sample rate,
+	// e.g. all error spans to a cheap store next to the sampled main backend
+	Pipelines []PipelineConfig `mapstructure:"pipelines"`
+
+	// SelfTest probes exporter connectivity when the application starts
+	SelfTest SelfTestConfig `mapstructure:"self_test"`
+
+	// Warmup connects the exporters when the provider is created
+	Warmup WarmupConfig `mapstructure:"warmup"`
+
+	// Deployment stamps every span with deployment metadata read from the
+	// environment, e.g. canary or stable
+	Deployment DeploymentConfig `mapstructure:"deployment"`
+
+	// InboundSampling controls whether callers' sampling decisions are
+	// followed, so untrusted clients cannot force traces to be sampled
+	InboundSampling InboundSamplingConfig `mapstructure:"inbound_sampling"`
+
+	// ShadowTraffic routes the spans of mirrored requests, so load tests
+	// replaying production traffic stay out of production traces
+	ShadowTraffic ShadowTrafficConfig `mapstructure:"shadow_traffic"`
+
+	// Synthetic emits synthetic traces on a timer to verify the export
+	// pipeline end-to-end
+	Synthetic SyntheticConfig `mapstructure:"synthetic"`
+
+	// Logs forwards logx records written inside sampled spans to an OTLP
+	// logs exporter
+	Logs LogsConfig `mapstructure:"logs"`
+
 	// OTLP configuration
 	OTLP OTLPConfig `mapstructure:"otlp"`
 
 	// Jaeger configuration
 	Jaeger JaegerConfig `mapstructure:"jaeger"`
+
+	// Zipkin configuration
+	Zipkin ZipkinConfig `mapstructure:"zipkin"`
+
+	// Datadog configuration
+	Datadog DatadogConfig `mapstructure:"datadog"`
+
+	// XRay configuration
+	XRay XRayConfig `mapstructure:"xray"`
+
+	// File configuration
+	File FileConfig `mapstructure:"file"`
+
+	// Exporters are the exporters of the tee provider, each receiving every
+	// exported span
+	Exporters []ExporterConfig `mapstructure:"exporters"`
+}
+
+// exporter returns the name of the exporter spans are sent to
+func (c Config) exporter() string {
+	switch c.Provider {
+	case "zipkin", "datadog", "xray", "file", "stdout", "tee":
+		return c.Provider
+	}
+	return "otlp"
 }
 
 // Prefix enables configx.Bind
 func (Config) Prefix() string { return "tracing" }
 
+// InstrumentationConfig contains the instrumentation scope configuration
+type InstrumentationConfig struct {
+	// Name is the instrumentation scope name
+	Name string `mapstructure:"name" default:"gostratum"`
+
+	// Version is the instrumentation scope version (defaults to the tracingx module version)
+	Version string `mapstructure:"version"`
+}
+
 // OTLPConfig contains OpenTelemetry Protocol configuration
 type OTLPConfig struct {
 	// Endpoint is the OTLP receiver endpoint
@@ -40,6 +178,39 @@ type OTLPConfig struct {
 
 	// Headers are additional headers to send with requests
 	Headers map[string]string `mapstructure:"headers"`
+
+	// Workers is the number of concurrent export workers, each with its own
+	// batch queue and connection, for span volumes a single stream cannot
+	// keep up with. Spans are distributed round-robin, so export order is
+	// not preserved.
+	Workers int `mapstructure:"workers" default:"1"`
+
+	// QueueSize is the number of ended spans each worker holds until they are
+	// exported, including the batch in flight; spans ending while it is full
+	// are dropped and counted in SpansOverflowed (defaults to the SDK's 2048)
+	QueueSize int `mapstructure:"queue_size" default:"2048"`
+
+	// BatchSize is the maximum number of spans per export request (defaults
+	// to the SDK's 512, capped at QueueSize)
+	BatchSize int `mapstructure:"batch_size" default:"512"`
+}
+
+// workers returns the configured number of export workers, at least one
+func (c OTLPConfig) workers() int {
+	return max(c.Workers, 1)
+}
+
+// batchOptions returns the batch span processor options sizing the queue
+// and export batches
+func (c OTLPConfig) batchOptions() []sdktrace.BatchSpanProcessorOption {
+	var opts []sdktrace.BatchSpanProcessorOption
+	if c.QueueSize > 0 {
+		opts = append(opts, sdktrace.WithMaxQueueSize(c.QueueSize))
+	}
+	if c.BatchSize > 0 {
+		opts = append(opts, sdktrace.WithMaxExportBatchSize(c.BatchSize))
+	}
+	return opts
 }
 
 // JaegerConfig contains Jaeger-specific configuration
@@ -68,21 +239,46 @@ func NewConfig(loader configx.Loader) (Config, error) {
 // This implements the logx.Sanitizable interface for automatic sanitization when logging.
 func (c Config) Sanitize() any {
 	out := c
-	if out.OTLP.Headers != nil {
-		out.OTLP.Headers = make(map[string]string, len(c.OTLP.Headers))
-		for k, v := range c.OTLP.Headers {
-			lk := strings.ToLower(k)
-			if strings.Contains(lk, "token") || strings.Contains(lk, "key") || strings.Contains(lk, "secret") || strings.Contains(lk, "authorization") {
-				out.OTLP.Headers[k] = "[redacted]"
-			} else {
-				out.OTLP.Headers[k] = v
-			}
+	out.OTLP = c.OTLP.sanitize()
+	if c.Pipelines != nil {
+		out.Pipelines = make([]PipelineConfig, len(c.Pipelines))
+		for i, pipeline := range c.Pipelines {
+			pipeline.OTLP = pipeline.OTLP.sanitize()
+			out.Pipelines[i] = pipeline
 		}
 	}
+	if c.Exporters != nil {
+		out.Exporters = make([]ExporterConfig, len(c.Exporters))
+		for i, exporter := range c.Exporters {
+			out.Exporters[i] = exporter.sanitize()
+		}
+	}
+	out.ShadowTraffic.Exporter = c.ShadowTraffic.Exporter.sanitize()
+	out.Logs.OTLP = c.Logs.OTLP.sanitize()
 	return out
 }
 
+// sanitize returns a copy of c with secret-like header values redacted
+func (c OTLPConfig) sanitize() OTLPConfig {
+	if c.Headers == nil {
+		return c
+	}
+	headers := make(map[string]string, len(c.Headers))
+	for k, v := range c.Headers {
+		lk := strings.ToLower(k)
+		if strings.Contains(lk, "token") || strings.Contains(lk, "key") || strings.Contains(lk, "secret") || strings.Contains(lk, "authorization") {
+			headers[k] = "[redacted]"
+		} else {
+			headers[k] = v
+		}
+	}
+	c.Headers = headers
+	return c
+}
+
 // ConfigSummary returns a compact diagnostic map for tracing configuration.
+//
+// Deprecated: use Provider.Diagnostics, which reports live provider state.
 func (c Config) ConfigSummary() map[string]any {
 	hasHeaders := len(c.OTLP.Headers) > 0
 	return map[string]any{
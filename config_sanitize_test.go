@@ -151,4 +151,44 @@ func TestConfig_Sanitize(t *testing.T) {
 			t.Errorf("Expected nil headers, got %v", sanitizedCfg.OTLP.Headers)
 		}
 	})
+	t.Run("redacts nested exporter headers", func(t *testing.T) {
+		secret := func() OTLPConfig {
+			return OTLPConfig{Headers: map[string]string{"x-api-key": "secret-key", "tenant": "acme"}}
+		}
+		cfg := Config{
+			OTLP:          secret(),
+			Pipelines:     []PipelineConfig{{Name: "errors", OTLP: secret()}},
+			Exporters:     []ExporterConfig{{Provider: "otlp", OTLP: secret()}},
+			ShadowTraffic: ShadowTrafficConfig{Exporter: ExporterConfig{OTLP: secret()}},
+			Logs:          LogsConfig{OTLP: secret()},
+		}
+
+		sanitizedCfg := cfg.Sanitize().(Config)
+		nested := map[string]OTLPConfig{
+			"pipelines[0].otlp":            sanitizedCfg.Pipelines[0].OTLP,
+			"exporters[0].otlp":            sanitizedCfg.Exporters[0].OTLP,
+			"shadow_traffic.exporter.otlp": sanitizedCfg.ShadowTraffic.Exporter.OTLP,
+			"logs.otlp":                    sanitizedCfg.Logs.OTLP,
+		}
+		for name, otlp := range nested {
+			if otlp.Headers["x-api-key"] != "[redacted]" {
+				t.Errorf("%s api key not redacted: %s", name, otlp.Headers["x-api-key"])
+			}
+			if otlp.Headers["tenant"] != "acme" {
+				t.Errorf("%s tenant header changed: %s", name, otlp.Headers["tenant"])
+			}
+		}
+
+		// Verify the original slices are not shared
+		if cfg.Pipelines[0].OTLP.Headers["x-api-key"] != "secret-key" {
+			t.Error("Original pipeline header was mutated")
+		}
+		if cfg.Exporters[0].OTLP.Headers["x-api-key"] != "secret-key" {
+			t.Error("Original exporter header was mutated")
+		}
+		sanitizedCfg.Pipelines[0].Name = "changed"
+		if cfg.Pipelines[0].Name != "errors" {
+			t.Error("Sanitized pipelines share the original slice")
+		}
+	})
 }
@@ -105,6 +105,11 @@ func TestOTLPConfig(t *testing.T) {
 		assert.True(t, cfg.Insecure)
 		assert.Nil(t, cfg.Headers)
 	})
+
+	t.Run("queue and batch sizes", func(t *testing.T) {
+		assert.Empty(t, OTLPConfig{}.batchOptions())
+		assert.Len(t, OTLPConfig{QueueSize: 8192, BatchSize: 1024}.batchOptions(), 2)
+	})
 }
 
 func TestJaegerConfig(t *testing.T) {
@@ -0,0 +1,107 @@
+package tracingx
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// CorrelationIDAttribute is the span attribute carrying a correlation (request) ID
+const CorrelationIDAttribute = "correlation.id"
+
+// RequestIDAttribute is the span attribute carrying the application request ID
+const RequestIDAttribute = "request.id"
+
+// RequestIDFunc returns the request ID carried by ctx, or an empty string.
+// Provide one through fx to attach request IDs from the application's request
+// context (e.g. gostratum request ID middleware) to every span.
+type RequestIDFunc func(ctx context.Context) string
+
+// defaultCorrelationHeaders are checked when CorrelationConfig.Headers is empty
+var defaultCorrelationHeaders = []string{"X-Request-ID", "X-Correlation-ID"}
+
+// CorrelationConfig configures stitching of orphan traces via correlation IDs
+type CorrelationConfig struct {
+	// Enabled attaches an incoming correlation ID to root spans when no
+	// trace context could be extracted
+	Enabled bool `mapstructure:"enabled" default:"false"`
+
+	// Headers lists the carrier keys checked for a correlation ID
+	// (defaults to X-Request-ID and X-Correlation-ID)
+	Headers []string `mapstructure:"headers"`
+}
+
+// headers returns the configured correlation headers or the defaults
+func (c CorrelationConfig) headers() []string {
+	if len(c.Headers) > 0 {
+		return c.Headers
+	}
+	return defaultCorrelationHeaders
+}
+
+// ContextWithCorrelationID returns a new context carrying the correlation ID.
+// Root spans started from this context get the correlation.id attribute.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID carried by ctx, if any
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+type correlationIDKey struct{}
+
+// extractCorrelationID attaches the first correlation ID found in carrier to
+// ctx when ctx carries no valid remote trace context
+func (c CorrelationConfig) extractCorrelationID(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	if !c.Enabled || trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx
+	}
+	for _, header := range c.headers() {
+		if id := getFold(carrier, header); id != "" {
+			return ContextWithCorrelationID(ctx, id)
+		}
+	}
+	return ctx
+}
+
+// getFold looks up key in carrier, falling back to a case-insensitive match
+func getFold(carrier propagation.TextMapCarrier, key string) string {
+	if v := carrier.Get(key); v != "" {
+		return v
+	}
+	for _, k := range carrier.Keys() {
+		if strings.EqualFold(k, key) {
+			return carrier.Get(k)
+		}
+	}
+	return ""
+}
+
+// TraceIDFromContext returns the trace ID of the span in ctx, or an empty
+// string. It lets request-ID and logging integrations read the trace ID
+// without depending on a Span.
+func TraceIDFromContext(ctx context.Context) string {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.TraceID()
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		return sc.TraceID().String()
+	}
+	return ""
+}
+
+// SpanIDFromContext returns the span ID of the span in ctx, or an empty string
+func SpanIDFromContext(ctx context.Context) string {
+	if span := SpanFromContext(ctx); span != nil {
+		return span.SpanID()
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		return sc.SpanID().String()
+	}
+	return ""
+}
@@ -0,0 +1,116 @@
+package tracingx
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestCorrelationIDContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, CorrelationIDFromContext(ctx))
+
+	ctx = ContextWithCorrelationID(ctx, "req-123")
+	assert.Equal(t, "req-123", CorrelationIDFromContext(ctx))
+}
+
+func TestExtractCorrelationID(t *testing.T) {
+	cfg := CorrelationConfig{Enabled: true}
+
+	t.Run("uses default headers case-insensitively", func(t *testing.T) {
+		carrier := HeaderCarrier(http.Header{"X-Request-Id": {"req-1"}})
+		ctx := cfg.extractCorrelationID(context.Background(), carrier)
+		assert.Equal(t, "req-1", CorrelationIDFromContext(ctx))
+	})
+
+	t.Run("uses configured headers", func(t *testing.T) {
+		custom := CorrelationConfig{Enabled: true, Headers: []string{"x-trace-ref"}}
+		carrier := propagation.MapCarrier{"x-trace-ref": "ref-9", "X-Request-ID": "ignored"}
+		ctx := custom.extractCorrelationID(context.Background(), carrier)
+		assert.Equal(t, "ref-9", CorrelationIDFromContext(ctx))
+	})
+
+	t.Run("is a no-op when disabled", func(t *testing.T) {
+		carrier := propagation.MapCarrier{"X-Request-ID": "req-1"}
+		ctx := CorrelationConfig{}.extractCorrelationID(context.Background(), carrier)
+		assert.Empty(t, CorrelationIDFromContext(ctx))
+	})
+}
+
+func TestExtractAttachesCorrelationID(t *testing.T) {
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "test-service",
+		SampleRate:  1.0,
+		Correlation: CorrelationConfig{Enabled: true},
+	}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("when no trace context is present", func(t *testing.T) {
+		ctx, err := provider.Extract(context.Background(), MapCarrier(map[string]string{"X-Request-ID": "req-42"}))
+		require.NoError(t, err)
+		assert.Equal(t, "req-42", CorrelationIDFromContext(ctx))
+
+		ctx, root := provider.Start(ctx, "root")
+		defer root.End()
+		assert.Equal(t, "req-42", attributesOf(t, root)[CorrelationIDAttribute])
+
+		_, child := provider.Start(ctx, "child")
+		defer child.End()
+		assert.NotContains(t, attributesOf(t, child), CorrelationIDAttribute)
+	})
+
+	t.Run("not when a trace context is present", func(t *testing.T) {
+		ctx, err := provider.Extract(context.Background(), MapCarrier(map[string]string{
+			"traceparent":  validTraceParent,
+			"X-Request-ID": "req-42",
+		}))
+		require.NoError(t, err)
+		assert.Empty(t, CorrelationIDFromContext(ctx))
+	})
+}
+
+type requestIDKey struct{}
+
+func TestRequestIDBridge(t *testing.T) {
+	requestID := func(ctx context.Context) string {
+		id, _ := ctx.Value(requestIDKey{}).(string)
+		return id
+	}
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger(),
+		withRequestIDFunc(requestID),
+	)
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("attaches the request ID to spans", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), requestIDKey{}, "req-7")
+		ctx, span := provider.Start(ctx, "handler")
+		defer span.End()
+		assert.Equal(t, "req-7", attributesOf(t, span)[RequestIDAttribute])
+
+		_, child := provider.Start(ctx, "child")
+		defer child.End()
+		assert.Equal(t, "req-7", attributesOf(t, child)[RequestIDAttribute])
+	})
+
+	t.Run("omits the attribute without a request ID", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "handler")
+		defer span.End()
+		assert.NotContains(t, attributesOf(t, span), RequestIDAttribute)
+	})
+
+	t.Run("exposes trace and span IDs from context", func(t *testing.T) {
+		ctx, span := provider.Start(context.Background(), "handler")
+		defer span.End()
+		assert.Equal(t, span.TraceID(), TraceIDFromContext(ctx))
+		assert.Equal(t, span.SpanID(), SpanIDFromContext(ctx))
+
+		assert.Empty(t, TraceIDFromContext(context.Background()))
+		assert.Empty(t, SpanIDFromContext(context.Background()))
+	})
+}
@@ -0,0 +1,66 @@
+package tracingx
+
+import (
+	"os"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// DeploymentTrackAttribute records the release track of the deployment that
+// recorded a span, e.g. canary or stable
+const DeploymentTrackAttribute = "deployment.track"
+
+// DeploymentConfig stamps every span with metadata of the running
+// deployment, read from environment variables when the provider is created
+// or reconfigured, so canary and baseline traces differ by one attribute.
+// Unset or empty variables are skipped.
+type DeploymentConfig struct {
+	// Enabled stamps spans with the deployment attributes
+	Enabled bool `mapstructure:"enabled" default:"false"`
+
+	// TrackEnv names the variable holding the release track, recorded as
+	// deployment.track
+	TrackEnv string `mapstructure:"track_env" default:"DEPLOYMENT_TRACK"`
+
+	// BuildSHAEnv names the variable holding the build's commit SHA, recorded
+	// as vcs.ref.head.revision
+	BuildSHAEnv string `mapstructure:"build_sha_env" default:"BUILD_SHA"`
+
+	// BranchEnv names the variable holding the build's branch, recorded as
+	// vcs.ref.head.name
+	BranchEnv string `mapstructure:"branch_env" default:"BUILD_BRANCH"`
+
+	// Env maps further attribute keys to the variables holding their values,
+	// e.g. experiment.cohort: EXPERIMENT_COHORT
+	Env map[string]string `mapstructure:"env"`
+}
+
+// attributes reads the deployment attributes from the environment
+func (c DeploymentConfig) attributes() []attribute.KeyValue {
+	if !c.Enabled {
+		return nil
+	}
+	var attrs []attribute.KeyValue
+	add := func(key, env string) {
+		if env == "" {
+			return
+		}
+		if value := os.Getenv(env); value != "" {
+			attrs = append(attrs, attribute.String(key, value))
+		}
+	}
+	add(DeploymentTrackAttribute, c.TrackEnv)
+	add(vcsRefHeadRevisionKey, c.BuildSHAEnv)
+	add(vcsRefHeadNameKey, c.BranchEnv)
+
+	keys := make([]string, 0, len(c.Env))
+	for key := range c.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		add(key, c.Env[key])
+	}
+	return attrs
+}
@@ -0,0 +1,42 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeploymentAttributes(t *testing.T) {
+	t.Setenv("DEPLOYMENT_TRACK", "canary")
+	t.Setenv("BUILD_SHA", "9f2c1e7")
+	t.Setenv("BUILD_BRANCH", "")
+	t.Setenv("EXPERIMENT_COHORT", "b")
+
+	config := DeploymentConfig{
+		Enabled:     true,
+		TrackEnv:    "DEPLOYMENT_TRACK",
+		BuildSHAEnv: "BUILD_SHA",
+		BranchEnv:   "BUILD_BRANCH",
+		Env:         map[string]string{"experiment.cohort": "EXPERIMENT_COHORT", "region": "UNSET_REGION"},
+	}
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0, Deployment: config}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	ctx, root := provider.Start(context.Background(), "checkout")
+	_, child := provider.Start(ctx, "charge", WithAttributes(map[string]any{"payment.id": "pay_1"}))
+	for _, span := range []Span{root, child} {
+		attrs := attributesOf(t, span)
+		assert.Equal(t, "canary", attrs[DeploymentTrackAttribute])
+		assert.Equal(t, "9f2c1e7", attrs["vcs.ref.head.revision"])
+		assert.Equal(t, "b", attrs["experiment.cohort"])
+		assert.NotContains(t, attrs, "vcs.ref.head.name", "empty variables are skipped")
+		assert.NotContains(t, attrs, "region", "unset variables are skipped")
+	}
+	assert.False(t, child.SetTagIfAbsent(DeploymentTrackAttribute, "stable"))
+
+	config.Enabled = false
+	assert.Empty(t, config.attributes())
+}
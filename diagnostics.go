@@ -0,0 +1,248 @@
+package tracingx
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// StartupSpanName is the name of the diagnostic span emitted when the
+// provider starts with tracing.startup_span enabled
+const StartupSpanName = "tracingx.startup"
+
+// startupDiagnosticsPrefix prefixes the startup diagnostics span attributes
+const startupDiagnosticsPrefix = "tracingx.config."
+
+// startupDiagnostics captures the effective configuration of a provider:
+// sampler, propagators, exporter settings, resource attributes and versions.
+// Exporter header values are omitted since they commonly carry credentials.
+func startupDiagnostics(config Config, sampler sdktrace.Sampler, res *resource.Resource) map[string]any {
+	headers := exporterHeaders(config.exporterConfigs())
+
+	diag := map[string]any{
+		"service":             config.ServiceName,
+		"sampler":             sampler.Description(),
+		"sample_rate":         config.SampleRate,
+		"propagators":         otel.GetTextMapPropagator().Fields(),
+		"exporter":            config.exporter(),
+		"exporter.endpoint":   config.OTLP.Endpoint,
+		"exporter.insecure":   config.OTLP.Insecure,
+		"exporter.headers":    headers,
+		"exporter.workers":    config.OTLP.workers(),
+		"pipelines":           len(config.Pipelines),
+		"max_spans_per_trace": config.MaxSpansPerTrace,
+		"id_generator":        config.idGenerator(),
+		"schema_url":          config.schemaURL(),
+		"version.tracingx":    moduleVersion(),
+		"version.otel":        otel.Version(),
+		"version.sdk":         sdk.Version(),
+	}
+	switch config.exporter() {
+	case "zipkin":
+		diag["exporter.endpoint"] = config.Zipkin.Endpoint
+		diag["exporter.timeout"] = config.Zipkin.Timeout.String()
+		delete(diag, "exporter.insecure")
+		delete(diag, "exporter.headers")
+		diag["exporter.workers"] = 1
+	case "datadog":
+		diag["exporter.endpoint"] = config.Datadog.endpoint()
+		diag["exporter.insecure"] = true
+	case "xray":
+		if region := config.XRay.Region; region != "" {
+			diag["exporter.region"] = region
+		}
+		if config.XRay.Direct {
+			delete(diag, "exporter.insecure")
+			delete(diag, "exporter.headers")
+			diag["exporter.endpoint"] = config.XRay.daemonAddress()
+			diag["exporter.workers"] = 1
+		}
+	case "file":
+		delete(diag, "exporter.endpoint")
+		delete(diag, "exporter.insecure")
+		delete(diag, "exporter.headers")
+		diag["exporter.path"] = config.File.Path
+		diag["exporter.max_size_mb"] = config.File.MaxSizeMB
+		diag["exporter.max_backups"] = config.File.MaxBackups
+		diag["exporter.workers"] = 1
+	case "stdout":
+		delete(diag, "exporter.endpoint")
+		delete(diag, "exporter.insecure")
+		delete(diag, "exporter.headers")
+		diag["exporter.workers"] = 1
+	case "tee":
+		delete(diag, "exporter.endpoint")
+		delete(diag, "exporter.insecure")
+		delete(diag, "exporter.workers")
+		exporters := make([]string, len(config.Exporters))
+		for i, e := range config.Exporters {
+			exporters[i] = e.Provider
+		}
+		diag["exporters"] = exporters
+	}
+	for _, kv := range res.Attributes() {
+		diag["resource."+string(kv.Key)] = kv.Value.Emit()
+	}
+	return diag
+}
+
+// exporterHeaders returns the sorted names of the headers sent by the
+// exporters using OTLP, without duplicates
+func exporterHeaders(exporters []ExporterConfig) []string {
+	headers := []string{}
+	for _, e := range exporters {
+		if !e.usesOTLP() {
+			continue
+		}
+		for k := range e.OTLP.Headers {
+			if !slices.Contains(headers, k) {
+				headers = append(headers, k)
+			}
+		}
+	}
+	sort.Strings(headers)
+	return headers
+}
+
+// diagnosticKeys returns the keys of diag in sorted order
+func diagnosticKeys(diag map[string]any) []string {
+	keys := make([]string, 0, len(diag))
+	for k := range diag {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// diagnosticFields converts diagnostics to log fields
+func diagnosticFields(diag map[string]any) []logx.Field {
+	fields := make([]logx.Field, 0, len(diag))
+	for _, k := range diagnosticKeys(diag) {
+		fields = append(fields, logx.Any(k, diag[k]))
+	}
+	return fields
+}
+
+// diagnosticAttributes converts diagnostics to span attributes
+func diagnosticAttributes(diag map[string]any) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(diag))
+	for _, k := range diagnosticKeys(diag) {
+		attrs = append(attrs, toAttribute(startupDiagnosticsPrefix+k, diag[k]))
+	}
+	return attrs
+}
+
+// Diagnostics reports the live state of a provider, e.g. for an admin endpoint
+type Diagnostics struct {
+	// Provider is the provider type (otlp, noop)
+	Provider string `json:"provider"`
+
+	// ServiceName identifies the service in traces
+	ServiceName string `json:"service_name,omitempty"`
+
+	// SampleRate is the current head sampling rate
+	SampleRate float64 `json:"sample_rate"`
+
+	// ExporterHealthy is false when the most recent export failed
+	ExporterHealthy bool `json:"exporter_healthy"`
+
+	// LastExportError describes the most recent export failure, if any
+	LastExportError string `json:"last_export_error,omitempty"`
+
+	// LastExportAt is the time of the most recent export attempt
+	LastExportAt time.Time `json:"last_export_at,omitempty"`
+
+	// QueueDepth estimates the spans waiting in the export queue
+	QueueDepth int `json:"queue_depth"`
+
+	// SpansExported counts spans accepted by the exporter
+	SpansExported uint64 `json:"spans_exported"`
+
+	// SpansFailed counts spans the exporter failed to deliver
+	SpansFailed uint64 `json:"spans_failed"`
+
+	// SpansFiltered counts spans dropped by the export filter
+	SpansFiltered uint64 `json:"spans_filtered"`
+
+	// SpansOverflowed counts spans dropped because the export queue was full
+	SpansOverflowed uint64 `json:"spans_overflowed"`
+
+	// Propagation counts Extract calls that found no usable trace context
+	Propagation PropagationStats `json:"propagation"`
+
+	// DryRun reports the effect of the candidate rules when dry_run is enabled
+	DryRun *DryRunDiagnostics `json:"dry_run,omitempty"`
+
+	// Workers reports each export worker when more than one is configured
+	Workers []WorkerDiagnostics `json:"workers,omitempty"`
+}
+
+// WorkerDiagnostics reports the state of one export worker
+type WorkerDiagnostics struct {
+	// ExporterHealthy is false when the worker's most recent export failed
+	ExporterHealthy bool `json:"exporter_healthy"`
+
+	// LastExportError describes the worker's most recent export failure, if any
+	LastExportError string `json:"last_export_error,omitempty"`
+
+	// QueueDepth estimates the spans waiting in the worker's queue
+	QueueDepth int `json:"queue_depth"`
+
+	// SpansExported counts spans the worker delivered
+	SpansExported uint64 `json:"spans_exported"`
+
+	// SpansFailed counts spans the worker failed to deliver
+	SpansFailed uint64 `json:"spans_failed"`
+
+	// SpansOverflowed counts spans dropped because the worker's queue was full
+	SpansOverflowed uint64 `json:"spans_overflowed"`
+}
+
+// Stats returns a snapshot of the provider's span and export counters, which
+// accumulate across Reconfigure
+func (p *otlpProvider) Stats() ExportStats {
+	return p.current().stats.snapshot()
+}
+
+// Diagnostics reports the provider's exporter health and export counters
+func (p *otlpProvider) Diagnostics() Diagnostics {
+	pipeline := p.current()
+	stats := pipeline.stats
+	stats.mu.Lock()
+	lastErr, lastExport := stats.lastErr, stats.lastExport
+	stats.mu.Unlock()
+
+	diag := Diagnostics{
+		Provider:        pipeline.config.exporter(),
+		ServiceName:     pipeline.config.ServiceName,
+		SampleRate:      pipeline.ratio.rate(),
+		ExporterHealthy: lastErr == nil,
+		LastExportAt:    lastExport,
+		QueueDepth:      stats.queueDepth(),
+		SpansExported:   stats.exported.Load(),
+		SpansFailed:     stats.failed.Load(),
+		SpansFiltered:   stats.filtered.Load(),
+		SpansOverflowed: stats.overflowed.Load(),
+		Propagation:     p.propagation.snapshot(),
+	}
+	if lastErr != nil {
+		diag.LastExportError = lastErr.Error()
+	}
+	if pipeline.dryRun != nil {
+		diag.DryRun = pipeline.dryRun.snapshot()
+	}
+	if len(pipeline.workers) > 1 {
+		diag.Workers = make([]WorkerDiagnostics, len(pipeline.workers))
+		for i, w := range pipeline.workers {
+			diag.Workers[i] = w.stats.workerDiagnostics()
+		}
+	}
+	return diag
+}
@@ -0,0 +1,302 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestStartupDiagnostics(t *testing.T) {
+	config := Config{
+		ServiceName: "orders",
+		SampleRate:  0.25,
+		OTLP: OTLPConfig{
+			Endpoint: "collector:4317",
+			Headers:  map[string]string{"api-key": "secret", "x-tenant": "acme"},
+		},
+	}
+	res := resource.NewSchemaless(serviceNameAttribute("orders"))
+
+	diag := startupDiagnostics(config, sdktrace.TraceIDRatioBased(0.25), res)
+	assert.Equal(t, "orders", diag["service"])
+	assert.Contains(t, diag["sampler"], "TraceIDRatioBased")
+	assert.Equal(t, 0.25, diag["sample_rate"])
+	assert.Equal(t, "collector:4317", diag["exporter.endpoint"])
+	assert.Equal(t, []string{"api-key", "x-tenant"}, diag["exporter.headers"])
+	assert.Equal(t, "orders", diag["resource.service.name"])
+	assert.NotEmpty(t, diag["version.sdk"])
+	assert.NotEmpty(t, diag["version.otel"])
+	for _, v := range diag {
+		assert.NotEqual(t, "secret", v)
+	}
+
+	tee := startupDiagnostics(Config{Provider: "tee", Exporters: []ExporterConfig{
+		{Provider: "otlp", OTLP: OTLPConfig{Headers: map[string]string{"x-tenant": "acme"}}},
+		{Provider: "zipkin", OTLP: OTLPConfig{Headers: map[string]string{"unused": "1"}}},
+		{Provider: "datadog", OTLP: OTLPConfig{Headers: map[string]string{"dd-api-key": "secret", "x-tenant": "acme"}}},
+	}}, sdktrace.AlwaysSample(), res)
+	assert.Equal(t, []string{"dd-api-key", "x-tenant"}, tee["exporter.headers"])
+
+	attrs := diagnosticAttributes(diag)
+	assert.Len(t, attrs, len(diag))
+	assert.Equal(t, startupDiagnosticsPrefix+"exporter", string(attrs[0].Key))
+	assert.Len(t, diagnosticFields(diag), len(diag))
+}
+
+func TestStartupSpan(t *testing.T) {
+	collector := newFakeCollector(t)
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "test-service",
+		SampleRate:  0,
+		StartupSpan: true,
+		OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+	}, getTestLogger())
+	require.NoError(t, err)
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	spans := collector.received()
+	require.Len(t, spans, 1)
+	assert.Equal(t, StartupSpanName, spans[0].Name)
+
+	keys := map[string]bool{}
+	for _, kv := range spans[0].Attributes {
+		keys[kv.Key] = true
+	}
+	assert.True(t, keys[SyntheticAttribute])
+	assert.True(t, keys[startupDiagnosticsPrefix+"sampler"])
+	assert.True(t, keys[startupDiagnosticsPrefix+"exporter.endpoint"])
+}
+
+func TestProviderDiagnostics(t *testing.T) {
+	t.Run("counts exported and filtered spans", func(t *testing.T) {
+		collector := newFakeCollector(t)
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			Export:      ExportConfig{ExcludeOperations: []string{"health"}},
+			OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+		}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		_, kept := provider.Start(context.Background(), "work")
+		kept.End()
+		_, dropped := provider.Start(context.Background(), "health")
+		dropped.End()
+		require.NoError(t, provider.ForceFlush(context.Background()))
+
+		diag := provider.Diagnostics()
+		assert.Equal(t, "otlp", diag.Provider)
+		assert.Equal(t, 1.0, diag.SampleRate)
+		assert.True(t, diag.ExporterHealthy)
+		assert.Empty(t, diag.LastExportError)
+		assert.False(t, diag.LastExportAt.IsZero())
+		assert.Equal(t, uint64(1), diag.SpansExported)
+		assert.Equal(t, uint64(1), diag.SpansFiltered)
+		assert.Equal(t, uint64(0), diag.SpansFailed)
+		assert.Equal(t, 0, diag.QueueDepth)
+	})
+
+	t.Run("reports export failures", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		require.Error(t, provider.SelfTest(ctx))
+
+		diag := provider.Diagnostics()
+		assert.False(t, diag.ExporterHealthy)
+		assert.NotEmpty(t, diag.LastExportError)
+		assert.Equal(t, uint64(1), diag.SpansFailed)
+		assert.Equal(t, 0, diag.QueueDepth)
+	})
+
+	t.Run("noop provider", func(t *testing.T) {
+		diag := newNoopProvider().Diagnostics()
+		assert.Equal(t, "noop", diag.Provider)
+		assert.True(t, diag.ExporterHealthy)
+	})
+}
+
+func TestProviderStats(t *testing.T) {
+	t.Run("counts spans through export", func(t *testing.T) {
+		collector := newFakeCollector(t)
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			Export:      ExportConfig{ExcludeOperations: []string{"health"}},
+			OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+		}, getTestLogger(), withSpanProcessors(SpanProcessor{
+			Name:    "drop-noise",
+			Stage:   StageFilter,
+			Process: func(s *FinishedSpan) bool { return s.Operation != "noise" },
+		}))
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		for _, name := range []string{"work", "health", "noise"} {
+			_, span := provider.Start(context.Background(), name)
+			span.End()
+		}
+		_, open := provider.Start(context.Background(), "open")
+		defer open.End()
+		require.NoError(t, provider.ForceFlush(context.Background()))
+
+		assert.Equal(t, ExportStats{
+			SpansStarted:  4,
+			SpansEnded:    3,
+			SpansSampled:  4,
+			SpansExported: 1,
+			SpansDropped:  2,
+		}, provider.Stats())
+	})
+
+	t.Run("counts unsampled spans", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 0}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		_, span := provider.Start(context.Background(), "work")
+		span.End()
+
+		stats := provider.Stats()
+		assert.Equal(t, uint64(1), stats.SpansStarted)
+		assert.Equal(t, uint64(1), stats.SpansEnded)
+		assert.Zero(t, stats.SpansSampled)
+	})
+
+	t.Run("does not count recorded-only spans as dropped", func(t *testing.T) {
+		collector := newFakeCollector(t)
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			SampleRate:  0,
+			Export:      ExportConfig{ExcludeOperations: []string{"health"}},
+			OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+			Pipelines:   []PipelineConfig{{Name: "errors", Filter: "errors", OTLP: OTLPConfig{Endpoint: collector.endpoint, Insecure: true}}},
+		}, getTestLogger(), withSpanProcessors(SpanProcessor{
+			Name:    "drop-noise",
+			Stage:   StageFilter,
+			Process: func(s *FinishedSpan) bool { return s.Operation != "noise" },
+		}))
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		for _, name := range []string{"health", "noise"} {
+			_, span := provider.Start(context.Background(), name)
+			span.End()
+		}
+		assert.Zero(t, provider.Stats().SpansDropped)
+	})
+
+	t.Run("reports the last export error", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		require.Error(t, provider.SelfTest(ctx))
+
+		stats := provider.Stats()
+		assert.NotEmpty(t, stats.LastExportError)
+		assert.Equal(t, uint64(1), stats.SpansDropped)
+	})
+
+	t.Run("noop provider", func(t *testing.T) {
+		assert.Equal(t, ExportStats{}, newNoopProvider().Stats())
+	})
+}
+
+func TestExportStatsIgnoreRecordOnlySpans(t *testing.T) {
+	collector := newFakeCollector(t)
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "billing",
+		SampleRate:  0,
+		Audit:       AuditConfig{Enabled: true, Operations: []string{"payment.refund"}},
+		OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+	}, getTestLogger(), withAuditSink(&memoryAuditSink{}))
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	_, span := provider.Start(context.Background(), "payment.refund")
+	span.End()
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	assert.Zero(t, provider.Diagnostics().QueueDepth, "recorded-only spans never reach the export queue")
+}
+
+func TestExportStatsQueueDepth(t *testing.T) {
+	stats := &exportStats{}
+	stats.enqueued.Add(5)
+	assert.Equal(t, 5, stats.queueDepth())
+	stats.recordExport(3, nil, time.Now())
+	assert.Equal(t, 2, stats.queueDepth())
+	stats.recordExport(4, nil, time.Now())
+	assert.Equal(t, 0, stats.queueDepth())
+
+	stats = &exportStats{}
+	stats.enqueued.Add(2)
+	stats.recordOverflow()
+	assert.Equal(t, 1, stats.queueDepth(), "overflowed spans are not queued")
+	stats.recordExport(1, nil, time.Now())
+	assert.Equal(t, 0, stats.queueDepth())
+}
+
+func TestExportQueueOverflow(t *testing.T) {
+	exporter := &blockingExporter{started: make(chan struct{}, 1), release: make(chan struct{})}
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "test-service",
+		SampleRate:  1.0,
+		OTLP:        OTLPConfig{QueueSize: 2, BatchSize: 1},
+	}, getTestLogger(), withExporter(exporter))
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	_, span := provider.Start(context.Background(), "exporting")
+	span.End()
+	<-exporter.started
+	for range 9 {
+		_, span := provider.Start(context.Background(), "work")
+		span.End()
+	}
+
+	stats := provider.Stats()
+	assert.Equal(t, uint64(8), stats.SpansOverflowed)
+	assert.Equal(t, uint64(8), stats.SpansDropped)
+	assert.Equal(t, 2, stats.QueueLength, "the span being exported and the queued one")
+
+	close(exporter.release)
+	require.NoError(t, provider.ForceFlush(context.Background()))
+	stats = provider.Stats()
+	assert.Equal(t, uint64(2), stats.SpansExported)
+	assert.Equal(t, uint64(8), stats.SpansDropped)
+	assert.Zero(t, stats.QueueLength)
+	diag := provider.Diagnostics()
+	assert.Zero(t, diag.QueueDepth)
+	assert.Equal(t, uint64(8), diag.SpansOverflowed)
+}
+
+// blockingExporter holds every export until release is closed, signaling
+// started when the first one begins
+type blockingExporter struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (e *blockingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	select {
+	case e.started <- struct{}{}:
+	default:
+	}
+	<-e.release
+	return nil
+}
+
+func (e *blockingExporter) Shutdown(context.Context) error { return nil }
@@ -0,0 +1,126 @@
+package tracingx
+
+import (
+	"context"
+	"sync/atomic"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// DryRunConfig describes candidate filter and sampling rules that are
+// evaluated on every finished span without being applied, so their effect
+// can be checked in production before enforcing them. The outcome is
+// reported by Provider.Diagnostics.
+type DryRunConfig struct {
+	// Enabled evaluates the candidate rules
+	Enabled bool `mapstructure:"enabled" default:"false"`
+
+	// SampleRate is the candidate sampling rate (0.0 to 1.0). Spans are
+	// only evaluated once sampled by the current rate, so only a lower
+	// candidate rate shows an effect.
+	SampleRate float64 `mapstructure:"sample_rate" default:"1.0"`
+
+	// Export is the candidate export filter; compression is not evaluated
+	Export ExportConfig `mapstructure:"export"`
+
+	// Pipeline lists the candidate processing steps, as in Config.Pipeline
+	Pipeline []string `mapstructure:"pipeline"`
+
+	// Processors configures the candidate steps
+	Processors ProcessorsConfig `mapstructure:"processors"`
+}
+
+// DryRunDiagnostics reports the effect the candidate rules of DryRunConfig
+// would have had since the provider was last configured
+type DryRunDiagnostics struct {
+	// SpansEvaluated counts finished spans evaluated against the rules
+	SpansEvaluated uint64 `json:"spans_evaluated"`
+
+	// SpansKept counts spans the rules would have exported
+	SpansKept uint64 `json:"spans_kept"`
+
+	// SpansFiltered counts spans the pipeline steps or export filter would
+	// have dropped
+	SpansFiltered uint64 `json:"spans_filtered"`
+
+	// SpansUnsampled counts spans the sampling rate would have dropped
+	SpansUnsampled uint64 `json:"spans_unsampled"`
+}
+
+// dryRunStats counts the outcomes of dry run evaluations
+type dryRunStats struct {
+	evaluated atomic.Uint64
+	kept      atomic.Uint64
+	filtered  atomic.Uint64
+	unsampled atomic.Uint64
+}
+
+// snapshot returns the current counters
+func (s *dryRunStats) snapshot() *DryRunDiagnostics {
+	return &DryRunDiagnostics{
+		SpansEvaluated: s.evaluated.Load(),
+		SpansKept:      s.kept.Load(),
+		SpansFiltered:  s.filtered.Load(),
+		SpansUnsampled: s.unsampled.Load(),
+	}
+}
+
+// dryRunProcessor evaluates candidate rules on finished spans and counts
+// the outcome, forwarding nothing
+type dryRunProcessor struct {
+	sampler      sdktrace.Sampler
+	steps        []SpanProcessor
+	exportFilter func(s sdktrace.ReadOnlySpan) bool
+	stats        *dryRunStats
+}
+
+// newDryRunProcessor builds the evaluation of the candidate rules of config
+func newDryRunProcessor(config DryRunConfig, stats *dryRunStats) (sdktrace.SpanProcessor, error) {
+	exportFilter, err := newExportFilter(config.Export)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := newPipelineSteps(config.Pipeline, config.Processors)
+	if err != nil {
+		return nil, err
+	}
+	return &dryRunProcessor{
+		sampler:      sdktrace.TraceIDRatioBased(config.SampleRate),
+		steps:        steps,
+		exportFilter: exportFilter,
+		stats:        stats,
+	}, nil
+}
+
+func (p *dryRunProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (p *dryRunProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	// Spans the current rules do not export, e.g. recorded for the audit
+	// trail, are not evaluated
+	if !s.SpanContext().IsSampled() {
+		return
+	}
+	p.stats.evaluated.Add(1)
+	if !traceSampled(p.sampler, s.SpanContext().TraceID()) {
+		p.stats.unsampled.Add(1)
+		return
+	}
+	if len(p.steps) > 0 {
+		// Steps may modify the span, so they get their own copy
+		span := newFinishedSpan(s)
+		for _, step := range p.steps {
+			if !step.Process(span) {
+				p.stats.filtered.Add(1)
+				return
+			}
+		}
+	}
+	if !p.exportFilter(s) {
+		p.stats.filtered.Add(1)
+		return
+	}
+	p.stats.kept.Add(1)
+}
+
+func (p *dryRunProcessor) Shutdown(context.Context) error   { return nil }
+func (p *dryRunProcessor) ForceFlush(context.Context) error { return nil }
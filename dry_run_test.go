@@ -0,0 +1,90 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gostratum/core/logx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestDryRun(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "test-service",
+		SampleRate:  1.0,
+		SpanLog:     SpanLogConfig{Enabled: true, Level: "info"},
+		DryRun: DryRunConfig{
+			Enabled:    true,
+			SampleRate: 1.0,
+			Export:     ExportConfig{ExcludeOperations: []string{"cache.*"}},
+			Pipeline:   []string{"drop_health"},
+		},
+	}, logx.ProvideAdapter(zap.New(core)))
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	for _, name := range []string{"cache.get", "checkout", "cache.set"} {
+		_, span := provider.Start(context.Background(), name)
+		span.End()
+	}
+	_, health := provider.Start(context.Background(), "HTTP GET", WithAttributes(map[string]any{urlPathKey: "/healthz"}))
+	health.End()
+
+	assert.Len(t, logs.FilterMessage("span finished").All(), 4, "candidate rules must not drop spans")
+	assert.Equal(t, &DryRunDiagnostics{
+		SpansEvaluated: 4,
+		SpansKept:      1,
+		SpansFiltered:  3,
+	}, provider.Diagnostics().DryRun)
+}
+
+func TestDryRunSampling(t *testing.T) {
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "test-service",
+		SampleRate:  1.0,
+		DryRun:      DryRunConfig{Enabled: true, SampleRate: 0},
+	}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	_, span := provider.Start(context.Background(), "checkout")
+	span.End()
+
+	diag := provider.Diagnostics()
+	assert.Equal(t, uint64(1), diag.DryRun.SpansUnsampled)
+	assert.Zero(t, diag.DryRun.SpansKept)
+	assert.Equal(t, 1.0, diag.SampleRate)
+}
+
+func TestDryRunSkipsRecordedOnlySpans(t *testing.T) {
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "billing",
+		SampleRate:  0,
+		Audit:       AuditConfig{Enabled: true, Operations: []string{"payment.refund"}},
+		DryRun:      DryRunConfig{Enabled: true, SampleRate: 1.0},
+	}, getTestLogger(), withAuditSink(&memoryAuditSink{}))
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	_, span := provider.Start(context.Background(), "payment.refund")
+	span.End()
+
+	assert.Equal(t, &DryRunDiagnostics{}, provider.Diagnostics().DryRun, "unsampled spans are not exported today")
+}
+
+func TestDryRunDisabled(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+	assert.Nil(t, provider.Diagnostics().DryRun)
+
+	_, err = newOTLPProvider(Config{
+		ServiceName: "test-service",
+		DryRun:      DryRunConfig{Enabled: true, Pipeline: []string{"sample"}},
+	}, getTestLogger())
+	assert.ErrorContains(t, err, "invalid dry run")
+}
@@ -0,0 +1,127 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// Command execution attribute keys recorded by the exec helpers
+const (
+	ExecCommandAttribute     = "process.executable.name"
+	ExecExitCodeAttribute    = "process.exit.code"
+	ExecStdoutBytesAttribute = "process.stdout.bytes"
+	ExecStderrBytesAttribute = "process.stderr.bytes"
+)
+
+// ExecResult is the outcome of a command
+type ExecResult struct {
+	// ExitCode is the exit status of the command, -1 when it did not exit
+	ExitCode int
+
+	// StdoutBytes and StderrBytes are the sizes of its output
+	StdoutBytes int64
+	StderrBytes int64
+}
+
+// TraceExec runs fn, executing command on host, in a client span named
+// "exec <program>" recording the exit code and output sizes fn reports.
+// Only the program name is recorded: arguments often carry secrets. host is
+// empty for local commands. The span is errored when fn fails or the
+// command exits with a non-zero status.
+func TraceExec(ctx context.Context, tracer Tracer, host, command string, fn func(ctx context.Context) (ExecResult, error)) (ExecResult, error) {
+	ctx, span := startExecSpan(ctx, tracer, host, command)
+	defer span.End()
+
+	result, err := fn(ctx)
+	recordExecResult(span, result, err)
+	return result, err
+}
+
+// RunCommand runs cmd like TraceExec, counting the bytes it writes to its
+// stdout and stderr and passing it the trace context through its
+// environment. For commands run over SSH, such as exec.Command("ssh", host,
+// script), pass host so the span records the remote host and the program of
+// script rather than ssh.
+func RunCommand(ctx context.Context, tracer Tracer, host string, cmd *exec.Cmd) error {
+	command := cmd.Path
+	if host != "" && len(cmd.Args) > 0 {
+		command = cmd.Args[len(cmd.Args)-1]
+	}
+	ctx, span := startExecSpan(ctx, tracer, host, command)
+	defer span.End()
+
+	if err := InjectCommand(ctx, tracer, cmd); err != nil {
+		span.SetError(err)
+		return fmt.Errorf("failed to inject trace context: %w", err)
+	}
+	stdout, stderr := &countingWriter{w: cmd.Stdout}, &countingWriter{w: cmd.Stderr}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+
+	runErr := cmd.Run()
+	result := ExecResult{ExitCode: -1, StdoutBytes: stdout.n.Load(), StderrBytes: stderr.n.Load()}
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	err := runErr
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// recorded from the exit code
+		err = nil
+	}
+	recordExecResult(span, result, err)
+	return runErr
+}
+
+// startExecSpan starts a client span for a command
+func startExecSpan(ctx context.Context, tracer Tracer, host, command string) (context.Context, Span) {
+	program := execProgram(command)
+	attrs := map[string]any{ExecCommandAttribute: program}
+	if host != "" {
+		attrs[serverAddressKey] = host
+	}
+	return tracer.Start(ctx, "exec "+program, WithSpanKind(SpanKindClient), WithAttributes(attrs))
+}
+
+// recordExecResult records the outcome of a command on span
+func recordExecResult(span Span, result ExecResult, err error) {
+	span.SetTagInt(ExecExitCodeAttribute, result.ExitCode)
+	span.SetTag(ExecStdoutBytesAttribute, result.StdoutBytes)
+	span.SetTag(ExecStderrBytesAttribute, result.StderrBytes)
+	switch {
+	case err != nil:
+		span.SetError(err)
+	case result.ExitCode != 0:
+		span.SetError(fmt.Errorf("command exited with status %d", result.ExitCode))
+	}
+}
+
+// execProgram returns the base name of the program run by command
+func execProgram(command string) string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return "unknown"
+	}
+	return filepath.Base(fields[0])
+}
+
+// countingWriter counts the bytes written through it to w, which may be nil
+type countingWriter struct {
+	w io.Writer
+	n atomic.Int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.w == nil {
+		c.n.Add(int64(len(p)))
+		return len(p), nil
+	}
+	n, err := c.w.Write(p)
+	c.n.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,115 @@
+package tracingx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestExecHelpers(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("records a remote command", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		result, err := TraceExec(context.Background(), spy, "db-1.internal", "/usr/bin/pg_dump --password=secret", func(ctx context.Context) (ExecResult, error) {
+			return ExecResult{ExitCode: 0, StdoutBytes: 1024, StderrBytes: 12}, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1024), result.StdoutBytes)
+
+		span := spy.started()[0]
+		ro := span.(*otlpSpan).span.(sdktrace.ReadOnlySpan)
+		assert.Equal(t, "exec pg_dump", ro.Name())
+		assert.Equal(t, trace.SpanKindClient, ro.SpanKind())
+
+		attrs := attributesOf(t, span)
+		assert.Equal(t, "pg_dump", attrs[ExecCommandAttribute])
+		assert.Equal(t, "db-1.internal", attrs[serverAddressKey])
+		assert.Equal(t, int64(0), attrs[ExecExitCodeAttribute])
+		assert.Equal(t, int64(1024), attrs[ExecStdoutBytesAttribute])
+		assert.Equal(t, int64(12), attrs[ExecStderrBytesAttribute])
+		assert.NotContains(t, attrs, "error")
+		for _, v := range attrs {
+			assert.NotContains(t, fmt.Sprint(v), "secret")
+		}
+	})
+
+	t.Run("errors on a non-zero exit code", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		_, err := TraceExec(context.Background(), spy, "", "false", func(ctx context.Context) (ExecResult, error) {
+			return ExecResult{ExitCode: 2}, nil
+		})
+		require.NoError(t, err)
+		attrs := attributesOf(t, spy.started()[0])
+		assert.Equal(t, int64(2), attrs[ExecExitCodeAttribute])
+		assert.Equal(t, true, attrs["error"])
+		assert.NotContains(t, attrs, serverAddressKey)
+	})
+
+	t.Run("records errors", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		_, err := TraceExec(context.Background(), spy, "web-1", "uptime", func(ctx context.Context) (ExecResult, error) {
+			return ExecResult{ExitCode: -1}, errors.New("connection refused")
+		})
+		require.Error(t, err)
+		assert.Equal(t, true, attributesOf(t, spy.started()[0])["error"])
+	})
+
+	t.Run("runs a command", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		var stdout bytes.Buffer
+		cmd := exec.Command("sh", "-c", `printf hello; printf "$TRACEPARENT" >&2`)
+		cmd.Stdout = &stdout
+		require.NoError(t, RunCommand(context.Background(), spy, "", cmd))
+		assert.Equal(t, "hello", stdout.String())
+
+		span := spy.started()[0]
+		attrs := attributesOf(t, span)
+		assert.Equal(t, "sh", attrs[ExecCommandAttribute])
+		assert.Equal(t, int64(0), attrs[ExecExitCodeAttribute])
+		assert.Equal(t, int64(5), attrs[ExecStdoutBytesAttribute])
+		assert.Equal(t, int64(55), attrs[ExecStderrBytesAttribute], "the child receives the trace context")
+	})
+
+	t.Run("records the exit code of a failed command", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		cmd := exec.Command("sh", "-c", "exit 3")
+		err := RunCommand(context.Background(), spy, "", cmd)
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, err, &exitErr)
+
+		attrs := attributesOf(t, spy.started()[0])
+		assert.Equal(t, int64(3), attrs[ExecExitCodeAttribute])
+		assert.Equal(t, true, attrs["error"])
+	})
+
+	t.Run("names commands run over ssh after the remote program", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		cmd := exec.Command("sh", "-c", "true", "/opt/provision/bootstrap.sh --token=abc")
+		require.NoError(t, RunCommand(context.Background(), spy, "node-7", cmd))
+
+		ro := spy.started()[0].(*otlpSpan).span.(sdktrace.ReadOnlySpan)
+		assert.Equal(t, "exec bootstrap.sh", ro.Name())
+		assert.Equal(t, "node-7", attributesOf(t, spy.started()[0])[serverAddressKey])
+	})
+
+	t.Run("records commands that fail to start", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		cmd := exec.Command("/nonexistent/tool")
+		require.Error(t, RunCommand(context.Background(), spy, "", cmd))
+
+		attrs := attributesOf(t, spy.started()[0])
+		assert.Equal(t, int64(-1), attrs[ExecExitCodeAttribute])
+		assert.Equal(t, true, attrs["error"])
+	})
+}
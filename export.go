@@ -0,0 +1,76 @@
+package tracingx
+
+import (
+	"fmt"
+	"path"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ExportConfig configures filtering applied to every export pipeline
+type ExportConfig struct {
+	// ExcludeOperations lists span names (path.Match patterns such as
+	// "cache.*") that are dropped before export
+	ExcludeOperations []string `mapstructure:"exclude_operations"`
+
+	// ExcludeKinds lists span kinds (internal, server, client, producer,
+	// consumer) that are dropped before export
+	ExcludeKinds []string `mapstructure:"exclude_kinds"`
+
+	// MinSpanDuration drops leaf spans shorter than the threshold; root spans
+	// and errored spans are always kept (0 disables the filter)
+	MinSpanDuration time.Duration `mapstructure:"min_span_duration"`
+
+	// Compression collapses runs of identical short sibling spans into one
+	Compression CompressionConfig `mapstructure:"compression"`
+}
+
+// newExportFilter builds the predicate deciding which finished spans are exported
+func newExportFilter(config ExportConfig) (func(s sdktrace.ReadOnlySpan) bool, error) {
+	for _, pattern := range config.ExcludeOperations {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude_operations pattern %q: %w", pattern, err)
+		}
+	}
+
+	kinds := make(map[trace.SpanKind]struct{}, len(config.ExcludeKinds))
+	for _, name := range config.ExcludeKinds {
+		kind, err := ParseSpanKind(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exclude_kinds entry: %w", err)
+		}
+		kinds[toOTelSpanKind(kind)] = struct{}{}
+	}
+
+	patterns := config.ExcludeOperations
+	minDuration := config.MinSpanDuration
+	return func(s sdktrace.ReadOnlySpan) bool {
+		if _, ok := kinds[s.SpanKind()]; ok {
+			return false
+		}
+		for _, pattern := range patterns {
+			if matched, _ := path.Match(pattern, s.Name()); matched {
+				return false
+			}
+		}
+		if minDuration > 0 && isShortLeaf(s, minDuration) {
+			return false
+		}
+		return true
+	}, nil
+}
+
+// isShortLeaf reports whether s is a non-root, error-free span without
+// children whose duration is below min
+func isShortLeaf(s sdktrace.ReadOnlySpan, min time.Duration) bool {
+	if s.EndTime().Sub(s.StartTime()) >= min {
+		return false
+	}
+	parent := s.Parent()
+	if !parent.IsValid() || parent.IsRemote() {
+		return false
+	}
+	return s.ChildSpanCount() == 0 && !isErrorSpan(s)
+}
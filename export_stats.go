@@ -0,0 +1,245 @@
+package tracingx
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ExportStats is a snapshot of the span counters of a provider, e.g. for an
+// application's own health page
+type ExportStats struct {
+	// SpansStarted counts spans started
+	SpansStarted uint64 `json:"spans_started"`
+
+	// SpansEnded counts spans ended
+	SpansEnded uint64 `json:"spans_ended"`
+
+	// SpansSampled counts started spans selected for export by sampling
+	SpansSampled uint64 `json:"spans_sampled"`
+
+	// SpansExported counts spans accepted by the exporter
+	SpansExported uint64 `json:"spans_exported"`
+
+	// SpansDropped counts sampled spans that were not exported: dropped
+	// by span processors or the export filter, dropped on a full export
+	// queue, or failed to export
+	SpansDropped uint64 `json:"spans_dropped"`
+
+	// SpansOverflowed counts sampled spans dropped because the export queue
+	// was full, included in SpansDropped
+	SpansOverflowed uint64 `json:"spans_overflowed"`
+
+	// QueueLength estimates the spans waiting in the export queue
+	QueueLength int `json:"queue_length"`
+
+	// LastExportError describes the most recent export failure, if the
+	// most recent export failed
+	LastExportError string `json:"last_export_error,omitempty"`
+}
+
+// exportStats counts spans moving through the main export pipeline
+type exportStats struct {
+	started    atomic.Uint64
+	ended      atomic.Uint64
+	sampled    atomic.Uint64
+	processed  atomic.Uint64 // dropped by span processors
+	filtered   atomic.Uint64
+	enqueued   atomic.Uint64
+	overflowed atomic.Uint64 // dropped on a full export queue
+	exported   atomic.Uint64
+	failed     atomic.Uint64
+
+	mu         sync.Mutex
+	lastExport time.Time
+	lastErr    error
+
+	// onExport, when set, is called after each recorded export or overflow
+	onExport func()
+}
+
+// recordExport records the outcome of an export of n spans
+func (s *exportStats) recordExport(n int, err error, at time.Time) {
+	if err != nil {
+		s.failed.Add(uint64(n))
+	} else {
+		s.exported.Add(uint64(n))
+	}
+	s.mu.Lock()
+	s.lastExport = at
+	s.lastErr = err
+	s.mu.Unlock()
+	if s.onExport != nil {
+		s.onExport()
+	}
+}
+
+// recordOverflow records a span dropped on a full export queue
+func (s *exportStats) recordOverflow() {
+	s.overflowed.Add(1)
+	if s.onExport != nil {
+		s.onExport()
+	}
+}
+
+// filter wraps keep so rejected sampled spans are counted
+func (s *exportStats) filter(keep func(sdktrace.ReadOnlySpan) bool) func(sdktrace.ReadOnlySpan) bool {
+	return func(span sdktrace.ReadOnlySpan) bool {
+		if keep(span) {
+			return true
+		}
+		if span.SpanContext().IsSampled() {
+			s.filtered.Add(1)
+		}
+		return false
+	}
+}
+
+// snapshot returns the counters as ExportStats
+func (s *exportStats) snapshot() ExportStats {
+	s.mu.Lock()
+	lastErr := s.lastErr
+	s.mu.Unlock()
+
+	stats := ExportStats{
+		SpansStarted:    s.started.Load(),
+		SpansEnded:      s.ended.Load(),
+		SpansSampled:    s.sampled.Load(),
+		SpansExported:   s.exported.Load(),
+		SpansDropped:    s.processed.Load() + s.filtered.Load() + s.overflowed.Load() + s.failed.Load(),
+		SpansOverflowed: s.overflowed.Load(),
+		QueueLength:     s.queueDepth(),
+	}
+	if lastErr != nil {
+		stats.LastExportError = lastErr.Error()
+	}
+	return stats
+}
+
+// queueDepth estimates the spans handed to the batcher but not yet exported
+func (s *exportStats) queueDepth() int {
+	done := s.exported.Load() + s.failed.Load() + s.overflowed.Load()
+	enqueued := s.enqueued.Load()
+	if done >= enqueued {
+		return 0
+	}
+	return int(enqueued - done)
+}
+
+// workerDiagnostics reports the stats of a single export worker
+func (s *exportStats) workerDiagnostics() WorkerDiagnostics {
+	s.mu.Lock()
+	lastErr := s.lastErr
+	s.mu.Unlock()
+
+	diag := WorkerDiagnostics{
+		ExporterHealthy: lastErr == nil,
+		QueueDepth:      s.queueDepth(),
+		SpansExported:   s.exported.Load(),
+		SpansFailed:     s.failed.Load(),
+		SpansOverflowed: s.overflowed.Load(),
+	}
+	if lastErr != nil {
+		diag.LastExportError = lastErr.Error()
+	}
+	return diag
+}
+
+// countingExporter records export outcomes in stats, releasing the spans'
+// places in queue when set. Exports run with instrumentation suppressed so
+// the exporter's own calls are not traced.
+type countingExporter struct {
+	next  sdktrace.SpanExporter
+	stats *exportStats
+	clock Clock
+	queue *exportQueue
+}
+
+func (e *countingExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.next.ExportSpans(SuppressInstrumentation(ctx), spans)
+	if e.queue != nil {
+		e.queue.release(len(spans))
+	}
+	e.stats.recordExport(len(spans), err, e.clock.Now())
+	return err
+}
+
+func (e *countingExporter) Shutdown(ctx context.Context) error {
+	return e.next.Shutdown(ctx)
+}
+
+// countingProcessor counts spans handed to next, typically a batcher, in
+// stats and, when set, in total. With a queue, sampled spans ending while it
+// is full are counted as overflowed instead of being handed to next.
+type countingProcessor struct {
+	next  sdktrace.SpanProcessor
+	stats *exportStats
+	total *exportStats
+	queue *exportQueue
+}
+
+func (p *countingProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p *countingProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	// Batchers drop unsampled spans, recorded only for other processors
+	if !s.SpanContext().IsSampled() {
+		p.next.OnEnd(s)
+		return
+	}
+	p.stats.enqueued.Add(1)
+	if p.total != nil {
+		p.total.enqueued.Add(1)
+	}
+	if p.queue != nil && !p.queue.reserve() {
+		p.stats.recordOverflow()
+		if p.total != nil {
+			p.total.recordOverflow()
+		}
+		return
+	}
+	p.next.OnEnd(s)
+}
+
+func (p *countingProcessor) Shutdown(ctx context.Context) error   { return p.next.Shutdown(ctx) }
+func (p *countingProcessor) ForceFlush(ctx context.Context) error { return p.next.ForceFlush(ctx) }
+
+// exportQueue bounds the spans of a worker from the time they are handed to
+// its batcher until their export ends. The SDK batcher silently drops spans
+// ending while its queue is full; keeping at most its queue size in flight,
+// spans over it are counted as overflowed instead.
+type exportQueue struct {
+	capacity int64
+	pending  atomic.Int64
+}
+
+// newExportQueue returns a queue of capacity spans, the SDK default when not
+// positive
+func newExportQueue(capacity int) *exportQueue {
+	if capacity <= 0 {
+		capacity = sdktrace.DefaultMaxQueueSize
+	}
+	return &exportQueue{capacity: int64(capacity)}
+}
+
+// reserve takes a place for one span, reporting false when the queue is full
+func (q *exportQueue) reserve() bool {
+	for {
+		n := q.pending.Load()
+		if n >= q.capacity {
+			return false
+		}
+		if q.pending.CompareAndSwap(n, n+1) {
+			return true
+		}
+	}
+}
+
+// release frees the places of n spans whose export ended
+func (q *exportQueue) release(n int) {
+	q.pending.Add(-int64(n))
+}
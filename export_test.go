@@ -0,0 +1,105 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestExportFilter(t *testing.T) {
+	filter, err := newExportFilter(ExportConfig{
+		ExcludeOperations: []string{"cache.*", "mutex.wait"},
+		ExcludeKinds:      []string{"Producer"},
+	})
+	require.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(newFilterProcessor(recorder, filter)))
+	defer tp.Shutdown(context.Background())
+	tracer := tp.Tracer("test")
+
+	for _, span := range []struct {
+		name string
+		kind trace.SpanKind
+	}{
+		{"cache.get", trace.SpanKindClient},
+		{"mutex.wait", trace.SpanKindInternal},
+		{"publish", trace.SpanKindProducer},
+		{"GET /orders", trace.SpanKindServer},
+		{"cachemiss", trace.SpanKindInternal},
+	} {
+		_, s := tracer.Start(context.Background(), span.name, trace.WithSpanKind(span.kind))
+		s.End()
+	}
+
+	var names []string
+	for _, s := range recorder.Ended() {
+		names = append(names, s.Name())
+	}
+	assert.Equal(t, []string{"GET /orders", "cachemiss"}, names)
+}
+
+func TestExportFilterValidation(t *testing.T) {
+	t.Run("rejects unknown kinds", func(t *testing.T) {
+		_, err := newExportFilter(ExportConfig{ExcludeKinds: []string{"sideways"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects malformed patterns", func(t *testing.T) {
+		_, err := newExportFilter(ExportConfig{ExcludeOperations: []string{"cache.["}})
+		assert.Error(t, err)
+	})
+
+	t.Run("keeps everything by default", func(t *testing.T) {
+		filter, err := newExportFilter(ExportConfig{})
+		require.NoError(t, err)
+		assert.NotNil(t, filter)
+	})
+}
+
+func TestExportFilterMinSpanDuration(t *testing.T) {
+	filter, err := newExportFilter(ExportConfig{MinSpanDuration: time.Millisecond})
+	require.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(newFilterProcessor(recorder, filter)))
+	defer tp.Shutdown(context.Background())
+	tracer := tp.Tracer("test")
+
+	start := time.Now()
+	short := trace.WithTimestamp(start.Add(500 * time.Microsecond))
+	end := trace.WithTimestamp(start.Add(time.Millisecond).Add(500 * time.Microsecond))
+
+	// root is short but always kept
+	rootCtx, root := tracer.Start(context.Background(), "root", trace.WithTimestamp(start))
+
+	// parent is short but has a child
+	parentCtx, parent := tracer.Start(rootCtx, "parent", trace.WithTimestamp(start))
+	_, grandchild := tracer.Start(parentCtx, "short-leaf", trace.WithTimestamp(start))
+	grandchild.End(short)
+	parent.End(short)
+
+	// errored short leaf is kept
+	_, failed := tracer.Start(rootCtx, "failed-leaf", trace.WithTimestamp(start))
+	failed.SetStatus(codes.Error, "boom")
+	failed.End(short)
+
+	// long leaf is kept
+	_, long := tracer.Start(rootCtx, "long-leaf", trace.WithTimestamp(start))
+	long.End(end)
+
+	root.End(short)
+
+	var names []string
+	for _, s := range recorder.Ended() {
+		names = append(names, s.Name())
+	}
+	assert.ElementsMatch(t, []string{"parent", "failed-leaf", "long-leaf", "root"}, names)
+}
@@ -0,0 +1,61 @@
+package tracingx
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogxFields converts logx fields to Fields, so the fields built for a log
+// line can be recorded on a span as they are:
+//
+//	fields := []logx.Field{logx.String("order.id", id), logx.Err(err)}
+//	logger.Error("payment failed", fields...)
+//	span.LogFields(tracingx.LogxFields(fields...)...)
+//
+// Skipped logx fields are dropped.
+func LogxFields(fields ...logx.Field) []Field {
+	out := make([]Field, 0, len(fields))
+	for _, f := range fields {
+		if f.Type == zapcore.SkipType {
+			continue
+		}
+		out = append(out, LogxField(f))
+	}
+	return out
+}
+
+// LogxField converts a logx field to a Field, keeping its value typed
+func LogxField(f logx.Field) Field {
+	switch f.Type {
+	case zapcore.StringType:
+		return String(f.Key, f.String)
+	case zapcore.BoolType:
+		return Bool(f.Key, f.Integer == 1)
+	case zapcore.Int64Type, zapcore.Int32Type, zapcore.Int16Type, zapcore.Int8Type:
+		return Int64(f.Key, f.Integer)
+	case zapcore.Uint32Type, zapcore.Uint16Type, zapcore.Uint8Type:
+		return Int64(f.Key, f.Integer)
+	case zapcore.Float64Type:
+		return Float64(f.Key, math.Float64frombits(uint64(f.Integer)))
+	case zapcore.Float32Type:
+		return Float64(f.Key, float64(math.Float32frombits(uint32(f.Integer))))
+	case zapcore.DurationType:
+		return Duration(f.Key, time.Duration(f.Integer))
+	case zapcore.ErrorType:
+		if err, ok := f.Interface.(error); ok {
+			return String(f.Key, err.Error())
+		}
+	case zapcore.StringerType:
+		if s, ok := f.Interface.(fmt.Stringer); ok {
+			return String(f.Key, s.String())
+		}
+	}
+	// Other types, e.g. objects and arrays, as zap encodes them
+	enc := zapcore.NewMapObjectEncoder()
+	f.AddTo(enc)
+	return Field{Key: f.Key, Value: enc.Fields[f.Key]}
+}
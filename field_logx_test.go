@@ -0,0 +1,45 @@
+package tracingx
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.uber.org/zap"
+)
+
+func TestLogxFields(t *testing.T) {
+	fields := LogxFields(
+		logx.String("order.id", "ord-1"),
+		logx.Int("attempt", 2),
+		logx.Bool("retry", true),
+		logx.Float64("ratio", 0.25),
+		zap.Float32("score", 0.5),
+		zap.Uint8("priority", 3),
+		zap.Duration("elapsed", 1500*time.Millisecond),
+		logx.Err(errors.New("declined")),
+		logx.Err(nil),
+		zap.Stringer("kind", SpanKindServer),
+		zap.Strings("tags", []string{"a", "b"}),
+	)
+
+	attrs := make([]attribute.KeyValue, len(fields))
+	for i, f := range fields {
+		attrs[i] = f.attribute()
+	}
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("order.id", "ord-1"),
+		attribute.Int64("attempt", 2),
+		attribute.Bool("retry", true),
+		attribute.Float64("ratio", 0.25),
+		attribute.Float64("score", 0.5),
+		attribute.Int64("priority", 3),
+		attribute.String("elapsed", "1.5s"),
+		attribute.String("error", "declined"),
+		attribute.String("kind", "server"),
+		attribute.String("tags", "[a b]"),
+	}, attrs)
+}
@@ -0,0 +1,64 @@
+package tracingx_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gostratum/core/logx"
+	"github.com/gostratum/tracingx"
+	"github.com/gostratum/tracingx/tracingxtest"
+)
+
+// FuzzExtract feeds hostile traceparent, tracestate and baggage headers to
+// Extract and checks that whatever is accepted propagates as valid context
+//
+//	go test -fuzz FuzzExtract
+func FuzzExtract(f *testing.F) {
+	tracingxtest.AddTraceContextCorpus(f)
+
+	collector := tracingxtest.StartCollector(f)
+	result, err := tracingx.NewTracer(tracingx.Params{
+		Config: tracingx.Config{
+			Enabled:     true,
+			Provider:    "otlp",
+			ServiceName: "fuzz",
+			SampleRate:  1.0,
+			Baggage:     tracingx.BaggageConfig{MaxBytes: 8192, InboundMaxBytes: 8192},
+			OTLP:        tracingx.OTLPConfig{Endpoint: collector.Endpoint(), Insecure: true},
+		},
+		Logger: logx.NewNoopLogger(),
+	})
+	if err != nil {
+		f.Fatal(err)
+	}
+	provider := result.Provider
+	f.Cleanup(func() { provider.Shutdown(context.Background()) })
+
+	f.Fuzz(func(t *testing.T, traceParent, traceState, baggage string) {
+		headers := tracingxtest.TraceContextHeaders(traceParent, traceState, baggage)
+		ctx, err := provider.Extract(context.Background(), tracingx.HeaderCarrier(headers))
+		if err != nil {
+			t.Fatalf("Extract failed on a header carrier: %v", err)
+		}
+
+		ctx, span := provider.Start(ctx, "fuzz")
+		defer span.End()
+		if err := tracingx.ValidateTraceParent(tracingx.FormatTraceParent(span)); err != nil {
+			t.Fatalf("span has invalid trace context: %v", err)
+		}
+		if remaining, ok := tracingx.RemainingBudget(ctx); ok && remaining < 0 {
+			t.Fatalf("negative timeout budget %s", remaining)
+		}
+
+		out := map[string]string{}
+		if err := provider.Inject(ctx, tracingx.MapCarrier(out)); err != nil {
+			t.Fatalf("Inject failed on a map carrier: %v", err)
+		}
+		if err := tracingx.ValidateTraceParent(out["traceparent"]); err != nil {
+			t.Fatalf("injected invalid traceparent %q: %v", out["traceparent"], err)
+		}
+		if n := len(out["baggage"]); n > 8192 {
+			t.Fatalf("injected %d bytes of baggage, limit 8192", n)
+		}
+	})
+}
@@ -0,0 +1,199 @@
+package tracingx
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// StatsHandlerOption configures the gRPC stats handlers
+type StatsHandlerOption func(*statsHandlerConfig)
+
+type statsHandlerConfig struct {
+	noMessageEvents   bool
+	networkAttributes bool
+}
+
+// WithoutMessageEvents stops the stats handlers from logging an event per
+// message sent and received, e.g. for long-lived streams
+func WithoutMessageEvents() StatsHandlerOption {
+	return func(c *statsHandlerConfig) {
+		c.noMessageEvents = true
+	}
+}
+
+// WithRPCNetworkAttributes records the connection of each RPC's peer:
+// network.peer.address, network.peer.port, network.transport and
+// network.type, formerly net.peer.ip, net.peer.port and net.transport
+func WithRPCNetworkAttributes() StatsHandlerOption {
+	return func(c *statsHandlerConfig) {
+		c.networkAttributes = true
+	}
+}
+
+// NewServerStatsHandler returns a gRPC stats.Handler running each incoming
+// RPC in a server span parented to the caller's trace context, for use with
+// grpc.StatsHandler. Unlike interceptors it sees every RPC regardless of the
+// interceptor chains a framework installs, and logs an event per message
+// sent and received. Server spans are marked as errored for the status codes
+// that indicate a server fault, such as Internal and Unavailable.
+func NewServerStatsHandler(tracer Tracer, opts ...StatsHandlerOption) stats.Handler {
+	return &statsHandler{tracer: tracer, config: newStatsHandlerConfig(opts), server: true}
+}
+
+// NewClientStatsHandler returns a gRPC stats.Handler running each outgoing
+// RPC in a client span and propagating its trace context in the request
+// metadata, for use with grpc.WithStatsHandler. Client spans are marked as
+// errored for any status other than OK.
+func NewClientStatsHandler(tracer Tracer, opts ...StatsHandlerOption) stats.Handler {
+	return &statsHandler{tracer: tracer, config: newStatsHandlerConfig(opts)}
+}
+
+func newStatsHandlerConfig(opts []StatsHandlerOption) statsHandlerConfig {
+	var config statsHandlerConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// statsHandler implements stats.Handler for either side of an RPC
+type statsHandler struct {
+	tracer Tracer
+	config statsHandlerConfig
+	server bool
+}
+
+// rpcState is the span of an RPC, carried in the context passed to HandleRPC
+type rpcState struct {
+	span     Span
+	ctx      context.Context
+	sent     atomic.Int64
+	received atomic.Int64
+}
+
+type rpcStateKey struct{}
+
+func (h *statsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	if IsInstrumentationSuppressed(ctx) {
+		return ctx
+	}
+	name, service, method := splitFullMethod(info.FullMethodName)
+	attrs := WithAttributes(map[string]any{
+		rpcSystemKey:  rpcSystemGRPC,
+		rpcServiceKey: service,
+		rpcMethodKey:  method,
+	})
+
+	var span Span
+	if h.server {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+			ctx = ContextWithPeerAddress(ctx, p.Addr.String())
+		}
+		ctx, span = StartFromCarrier(ctx, h.tracer, MetadataCarrier(md), name, attrs)
+	} else {
+		ctx, span = h.tracer.Start(ctx, name, WithSpanKind(SpanKindClient), attrs)
+		md, _ := metadata.FromOutgoingContext(ctx)
+		md = md.Copy()
+		if err := h.tracer.Inject(ctx, MetadataCarrier(md)); err != nil {
+			span.LogFields(
+				String("event", "inject_failed"),
+				Err(err),
+			)
+		}
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+	RecordBudget(ctx, span)
+	return context.WithValue(ctx, rpcStateKey{}, &rpcState{span: span, ctx: ctx})
+}
+
+func (h *statsHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	state, _ := ctx.Value(rpcStateKey{}).(*rpcState)
+	if state == nil {
+		return
+	}
+	switch rs := rs.(type) {
+	case *stats.InHeader:
+		// The server learns its peer from the request headers
+		if h.server && h.config.networkAttributes {
+			recordNetworkPeerAddr(state.span, rs.RemoteAddr)
+		}
+	case *stats.OutHeader:
+		// The client from the transport the request headers are sent on
+		if !h.server && h.config.networkAttributes {
+			recordNetworkPeerAddr(state.span, rs.RemoteAddr)
+		}
+	case *stats.InPayload:
+		if !h.config.noMessageEvents {
+			h.logMessage(state.span, "RECEIVED", state.received.Add(1), rs.Length, rs.CompressedLength)
+		}
+	case *stats.OutPayload:
+		if !h.config.noMessageEvents {
+			h.logMessage(state.span, "SENT", state.sent.Add(1), rs.Length, rs.CompressedLength)
+		}
+	case *stats.End:
+		h.end(state, rs)
+	}
+}
+
+// logMessage logs a message event with its sequence number and sizes
+func (h *statsHandler) logMessage(span Span, direction string, id int64, size, compressed int) {
+	span.LogFields(
+		String("event", "message"),
+		String(rpcMessageTypeKey, direction),
+		Int64(rpcMessageIDKey, id),
+		Int(rpcMessageUncompressedSizeKey, size),
+		Int(rpcMessageCompressedSizeKey, compressed),
+	)
+}
+
+// end records the RPC's status and ends its span
+func (h *statsHandler) end(state *rpcState, rs *stats.End) {
+	span := state.span
+	code := status.Code(rs.Error)
+	span.SetTagInt(rpcGRPCStatusCodeKey, int(code))
+	if h.server && (code == codes.Canceled || code == codes.DeadlineExceeded) {
+		// The stream context is canceled once the RPC is done, so only RPCs
+		// that failed because of it are attributed to cancellation
+		RecordCancellation(state.ctx, span)
+	}
+	if rs.Error != nil && (!h.server || grpcServerFault(code)) {
+		span.SetError(rs.Error)
+	}
+	span.End()
+}
+
+func (h *statsHandler) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h *statsHandler) HandleConn(context.Context, stats.ConnStats) {}
+
+// grpcServerFault reports whether a status code returned by a server
+// indicates a server fault rather than a caller error
+func grpcServerFault(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
+
+// splitFullMethod splits a full RPC method name such as /pkg.Service/Method
+// into the span name pkg.Service/Method and its service and method parts
+func splitFullMethod(fullMethod string) (name, service, method string) {
+	name = strings.TrimPrefix(fullMethod, "/")
+	service, method, ok := strings.Cut(name, "/")
+	if !ok {
+		return name, "", name
+	}
+	return name, service, method
+}
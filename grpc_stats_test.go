@@ -0,0 +1,152 @@
+package tracingx
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatsHandlers(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	// call serves svc with the server handler and calls Check through the
+	// client handler, returning the ended client and server spans
+	call := func(t *testing.T, svc healthpb.HealthServer, service string, opts ...StatsHandlerOption) (client, server Span, err error) {
+		serverTracer, clientTracer := &spyTracer{Tracer: provider}, &spyTracer{Tracer: provider}
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		srv := grpc.NewServer(grpc.StatsHandler(NewServerStatsHandler(serverTracer, opts...)))
+		healthpb.RegisterHealthServer(srv, svc)
+		go func() { _ = srv.Serve(lis) }()
+		defer srv.Stop()
+
+		conn, err := grpc.NewClient(lis.Addr().String(),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithStatsHandler(NewClientStatsHandler(clientTracer, opts...)),
+		)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+		require.Len(t, clientTracer.started(), 1)
+		require.Eventually(t, func() bool {
+			spans := serverTracer.started()
+			return len(spans) == 1 && spans[0].(*otlpSpan).ended()
+		}, 5*time.Second, 5*time.Millisecond)
+		return clientTracer.started()[0], serverTracer.started()[0], err
+	}
+	readOnly := func(span Span) sdktrace.ReadOnlySpan {
+		return span.(*otlpSpan).span.(sdktrace.ReadOnlySpan)
+	}
+
+	t.Run("traces both sides of a call", func(t *testing.T) {
+		client, server, err := call(t, health.NewServer(), "")
+		require.NoError(t, err)
+
+		assert.Equal(t, "grpc.health.v1.Health/Check", readOnly(client).Name())
+		assert.Equal(t, trace.SpanKindClient, readOnly(client).SpanKind())
+		assert.Equal(t, trace.SpanKindServer, readOnly(server).SpanKind())
+		assert.Equal(t, client.TraceID(), server.TraceID())
+		assert.Equal(t, client.SpanID(), server.ParentSpanID())
+
+		for _, span := range []Span{client, server} {
+			attrs := attributesOf(t, span)
+			assert.Equal(t, "grpc", attrs[rpcSystemKey])
+			assert.Equal(t, "grpc.health.v1.Health", attrs[rpcServiceKey])
+			assert.Equal(t, "Check", attrs[rpcMethodKey])
+			assert.Equal(t, int64(codes.OK), attrs[rpcGRPCStatusCodeKey])
+			assert.NotContains(t, attrs, "error")
+			assert.NotContains(t, attrs, CancellationAttribute)
+
+			events := readOnly(span).Events()
+			require.Len(t, events, 2)
+			types := map[string]bool{}
+			for _, e := range events {
+				for _, kv := range e.Attributes {
+					if string(kv.Key) == rpcMessageTypeKey {
+						types[kv.Value.AsString()] = true
+					}
+				}
+			}
+			assert.Equal(t, map[string]bool{"SENT": true, "RECEIVED": true}, types)
+		}
+	})
+
+	t.Run("marks server faults on both sides", func(t *testing.T) {
+		client, server, err := call(t, healthpb.UnimplementedHealthServer{}, "")
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+		assert.Equal(t, true, attributesOf(t, client)["error"])
+		assert.Equal(t, true, attributesOf(t, server)["error"])
+		assert.Equal(t, int64(codes.Unimplemented), attributesOf(t, server)[rpcGRPCStatusCodeKey])
+	})
+
+	t.Run("marks caller errors on the client only", func(t *testing.T) {
+		client, server, err := call(t, health.NewServer(), "unknown.Service")
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Equal(t, true, attributesOf(t, client)["error"])
+		assert.NotContains(t, attributesOf(t, server), "error")
+	})
+
+	t.Run("records server timeouts", func(t *testing.T) {
+		_, server, err := call(t, blockingHealthServer{}, "")
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+		assert.Equal(t, CancellationTimeout, attributesOf(t, server)[CancellationAttribute])
+	})
+
+	t.Run("message events can be disabled", func(t *testing.T) {
+		client, server, err := call(t, health.NewServer(), "", WithoutMessageEvents())
+		require.NoError(t, err)
+		assert.Empty(t, readOnly(client).Events())
+		assert.Empty(t, readOnly(server).Events())
+	})
+
+	t.Run("records network attributes when enabled", func(t *testing.T) {
+		client, server, err := call(t, health.NewServer(), "")
+		require.NoError(t, err)
+		assert.NotContains(t, attributesOf(t, client), networkPeerAddressKey)
+
+		client, server, err = call(t, health.NewServer(), "", WithRPCNetworkAttributes())
+		require.NoError(t, err)
+		for _, span := range []Span{client, server} {
+			attrs := attributesOf(t, span)
+			assert.Equal(t, "127.0.0.1", attrs[networkPeerAddressKey])
+			assert.Equal(t, "tcp", attrs[networkTransportKey])
+			assert.Equal(t, "ipv4", attrs[networkTypeKey])
+			assert.NotZero(t, attrs[networkPeerPortKey])
+		}
+	})
+}
+
+// blockingHealthServer answers health checks once the caller gives up
+type blockingHealthServer struct {
+	healthpb.UnimplementedHealthServer
+}
+
+func (blockingHealthServer) Check(ctx context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	<-ctx.Done()
+	return nil, status.FromContextError(ctx.Err()).Err()
+}
+
+func TestSplitFullMethod(t *testing.T) {
+	name, service, method := splitFullMethod("/pkg.Service/Method")
+	assert.Equal(t, []string{"pkg.Service/Method", "pkg.Service", "Method"}, []string{name, service, method})
+
+	name, service, method = splitFullMethod("Method")
+	assert.Equal(t, []string{"Method", "", "Method"}, []string{name, service, method})
+}
@@ -0,0 +1,127 @@
+package tracingx
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// TraceResponseHeader is the draft W3C response header echoing the trace
+// context of the server span back to the caller
+const TraceResponseHeader = "traceresponse"
+
+// MiddlewareOption configures the tracing HTTP middleware
+type MiddlewareOption func(*middlewareConfig)
+
+// middlewareConfig contains configuration for the tracing HTTP middleware
+type middlewareConfig struct {
+	traceResponse       bool
+	serverTimingContext bool
+	networkAttributes   bool
+	browserOrigins      []string
+	routeSampling       []RouteSamplingHint
+}
+
+// WithTraceResponse emits the draft `traceresponse` header on responses so
+// upstream gateways can correlate their measurements to the server span
+func WithTraceResponse() MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.traceResponse = true
+	}
+}
+
+// WithServerTimingTraceParent adds a `traceparent` metric to the
+// Server-Timing response header, which browsers expose to frontend code via
+// the Resource Timing API
+func WithServerTimingTraceParent() MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.serverTimingContext = true
+	}
+}
+
+// WithServerNetworkAttributes records the client connection on server spans:
+// network.peer.address, network.peer.port, network.transport and network.type,
+// formerly net.peer.ip, net.peer.port and net.transport. Behind a proxy or
+// load balancer the peer is the proxy.
+func WithServerNetworkAttributes() MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.networkAttributes = true
+	}
+}
+
+// NewMiddleware returns HTTP middleware that extracts the incoming trace
+// context and runs each request in a server span, recording the response
+// status and body size. Responses with a 5xx status mark the span as
+// errored; requests whose context ends early are annotated with
+// CancellationAttribute. The wrapped ResponseWriter keeps the http.Flusher,
+// http.Hijacker, io.ReaderFrom and http.Pusher implementations of the
+// original, so streaming responses and websockets work unchanged. Requests
+// whose context is marked by SuppressInstrumentation pass through untraced.
+func NewMiddleware(tracer Tracer, opts ...MiddlewareOption) func(http.Handler) http.Handler {
+	var config middlewareConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if IsInstrumentationSuppressed(r.Context()) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			attrs := map[string]any{
+				httpRequestMethodKey: httpMethods.get(r.Method),
+				urlPathKey:           r.URL.Path,
+				serverAddressKey:     r.Host,
+			}
+			ctx := ContextWithPeerAddress(r.Context(), r.RemoteAddr)
+			if priority := config.routePriority(r.URL.Path); priority != 0 {
+				attrs[SamplingPriorityAttribute] = priority
+				ctx = contextWithSamplingPriority(ctx, priority)
+			}
+			ctx, span := StartFromCarrier(ctx, tracer, HeaderCarrier(r.Header), httpSpanNames.get(r.Method),
+				WithAttributes(attrs),
+			)
+			defer span.End()
+			RecordBudget(ctx, span)
+			if config.networkAttributes {
+				recordNetworkPeer(span, requestNetwork(r), r.RemoteAddr)
+			}
+
+			// Response headers must be set before the handler writes the status
+			config.writeBrowserHeaders(w, r)
+			if traceParent := FormatTraceParent(span); traceParent != "" {
+				if config.traceResponse {
+					w.Header().Set(TraceResponseHeader, traceParent)
+				}
+				if config.serverTimingContext {
+					w.Header().Add(ServerTimingHeader, fmt.Sprintf("traceparent;desc=%q", traceParent))
+				}
+			}
+
+			rw := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(rw.wrap(), r.WithContext(ctx))
+			RecordCancellation(ctx, span)
+
+			if rw.hijacked && !rw.wroteHeader {
+				// The handler took over the connection (e.g. a websocket)
+				span.SetTag(httpConnectionHijackedKey, true)
+				return
+			}
+			span.SetTag(httpResponseStatusCodeKey, rw.status)
+			span.SetTag(httpResponseBodySizeKey, rw.size)
+			if rw.status >= http.StatusInternalServerError {
+				span.SetError(fmt.Errorf("HTTP %d", rw.status))
+			}
+		})
+	}
+}
+
+// requestNetwork returns the network r was received over, tcp unless the
+// server reports otherwise, e.g. for a unix socket listener
+func requestNetwork(r *http.Request) string {
+	if addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok {
+		return addr.Network()
+	}
+	return "tcp"
+}
@@ -0,0 +1,94 @@
+package tracingx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMiddleware(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	serve := func(mw func(http.Handler) http.Handler, req *http.Request, handler http.HandlerFunc) (*httptest.ResponseRecorder, Span) {
+		var span Span
+		rec := httptest.NewRecorder()
+		mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			span = SpanFromContext(r.Context())
+			handler(w, r)
+		})).ServeHTTP(rec, req)
+		return rec, span
+	}
+	ok := func(w http.ResponseWriter, r *http.Request) { _, _ = w.Write([]byte("ok")) }
+
+	t.Run("runs request in server span parented to caller", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.Header.Set("traceparent", validTraceParent)
+
+		_, span := serve(NewMiddleware(provider), req, ok)
+		require.NotNil(t, span)
+
+		ro := span.(*otlpSpan).span.(sdktrace.ReadOnlySpan)
+		assert.Equal(t, "HTTP POST", ro.Name())
+		assert.Equal(t, trace.SpanKindServer, ro.SpanKind())
+		assert.True(t, span.IsRemoteParent())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.TraceID())
+
+		attrs := attributesOf(t, span)
+		assert.Equal(t, "POST", attrs[httpRequestMethodKey])
+		assert.Equal(t, "/orders", attrs[urlPathKey])
+		assert.Equal(t, int64(http.StatusOK), attrs[httpResponseStatusCodeKey])
+	})
+
+	t.Run("does not emit trace headers by default", func(t *testing.T) {
+		rec, _ := serve(NewMiddleware(provider), httptest.NewRequest(http.MethodGet, "/", nil), ok)
+		assert.Empty(t, rec.Header().Get(TraceResponseHeader))
+		assert.Empty(t, rec.Header().Get(ServerTimingHeader))
+	})
+
+	t.Run("emits traceresponse header", func(t *testing.T) {
+		rec, span := serve(NewMiddleware(provider, WithTraceResponse()), httptest.NewRequest(http.MethodGet, "/", nil), ok)
+		assert.Equal(t, FormatTraceParent(span), rec.Header().Get(TraceResponseHeader))
+	})
+
+	t.Run("emits Server-Timing traceparent", func(t *testing.T) {
+		rec, span := serve(NewMiddleware(provider, WithServerTimingTraceParent()), httptest.NewRequest(http.MethodGet, "/", nil), ok)
+
+		metrics := ParseServerTiming(rec.Header().Values(ServerTimingHeader)...)
+		require.Len(t, metrics, 1)
+		assert.Equal(t, "traceparent", metrics[0].Name)
+		assert.Equal(t, FormatTraceParent(span), metrics[0].Description)
+	})
+
+	t.Run("records network attributes when enabled", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = "[2001:db8::7]:51234"
+
+		_, span := serve(NewMiddleware(provider), req, ok)
+		assert.NotContains(t, attributesOf(t, span), networkPeerAddressKey)
+
+		_, span = serve(NewMiddleware(provider, WithServerNetworkAttributes()), req, ok)
+		attrs := attributesOf(t, span)
+		assert.Equal(t, "2001:db8::7", attrs[networkPeerAddressKey])
+		assert.Equal(t, int64(51234), attrs[networkPeerPortKey])
+		assert.Equal(t, "tcp", attrs[networkTransportKey])
+		assert.Equal(t, "ipv6", attrs[networkTypeKey])
+	})
+
+	t.Run("records status and marks server errors", func(t *testing.T) {
+		_, span := serve(NewMiddleware(provider), httptest.NewRequest(http.MethodGet, "/", nil), func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		attrs := attributesOf(t, span)
+		assert.Equal(t, int64(http.StatusServiceUnavailable), attrs[httpResponseStatusCodeKey])
+		assert.Equal(t, true, attrs["error"])
+	})
+}
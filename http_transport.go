@@ -0,0 +1,227 @@
+package tracingx
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptrace"
+	"strconv"
+	"time"
+)
+
+// TransportOption configures the tracing HTTP transport
+type TransportOption func(*transportConfig)
+
+// transportConfig contains configuration for the tracing HTTP transport
+type transportConfig struct {
+	serverTimingSpans bool
+	strictURLs        bool
+	forceSample       bool
+	networkAttributes bool
+	allowlist         map[string]bool
+}
+
+// WithServerTimingSpans records Server-Timing metrics reported by the
+// downstream service as child spans of the client span instead of events.
+// Child spans end when the response headers arrive and are backdated by the
+// reported duration.
+func WithServerTimingSpans() TransportOption {
+	return func(c *transportConfig) {
+		c.serverTimingSpans = true
+	}
+}
+
+// WithStrictURLs records request URLs without their query, fragment and
+// credentials, and with path segments that look like identifiers, such as
+// /v1/charges/ch_3N1x, replaced by {id}
+func WithStrictURLs() TransportOption {
+	return func(c *transportConfig) {
+		c.strictURLs = true
+	}
+}
+
+// WithForceSampling samples every request whatever the sample rate
+func WithForceSampling() TransportOption {
+	return func(c *transportConfig) {
+		c.forceSample = true
+	}
+}
+
+// WithClientNetworkAttributes records the connection each request is sent
+// over on client spans: network.peer.address, network.peer.port,
+// network.transport and network.type, formerly net.peer.ip, net.peer.port and
+// net.transport. With a proxy configured the peer is the proxy.
+func WithClientNetworkAttributes() TransportOption {
+	return func(c *transportConfig) {
+		c.networkAttributes = true
+	}
+}
+
+// WithAttributeAllowlist records only the listed attributes on client spans,
+// including those added by the provider such as attribute profiles,
+// peer.service and deployment attributes, and only the listed fields in
+// their events. Errors keep their type; their message and stack trace are
+// recorded only when exception.message and exception.stacktrace are listed.
+// Calling it again extends the list.
+func WithAttributeAllowlist(keys ...string) TransportOption {
+	return func(c *transportConfig) {
+		if c.allowlist == nil {
+			c.allowlist = make(map[string]bool, len(keys))
+		}
+		for _, key := range keys {
+			c.allowlist[key] = true
+		}
+	}
+}
+
+// transport is an http.RoundTripper that traces outgoing requests
+type transport struct {
+	tracer Tracer
+	base   http.RoundTripper
+	config transportConfig
+}
+
+// NewTransport wraps base (http.DefaultTransport if nil) so every request
+// runs in a client span, carries the trace context in its headers, and
+// records the response size, content encoding, cache status headers,
+// redirect chain and Server-Timing metrics reported in the response. The
+// span ends when the response headers are received. Requests whose context
+// was created by ContextWithRetry are annotated as retry attempts. Requests
+// whose context is marked by SuppressInstrumentation pass through untraced.
+func NewTransport(tracer Tracer, base http.RoundTripper, opts ...TransportOption) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &transport{tracer: tracer, base: base}
+	for _, opt := range opts {
+		opt(&t.config)
+	}
+	return t
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if IsInstrumentationSuppressed(req.Context()) {
+		return t.base.RoundTrip(req)
+	}
+	attrs := map[string]any{
+		httpRequestMethodKey: req.Method,
+		urlFullKey:           t.config.url(req.URL),
+		serverAddressKey:     req.URL.Hostname(),
+	}
+	if port, err := strconv.Atoi(req.URL.Port()); err == nil {
+		attrs[serverPortKey] = port
+	}
+	opts := []SpanOption{
+		WithSpanKind(SpanKindClient),
+		WithAttributes(attrs),
+	}
+	if t.config.allowlist != nil {
+		opts = append(opts, withAttributeAllowlist(t.config.allowlist))
+	}
+	if t.config.forceSample {
+		opts = append(opts, WithForceSample())
+	}
+	ctx, span := StartRetryAttempt(req.Context(), t.tracer, httpSpanNames.get(req.Method), opts...)
+	defer span.End()
+	span = t.config.wrap(span)
+	// Tags added through the request context by the base transport are
+	// restricted too
+	ctx = ContextWithSpan(ctx, span)
+	RecordBudget(ctx, span)
+
+	if t.config.networkAttributes {
+		ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+			GotConn: func(info httptrace.GotConnInfo) {
+				recordNetworkPeerAddr(span, info.Conn.RemoteAddr())
+			},
+		})
+	}
+	req = req.Clone(ctx)
+	if err := t.tracer.Inject(ctx, HeaderCarrier(req.Header)); err != nil {
+		span.LogFields(
+			String("event", "inject_failed"),
+			Err(err),
+		)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.SetError(t.config.sanitizeError(err))
+		return nil, err
+	}
+
+	span.SetTag(httpResponseStatusCodeKey, resp.StatusCode)
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetError(fmt.Errorf("HTTP %d", resp.StatusCode))
+	}
+	recordResponse(span, resp)
+	t.recordRedirects(span, req)
+	t.recordServerTiming(span, resp.Header.Values(ServerTimingHeader))
+	return resp, nil
+}
+
+// cacheHeaders are CDN and proxy cache status headers recorded on client spans
+var cacheHeaders = []string{"Age", "Cache-Status", "CF-Cache-Status", "X-Cache"}
+
+// recordResponse records the response size, content encoding and cache
+// status headers on span
+func recordResponse(span Span, resp *http.Response) {
+	if resp.ContentLength >= 0 {
+		span.SetTag(httpResponseBodySizeKey, resp.ContentLength)
+	}
+	switch {
+	case resp.Uncompressed:
+		// Negotiated and decoded by net/http, which removes the header
+		span.SetTag(httpResponseHeaderKey("Content-Encoding"), "gzip")
+	case resp.Header.Get("Content-Encoding") != "":
+		span.SetTag(httpResponseHeaderKey("Content-Encoding"), resp.Header.Get("Content-Encoding"))
+	}
+	for _, name := range cacheHeaders {
+		if value := resp.Header.Get(name); value != "" {
+			span.SetTag(httpResponseHeaderKey(name), value)
+		}
+	}
+}
+
+// recordRedirects records the redirect chain that led to req, oldest first,
+// when http.Client is following redirects
+func (t *transport) recordRedirects(span Span, req *http.Request) {
+	var chain []string
+	for resp := req.Response; resp != nil && resp.Request != nil; resp = resp.Request.Response {
+		chain = append([]string{t.config.url(resp.Request.URL)}, chain...)
+	}
+	if len(chain) == 0 {
+		return
+	}
+	span.SetTag(httpRequestResendCountKey, len(chain))
+	span.SetTag("http.redirect.chain", chain)
+}
+
+// recordServerTiming attaches the downstream Server-Timing metrics to span
+func (t *transport) recordServerTiming(span Span, values []string) {
+	received := time.Now()
+	for _, metric := range ParseServerTiming(values...) {
+		attrs := map[string]any{"server_timing.name": metric.Name}
+		if metric.Description != "" {
+			attrs["server_timing.description"] = metric.Description
+		}
+
+		if t.config.serverTimingSpans {
+			RecordSpan(span.Context(), t.tracer, "server_timing "+metric.Name,
+				received.Add(-metric.Duration), received,
+				WithSpanKind(SpanKindInternal),
+				WithAttributes(attrs),
+			)
+			continue
+		}
+
+		fields := []Field{
+			{Key: "event", Value: "server_timing"},
+			{Key: "server_timing.duration_ms", Value: float64(metric.Duration) / float64(time.Millisecond)},
+		}
+		for k, v := range attrs {
+			fields = append(fields, Field{Key: k, Value: v})
+		}
+		span.LogFields(fields...)
+	}
+}
@@ -0,0 +1,217 @@
+package tracingx
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spyTracer records the spans started through it
+type spyTracer struct {
+	Tracer
+	mu    sync.Mutex
+	spans []Span
+}
+
+func (s *spyTracer) Start(ctx context.Context, operationName string, opts ...SpanOption) (context.Context, Span) {
+	ctx, span := s.Tracer.Start(ctx, operationName, opts...)
+	s.mu.Lock()
+	s.spans = append(s.spans, span)
+	s.mu.Unlock()
+	return ctx, span
+}
+
+func (s *spyTracer) started() []Span {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Span(nil), s.spans...)
+}
+
+func TestTransport(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.Header().Add(ServerTimingHeader, `db;dur=20;desc="Primary DB"`)
+		w.Header().Add(ServerTimingHeader, "cache;desc=miss")
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("traces request and records server timing events", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		client := &http.Client{Transport: NewTransport(spy, nil)}
+
+		resp, err := client.Get(server.URL + "/orders")
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		spans := spy.started()
+		require.Len(t, spans, 1)
+		span := spans[0]
+		ro := span.(*otlpSpan).span.(sdktrace.ReadOnlySpan)
+		assert.Equal(t, "HTTP GET", ro.Name())
+		assert.Equal(t, trace.SpanKindClient, ro.SpanKind())
+
+		attrs := attributesOf(t, span)
+		assert.Equal(t, "GET", attrs[httpRequestMethodKey])
+		assert.Equal(t, server.URL+"/orders", attrs[urlFullKey])
+		assert.Equal(t, int64(http.StatusOK), attrs[httpResponseStatusCodeKey])
+
+		assert.Contains(t, received.Get("Traceparent"), span.TraceID())
+
+		events := ro.Events()
+		require.Len(t, events, 2)
+		first := map[string]any{}
+		for _, kv := range events[0].Attributes {
+			first[string(kv.Key)] = kv.Value.AsInterface()
+		}
+		assert.Equal(t, "db", first["server_timing.name"])
+		assert.Equal(t, "Primary DB", first["server_timing.description"])
+		assert.Equal(t, 20.0, first["server_timing.duration_ms"])
+	})
+
+	t.Run("records server timing as child spans", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		client := &http.Client{Transport: NewTransport(spy, nil, WithServerTimingSpans())}
+
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		spans := spy.started()
+		require.Len(t, spans, 3)
+		clientSpan, db := spans[0], spans[1]
+		assert.Equal(t, clientSpan.SpanID(), db.ParentSpanID())
+		assert.Equal(t, 20*time.Millisecond, db.Duration())
+		assert.Empty(t, clientSpan.(*otlpSpan).span.(sdktrace.ReadOnlySpan).Events())
+	})
+
+	t.Run("records network attributes when enabled", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		client := &http.Client{Transport: NewTransport(spy, nil, WithClientNetworkAttributes())}
+
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		attrs := attributesOf(t, spy.started()[0])
+		assert.Equal(t, "127.0.0.1", attrs[networkPeerAddressKey])
+		assert.Equal(t, "tcp", attrs[networkTransportKey])
+		assert.Equal(t, "ipv4", attrs[networkTypeKey])
+		assert.NotZero(t, attrs[networkPeerPortKey])
+	})
+
+	t.Run("marks server errors", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		client := &http.Client{Transport: NewTransport(spy, nil)}
+
+		resp, err := client.Get(server.URL + "/fail")
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, true, attributesOf(t, spy.started()[0])["error"])
+	})
+
+	t.Run("records transport errors", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		failing := roundTripFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errors.New("connection refused")
+		})
+		client := &http.Client{Transport: NewTransport(spy, failing)}
+
+		_, err := client.Get(server.URL)
+		require.Error(t, err)
+		assert.Equal(t, true, attributesOf(t, spy.started()[0])["error"])
+	})
+}
+
+func TestTransportResponseDetails(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/old":
+			http.Redirect(w, r, "/moved", http.StatusMovedPermanently)
+		case "/moved":
+			http.Redirect(w, r, "/asset", http.StatusFound)
+		case "/gzip":
+			w.Header().Set("Content-Encoding", "gzip")
+			gz := gzip.NewWriter(w)
+			gz.Write([]byte("compressed"))
+			gz.Close()
+		default:
+			w.Header().Set("CF-Cache-Status", "HIT")
+			w.Header().Set("Age", "42")
+			w.Write([]byte("payload"))
+		}
+	}))
+	defer server.Close()
+
+	t.Run("records size and cache status", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		client := &http.Client{Transport: NewTransport(spy, nil)}
+
+		resp, err := client.Get(server.URL + "/asset")
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		attrs := attributesOf(t, spy.started()[0])
+		assert.Equal(t, int64(len("payload")), attrs[httpResponseBodySizeKey])
+		assert.Equal(t, "HIT", attrs["http.response.header.cf-cache-status"])
+		assert.Equal(t, "42", attrs["http.response.header.age"])
+		assert.NotContains(t, attrs, httpRequestResendCountKey)
+	})
+
+	t.Run("records content encoding decoded by net/http", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		client := &http.Client{Transport: NewTransport(spy, nil)}
+
+		resp, err := client.Get(server.URL + "/gzip")
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, "compressed", string(body))
+
+		assert.Equal(t, "gzip", attributesOf(t, spy.started()[0])["http.response.header.content-encoding"])
+	})
+
+	t.Run("records redirect chains", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		client := &http.Client{Transport: NewTransport(spy, nil)}
+
+		resp, err := client.Get(server.URL + "/old")
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		spans := spy.started()
+		require.Len(t, spans, 3)
+		assert.NotContains(t, attributesOf(t, spans[0]), "http.redirect.chain")
+		attrs := attributesOf(t, spans[2])
+		assert.Equal(t, int64(2), attrs[httpRequestResendCountKey])
+		assert.Equal(t, []string{server.URL + "/old", server.URL + "/moved"}, attrs["http.redirect.chain"])
+	})
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) { return f(req) }
@@ -0,0 +1,150 @@
+package tracingx
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"strings"
+
+	"github.com/gostratum/core/logx"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Inbound trust policies accepted in InboundSamplingConfig.Trust
+const (
+	// InboundTrustNone ignores callers' sampling decisions: traces continued
+	// from a caller are sampled at the configured rate
+	InboundTrustNone = "none"
+
+	// InboundTrustAll follows the sampled flag of every caller
+	InboundTrustAll = "all"
+
+	// InboundTrustInternalOnly follows the sampled flag of the callers listed
+	// in InboundSamplingConfig.Internal only
+	InboundTrustInternalOnly = "internal_only"
+)
+
+// InboundSamplingConfig controls whether traces continued from a caller
+// follow the caller's sampling decision. Followed decisions apply to every
+// span the service starts for the request; route sampling hints and forced
+// sampling still take precedence.
+type InboundSamplingConfig struct {
+	// Trust is the policy: none, all or internal_only
+	Trust string `mapstructure:"trust" default:"none"`
+
+	// Internal lists the callers trusted under internal_only: IP addresses,
+	// CIDR ranges, or hostnames, resolved when the provider is configured
+	Internal []string `mapstructure:"internal"`
+}
+
+// inboundTrust decides whose sampling decisions are followed
+type inboundTrust struct {
+	trust    string
+	internal []netip.Prefix
+}
+
+// newInboundTrust builds the trust policy from config. Hostnames that do
+// not resolve are logged and skipped, so a DNS outage does not stop the
+// service from starting.
+func newInboundTrust(ctx context.Context, config InboundSamplingConfig, logger logx.Logger) (*inboundTrust, error) {
+	policy := &inboundTrust{trust: strings.ToLower(config.Trust)}
+	switch policy.trust {
+	case "", InboundTrustNone, InboundTrustAll:
+		return policy, nil
+	case InboundTrustInternalOnly:
+	default:
+		return nil, fmt.Errorf("unknown inbound_sampling.trust %q", config.Trust)
+	}
+	for _, entry := range config.Internal {
+		entry = strings.TrimSpace(entry)
+		if strings.Contains(entry, "/") {
+			prefix, err := netip.ParsePrefix(entry)
+			if err != nil {
+				return nil, fmt.Errorf("invalid inbound_sampling.internal entry %q: %w", entry, err)
+			}
+			policy.internal = append(policy.internal, prefix.Masked())
+			continue
+		}
+		if addr, err := netip.ParseAddr(entry); err == nil {
+			policy.internal = append(policy.internal, hostPrefix(addr))
+			continue
+		}
+		addrs, err := net.DefaultResolver.LookupHost(ctx, entry)
+		if err != nil {
+			logger.Warn("failed to resolve trusted caller", logx.String("host", entry), logx.Err(err))
+			continue
+		}
+		for _, a := range addrs {
+			if addr, err := netip.ParseAddr(a); err == nil {
+				policy.internal = append(policy.internal, hostPrefix(addr))
+			}
+		}
+	}
+	return policy, nil
+}
+
+// hostPrefix returns the prefix holding addr alone
+func hostPrefix(addr netip.Addr) netip.Prefix {
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen())
+}
+
+// trusts reports whether the caller at address, a host or host:port, is
+// trusted. Callers of unknown address are only trusted by InboundTrustAll.
+func (t *inboundTrust) trusts(address string) bool {
+	switch t.trust {
+	case InboundTrustAll:
+		return true
+	case InboundTrustInternalOnly:
+	default:
+		return false
+	}
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		address = host
+	}
+	addr, err := netip.ParseAddr(strings.TrimSuffix(strings.TrimPrefix(address, "["), "]"))
+	if err != nil {
+		return false
+	}
+	addr = addr.WithZone("").Unmap()
+	for _, prefix := range t.internal {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// apply makes spans started from extracted follow the sampling decision of
+// the remote parent extracted from ctx's carrier when the caller is
+// trusted. A sampling priority already on ctx, e.g. from a route hint, wins.
+func (t *inboundTrust) apply(ctx, extracted context.Context) context.Context {
+	if t == nil || !extractedRemote(ctx, extracted) || samplingPriorityFromContext(extracted) != 0 {
+		return extracted
+	}
+	if !t.trusts(peerAddressFromContext(ctx)) {
+		return extracted
+	}
+	if trace.SpanContextFromContext(extracted).IsSampled() {
+		return contextWithSamplingPriority(extracted, 1)
+	}
+	return contextWithSamplingPriority(extracted, -1)
+}
+
+// ContextWithPeerAddress returns a new context recording the network
+// address (host or host:port) of the caller whose trace context is
+// extracted from it, for the inbound sampling trust policy. The HTTP
+// middleware and the gRPC, Connect and Twirp instrumentation record it
+// themselves.
+func ContextWithPeerAddress(ctx context.Context, address string) context.Context {
+	return context.WithValue(ctx, peerAddressKey{}, address)
+}
+
+// peerAddressFromContext returns the caller address recorded on ctx, if any
+func peerAddressFromContext(ctx context.Context) string {
+	address, _ := ctx.Value(peerAddressKey{}).(string)
+	return address
+}
+
+type peerAddressKey struct{}
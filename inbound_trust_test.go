@@ -0,0 +1,123 @@
+package tracingx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInboundTrust(t *testing.T) {
+	t.Run("matches internal callers", func(t *testing.T) {
+		policy, err := newInboundTrust(context.Background(), InboundSamplingConfig{
+			Trust:    InboundTrustInternalOnly,
+			Internal: []string{"10.0.0.0/8", "192.168.1.7", "fd00::/8", "localhost"},
+		}, getTestLogger())
+		require.NoError(t, err)
+
+		for address, want := range map[string]bool{
+			"10.20.30.40:5000":  true,
+			"10.20.30.40":       true,
+			"192.168.1.7:443":   true,
+			"192.168.1.8:443":   false,
+			"[fd12::1]:8080":    true,
+			"[::ffff:10.0.0.1]": true,
+			"127.0.0.1:9000":    true,
+			"203.0.113.9:5000":  false,
+			"":                  false,
+			"not-an-address":    false,
+		} {
+			assert.Equal(t, want, policy.trusts(address), address)
+		}
+	})
+
+	t.Run("trusts everyone or no one", func(t *testing.T) {
+		all, err := newInboundTrust(context.Background(), InboundSamplingConfig{Trust: InboundTrustAll}, getTestLogger())
+		require.NoError(t, err)
+		assert.True(t, all.trusts(""))
+
+		none, err := newInboundTrust(context.Background(), InboundSamplingConfig{Trust: "", Internal: []string{"10.0.0.0/8"}}, getTestLogger())
+		require.NoError(t, err)
+		assert.False(t, none.trusts("10.0.0.1:80"))
+	})
+
+	t.Run("skips unresolvable hostnames", func(t *testing.T) {
+		policy, err := newInboundTrust(context.Background(), InboundSamplingConfig{
+			Trust:    InboundTrustInternalOnly,
+			Internal: []string{"no-such-host.invalid"},
+		}, getTestLogger())
+		require.NoError(t, err)
+		assert.Empty(t, policy.internal)
+	})
+
+	t.Run("rejects invalid configuration", func(t *testing.T) {
+		_, err := newOTLPProvider(Config{ServiceName: "test-service", InboundSampling: InboundSamplingConfig{Trust: "sometimes"}}, getTestLogger())
+		assert.ErrorContains(t, err, `unknown inbound_sampling.trust "sometimes"`)
+
+		_, err = newOTLPProvider(Config{ServiceName: "test-service", InboundSampling: InboundSamplingConfig{
+			Trust:    InboundTrustInternalOnly,
+			Internal: []string{"10.0.0.0/33"},
+		}}, getTestLogger())
+		assert.ErrorContains(t, err, `invalid inbound_sampling.internal entry "10.0.0.0/33"`)
+	})
+}
+
+func TestMiddlewareInboundTrust(t *testing.T) {
+	const (
+		sampled   = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+		unsampled = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"
+	)
+	serve := func(t *testing.T, rate float64, remoteAddr, traceParent string, opts ...MiddlewareOption) (server, child bool) {
+		t.Helper()
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			SampleRate:  rate,
+			InboundSampling: InboundSamplingConfig{
+				Trust:    InboundTrustInternalOnly,
+				Internal: []string{"10.0.0.0/8"},
+			},
+		}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		handler := NewMiddleware(provider, opts...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			server = SpanFromContext(r.Context()).IsSampled()
+			_, span := provider.Start(r.Context(), "db.query")
+			defer span.End()
+			child = span.IsSampled()
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("traceparent", traceParent)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return server, child
+	}
+
+	t.Run("follows internal callers", func(t *testing.T) {
+		server, child := serve(t, 0, "10.1.2.3:5000", sampled)
+		assert.True(t, server)
+		assert.True(t, child)
+
+		server, child = serve(t, 1.0, "10.1.2.3:5000", unsampled)
+		assert.False(t, server)
+		assert.False(t, child)
+	})
+
+	t.Run("ignores external callers", func(t *testing.T) {
+		server, child := serve(t, 0, "203.0.113.9:5000", sampled)
+		assert.False(t, server)
+		assert.False(t, child)
+
+		server, _ = serve(t, 1.0, "203.0.113.9:5000", unsampled)
+		assert.True(t, server)
+	})
+
+	t.Run("route hints win", func(t *testing.T) {
+		server, child := serve(t, 0, "10.1.2.3:5000", unsampled, WithRouteSampling(AlwaysSampleRoute("/orders")))
+		assert.True(t, server)
+		assert.True(t, child)
+	})
+}
@@ -0,0 +1,76 @@
+package tracingx
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// internTableMax caps the entries of an internTable, so keys derived from
+// untrusted input, such as header names, cannot grow it without bound
+const internTableMax = 1024
+
+// internTable caches strings derived from a small set of inputs, such as
+// attribute keys built by prefixing a name, so hot paths stop allocating a
+// new string per span. Lookups are lock-free: the map is replaced, never
+// modified, when an entry is added.
+type internTable struct {
+	derive func(string) string
+
+	mu      sync.Mutex // serializes adds
+	entries atomic.Pointer[map[string]string]
+}
+
+// newInternTable returns a table caching derive(s) by s
+func newInternTable(derive func(string) string) *internTable {
+	return &internTable{derive: derive}
+}
+
+// get returns derive(s), computed once per distinct s while the table has room
+func (t *internTable) get(s string) string {
+	if entries := t.entries.Load(); entries != nil {
+		if v, ok := (*entries)[s]; ok {
+			return v
+		}
+	}
+	v := t.derive(s)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var old map[string]string
+	if entries := t.entries.Load(); entries != nil {
+		old = *entries
+	}
+	if _, ok := old[s]; ok || len(old) >= internTableMax {
+		return v
+	}
+	next := make(map[string]string, len(old)+1)
+	for k, existing := range old {
+		next[k] = existing
+	}
+	// s may alias a larger buffer, e.g. a request line; keep only a copy
+	next[strings.Clone(s)] = v
+	t.entries.Store(&next)
+	return v
+}
+
+// reservedKeys caches reserved keys recorded under ReservedAttributePrefix
+var reservedKeys = newInternTable(func(key string) string {
+	return ReservedAttributePrefix + key
+})
+
+// responseHeaderKeys caches http.response.header.<key> attribute keys by
+// header name
+var responseHeaderKeys = newInternTable(func(name string) string {
+	return "http.response.header." + strings.ToLower(name)
+})
+
+// httpSpanNames caches "HTTP <method>" span names by request method
+var httpSpanNames = newInternTable(func(method string) string {
+	return "HTTP " + method
+})
+
+// httpMethods interns request methods recorded as attribute values. Methods
+// parsed by net/http share the memory of the whole request line, which a
+// queued span would otherwise keep alive until it is exported.
+var httpMethods = newInternTable(strings.Clone)
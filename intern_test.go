@@ -0,0 +1,109 @@
+package tracingx
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInternTable(t *testing.T) {
+	t.Run("derives each input once", func(t *testing.T) {
+		calls := 0
+		table := newInternTable(func(s string) string {
+			calls++
+			return "prefix." + s
+		})
+		assert.Equal(t, "prefix.a", table.get("a"))
+		assert.Equal(t, "prefix.a", table.get("a"))
+		assert.Equal(t, "prefix.b", table.get("b"))
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("stops caching when full", func(t *testing.T) {
+		table := newInternTable(strings.ToUpper)
+		for i := 0; i < internTableMax+10; i++ {
+			assert.Equal(t, "K"+strconv.Itoa(i), table.get("k"+strconv.Itoa(i)))
+		}
+		assert.Len(t, *table.entries.Load(), internTableMax)
+	})
+
+	t.Run("does not retain the input's memory", func(t *testing.T) {
+		line := "GET /orders HTTP/1.1"
+		method := line[:3]
+		interned := newInternTable(strings.Clone).get(method)
+		assert.Equal(t, "GET", interned)
+		assert.True(t, unsafe.StringData(method) != unsafe.StringData(interned))
+	})
+
+	t.Run("allocation free once cached", func(t *testing.T) {
+		httpResponseHeaderKey("Cache-Status")
+		allocs := testing.AllocsPerRun(100, func() {
+			httpResponseHeaderKey("Cache-Status")
+			httpSpanNames.get("GET")
+		})
+		assert.Zero(t, allocs)
+	})
+}
+
+// benchmarkKey keeps benchmarked keys from being optimized away
+var benchmarkKey string
+
+// BenchmarkAttributeKeys compares building attribute keys and span names per
+// call with the interned lookups used on the span path
+//
+//	go test -run XXX -bench AttributeKeys -benchmem
+func BenchmarkAttributeKeys(b *testing.B) {
+	header := strings.Clone("X-Cache")
+	b.Run("header/concat", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			benchmarkKey = "http.response.header." + strings.ToLower(header)
+		}
+	})
+	b.Run("header/interned", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			benchmarkKey = httpResponseHeaderKey(header)
+		}
+	})
+
+	method := strings.Clone("GET")
+	b.Run("span_name/concat", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			benchmarkKey = "HTTP " + method
+		}
+	})
+	b.Run("span_name/interned", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			benchmarkKey = httpSpanNames.get(method)
+		}
+	})
+}
+
+// BenchmarkSetTagReserved measures tagging a span with a reserved key, which
+// is recorded under ReservedAttributePrefix
+func BenchmarkSetTagReserved(b *testing.B) {
+	collector := newFakeCollector(b)
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "bench",
+		SampleRate:  1.0,
+		OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+	}, getTestLogger())
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer provider.Shutdown(context.Background())
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, span := provider.Start(context.Background(), "bench")
+		span.SetTag("service.version", "1.2.3")
+		span.End()
+	}
+}
@@ -0,0 +1,38 @@
+package tracingx
+
+import (
+	"context"
+
+	"github.com/gostratum/core/logx"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// DebugBaggageKey is the baggage member (debug=true) with which a caller
+// requests detailed logs for a request, whether or not it is sampled
+const DebugBaggageKey = "debug"
+
+// DebugLogsRequested reports whether the trace in ctx is sampled or its
+// baggage carries the debug flag
+func DebugLogsRequested(ctx context.Context) bool {
+	if trace.SpanContextFromContext(ctx).IsSampled() {
+		return true
+	}
+	switch baggage.FromContext(ctx).Member(DebugBaggageKey).Value() {
+	case "true", "1":
+		return true
+	default:
+		return false
+	}
+}
+
+// TraceLevelLogger returns verbose when DebugLogsRequested(ctx) and base
+// otherwise. verbose is typically the application logger with debug level
+// enabled, so detailed logs are written exactly for requests that have a
+// trace to go with them.
+func TraceLevelLogger(ctx context.Context, base, verbose logx.Logger) logx.Logger {
+	if DebugLogsRequested(ctx) {
+		return verbose
+	}
+	return base
+}
@@ -0,0 +1,58 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gostratum/core/logx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestTraceLevelLogger(t *testing.T) {
+	infoCore, infoLogs := observer.New(zapcore.InfoLevel)
+	debugCore, debugLogs := observer.New(zapcore.DebugLevel)
+	base := logx.ProvideAdapter(zap.New(infoCore))
+	verbose := logx.ProvideAdapter(zap.New(debugCore))
+
+	sampled, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer sampled.Shutdown(context.Background())
+
+	t.Run("sampled traces log at debug level", func(t *testing.T) {
+		ctx, span := sampled.Start(context.Background(), "work")
+		defer span.End()
+
+		assert.True(t, DebugLogsRequested(ctx))
+		TraceLevelLogger(ctx, base, verbose).Debug("cache miss")
+		assert.Equal(t, 1, debugLogs.FilterMessage("cache miss").Len())
+	})
+
+	t.Run("debug baggage flag logs at debug level", func(t *testing.T) {
+		member, err := baggage.NewMember(DebugBaggageKey, "true")
+		require.NoError(t, err)
+		bag, err := baggage.New(member)
+		require.NoError(t, err)
+		ctx := baggage.ContextWithBaggage(context.Background(), bag)
+
+		assert.True(t, DebugLogsRequested(ctx))
+		TraceLevelLogger(ctx, base, verbose).Debug("flagged")
+		assert.Equal(t, 1, debugLogs.FilterMessage("flagged").Len())
+	})
+
+	t.Run("other requests keep the base level", func(t *testing.T) {
+		ctx := context.Background()
+		assert.False(t, DebugLogsRequested(ctx))
+
+		logger := TraceLevelLogger(ctx, base, verbose)
+		logger.Debug("dropped")
+		logger.Info("kept")
+		assert.Equal(t, 0, debugLogs.FilterMessage("dropped").Len())
+		assert.Equal(t, 0, infoLogs.FilterMessage("dropped").Len())
+		assert.Equal(t, 1, infoLogs.FilterMessage("kept").Len())
+	})
+}
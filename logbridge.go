@@ -0,0 +1,200 @@
+package tracingx
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogsConfig configures the bridge forwarding logx records to an OTLP logs
+// exporter
+type LogsConfig struct {
+	// Enabled forwards records logged inside sampled spans, with their trace
+	// and span IDs, so backends can join logs and traces exactly
+	Enabled bool `mapstructure:"enabled" default:"false"`
+
+	// OTLP configures the logs exporter; Workers is ignored
+	OTLP OTLPConfig `mapstructure:"otlp"`
+}
+
+// LogBridge forwards logx records written inside sampled spans to an OTLP
+// logs exporter. A nil *LogBridge, returned when the bridge is disabled,
+// forwards nothing.
+type LogBridge struct {
+	provider *sdklog.LoggerProvider
+	logger   log.Logger
+	clock    Clock
+}
+
+// NewLogBridge creates the log bridge configured by config.Logs, or returns
+// nil when it is disabled
+func NewLogBridge(config Config) (*LogBridge, error) {
+	if !config.Logs.Enabled {
+		return nil, nil
+	}
+	exporter, err := newOTLPLogExporter(context.Background(), config.Logs.OTLP)
+	if err != nil {
+		return nil, err
+	}
+	return newLogBridge(config, exporter, systemClock{})
+}
+
+// newLogBridge creates a log bridge batching records to exporter
+func newLogBridge(config Config, exporter sdklog.Exporter, clock Clock) (*LogBridge, error) {
+	res, err := newServiceResource(context.Background(), config)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+	)
+	return &LogBridge{
+		provider: provider,
+		logger: provider.Logger(config.Instrumentation.name(),
+			log.WithInstrumentationVersion(config.Instrumentation.version()),
+			log.WithSchemaURL(config.schemaURL()),
+		),
+		clock: clock,
+	}, nil
+}
+
+// newOTLPLogExporter creates an OTLP gRPC logs exporter from configuration
+func newOTLPLogExporter(ctx context.Context, config OTLPConfig) (sdklog.Exporter, error) {
+	opts := []otlploggrpc.Option{
+		otlploggrpc.WithEndpoint(config.Endpoint),
+	}
+	if config.Insecure {
+		opts = append(opts, otlploggrpc.WithInsecure())
+	}
+	if len(config.Headers) > 0 {
+		opts = append(opts, otlploggrpc.WithHeaders(config.Headers))
+	}
+
+	exporter, err := otlploggrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP log exporter: %w", err)
+	}
+	return exporter, nil
+}
+
+// Logger returns a logger writing to next that also forwards each record to
+// the bridge when the span in ctx is sampled
+func (b *LogBridge) Logger(ctx context.Context, next logx.Logger) logx.Logger {
+	if b == nil || !trace.SpanContextFromContext(ctx).IsSampled() {
+		return next
+	}
+	return &bridgeLogger{next: next, bridge: b, ctx: ctx}
+}
+
+// ForceFlush exports all forwarded records that have not been exported yet
+func (b *LogBridge) ForceFlush(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	return b.provider.ForceFlush(ctx)
+}
+
+// Shutdown flushes and stops the bridge
+func (b *LogBridge) Shutdown(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+	return b.provider.Shutdown(ctx)
+}
+
+// emit forwards a record; the SDK attaches the trace and span IDs of ctx
+func (b *LogBridge) emit(ctx context.Context, severity log.Severity, msg string, fields []logx.Field) {
+	var record log.Record
+	record.SetTimestamp(b.clock.Now())
+	record.SetObservedTimestamp(b.clock.Now())
+	record.SetSeverity(severity)
+	record.SetSeverityText(severity.String())
+	record.SetBody(log.StringValue(msg))
+	record.AddAttributes(logAttributes(fields)...)
+	b.logger.Emit(ctx, record)
+}
+
+// bridgeLogger tees records to a logx logger and the log bridge
+type bridgeLogger struct {
+	next   logx.Logger
+	bridge *LogBridge
+	ctx    context.Context
+	fields []logx.Field
+}
+
+func (l *bridgeLogger) Debug(msg string, fields ...logx.Field) {
+	l.next.Debug(msg, fields...)
+	l.emit(log.SeverityDebug, msg, fields)
+}
+
+func (l *bridgeLogger) Info(msg string, fields ...logx.Field) {
+	l.next.Info(msg, fields...)
+	l.emit(log.SeverityInfo, msg, fields)
+}
+
+func (l *bridgeLogger) Warn(msg string, fields ...logx.Field) {
+	l.next.Warn(msg, fields...)
+	l.emit(log.SeverityWarn, msg, fields)
+}
+
+func (l *bridgeLogger) Error(msg string, fields ...logx.Field) {
+	l.next.Error(msg, fields...)
+	l.emit(log.SeverityError, msg, fields)
+}
+
+func (l *bridgeLogger) With(fields ...logx.Field) logx.Logger {
+	return &bridgeLogger{
+		next:   l.next.With(fields...),
+		bridge: l.bridge,
+		ctx:    l.ctx,
+		fields: append(append([]logx.Field(nil), l.fields...), fields...),
+	}
+}
+
+func (l *bridgeLogger) emit(severity log.Severity, msg string, fields []logx.Field) {
+	if len(l.fields) > 0 {
+		fields = append(append([]logx.Field(nil), l.fields...), fields...)
+	}
+	l.bridge.emit(l.ctx, severity, msg, fields)
+}
+
+// logAttributes converts logx fields to log attributes
+func logAttributes(fields []logx.Field) []log.KeyValue {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	attrs := make([]log.KeyValue, 0, len(enc.Fields))
+	for k, v := range enc.Fields {
+		attrs = append(attrs, logAttribute(k, v))
+	}
+	return attrs
+}
+
+// logAttribute converts an encoded field value to a log attribute
+func logAttribute(key string, value any) log.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return log.String(key, v)
+	case bool:
+		return log.Bool(key, v)
+	case int:
+		return log.Int(key, v)
+	case int64:
+		return log.Int64(key, v)
+	case float64:
+		return log.Float64(key, v)
+	case time.Duration:
+		return log.Int64(key, int64(v))
+	default:
+		return log.String(key, fmt.Sprintf("%v", v))
+	}
+}
@@ -0,0 +1,92 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/gostratum/core/logx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// memoryLogExporter collects exported log records in memory
+type memoryLogExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *memoryLogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *memoryLogExporter) Shutdown(ctx context.Context) error   { return nil }
+func (e *memoryLogExporter) ForceFlush(ctx context.Context) error { return nil }
+
+func (e *memoryLogExporter) exported() []sdklog.Record {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]sdklog.Record(nil), e.records...)
+}
+
+func TestLogBridge(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	exporter := &memoryLogExporter{}
+	bridge, err := newLogBridge(Config{ServiceName: "test-service"}, exporter, systemClock{})
+	require.NoError(t, err)
+	defer bridge.Shutdown(context.Background())
+
+	t.Run("forwards records inside sampled spans", func(t *testing.T) {
+		ctx, span := provider.Start(context.Background(), "work")
+		defer span.End()
+
+		logger := bridge.Logger(ctx, getTestLogger()).With(logx.String("component", "billing"))
+		logger.Warn("payment slow", logx.Int("attempt", 2), logx.Err(errors.New("timeout")))
+		require.NoError(t, bridge.ForceFlush(context.Background()))
+
+		records := exporter.exported()
+		require.Len(t, records, 1)
+		record := records[0]
+		assert.Equal(t, "payment slow", record.Body().AsString())
+		assert.Equal(t, log.SeverityWarn, record.Severity())
+		assert.Equal(t, span.TraceID(), record.TraceID().String())
+		assert.Equal(t, span.SpanID(), record.SpanID().String())
+
+		attrs := map[string]log.Value{}
+		record.WalkAttributes(func(kv log.KeyValue) bool {
+			attrs[kv.Key] = kv.Value
+			return true
+		})
+		assert.Equal(t, "billing", attrs["component"].AsString())
+		assert.Equal(t, int64(2), attrs["attempt"].AsInt64())
+		assert.Equal(t, "timeout", attrs["error"].AsString())
+	})
+
+	t.Run("skips unsampled contexts", func(t *testing.T) {
+		next := getTestLogger()
+		assert.Equal(t, next, bridge.Logger(context.Background(), next))
+	})
+
+	t.Run("nil bridge is disabled", func(t *testing.T) {
+		disabled, err := NewLogBridge(Config{})
+		require.NoError(t, err)
+		assert.Nil(t, disabled)
+
+		ctx, span := provider.Start(context.Background(), "work")
+		defer span.End()
+		next := getTestLogger()
+		assert.Equal(t, next, disabled.Logger(ctx, next))
+		assert.NoError(t, disabled.Shutdown(context.Background()))
+	})
+}
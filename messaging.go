@@ -0,0 +1,45 @@
+package tracingx
+
+import "time"
+
+// QueueLatencyAttribute records how long a message waited in the queue before
+// the consumer started processing it
+const QueueLatencyAttribute = "messaging.queue_latency_ms"
+
+// WithQueueLatency records on a consumer span the time between enqueuedAt,
+// the enqueue timestamp carried by the message, and the span start. Negative
+// latencies caused by clock skew between producer and consumer are recorded
+// as zero.
+func WithQueueLatency(enqueuedAt time.Time) SpanOption {
+	return func(c *SpanConfig) {
+		setQueueLatency(c, enqueuedAt)
+	}
+}
+
+// WithBackdatedQueueLatency records the queue latency like WithQueueLatency
+// and also starts the span at enqueuedAt, so the time spent waiting in the
+// queue is part of the consumer span's duration
+func WithBackdatedQueueLatency(enqueuedAt time.Time) SpanOption {
+	return func(c *SpanConfig) {
+		if setQueueLatency(c, enqueuedAt) {
+			c.Timestamp = enqueuedAt
+		}
+	}
+}
+
+// setQueueLatency sets the queue latency attribute relative to the span
+// start, reporting whether enqueuedAt was usable
+func setQueueLatency(c *SpanConfig, enqueuedAt time.Time) bool {
+	if enqueuedAt.IsZero() {
+		return false
+	}
+	latency := c.Timestamp.Sub(enqueuedAt)
+	if latency < 0 {
+		latency = 0
+	}
+	if c.Attributes == nil {
+		c.Attributes = make(map[string]any)
+	}
+	c.Attributes[QueueLatencyAttribute] = float64(latency) / float64(time.Millisecond)
+	return latency > 0
+}
@@ -0,0 +1,55 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestQueueLatency(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := newFakeClock(now)
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger(), withClock(clock))
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("records latency", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "consume",
+			WithSpanKind(SpanKindConsumer),
+			WithQueueLatency(now.Add(-1500*time.Millisecond)),
+		)
+		span.End()
+
+		assert.Equal(t, 1500.0, attributesOf(t, span)[QueueLatencyAttribute])
+		assert.True(t, span.(*otlpSpan).span.(sdktrace.ReadOnlySpan).StartTime().Equal(now))
+	})
+
+	t.Run("backdates span start", func(t *testing.T) {
+		enqueuedAt := now.Add(-2 * time.Second)
+		_, span := provider.Start(context.Background(), "consume", WithBackdatedQueueLatency(enqueuedAt))
+		span.End()
+
+		assert.Equal(t, 2000.0, attributesOf(t, span)[QueueLatencyAttribute])
+		assert.True(t, span.(*otlpSpan).span.(sdktrace.ReadOnlySpan).StartTime().Equal(enqueuedAt))
+		assert.Equal(t, 2*time.Second, span.Duration())
+	})
+
+	t.Run("clamps clock skew to zero", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "consume", WithBackdatedQueueLatency(now.Add(time.Second)))
+		span.End()
+
+		assert.Equal(t, 0.0, attributesOf(t, span)[QueueLatencyAttribute])
+		assert.True(t, span.(*otlpSpan).span.(sdktrace.ReadOnlySpan).StartTime().Equal(now))
+	})
+
+	t.Run("ignores missing enqueue time", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "consume", WithQueueLatency(time.Time{}))
+		span.End()
+
+		assert.NotContains(t, attributesOf(t, span), QueueLatencyAttribute)
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/gostratum/core/logx"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.uber.org/fx"
 )
 
@@ -12,6 +13,32 @@ type Params struct {
 	fx.In
 	Config Config
 	Logger logx.Logger
+
+	// RequestID optionally bridges the application's request ID context;
+	// when provided, spans carry the request ID as the request.id attribute
+	RequestID RequestIDFunc `optional:"true"`
+
+	// PeerService optionally resolves peer.service names of client spans,
+	// e.g. from service discovery, for addresses not in the configured map
+	PeerService PeerServiceResolver `optional:"true"`
+
+	// AuditSink optionally replaces the file sink used for audit records
+	AuditSink AuditSink `optional:"true"`
+
+	// Exporter optionally replaces the exporter built from the provider
+	// configuration, e.g. with an in-memory exporter in tests. It is kept
+	// across Reconfigure and shut down with the provider.
+	Exporter sdktrace.SpanExporter `optional:"true"`
+
+	// Clock optionally replaces the system clock used for span timestamps
+	Clock Clock `optional:"true"`
+
+	// Propagators are custom header schemes registered with ProvidePropagator
+	Propagators []Propagator `group:"tracingx.propagators"`
+
+	// SpanProcessors are the span processors registered with
+	// ProvideSpanProcessor, run by stage and priority before export
+	SpanProcessors []SpanProcessor `group:"tracingx.processors"`
 }
 
 // Result contains outputs from the tracing module
@@ -27,8 +54,10 @@ func Module() fx.Option {
 		fx.Provide(
 			NewConfig,
 			NewTracer,
+			NewLogBridge,
 		),
 		fx.Invoke(registerLifecycle),
+		fx.Invoke(registerLogBridgeLifecycle),
 	)
 }
 
@@ -48,7 +77,19 @@ func NewTracer(p Params) (Result, error) {
 
 	switch p.Config.Provider {
 	case "otlp":
-		provider, err = newOTLPProvider(p.Config, p.Logger)
+		provider, err = newOTLPProvider(p.Config, p.Logger, p.providerOptions()...)
+	case "zipkin":
+		provider, err = newZipkinProvider(p.Config, p.Logger, p.providerOptions()...)
+	case "datadog":
+		provider, err = newDatadogProvider(p.Config, p.Logger, p.providerOptions()...)
+	case "xray":
+		provider, err = newXRayProvider(p.Config, p.Logger, p.providerOptions()...)
+	case "file":
+		provider, err = newFileProvider(p.Config, p.Logger, p.providerOptions()...)
+	case "stdout":
+		provider, err = newStdoutProvider(p.Config, p.Logger, p.providerOptions()...)
+	case "tee":
+		provider, err = newTeeProvider(p.Config, p.Logger, p.providerOptions()...)
 	case "noop":
 		provider = newNoopProvider()
 	default:
@@ -67,15 +108,31 @@ func NewTracer(p Params) (Result, error) {
 }
 
 // registerLifecycle registers the tracing lifecycle hooks
-func registerLifecycle(lc fx.Lifecycle, provider Provider, logger logx.Logger) {
+func registerLifecycle(lc fx.Lifecycle, config Config, provider Provider, logger logx.Logger) {
+	stopSynthetic := func() {}
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			logger.Info("starting tracing provider")
+			if err := runWarmup(ctx, config.Warmup, provider, logger); err != nil {
+				return err
+			}
+			if err := runSelfTest(ctx, config.SelfTest, provider, logger); err != nil {
+				return err
+			}
+			stopSynthetic = startSyntheticTraces(config.Synthetic, provider, logger)
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
 			logger.Info("stopping tracing provider")
+			stopSynthetic()
 			return provider.Shutdown(ctx)
 		},
 	})
 }
+
+// registerLogBridgeLifecycle flushes and stops the log bridge on shutdown
+func registerLogBridgeLifecycle(lc fx.Lifecycle, bridge *LogBridge) {
+	lc.Append(fx.Hook{
+		OnStop: bridge.Shutdown,
+	})
+}
@@ -0,0 +1,56 @@
+package tracingx
+
+import (
+	"net"
+	"net/netip"
+	"strconv"
+	"strings"
+)
+
+// recordNetworkPeer records the connection to the peer at address, reached
+// over network as reported by net.Addr.Network, on span: network.peer.address
+// and network.peer.port, the network.transport (tcp, udp or unix) and, for IP
+// peers, the network.type (ipv4 or ipv6)
+func recordNetworkPeer(span Span, network, address string) {
+	if address == "" {
+		return
+	}
+	transport := strings.TrimRight(network, "46")
+	switch transport {
+	case "tcp", "udp":
+	case "unix", "unixgram", "unixpacket":
+		span.SetTagString(networkTransportKey, "unix")
+		span.SetTagString(networkPeerAddressKey, address)
+		return
+	default:
+		return
+	}
+	span.SetTagString(networkTransportKey, transport)
+
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		// Not an IP address, e.g. a host name
+		if host, port, err := net.SplitHostPort(address); err == nil {
+			span.SetTagString(networkPeerAddressKey, host)
+			if port, err := strconv.Atoi(port); err == nil {
+				span.SetTagInt(networkPeerPortKey, port)
+			}
+		}
+		return
+	}
+	ip := addrPort.Addr().Unmap()
+	span.SetTagString(networkPeerAddressKey, ip.String())
+	span.SetTagInt(networkPeerPortKey, int(addrPort.Port()))
+	if ip.Is4() {
+		span.SetTagString(networkTypeKey, "ipv4")
+	} else {
+		span.SetTagString(networkTypeKey, "ipv6")
+	}
+}
+
+// recordNetworkPeerAddr records the connection to addr on span
+func recordNetworkPeerAddr(span Span, addr net.Addr) {
+	if addr != nil {
+		recordNetworkPeer(span, addr.Network(), addr.String())
+	}
+}
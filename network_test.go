@@ -0,0 +1,95 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordNetworkPeer(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	tests := []struct {
+		name     string
+		network  string
+		address  string
+		expected map[string]any
+	}{
+		{
+			name:    "ipv4",
+			network: "tcp",
+			address: "10.0.4.12:8443",
+			expected: map[string]any{
+				networkPeerAddressKey: "10.0.4.12",
+				networkPeerPortKey:    int64(8443),
+				networkTransportKey:   "tcp",
+				networkTypeKey:        "ipv4",
+			},
+		},
+		{
+			name:    "ipv4 mapped ipv6",
+			network: "tcp6",
+			address: "[::ffff:10.0.4.12]:8443",
+			expected: map[string]any{
+				networkPeerAddressKey: "10.0.4.12",
+				networkPeerPortKey:    int64(8443),
+				networkTransportKey:   "tcp",
+				networkTypeKey:        "ipv4",
+			},
+		},
+		{
+			name:    "udp ipv6",
+			network: "udp6",
+			address: "[2001:db8::7]:53",
+			expected: map[string]any{
+				networkPeerAddressKey: "2001:db8::7",
+				networkPeerPortKey:    int64(53),
+				networkTransportKey:   "udp",
+				networkTypeKey:        "ipv6",
+			},
+		},
+		{
+			name:    "host name",
+			network: "tcp",
+			address: "db.internal:5432",
+			expected: map[string]any{
+				networkPeerAddressKey: "db.internal",
+				networkPeerPortKey:    int64(5432),
+				networkTransportKey:   "tcp",
+			},
+		},
+		{
+			name:    "unix socket",
+			network: "unix",
+			address: "/var/run/app.sock",
+			expected: map[string]any{
+				networkPeerAddressKey: "/var/run/app.sock",
+				networkTransportKey:   "unix",
+			},
+		},
+		{
+			name:     "unknown network",
+			network:  "pipe",
+			address:  `\\.\pipe\app`,
+			expected: map[string]any{},
+		},
+		{
+			name:     "empty address",
+			network:  "tcp",
+			expected: map[string]any{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, span := provider.Start(context.Background(), "call")
+			defer span.End()
+			recordNetworkPeer(span, tt.network, tt.address)
+			assert.Equal(t, tt.expected, attributesOf(t, span))
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package tracingx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Notification attribute keys recorded by the notification helpers
+const (
+	NotificationChannelAttribute   = "notification.channel"
+	NotificationProviderAttribute  = "notification.provider"
+	NotificationTemplateAttribute  = "notification.template"
+	NotificationStatusAttribute    = "notification.delivery_status"
+	NotificationMessageIDAttribute = "notification.message_id"
+)
+
+// Notification describes an outbound notification
+type Notification struct {
+	// Channel is the delivery channel, e.g. "email", "sms" or "webhook"
+	Channel string
+
+	// Provider is the service delivering it, e.g. "sendgrid" or "twilio"
+	Provider string
+
+	// Template identifies the message template, e.g. "password_reset"
+	Template string
+}
+
+// DeliveryStatus is the outcome of a notification reported by its provider
+type DeliveryStatus string
+
+// Delivery statuses
+const (
+	DeliveryQueued    DeliveryStatus = "queued"
+	DeliverySent      DeliveryStatus = "sent"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryRejected  DeliveryStatus = "rejected"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// Delivery is the provider's answer to a dispatched notification
+type Delivery struct {
+	Status DeliveryStatus
+
+	// MessageID is the provider's ID of the message, for looking it up in
+	// the provider's logs
+	MessageID string
+}
+
+// TraceNotification runs fn, dispatching n, in a producer span named
+// "notify.<channel> <template>" recording the provider, template and the
+// delivery status fn reports. The span is errored when fn fails or the
+// notification is rejected or failed.
+func TraceNotification(ctx context.Context, tracer Tracer, n Notification, fn func(ctx context.Context) (Delivery, error)) (Delivery, error) {
+	ctx, span := tracer.Start(ctx, notificationSpanName(n), WithSpanKind(SpanKindProducer), WithAttributes(n.attributes()))
+	defer span.End()
+
+	delivery, err := fn(ctx)
+	if delivery.Status != "" {
+		span.SetTagString(NotificationStatusAttribute, string(delivery.Status))
+	}
+	if delivery.MessageID != "" {
+		span.SetTagString(NotificationMessageIDAttribute, delivery.MessageID)
+	}
+	switch {
+	case err != nil:
+		span.SetError(err)
+	case delivery.Status == DeliveryRejected || delivery.Status == DeliveryFailed:
+		span.SetError(fmt.Errorf("notification %s", delivery.Status))
+	}
+	return delivery, err
+}
+
+// notificationSpanName returns the span name of n
+func notificationSpanName(n Notification) string {
+	name := "notify." + n.Channel
+	if n.Template != "" {
+		name += " " + n.Template
+	}
+	return name
+}
+
+// attributes returns the span attributes of n
+func (n Notification) attributes() map[string]any {
+	attrs := map[string]any{NotificationChannelAttribute: n.Channel}
+	if n.Provider != "" {
+		attrs[NotificationProviderAttribute] = n.Provider
+	}
+	if n.Template != "" {
+		attrs[NotificationTemplateAttribute] = n.Template
+	}
+	return attrs
+}
+
+// WebhookTraceContextKey is the webhook payload field carrying trace context
+const WebhookTraceContextKey = "trace_context"
+
+// InjectWebhookPayload sets the trace context of ctx in payload, under
+// WebhookTraceContextKey in the format of MarshalContext, so receivers that
+// queue webhooks before handling them can still continue the trace. It
+// does nothing when ctx carries no trace context.
+func InjectWebhookPayload(ctx context.Context, payload map[string]any) {
+	if data := MarshalContext(ctx); data != nil {
+		payload[WebhookTraceContextKey] = json.RawMessage(data)
+	}
+}
+
+// ExtractWebhookPayload restores the trace context injected into a webhook
+// body by InjectWebhookPayload. A body without one yields a background
+// context.
+func ExtractWebhookPayload(body []byte) (context.Context, error) {
+	var payload struct {
+		TraceContext json.RawMessage `json:"trace_context"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return context.Background(), fmt.Errorf("failed to unmarshal webhook payload: %w", err)
+	}
+	return UnmarshalContext(payload.TraceContext)
+}
@@ -0,0 +1,114 @@
+package tracingx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceNotification(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("records a delivered notification", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		delivery, err := TraceNotification(context.Background(), spy, Notification{
+			Channel:  "email",
+			Provider: "sendgrid",
+			Template: "password_reset",
+		}, func(ctx context.Context) (Delivery, error) {
+			return Delivery{Status: DeliveryQueued, MessageID: "msg-42"}, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, DeliveryQueued, delivery.Status)
+
+		span := spy.started()[0]
+		ro := span.(*otlpSpan).span.(sdktrace.ReadOnlySpan)
+		assert.Equal(t, "notify.email password_reset", ro.Name())
+		assert.Equal(t, trace.SpanKindProducer, ro.SpanKind())
+
+		attrs := attributesOf(t, span)
+		assert.Equal(t, "email", attrs[NotificationChannelAttribute])
+		assert.Equal(t, "sendgrid", attrs[NotificationProviderAttribute])
+		assert.Equal(t, "password_reset", attrs[NotificationTemplateAttribute])
+		assert.Equal(t, "queued", attrs[NotificationStatusAttribute])
+		assert.Equal(t, "msg-42", attrs[NotificationMessageIDAttribute])
+		assert.NotContains(t, attrs, "error")
+	})
+
+	t.Run("errors rejected notifications", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		_, err := TraceNotification(context.Background(), spy, Notification{Channel: "sms", Provider: "twilio"}, func(ctx context.Context) (Delivery, error) {
+			return Delivery{Status: DeliveryRejected}, nil
+		})
+		require.NoError(t, err)
+
+		span := spy.started()[0]
+		assert.Equal(t, "notify.sms", span.(*otlpSpan).span.(sdktrace.ReadOnlySpan).Name())
+		attrs := attributesOf(t, span)
+		assert.Equal(t, "rejected", attrs[NotificationStatusAttribute])
+		assert.Equal(t, true, attrs["error"])
+		assert.NotContains(t, attrs, NotificationTemplateAttribute)
+	})
+
+	t.Run("records errors", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		_, err := TraceNotification(context.Background(), spy, Notification{Channel: "webhook"}, func(ctx context.Context) (Delivery, error) {
+			return Delivery{}, errors.New("timeout")
+		})
+		require.Error(t, err)
+		attrs := attributesOf(t, spy.started()[0])
+		assert.Equal(t, true, attrs["error"])
+		assert.NotContains(t, attrs, NotificationStatusAttribute)
+	})
+}
+
+func TestWebhookPayload(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("round trips trace context through the payload", func(t *testing.T) {
+		ctx := ContextWithCorrelationID(context.Background(), "req-7")
+		ctx, sender := provider.Start(ctx, "notify.webhook", WithSpanKind(SpanKindProducer))
+		defer sender.End()
+
+		payload := map[string]any{"event": "order.shipped", "order_id": 42}
+		InjectWebhookPayload(ctx, payload)
+		body, err := json.Marshal(payload)
+		require.NoError(t, err)
+		assert.Contains(t, string(body), `"trace_context":{"traceparent":"00-`+sender.TraceID())
+
+		restored, err := ExtractWebhookPayload(body)
+		require.NoError(t, err)
+		sc := trace.SpanContextFromContext(restored)
+		assert.Equal(t, sender.TraceID(), sc.TraceID().String())
+		assert.Equal(t, sender.SpanID(), sc.SpanID().String())
+		assert.Equal(t, "req-7", CorrelationIDFromContext(restored))
+	})
+
+	t.Run("leaves payloads untouched without trace context", func(t *testing.T) {
+		payload := map[string]any{"event": "order.shipped"}
+		InjectWebhookPayload(context.Background(), payload)
+		assert.NotContains(t, payload, WebhookTraceContextKey)
+
+		restored, err := ExtractWebhookPayload([]byte(`{"event":"order.shipped"}`))
+		require.NoError(t, err)
+		assert.False(t, trace.SpanContextFromContext(restored).IsValid())
+	})
+
+	t.Run("rejects malformed bodies", func(t *testing.T) {
+		_, err := ExtractWebhookPayload([]byte("not json"))
+		assert.Error(t, err)
+
+		_, err = ExtractWebhookPayload([]byte(`{"trace_context":{"traceparent":"garbage"}}`))
+		assert.True(t, errors.Is(err, ErrInvalidTraceParent))
+	})
+}
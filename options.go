@@ -0,0 +1,102 @@
+package tracingx
+
+import sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+// providerOptions carries optional dependencies injected into providers
+type providerOptions struct {
+	requestID   RequestIDFunc
+	peerService PeerServiceResolver
+	auditSink   AuditSink
+	exporter    sdktrace.SpanExporter
+	clock       Clock
+	propagators []Propagator
+	processors  []SpanProcessor
+}
+
+// providerOption configures optional provider dependencies
+type providerOption func(*providerOptions)
+
+// withRequestIDFunc sets the function used to read request IDs from contexts
+func withRequestIDFunc(fn RequestIDFunc) providerOption {
+	return func(o *providerOptions) {
+		o.requestID = fn
+	}
+}
+
+// withPeerServiceResolver sets the function resolving peer.service names
+func withPeerServiceResolver(fn PeerServiceResolver) providerOption {
+	return func(o *providerOptions) {
+		o.peerService = fn
+	}
+}
+
+// withAuditSink sets the sink receiving audit records
+func withAuditSink(sink AuditSink) providerOption {
+	return func(o *providerOptions) {
+		o.auditSink = sink
+	}
+}
+
+// withExporter sets the exporter replacing the configured one
+func withExporter(exporter sdktrace.SpanExporter) providerOption {
+	return func(o *providerOptions) {
+		o.exporter = exporter
+	}
+}
+
+// withClock sets the clock used for span timestamps and X-Ray trace IDs
+func withClock(clock Clock) providerOption {
+	return func(o *providerOptions) {
+		o.clock = clock
+	}
+}
+
+// withPropagators adds custom propagators
+func withPropagators(propagators ...Propagator) providerOption {
+	return func(o *providerOptions) {
+		o.propagators = append(o.propagators, propagators...)
+	}
+}
+
+// withSpanProcessors adds span processors run before export
+func withSpanProcessors(processors ...SpanProcessor) providerOption {
+	return func(o *providerOptions) {
+		o.processors = append(o.processors, processors...)
+	}
+}
+
+// applyProviderOptions applies provider options and returns the result
+func applyProviderOptions(opts ...providerOption) providerOptions {
+	o := providerOptions{clock: systemClock{}}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// providerOptions converts the optional fx dependencies into provider options
+func (p Params) providerOptions() []providerOption {
+	var opts []providerOption
+	if p.RequestID != nil {
+		opts = append(opts, withRequestIDFunc(p.RequestID))
+	}
+	if p.PeerService != nil {
+		opts = append(opts, withPeerServiceResolver(p.PeerService))
+	}
+	if p.AuditSink != nil {
+		opts = append(opts, withAuditSink(p.AuditSink))
+	}
+	if p.Exporter != nil {
+		opts = append(opts, withExporter(p.Exporter))
+	}
+	if p.Clock != nil {
+		opts = append(opts, withClock(p.Clock))
+	}
+	if len(p.Propagators) > 0 {
+		opts = append(opts, withPropagators(p.Propagators...))
+	}
+	if len(p.SpanProcessors) > 0 {
+		opts = append(opts, withSpanProcessors(p.SpanProcessors...))
+	}
+	return opts
+}
@@ -0,0 +1,86 @@
+package tracingx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// outboxPropagator serializes trace context for storage independently of the
+// globally configured propagator, so stored records stay readable when the
+// wire format changes
+var outboxPropagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{},
+	propagation.Baggage{},
+)
+
+// storedContext is the persisted form of a trace context
+type storedContext struct {
+	TraceParent   string `json:"traceparent,omitempty"`
+	TraceState    string `json:"tracestate,omitempty"`
+	Baggage       string `json:"baggage,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+}
+
+// MarshalContext serializes the trace context, baggage and correlation ID of
+// ctx for storage, e.g. in a transactional outbox table next to the event. It
+// returns nil if ctx carries no trace context.
+func MarshalContext(ctx context.Context) []byte {
+	carrier := propagation.MapCarrier{}
+	outboxPropagator.Inject(ctx, carrier)
+
+	stored := storedContext{
+		TraceParent:   carrier.Get("traceparent"),
+		TraceState:    carrier.Get("tracestate"),
+		Baggage:       carrier.Get("baggage"),
+		CorrelationID: CorrelationIDFromContext(ctx),
+	}
+	if stored == (storedContext{}) {
+		return nil
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// UnmarshalContext restores a trace context serialized by MarshalContext, so
+// the relay publishing an outbox record continues the trace that wrote it.
+// Spans started from the returned context are children of the stored span.
+// Empty data yields a background context.
+func UnmarshalContext(data []byte) (context.Context, error) {
+	ctx := context.Background()
+	if len(data) == 0 {
+		return ctx, nil
+	}
+
+	var stored storedContext
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return ctx, fmt.Errorf("failed to unmarshal trace context: %w", err)
+	}
+	if stored.TraceParent != "" {
+		if err := ValidateTraceParent(stored.TraceParent); err != nil {
+			return ctx, fmt.Errorf("failed to unmarshal trace context: %w", err)
+		}
+	}
+
+	carrier := propagation.MapCarrier{}
+	for key, value := range map[string]string{
+		"traceparent": stored.TraceParent,
+		"tracestate":  stored.TraceState,
+		"baggage":     stored.Baggage,
+	} {
+		if value != "" {
+			carrier.Set(key, value)
+		}
+	}
+	ctx = outboxPropagator.Extract(ctx, carrier)
+	if stored.CorrelationID != "" {
+		ctx = ContextWithCorrelationID(ctx, stored.CorrelationID)
+	}
+	return ctx, nil
+}
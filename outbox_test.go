@@ -0,0 +1,61 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestMarshalContext(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("round trips trace context, baggage and correlation ID", func(t *testing.T) {
+		ctx := baggage.ContextWithBaggage(context.Background(), newTestBaggage(t, "tenant", "acme"))
+		ctx = ContextWithCorrelationID(ctx, "req-123")
+		ctx, writer := provider.Start(ctx, "write-order")
+		defer writer.End()
+
+		data := MarshalContext(ctx)
+		require.NotEmpty(t, data)
+
+		restored, err := UnmarshalContext(data)
+		require.NoError(t, err)
+
+		sc := trace.SpanContextFromContext(restored)
+		assert.Equal(t, writer.TraceID(), sc.TraceID().String())
+		assert.Equal(t, writer.SpanID(), sc.SpanID().String())
+		assert.True(t, sc.IsRemote())
+		assert.Equal(t, "acme", baggage.FromContext(restored).Member("tenant").Value())
+		assert.Equal(t, "req-123", CorrelationIDFromContext(restored))
+
+		_, relay := provider.Start(restored, "publish-order", WithSpanKind(SpanKindProducer))
+		defer relay.End()
+		assert.Equal(t, writer.TraceID(), relay.TraceID())
+		assert.Equal(t, writer.SpanID(), relay.ParentSpanID())
+	})
+
+	t.Run("returns nil without trace context", func(t *testing.T) {
+		assert.Nil(t, MarshalContext(context.Background()))
+	})
+
+	t.Run("empty data yields background context", func(t *testing.T) {
+		ctx, err := UnmarshalContext(nil)
+		require.NoError(t, err)
+		assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+	})
+
+	t.Run("rejects malformed data", func(t *testing.T) {
+		_, err := UnmarshalContext([]byte("not json"))
+		assert.Error(t, err)
+
+		_, err = UnmarshalContext([]byte(`{"traceparent":"garbage"}`))
+		assert.True(t, errors.Is(err, ErrInvalidTraceParent))
+	})
+}
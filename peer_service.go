@@ -0,0 +1,80 @@
+package tracingx
+
+import (
+	"fmt"
+	"maps"
+	"net"
+)
+
+// PeerServiceResolver returns the logical name of the service listening on
+// address, host:port or a bare host when the port is unknown, or an empty
+// string when it does not know it. It is called as client spans start, so
+// it must be safe for concurrent use and answer from memory, e.g. from a
+// service discovery cache.
+type PeerServiceResolver func(address string) string
+
+// PeerServiceConfig maps the destinations of client spans to logical service
+// names, so service maps show payments-api instead of 10.0.4.12:8443
+type PeerServiceConfig struct {
+	// Services maps destination addresses, host:port or host, to the
+	// peer.service name recorded on client spans calling them
+	Services map[string]string `mapstructure:"services"`
+}
+
+// peerServices resolves the peer.service name of client spans from their
+// server.address and server.port
+type peerServices struct {
+	services map[string]string
+	resolver PeerServiceResolver
+}
+
+// newPeerServices returns the peer.service resolution configured, nil when
+// there is none. The peer.service lookups of client attribute profiles,
+// taken from lookups, extend Services, whose entries win.
+func newPeerServices(config PeerServiceConfig, lookups map[string]string, resolver PeerServiceResolver) *peerServices {
+	if len(config.Services) == 0 && len(lookups) == 0 && resolver == nil {
+		return nil
+	}
+	services := make(map[string]string, len(config.Services)+len(lookups))
+	maps.Copy(services, lookups)
+	maps.Copy(services, config.Services)
+	return &peerServices{services: services, resolver: resolver}
+}
+
+// apply sets peer.service in attrs, the start attributes of a client span,
+// unless it is set already or the destination is unknown
+func (p *peerServices) apply(attrs map[string]any) {
+	if p == nil {
+		return
+	}
+	if _, set := attrs[peerServiceKey]; set {
+		return
+	}
+	address, ok := attrs[serverAddressKey].(string)
+	if !ok || address == "" {
+		return
+	}
+	if port, ok := attrs[serverPortKey]; ok {
+		address = net.JoinHostPort(address, fmt.Sprint(port))
+	}
+	if name := p.resolve(address); name != "" {
+		attrs[peerServiceKey] = name
+	}
+}
+
+// resolve returns the service name of address, trying the configured map by
+// host:port, then by host, then the resolver
+func (p *peerServices) resolve(address string) string {
+	if name, ok := p.services[address]; ok {
+		return name
+	}
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		if name, ok := p.services[host]; ok {
+			return name
+		}
+	}
+	if p.resolver != nil {
+		return p.resolver(address)
+	}
+	return ""
+}
@@ -0,0 +1,129 @@
+package tracingx
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerServices(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	address := server.Listener.Addr().String()
+	_, port, err := net.SplitHostPort(address)
+	require.NoError(t, err)
+
+	get := func(t *testing.T, provider Provider) map[string]any {
+		t.Helper()
+		spy := &spyTracer{Tracer: provider}
+		client := &http.Client{Transport: NewTransport(spy, nil)}
+		resp, err := client.Get(server.URL + "/charges")
+		require.NoError(t, err)
+		resp.Body.Close()
+		return attributesOf(t, spy.started()[0])
+	}
+
+	t.Run("resolves client spans from the configured map", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			PeerService: PeerServiceConfig{Services: map[string]string{address: "payments-api"}},
+		}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		attrs := get(t, provider)
+		assert.Equal(t, "payments-api", attrs[peerServiceKey])
+		assert.Equal(t, port, fmt.Sprint(attrs[serverPortKey]))
+	})
+
+	t.Run("falls back to the resolver", func(t *testing.T) {
+		var asked []string
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger(),
+			withPeerServiceResolver(func(address string) string {
+				asked = append(asked, address)
+				return "ledger"
+			}),
+		)
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		assert.Equal(t, "ledger", get(t, provider)[peerServiceKey])
+		assert.Equal(t, []string{address}, asked)
+	})
+
+	t.Run("takes the peer.service lookups of profiles", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			PeerService: PeerServiceConfig{Services: map[string]string{"10.0.4.12": "payments-api"}},
+			Attributes: AttributesConfig{Profiles: []AttributeProfile{{
+				Kind: "client",
+				Lookups: []AttributeLookup{{
+					Key:    peerServiceKey,
+					From:   serverAddressKey,
+					Values: map[string]string{"10.0.4.12": "payments-legacy", "10.0.4.13": "ledger"},
+				}},
+			}}},
+		}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+		assert.Empty(t, provider.(*otlpProvider).current().profiles[SpanKindClient][0].Lookups)
+
+		for address, expected := range map[string]string{"10.0.4.12": "payments-api", "10.0.4.13": "ledger"} {
+			_, span := provider.Start(context.Background(), "call", WithSpanKind(SpanKindClient), WithAttributes(map[string]any{
+				serverAddressKey: address,
+				serverPortKey:    8443,
+			}))
+			assert.Equal(t, expected, attributesOf(t, span)[peerServiceKey], address)
+			span.End()
+		}
+	})
+
+	t.Run("leaves other spans alone", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			PeerService: PeerServiceConfig{Services: map[string]string{"10.0.4.12": "payments-api"}},
+		}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		attrs := map[string]any{serverAddressKey: "10.0.4.12"}
+		_, server := provider.Start(context.Background(), "GET /", WithSpanKind(SpanKindServer), WithAttributes(attrs))
+		defer server.End()
+		assert.NotContains(t, attributesOf(t, server), peerServiceKey)
+
+		_, named := provider.Start(context.Background(), "charge", WithSpanKind(SpanKindClient), WithAttributes(map[string]any{
+			serverAddressKey: "10.0.4.12",
+			peerServiceKey:   "stripe",
+		}))
+		defer named.End()
+		assert.Equal(t, "stripe", attributesOf(t, named)[peerServiceKey])
+	})
+}
+
+func TestPeerServicesResolve(t *testing.T) {
+	p := newPeerServices(PeerServiceConfig{Services: map[string]string{
+		"10.0.4.12:8443": "payments-api",
+		"10.0.4.12":      "payments-admin",
+		"db.internal":    "orders-db",
+	}}, nil, nil)
+
+	assert.Equal(t, "payments-api", p.resolve("10.0.4.12:8443"))
+	assert.Equal(t, "payments-admin", p.resolve("10.0.4.12:9000"))
+	assert.Equal(t, "orders-db", p.resolve("db.internal"))
+	assert.Empty(t, p.resolve("10.9.9.9:80"))
+
+	assert.Nil(t, newPeerServices(PeerServiceConfig{}, nil, nil))
+
+	attrs := map[string]any{serverAddressKey: "10.0.4.12", serverPortKey: 8443}
+	p.apply(attrs)
+	assert.Equal(t, "payments-api", attrs[peerServiceKey])
+}
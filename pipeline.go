@@ -0,0 +1,60 @@
+package tracingx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// PipelineConfig configures an additional export pipeline with its own
+// filter and sampling, running next to the main pipeline
+type PipelineConfig struct {
+	// Name identifies the pipeline in logs and diagnostics
+	Name string `mapstructure:"name"`
+
+	// Filter selects the spans this pipeline exports: "all" or "errors"
+	Filter string `mapstructure:"filter" default:"all"`
+
+	// SampleRate is the fraction of matching traces exported (0.0 to 1.0)
+	SampleRate float64 `mapstructure:"sample_rate" default:"1.0"`
+
+	// OTLP configures the pipeline's exporter
+	OTLP OTLPConfig `mapstructure:"otlp"`
+}
+
+// matcher returns the span filter selected by the pipeline
+func (c PipelineConfig) matcher() (func(s sdktrace.ReadOnlySpan) bool, error) {
+	switch strings.ToLower(c.Filter) {
+	case "", "all":
+		return func(sdktrace.ReadOnlySpan) bool { return true }, nil
+	case "errors":
+		return isErrorSpan, nil
+	default:
+		return nil, fmt.Errorf("pipeline %q: unknown filter %q", c.Name, c.Filter)
+	}
+}
+
+// newPipelineProcessor builds the processor chain of an additional pipeline:
+// export filter, pipeline filter and trace ID ratio sampling in front of
+// sibling compression and a batching OTLP exporter. The pipeline's export
+// workers are returned too, for warm-up.
+func newPipelineProcessor(ctx context.Context, config PipelineConfig, exportFilter func(s sdktrace.ReadOnlySpan) bool, compression CompressionConfig, warm bool) (sdktrace.SpanProcessor, []exportWorker, error) {
+	match, err := config.matcher()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	workers, err := newExportWorkers(ctx, config.OTLP, warm, systemClock{}, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("pipeline %q: %w", config.Name, err)
+	}
+
+	sampler := sdktrace.TraceIDRatioBased(config.SampleRate)
+	batcher := newWorkerProcessor(workers)
+	next := newCompressionProcessor(&forceSampledProcessor{next: batcher}, compression)
+	return newFilterProcessor(next, func(s sdktrace.ReadOnlySpan) bool {
+		return exportFilter(s) && match(s) && traceSampled(sampler, s.SpanContext().TraceID())
+	}), workers, nil
+}
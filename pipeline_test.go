@@ -0,0 +1,53 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPipelineConfigMatcher(t *testing.T) {
+	t.Run("defaults to all spans", func(t *testing.T) {
+		match, err := PipelineConfig{}.matcher()
+		require.NoError(t, err)
+		assert.NotNil(t, match)
+	})
+
+	t.Run("supports errors filter", func(t *testing.T) {
+		match, err := PipelineConfig{Filter: "Errors"}.matcher()
+		require.NoError(t, err)
+		assert.NotNil(t, match)
+	})
+
+	t.Run("rejects unknown filters", func(t *testing.T) {
+		_, err := PipelineConfig{Name: "p", Filter: "slow"}.matcher()
+		assert.Error(t, err)
+	})
+}
+
+func TestOTLPProviderWithPipelines(t *testing.T) {
+	t.Run("creates additional pipelines", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			SampleRate:  0.1,
+			Pipelines: []PipelineConfig{
+				{Name: "errors", Filter: "errors", SampleRate: 1.0, OTLP: OTLPConfig{Endpoint: "localhost:4317", Insecure: true}},
+			},
+		}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		_, span := provider.Start(context.Background(), "op")
+		span.End()
+	})
+
+	t.Run("fails on invalid pipeline", func(t *testing.T) {
+		_, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			Pipelines:   []PipelineConfig{{Name: "bad", Filter: "nope"}},
+		}, getTestLogger())
+		assert.Error(t, err)
+	})
+}
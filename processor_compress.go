@@ -0,0 +1,244 @@
+package tracingx
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attributes recorded on a span representing a compressed run of siblings
+const (
+	CompressedCountAttribute         = "compressed.count"
+	CompressedDurationSumMsAttribute = "compressed.duration_sum_ms"
+)
+
+// CompressionConfig configures collapsing of repetitive sibling spans
+type CompressionConfig struct {
+	// Enabled collapses consecutive identical short sibling spans (same name,
+	// kind and attributes, e.g. hundreds of redis.GET calls) into one span
+	Enabled bool `mapstructure:"enabled" default:"false"`
+
+	// MaxDuration is the longest span eligible for compression
+	MaxDuration time.Duration `mapstructure:"max_duration" default:"5ms"`
+
+	// MaxDelay is the longest a run is held back before it is exported,
+	// e.g. when its parent is never exported or ends before its children
+	MaxDelay time.Duration `mapstructure:"max_delay" default:"1s"`
+
+	// MaxCount is the most spans compressed into one; a full run is
+	// exported and the next sibling starts a new one
+	MaxCount int `mapstructure:"max_count" default:"1000"`
+}
+
+// maxDelay returns MaxDelay, one second when unset
+func (c CompressionConfig) maxDelay() time.Duration {
+	if c.MaxDelay <= 0 {
+		return time.Second
+	}
+	return c.MaxDelay
+}
+
+// maxCount returns MaxCount, 1000 when unset
+func (c CompressionConfig) maxCount() int {
+	if c.MaxCount <= 0 {
+		return 1000
+	}
+	return c.MaxCount
+}
+
+// compressionProcessor buffers runs of identical short leaf spans per parent
+// and forwards each run to next as a single span once the run is broken by a
+// different sibling, the parent ends, the run is full or it has been held
+// for maxDelay
+type compressionProcessor struct {
+	next        sdktrace.SpanProcessor
+	maxDuration time.Duration
+	maxDelay    time.Duration
+	maxCount    int
+
+	mu      sync.Mutex
+	pending map[trace.SpanID]*compressedRun
+}
+
+// newCompressionProcessor wraps next with sibling compression, or returns
+// next unchanged when compression is disabled
+func newCompressionProcessor(next sdktrace.SpanProcessor, config CompressionConfig) sdktrace.SpanProcessor {
+	if !config.Enabled {
+		return next
+	}
+	return &compressionProcessor{
+		next:        next,
+		maxDuration: config.MaxDuration,
+		maxDelay:    config.maxDelay(),
+		maxCount:    config.maxCount(),
+		pending:     make(map[trace.SpanID]*compressedRun),
+	}
+}
+
+func (p *compressionProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p *compressionProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	var out []sdktrace.ReadOnlySpan
+	parentID := s.Parent().SpanID()
+
+	p.mu.Lock()
+	if p.compressible(s) {
+		run := p.pending[parentID]
+		if run != nil && run.matches(s) {
+			run.add(s)
+			if run.count < p.maxCount {
+				p.mu.Unlock()
+				return
+			}
+			// Full: exported now, the next sibling starts a new run
+			run.timer.Stop()
+			delete(p.pending, parentID)
+			p.mu.Unlock()
+			p.next.OnEnd(run.span())
+			return
+		}
+		if run != nil {
+			run.timer.Stop()
+			out = append(out, run.span())
+		}
+		p.pending[parentID] = p.newRun(parentID, s)
+	} else {
+		// A different sibling breaks the run; an ending parent flushes its children
+		for _, id := range []trace.SpanID{parentID, s.SpanContext().SpanID()} {
+			if run, ok := p.pending[id]; ok {
+				run.timer.Stop()
+				out = append(out, run.span())
+				delete(p.pending, id)
+			}
+		}
+		out = append(out, s)
+	}
+	p.mu.Unlock()
+
+	for _, span := range out {
+		p.next.OnEnd(span)
+	}
+}
+
+func (p *compressionProcessor) Shutdown(ctx context.Context) error {
+	p.flush()
+	return p.next.Shutdown(ctx)
+}
+
+func (p *compressionProcessor) ForceFlush(ctx context.Context) error {
+	p.flush()
+	return p.next.ForceFlush(ctx)
+}
+
+// flush forwards every pending run
+func (p *compressionProcessor) flush() {
+	p.mu.Lock()
+	runs := p.pending
+	p.pending = make(map[trace.SpanID]*compressedRun)
+	p.mu.Unlock()
+
+	for _, run := range runs {
+		run.timer.Stop()
+		p.next.OnEnd(run.span())
+	}
+}
+
+// newRun starts the run of s under parentID, exported after maxDelay unless
+// it is forwarded sooner
+func (p *compressionProcessor) newRun(parentID trace.SpanID, s sdktrace.ReadOnlySpan) *compressedRun {
+	run := newCompressedRun(s)
+	run.timer = time.AfterFunc(p.maxDelay, func() { p.expire(parentID, run) })
+	return run
+}
+
+// expire forwards run if it is still pending under parentID
+func (p *compressionProcessor) expire(parentID trace.SpanID, run *compressedRun) {
+	p.mu.Lock()
+	if p.pending[parentID] != run {
+		p.mu.Unlock()
+		return
+	}
+	delete(p.pending, parentID)
+	p.mu.Unlock()
+	p.next.OnEnd(run.span())
+}
+
+// compressible reports whether s is a short, error-free leaf span with a local parent
+func (p *compressionProcessor) compressible(s sdktrace.ReadOnlySpan) bool {
+	parent := s.Parent()
+	if !parent.IsValid() || parent.IsRemote() {
+		return false
+	}
+	return s.ChildSpanCount() == 0 && !isErrorSpan(s) && s.EndTime().Sub(s.StartTime()) <= p.maxDuration
+}
+
+// compressedRun is a run of identical sibling spans
+type compressedRun struct {
+	first sdktrace.ReadOnlySpan
+	attrs attribute.Set
+	count int
+	sum   time.Duration
+	end   time.Time
+	// timer exports the run once it has been held for the maximum delay
+	timer *time.Timer
+}
+
+func newCompressedRun(s sdktrace.ReadOnlySpan) *compressedRun {
+	return &compressedRun{
+		first: s,
+		attrs: attribute.NewSet(s.Attributes()...),
+		count: 1,
+		sum:   s.EndTime().Sub(s.StartTime()),
+		end:   s.EndTime(),
+	}
+}
+
+// matches reports whether s has the same shape as the spans in the run
+func (r *compressedRun) matches(s sdktrace.ReadOnlySpan) bool {
+	if s.Name() != r.first.Name() || s.SpanKind() != r.first.SpanKind() {
+		return false
+	}
+	attrs := attribute.NewSet(s.Attributes()...)
+	return r.attrs.Equals(&attrs)
+}
+
+func (r *compressedRun) add(s sdktrace.ReadOnlySpan) {
+	r.count++
+	r.sum += s.EndTime().Sub(s.StartTime())
+	if s.EndTime().After(r.end) {
+		r.end = s.EndTime()
+	}
+}
+
+// span returns the span representing the run
+func (r *compressedRun) span() sdktrace.ReadOnlySpan {
+	if r.count == 1 {
+		return r.first
+	}
+	return compressedSpan{ReadOnlySpan: r.first, run: r}
+}
+
+// compressedSpan presents a run of siblings as its first span, extended to
+// the end of the last one and annotated with the run's count and total duration
+type compressedSpan struct {
+	sdktrace.ReadOnlySpan
+	run *compressedRun
+}
+
+func (s compressedSpan) EndTime() time.Time {
+	return s.run.end
+}
+
+func (s compressedSpan) Attributes() []attribute.KeyValue {
+	attrs := append([]attribute.KeyValue(nil), s.ReadOnlySpan.Attributes()...)
+	return append(attrs,
+		attribute.Int(CompressedCountAttribute, s.run.count),
+		attribute.Float64(CompressedDurationSumMsAttribute, float64(s.run.sum)/float64(time.Millisecond)),
+	)
+}
@@ -0,0 +1,146 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestCompressionProcessor(t *testing.T) {
+	newTracer := func(t *testing.T) (trace.Tracer, *tracetest.SpanRecorder) {
+		recorder := tracetest.NewSpanRecorder()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(
+			newCompressionProcessor(recorder, CompressionConfig{Enabled: true, MaxDuration: 5 * time.Millisecond}),
+		))
+		t.Cleanup(func() { tp.Shutdown(context.Background()) })
+		return tp.Tracer("test"), recorder
+	}
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	child := func(ctx context.Context, tracer trace.Tracer, name string, offset, duration time.Duration, attrs ...attribute.KeyValue) trace.Span {
+		_, span := tracer.Start(ctx, name, trace.WithTimestamp(start.Add(offset)), trace.WithAttributes(attrs...))
+		span.End(trace.WithTimestamp(start.Add(offset + duration)))
+		return span
+	}
+
+	t.Run("collapses identical short siblings", func(t *testing.T) {
+		tracer, recorder := newTracer(t)
+		ctx, parent := tracer.Start(context.Background(), "handler", trace.WithTimestamp(start))
+		for i := 0; i < 5; i++ {
+			child(ctx, tracer, "redis.GET", time.Duration(i)*2*time.Millisecond, time.Millisecond, attribute.String("db.system", "redis"))
+		}
+		assert.Empty(t, recorder.Ended())
+		parent.End()
+
+		ended := recorder.Ended()
+		require.Len(t, ended, 2)
+		compressed := ended[0]
+		assert.Equal(t, "redis.GET", compressed.Name())
+		assert.Equal(t, start, compressed.StartTime())
+		assert.Equal(t, start.Add(9*time.Millisecond), compressed.EndTime())
+
+		attrs := map[string]any{}
+		for _, kv := range compressed.Attributes() {
+			attrs[string(kv.Key)] = kv.Value.AsInterface()
+		}
+		assert.Equal(t, int64(5), attrs[CompressedCountAttribute])
+		assert.Equal(t, 5.0, attrs[CompressedDurationSumMsAttribute])
+		assert.Equal(t, "redis", attrs["db.system"])
+		assert.Equal(t, "handler", ended[1].Name())
+	})
+
+	t.Run("different sibling breaks the run", func(t *testing.T) {
+		tracer, recorder := newTracer(t)
+		ctx, parent := tracer.Start(context.Background(), "handler")
+		child(ctx, tracer, "redis.GET", 0, time.Millisecond)
+		child(ctx, tracer, "redis.GET", 0, time.Millisecond)
+		child(ctx, tracer, "redis.SET", 0, time.Millisecond)
+		child(ctx, tracer, "redis.GET", 0, time.Millisecond, attribute.String("key", "other"))
+		parent.End()
+
+		var names []string
+		for _, s := range recorder.Ended() {
+			names = append(names, s.Name())
+		}
+		assert.Equal(t, []string{"redis.GET", "redis.SET", "redis.GET", "handler"}, names)
+		assert.Len(t, recorder.Ended()[1].Attributes(), 0)
+	})
+
+	t.Run("keeps long and errored spans", func(t *testing.T) {
+		tracer, recorder := newTracer(t)
+		ctx, parent := tracer.Start(context.Background(), "handler")
+		child(ctx, tracer, "sql", 0, 10*time.Millisecond)
+		child(ctx, tracer, "sql", 0, 10*time.Millisecond)
+		_, failed := tracer.Start(ctx, "cache")
+		failed.RecordError(errors.New("boom"))
+		failed.SetAttributes(attribute.Bool("error", true))
+		failed.End()
+		assert.Len(t, recorder.Ended(), 3)
+		parent.End()
+		assert.Len(t, recorder.Ended(), 4)
+	})
+
+	t.Run("flushes pending runs on ForceFlush", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		processor := newCompressionProcessor(recorder, CompressionConfig{Enabled: true, MaxDuration: 5 * time.Millisecond})
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(processor))
+		defer tp.Shutdown(context.Background())
+		tracer := tp.Tracer("test")
+
+		ctx, parent := tracer.Start(context.Background(), "handler")
+		defer parent.End()
+		child(ctx, tracer, "redis.GET", 0, time.Millisecond)
+		child(ctx, tracer, "redis.GET", 0, time.Millisecond)
+
+		require.NoError(t, processor.ForceFlush(context.Background()))
+		require.Len(t, recorder.Ended(), 1)
+	})
+
+	t.Run("exports runs held for MaxDelay", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(
+			newCompressionProcessor(recorder, CompressionConfig{Enabled: true, MaxDuration: 5 * time.Millisecond, MaxDelay: 20 * time.Millisecond}),
+		))
+		defer tp.Shutdown(context.Background())
+		tracer := tp.Tracer("test")
+
+		// The parent ends before its children, so it cannot flush them
+		ctx, parent := tracer.Start(context.Background(), "handler")
+		parent.End()
+		child(ctx, tracer, "redis.GET", 0, time.Millisecond)
+		child(ctx, tracer, "redis.GET", 0, time.Millisecond)
+
+		require.Eventually(t, func() bool { return len(recorder.Ended()) == 2 }, time.Second, 5*time.Millisecond)
+		assert.Equal(t, "redis.GET", recorder.Ended()[1].Name())
+	})
+
+	t.Run("exports full runs", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(
+			newCompressionProcessor(recorder, CompressionConfig{Enabled: true, MaxDuration: 5 * time.Millisecond, MaxCount: 3}),
+		))
+		defer tp.Shutdown(context.Background())
+		tracer := tp.Tracer("test")
+
+		ctx, parent := tracer.Start(context.Background(), "handler")
+		defer parent.End()
+		for range 4 {
+			child(ctx, tracer, "redis.GET", 0, time.Millisecond)
+		}
+
+		require.Len(t, recorder.Ended(), 1, "the fourth span starts a new run")
+		assert.Contains(t, recorder.Ended()[0].Attributes(), attribute.Int(CompressedCountAttribute, 3))
+	})
+
+	t.Run("disabled returns next unchanged", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		assert.Same(t, recorder, newCompressionProcessor(recorder, CompressionConfig{}))
+	})
+}
@@ -0,0 +1,161 @@
+package tracingx
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// Steps of Config.Pipeline
+const (
+	stepRedact      = "redact"
+	stepDropHealth  = "drop_health"
+	stepMinDuration = "min_duration"
+	stepBatch       = "batch"
+)
+
+// redactedValue replaces the values of redacted attributes and event fields
+const redactedValue = "[redacted]"
+
+// defaultHealthPaths are the request paths dropped by the drop_health step
+// when none are configured
+var defaultHealthPaths = []string{"/health", "/healthz", "/livez", "/ready", "/readyz"}
+
+// grpcHealthService is the rpc.service of gRPC health checks
+const grpcHealthService = "grpc.health.v1.Health"
+
+// ProcessorsConfig holds the settings of the steps listed in Config.Pipeline
+type ProcessorsConfig struct {
+	// Redact configures the redact step
+	Redact RedactConfig `mapstructure:"redact"`
+
+	// DropHealth configures the drop_health step
+	DropHealth DropHealthConfig `mapstructure:"drop_health"`
+
+	// MinDuration configures the min_duration step
+	MinDuration MinDurationConfig `mapstructure:"min_duration"`
+}
+
+// RedactConfig configures the redact step, which replaces the values of
+// matching attributes and event fields with [redacted]
+type RedactConfig struct {
+	// Keys lists the attribute keys to redact, as path.Match patterns such
+	// as "http.request.header.*"
+	Keys []string `mapstructure:"keys"`
+}
+
+// DropHealthConfig configures the drop_health step, which drops health
+// check spans: requests to the listed paths and gRPC health checks
+type DropHealthConfig struct {
+	// Paths lists the url.path values of health check requests, as
+	// path.Match patterns (defaults to /health, /healthz, /livez, /ready
+	// and /readyz)
+	Paths []string `mapstructure:"paths"`
+}
+
+// MinDurationConfig configures the min_duration step, which drops spans
+// shorter than Threshold; root and errored spans are always kept
+type MinDurationConfig struct {
+	Threshold time.Duration `mapstructure:"threshold"`
+}
+
+// newPipelineSteps returns the span processors of the configured pipeline
+// steps, in order. The batch step, exporting the spans, is implicit when
+// omitted and must come last.
+func newPipelineSteps(steps []string, config ProcessorsConfig) ([]SpanProcessor, error) {
+	processors := make([]SpanProcessor, 0, len(steps))
+	for i, step := range steps {
+		step = strings.ToLower(strings.TrimSpace(step))
+		var process func(*FinishedSpan) bool
+		stage := StageFilter
+		switch step {
+		case stepRedact:
+			stage = StageRedact
+			if err := validatePatterns(config.Redact.Keys); err != nil {
+				return nil, fmt.Errorf("invalid pipeline step %s: %w", step, err)
+			}
+			process = redactKeys(config.Redact.Keys)
+		case stepDropHealth:
+			if err := validatePatterns(config.DropHealth.Paths); err != nil {
+				return nil, fmt.Errorf("invalid pipeline step %s: %w", step, err)
+			}
+			process = dropHealth(config.DropHealth.Paths)
+		case stepMinDuration:
+			process = minDuration(config.MinDuration.Threshold)
+		case stepBatch:
+			if i != len(steps)-1 {
+				return nil, fmt.Errorf("invalid pipeline: %s must be the last step", stepBatch)
+			}
+			continue
+		default:
+			return nil, fmt.Errorf("invalid pipeline: unknown step %q", step)
+		}
+		processors = append(processors, SpanProcessor{Name: step, Stage: stage, Process: process})
+	}
+	return processors, nil
+}
+
+// validatePatterns reports the first malformed path.Match pattern
+func validatePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// matchAny reports whether value matches one of patterns
+func matchAny(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(pattern, value); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// redactKeys returns a step redacting the attributes and event fields
+// matching keys
+func redactKeys(keys []string) func(*FinishedSpan) bool {
+	redact := func(attrs map[string]any) {
+		for key := range attrs {
+			if matchAny(keys, key) {
+				attrs[key] = redactedValue
+			}
+		}
+	}
+	return func(span *FinishedSpan) bool {
+		redact(span.Attributes)
+		for _, e := range span.Events {
+			redact(e.Attributes)
+		}
+		return true
+	}
+}
+
+// dropHealth returns a step dropping requests to paths and gRPC health checks
+func dropHealth(paths []string) func(*FinishedSpan) bool {
+	if len(paths) == 0 {
+		paths = defaultHealthPaths
+	}
+	return func(span *FinishedSpan) bool {
+		if span.Attributes[rpcServiceKey] == grpcHealthService {
+			return false
+		}
+		urlPath, ok := span.Attributes[urlPathKey].(string)
+		return !ok || !matchAny(paths, urlPath)
+	}
+}
+
+// minDuration returns a step dropping non-root, error-free spans shorter
+// than threshold
+func minDuration(threshold time.Duration) func(*FinishedSpan) bool {
+	return func(span *FinishedSpan) bool {
+		if span.ParentSpanID == "" || span.isError() {
+			return true
+		}
+		return span.EndTime.Sub(span.StartTime) >= threshold
+	}
+}
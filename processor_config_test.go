@@ -0,0 +1,134 @@
+package tracingx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestNewPipelineSteps(t *testing.T) {
+	t.Run("builds the steps in order", func(t *testing.T) {
+		steps, err := newPipelineSteps([]string{"min_duration", " Redact ", "drop_health", "batch"}, ProcessorsConfig{})
+		require.NoError(t, err)
+		var names []string
+		for _, step := range steps {
+			names = append(names, step.Name)
+		}
+		assert.Equal(t, []string{"min_duration", "redact", "drop_health"}, names)
+	})
+
+	t.Run("rejects unknown steps", func(t *testing.T) {
+		_, err := newPipelineSteps([]string{"sample"}, ProcessorsConfig{})
+		assert.ErrorContains(t, err, `unknown step "sample"`)
+	})
+
+	t.Run("requires batch to come last", func(t *testing.T) {
+		_, err := newPipelineSteps([]string{"batch", "redact"}, ProcessorsConfig{})
+		assert.ErrorContains(t, err, "batch must be the last step")
+	})
+
+	t.Run("rejects malformed patterns", func(t *testing.T) {
+		_, err := newPipelineSteps([]string{"redact"}, ProcessorsConfig{Redact: RedactConfig{Keys: []string{"["}}})
+		assert.Error(t, err)
+
+		_, err = newOTLPProvider(Config{ServiceName: "test-service", Pipeline: []string{"compress"}}, getTestLogger())
+		assert.Error(t, err)
+	})
+}
+
+func TestPipelineSteps(t *testing.T) {
+	t.Run("redact", func(t *testing.T) {
+		span := &FinishedSpan{
+			Attributes: map[string]any{"user.email": "a@example.com", "http.request.header.authorization": "Bearer x", "user.id": 7},
+			Events:     []FinishedEvent{{Name: "log", Attributes: map[string]any{"user.email": "a@example.com"}}},
+		}
+		assert.True(t, redactKeys([]string{"user.email", "http.request.header.*"})(span))
+		assert.Equal(t, map[string]any{
+			"user.email":                        redactedValue,
+			"http.request.header.authorization": redactedValue,
+			"user.id":                           7,
+		}, span.Attributes)
+		assert.Equal(t, redactedValue, span.Events[0].Attributes["user.email"])
+	})
+
+	t.Run("drop_health", func(t *testing.T) {
+		drop := dropHealth(nil)
+		assert.False(t, drop(&FinishedSpan{Attributes: map[string]any{urlPathKey: "/healthz"}}))
+		assert.False(t, drop(&FinishedSpan{Attributes: map[string]any{rpcServiceKey: grpcHealthService}}))
+		assert.True(t, drop(&FinishedSpan{Attributes: map[string]any{urlPathKey: "/orders"}}))
+		assert.True(t, drop(&FinishedSpan{Attributes: map[string]any{}}))
+
+		custom := dropHealth([]string{"/internal/*"})
+		assert.False(t, custom(&FinishedSpan{Attributes: map[string]any{urlPathKey: "/internal/ping"}}))
+		assert.True(t, custom(&FinishedSpan{Attributes: map[string]any{urlPathKey: "/healthz"}}))
+	})
+
+	t.Run("min_duration", func(t *testing.T) {
+		start := time.Now()
+		short := func(s *FinishedSpan) *FinishedSpan {
+			s.StartTime, s.EndTime = start, start.Add(time.Millisecond)
+			if s.Attributes == nil {
+				s.Attributes = map[string]any{}
+			}
+			return s
+		}
+		keep := minDuration(5 * time.Millisecond)
+		assert.False(t, keep(short(&FinishedSpan{ParentSpanID: "00f067aa0ba902b7"})))
+		assert.True(t, keep(short(&FinishedSpan{})))
+		assert.True(t, keep(short(&FinishedSpan{ParentSpanID: "00f067aa0ba902b7", Status: "Error"})))
+		assert.True(t, keep(short(&FinishedSpan{ParentSpanID: "00f067aa0ba902b7", Attributes: map[string]any{"error": true}})))
+	})
+}
+
+func TestConfiguredPipeline(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "test-service",
+		SampleRate:  1.0,
+		SpanLog:     SpanLogConfig{Enabled: true, Level: "info"},
+		Pipeline:    []string{"drop_health", "batch"},
+	}, logx.ProvideAdapter(zap.New(core)))
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	handler := NewMiddleware(provider)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	for _, path := range []string{"/healthz", "/orders"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	entries := logs.FilterMessage("span finished").All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "HTTP GET", entries[0].ContextMap()["span"])
+}
+
+func TestConfiguredPipelineStages(t *testing.T) {
+	var seen []any
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "test-service",
+		SampleRate:  1.0,
+		Pipeline:    []string{"redact"},
+		Processors:  ProcessorsConfig{Redact: RedactConfig{Keys: []string{"card.number"}}},
+	}, getTestLogger(), withSpanProcessors(SpanProcessor{
+		Name:  "inspect",
+		Stage: StageFilter,
+		Process: func(s *FinishedSpan) bool {
+			seen = append(seen, s.Attributes["card.number"])
+			return true
+		},
+	}))
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	_, span := provider.Start(context.Background(), "charge", WithAttributes(map[string]any{"card.number": "4111"}))
+	span.End()
+
+	assert.Equal(t, []any{redactedValue}, seen, "the redact step runs before code filters")
+}
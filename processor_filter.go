@@ -0,0 +1,84 @@
+package tracingx
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// filterProcessor forwards finished spans to next only when keep returns true
+type filterProcessor struct {
+	next sdktrace.SpanProcessor
+	keep func(s sdktrace.ReadOnlySpan) bool
+}
+
+// newFilterProcessor wraps next so only spans accepted by keep reach it
+func newFilterProcessor(next sdktrace.SpanProcessor, keep func(s sdktrace.ReadOnlySpan) bool) sdktrace.SpanProcessor {
+	return &filterProcessor{next: next, keep: keep}
+}
+
+func (p *filterProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p *filterProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if p.keep(s) {
+		p.next.OnEnd(s)
+	}
+}
+
+func (p *filterProcessor) Shutdown(ctx context.Context) error   { return p.next.Shutdown(ctx) }
+func (p *filterProcessor) ForceFlush(ctx context.Context) error { return p.next.ForceFlush(ctx) }
+
+// forceSampledProcessor presents every span to next as sampled, so batchers
+// export RecordOnly spans selected by a pipeline's own sampling
+type forceSampledProcessor struct {
+	next sdktrace.SpanProcessor
+}
+
+func (p *forceSampledProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	p.next.OnStart(parent, s)
+}
+
+func (p *forceSampledProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		s = sampledSpan{ReadOnlySpan: s}
+	}
+	p.next.OnEnd(s)
+}
+
+func (p *forceSampledProcessor) Shutdown(ctx context.Context) error   { return p.next.Shutdown(ctx) }
+func (p *forceSampledProcessor) ForceFlush(ctx context.Context) error { return p.next.ForceFlush(ctx) }
+
+// sampledSpan overrides the span context of a span to carry the sampled flag
+type sampledSpan struct {
+	sdktrace.ReadOnlySpan
+}
+
+func (s sampledSpan) SpanContext() trace.SpanContext {
+	sc := s.ReadOnlySpan.SpanContext()
+	return sc.WithTraceFlags(sc.TraceFlags().WithSampled(true))
+}
+
+// isErrorSpan reports whether a span ended with an error status or was
+// marked with the error attribute by SetError
+func isErrorSpan(s sdktrace.ReadOnlySpan) bool {
+	if s.Status().Code == codes.Error {
+		return true
+	}
+	for _, kv := range s.Attributes() {
+		if kv.Key == "error" && kv.Value.Type() == attribute.BOOL && kv.Value.AsBool() {
+			return true
+		}
+	}
+	return false
+}
+
+// traceSampled applies trace ID ratio sampling so every span of a trace gets
+// the same decision
+func traceSampled(sampler sdktrace.Sampler, traceID trace.TraceID) bool {
+	return sampler.ShouldSample(sdktrace.SamplingParameters{TraceID: traceID}).Decision == sdktrace.RecordAndSample
+}
@@ -0,0 +1,71 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestFilterProcessor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(newFilterProcessor(recorder, func(s sdktrace.ReadOnlySpan) bool {
+			return s.Name() != "noisy"
+		})),
+	)
+	defer tp.Shutdown(context.Background())
+
+	tracer := tp.Tracer("test")
+	_, keep := tracer.Start(context.Background(), "important")
+	keep.End()
+	_, drop := tracer.Start(context.Background(), "noisy")
+	drop.End()
+
+	assert.Len(t, recorder.Started(), 2)
+	ended := recorder.Ended()
+	require.Len(t, ended, 1)
+	assert.Equal(t, "important", ended[0].Name())
+}
+
+func TestForceSampledProcessor(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(newRecordAllSampler(sdktrace.NeverSample())),
+		sdktrace.WithSpanProcessor(&forceSampledProcessor{next: recorder}),
+	)
+	defer tp.Shutdown(context.Background())
+
+	_, span := tp.Tracer("test").Start(context.Background(), "record-only")
+	assert.False(t, span.SpanContext().IsSampled())
+	span.End()
+
+	ended := recorder.Ended()
+	require.Len(t, ended, 1)
+	assert.True(t, ended[0].SpanContext().IsSampled())
+	assert.Equal(t, span.SpanContext().SpanID(), ended[0].SpanContext().SpanID())
+}
+
+func TestIsErrorSpan(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	defer tp.Shutdown(context.Background())
+	tracer := tp.Tracer("test")
+
+	_, ok := tracer.Start(context.Background(), "ok")
+	ok.End()
+	_, status := tracer.Start(context.Background(), "status")
+	status.SetStatus(codes.Error, "failed")
+	status.End()
+	_, attr := tracer.Start(context.Background(), "attr")
+	attr.SetAttributes(attribute.Bool("error", true))
+	attr.End()
+
+	assert.False(t, isErrorSpan(ok.(sdktrace.ReadOnlySpan)))
+	assert.True(t, isErrorSpan(status.(sdktrace.ReadOnlySpan)))
+	assert.True(t, isErrorSpan(attr.(sdktrace.ReadOnlySpan)))
+}
@@ -0,0 +1,59 @@
+package tracingx
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gostratum/core/logx"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SpanLogConfig configures mirroring of finished spans to the logger
+type SpanLogConfig struct {
+	// Enabled emits one structured log line per finished span
+	Enabled bool `mapstructure:"enabled" default:"false"`
+
+	// Level is the log level used for span lines (debug, info, warn, error)
+	Level string `mapstructure:"level" default:"debug"`
+}
+
+// spanLogProcessor logs one line per finished span
+type spanLogProcessor struct {
+	log func(msg string, fields ...logx.Field)
+}
+
+// newSpanLogProcessor creates a processor logging finished spans at the configured level
+func newSpanLogProcessor(config SpanLogConfig, logger logx.Logger) sdktrace.SpanProcessor {
+	var log func(msg string, fields ...logx.Field)
+	switch strings.ToLower(config.Level) {
+	case "info":
+		log = logger.Info
+	case "warn":
+		log = logger.Warn
+	case "error":
+		log = logger.Error
+	default:
+		log = logger.Debug
+	}
+	return &spanLogProcessor{log: log}
+}
+
+func (p *spanLogProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {}
+
+func (p *spanLogProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	fields := []logx.Field{
+		logx.String("span", s.Name()),
+		logx.String("kind", s.SpanKind().String()),
+		logx.Duration("duration", s.EndTime().Sub(s.StartTime())),
+		logx.String("status", s.Status().Code.String()),
+		logx.String("trace_id", s.SpanContext().TraceID().String()),
+		logx.String("span_id", s.SpanContext().SpanID().String()),
+	}
+	if desc := s.Status().Description; desc != "" {
+		fields = append(fields, logx.String("status_description", desc))
+	}
+	p.log("span finished", fields...)
+}
+
+func (p *spanLogProcessor) Shutdown(ctx context.Context) error   { return nil }
+func (p *spanLogProcessor) ForceFlush(ctx context.Context) error { return nil }
@@ -0,0 +1,55 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gostratum/core/logx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSpanLogProcessor(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	logger := logx.ProvideAdapter(zap.New(core))
+
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "test-service",
+		SampleRate:  1.0,
+		SpanLog:     SpanLogConfig{Enabled: true, Level: "info"},
+	}, logger)
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	_, span := provider.Start(context.Background(), "checkout", WithSpanKind(SpanKindServer))
+	span.SetError(errors.New("boom"))
+	span.End()
+
+	entries := logs.FilterMessage("span finished").All()
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, zapcore.InfoLevel, entry.Level)
+
+	fields := entry.ContextMap()
+	assert.Equal(t, "checkout", fields["span"])
+	assert.Equal(t, "server", fields["kind"])
+	assert.Equal(t, span.TraceID(), fields["trace_id"])
+	assert.Equal(t, span.SpanID(), fields["span_id"])
+	assert.Contains(t, fields, "duration")
+	assert.Contains(t, fields, "status")
+}
+
+func TestNewSpanLogProcessorLevels(t *testing.T) {
+	for _, level := range []string{"debug", "info", "warn", "error", "bogus"} {
+		t.Run(level, func(t *testing.T) {
+			p := newSpanLogProcessor(SpanLogConfig{Level: level}, logx.NewNoopLogger())
+			assert.NotNil(t, p)
+			assert.NoError(t, p.ForceFlush(context.Background()))
+			assert.NoError(t, p.Shutdown(context.Background()))
+		})
+	}
+}
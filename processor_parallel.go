@@ -0,0 +1,119 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+)
+
+// exportWorker is one batch export worker with its own queue and exporter
+// connection
+type exportWorker struct {
+	exporter sdktrace.SpanExporter
+	batcher  sdktrace.SpanProcessor
+	stats    *exportStats
+	// conn is the worker's connection when dialed ahead by warm-up
+	conn *grpc.ClientConn
+}
+
+// newExportWorkers creates config.Workers export workers, at least one. Each
+// worker records its exports in its own stats and, when non-nil, in total.
+// With warm set the workers' connections are dialed by tracingx so they can be
+// connected ahead of the first export.
+func newExportWorkers(ctx context.Context, config OTLPConfig, warm bool, clock Clock, total *exportStats) ([]exportWorker, error) {
+	workers := make([]exportWorker, config.workers())
+	for i := range workers {
+		otlpExporter, conn, err := newOTLPExporter(ctx, config, warm)
+		if err != nil {
+			closeWorkers(workers[:i])
+			return nil, err
+		}
+		workers[i] = newExportWorker(otlpExporter, conn, config, clock, total)
+	}
+	return workers, nil
+}
+
+// newExportWorker batches spans to exporter with the queue and batch sizes
+// of batching, recording its exports in the worker's stats and, when
+// non-nil, in total
+func newExportWorker(exporter sdktrace.SpanExporter, conn *grpc.ClientConn, batching OTLPConfig, clock Clock, total *exportStats) exportWorker {
+	stats := &exportStats{}
+	queue := newExportQueue(batching.QueueSize)
+	exporter = &countingExporter{next: exporter, stats: stats, clock: clock, queue: queue}
+	if total != nil {
+		exporter = &countingExporter{next: exporter, stats: total, clock: clock}
+	}
+	return exportWorker{
+		exporter: exporter,
+		batcher:  &countingProcessor{next: sdktrace.NewBatchSpanProcessor(exporter, batching.batchOptions()...), stats: stats, total: total, queue: queue},
+		stats:    stats,
+		conn:     conn,
+	}
+}
+
+// newWorkerProcessor returns the processor feeding workers; a single worker
+// is used directly
+func newWorkerProcessor(workers []exportWorker) sdktrace.SpanProcessor {
+	if len(workers) == 1 {
+		return workers[0].batcher
+	}
+	p := &parallelProcessor{workers: make([]sdktrace.SpanProcessor, len(workers))}
+	for i, w := range workers {
+		p.workers[i] = w.batcher
+	}
+	return p
+}
+
+// parallelProcessor distributes finished spans round-robin over export
+// workers. Spans of one trace may be exported by different workers, so
+// export order is not preserved.
+type parallelProcessor struct {
+	workers []sdktrace.SpanProcessor
+	next    atomic.Uint64
+}
+
+// OnStart is a no-op: the worker exporting a span is picked when it ends
+func (p *parallelProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {}
+
+func (p *parallelProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	i := (p.next.Add(1) - 1) % uint64(len(p.workers))
+	p.workers[i].OnEnd(s)
+}
+
+func (p *parallelProcessor) Shutdown(ctx context.Context) error {
+	return p.each(func(w sdktrace.SpanProcessor) error { return w.Shutdown(ctx) })
+}
+
+func (p *parallelProcessor) ForceFlush(ctx context.Context) error {
+	return p.each(func(w sdktrace.SpanProcessor) error { return w.ForceFlush(ctx) })
+}
+
+// each calls fn on all workers concurrently and joins their errors
+func (p *parallelProcessor) each(fn func(sdktrace.SpanProcessor) error) error {
+	errs := make([]error, len(p.workers))
+	var wg sync.WaitGroup
+	for i, w := range p.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = fn(w)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// workerConns returns the warm-up connections of workers
+func workerConns(workers []exportWorker) []*grpc.ClientConn {
+	var conns []*grpc.ClientConn
+	for _, w := range workers {
+		if w.conn != nil {
+			conns = append(conns, w.conn)
+		}
+	}
+	return conns
+}
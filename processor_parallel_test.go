@@ -0,0 +1,116 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// countingSpanProcessor counts ended spans and lifecycle calls
+type countingSpanProcessor struct {
+	mu       sync.Mutex
+	ended    int
+	flushed  int
+	shutdown int
+	err      error
+}
+
+func (p *countingSpanProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {}
+
+func (p *countingSpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ended++
+}
+
+func (p *countingSpanProcessor) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.shutdown++
+	return p.err
+}
+
+func (p *countingSpanProcessor) ForceFlush(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.flushed++
+	return p.err
+}
+
+func TestParallelProcessor(t *testing.T) {
+	workers := []*countingSpanProcessor{{}, {}, {err: errors.New("worker down")}}
+	p := &parallelProcessor{}
+	for _, w := range workers {
+		p.workers = append(p.workers, w)
+	}
+
+	span := tracetest.SpanStub{Name: "work"}.Snapshot()
+	for i := 0; i < 9; i++ {
+		p.OnEnd(span)
+	}
+	for _, w := range workers {
+		assert.Equal(t, 3, w.ended)
+	}
+
+	assert.ErrorContains(t, p.ForceFlush(context.Background()), "worker down")
+	assert.ErrorContains(t, p.Shutdown(context.Background()), "worker down")
+	for _, w := range workers {
+		assert.Equal(t, 1, w.flushed)
+		assert.Equal(t, 1, w.shutdown)
+	}
+}
+
+func TestOTLPConfigWorkers(t *testing.T) {
+	assert.Equal(t, 1, OTLPConfig{}.workers())
+	assert.Equal(t, 1, OTLPConfig{Workers: -2}.workers())
+	assert.Equal(t, 4, OTLPConfig{Workers: 4}.workers())
+}
+
+func TestParallelExport(t *testing.T) {
+	collector := newFakeCollector(t)
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "test-service",
+		SampleRate:  1.0,
+		OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true, Workers: 3},
+	}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 15; j++ {
+				_, span := provider.Start(context.Background(), "work")
+				span.End()
+			}
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	assert.Len(t, collector.received(), 60)
+	diag := provider.Diagnostics()
+	assert.Equal(t, uint64(60), diag.SpansExported)
+	require.Len(t, diag.Workers, 3)
+	for _, w := range diag.Workers {
+		assert.True(t, w.ExporterHealthy)
+		assert.Equal(t, uint64(20), w.SpansExported)
+		assert.Equal(t, 0, w.QueueDepth)
+	}
+
+	single, err := newOTLPProvider(Config{
+		ServiceName: "test-service",
+		OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+	}, getTestLogger())
+	require.NoError(t, err)
+	defer single.Shutdown(context.Background())
+	assert.Empty(t, single.Diagnostics().Workers)
+}
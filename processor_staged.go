@@ -0,0 +1,278 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/fx"
+)
+
+// SpanProcessorsGroup is the fx value group collecting span processors
+const SpanProcessorsGroup = "tracingx.processors"
+
+// ProcessorStage is the stage of the export path a SpanProcessor runs in.
+// Stages run in order, enrich → redact → filter, before the export filters
+// of Config.Export and every exporter, span log and audit sink, so a redactor
+// always sees application spans before they leave the process. The audit
+// sink receives spans after the enrich and redact stages only, so filters do
+// not drop audit records.
+type ProcessorStage int
+
+const (
+	// StageEnrich adds attributes derived from the span
+	StageEnrich ProcessorStage = iota
+	// StageRedact removes or masks sensitive attributes and event fields
+	StageRedact
+	// StageFilter drops spans that should not be exported
+	StageFilter
+)
+
+// String returns the name of the stage
+func (s ProcessorStage) String() string {
+	switch s {
+	case StageEnrich:
+		return "enrich"
+	case StageRedact:
+		return "redact"
+	case StageFilter:
+		return "filter"
+	default:
+		return fmt.Sprintf("ProcessorStage(%d)", int(s))
+	}
+}
+
+// SpanProcessor processes finished spans in a stage of the export path.
+// Within a stage, processors run by ascending Priority, then in registration
+// order.
+type SpanProcessor struct {
+	// Name identifies the processor in errors
+	Name string
+
+	// Stage is the stage the processor runs in
+	Stage ProcessorStage
+
+	// Priority orders processors within the stage, lowest first
+	Priority int
+
+	// Process modifies the attributes and events of span in place and
+	// returns false to drop it. It is called concurrently by ending spans.
+	Process func(span *FinishedSpan) bool
+}
+
+// FinishedSpan is the view of an ended span passed to a SpanProcessor. The
+// changes made to Attributes and Events are exported; the other fields are
+// informational.
+type FinishedSpan struct {
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Operation    string
+	Kind         string
+	StartTime    time.Time
+	EndTime      time.Time
+	Status       string
+	Attributes   map[string]any
+	Events       []FinishedEvent
+}
+
+// FinishedEvent is an event of a FinishedSpan
+type FinishedEvent struct {
+	Name       string
+	Time       time.Time
+	Attributes map[string]any
+}
+
+// ProvideSpanProcessor registers the SpanProcessor returned by constructor
+// with the tracing module
+func ProvideSpanProcessor(constructor any) fx.Option {
+	return fx.Provide(fx.Annotate(
+		constructor,
+		fx.ResultTags(`group:"`+SpanProcessorsGroup+`"`),
+	))
+}
+
+// sortSpanProcessors validates processors and returns them in execution order
+func sortSpanProcessors(processors []SpanProcessor) ([]SpanProcessor, error) {
+	for _, p := range processors {
+		if p.Process == nil {
+			return nil, fmt.Errorf("invalid span processor %q: Process is nil", p.Name)
+		}
+		if p.Stage < StageEnrich || p.Stage > StageFilter {
+			return nil, fmt.Errorf("invalid span processor %q: unknown stage %s", p.Name, p.Stage)
+		}
+	}
+	sorted := slices.Clone(processors)
+	slices.SortStableFunc(sorted, func(a, b SpanProcessor) int {
+		if a.Stage != b.Stage {
+			return int(a.Stage - b.Stage)
+		}
+		return a.Priority - b.Priority
+	})
+	return sorted, nil
+}
+
+// stagedProcessor runs span processors on finished spans before forwarding
+// the spans they keep to every processor in next. The audit processor, when
+// set, sees spans after the enrich and redact processors only, so filters do
+// not delete audit records.
+type stagedProcessor struct {
+	processors []SpanProcessor
+	filters    int // index of the first StageFilter processor
+	next       []sdktrace.SpanProcessor
+	audit      *auditProcessor
+	stats      *exportStats
+}
+
+// newStagedProcessor wraps next and audit, which may be nil, so processors,
+// in execution order, run first, counting the sampled spans they drop in
+// stats
+func newStagedProcessor(processors []SpanProcessor, next []sdktrace.SpanProcessor, audit *auditProcessor, stats *exportStats) sdktrace.SpanProcessor {
+	filters := slices.IndexFunc(processors, func(p SpanProcessor) bool { return p.Stage == StageFilter })
+	if filters < 0 {
+		filters = len(processors)
+	}
+	return &stagedProcessor{processors: processors, filters: filters, next: next, audit: audit, stats: stats}
+}
+
+func (p *stagedProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	for _, next := range p.next {
+		next.OnStart(parent, s)
+	}
+}
+
+func (p *stagedProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	audited := p.audit != nil && p.audit.audits(s)
+	span := newFinishedSpan(s)
+	kept := true
+	for _, processor := range p.processors[:p.filters] {
+		// Audit records do not depend on the verdicts, so an audited span
+		// goes through every enrich and redact processor regardless
+		if !processor.Process(span) {
+			kept = false
+			if !audited {
+				break
+			}
+		}
+	}
+	if audited {
+		p.audit.OnEnd(newProcessedSpan(s, span))
+	}
+	if kept {
+		for _, processor := range p.processors[p.filters:] {
+			if !processor.Process(span) {
+				kept = false
+				break
+			}
+		}
+	}
+	if !kept {
+		if s.SpanContext().IsSampled() {
+			p.stats.processed.Add(1)
+		}
+		return
+	}
+	processed := newProcessedSpan(s, span)
+	for _, next := range p.next {
+		next.OnEnd(processed)
+	}
+}
+
+func (p *stagedProcessor) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, next := range p.forwards() {
+		errs = append(errs, next.Shutdown(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+func (p *stagedProcessor) ForceFlush(ctx context.Context) error {
+	var errs []error
+	for _, next := range p.forwards() {
+		errs = append(errs, next.ForceFlush(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// forwards returns every processor spans are forwarded to
+func (p *stagedProcessor) forwards() []sdktrace.SpanProcessor {
+	if p.audit == nil {
+		return p.next
+	}
+	return append(slices.Clip(p.next), p.audit)
+}
+
+// newFinishedSpan converts s into the view passed to span processors
+func newFinishedSpan(s sdktrace.ReadOnlySpan) *FinishedSpan {
+	span := &FinishedSpan{
+		TraceID:    s.SpanContext().TraceID().String(),
+		SpanID:     s.SpanContext().SpanID().String(),
+		Operation:  s.Name(),
+		Kind:       s.SpanKind().String(),
+		StartTime:  s.StartTime(),
+		EndTime:    s.EndTime(),
+		Status:     s.Status().Code.String(),
+		Attributes: attributeMap(s.Attributes()),
+	}
+	if s.Parent().IsValid() {
+		span.ParentSpanID = s.Parent().SpanID().String()
+	}
+	for _, e := range s.Events() {
+		span.Events = append(span.Events, FinishedEvent{
+			Name:       e.Name,
+			Time:       e.Time,
+			Attributes: attributeMap(e.Attributes),
+		})
+	}
+	return span
+}
+
+// isError reports whether the span ended with an error status or was marked
+// with the error attribute by SetError
+func (s *FinishedSpan) isError() bool {
+	return s.Status == codes.Error.String() || s.Attributes["error"] == true
+}
+
+// attributeMap converts attributes into a map keyed by attribute key
+func attributeMap(attrs []attribute.KeyValue) map[string]any {
+	m := make(map[string]any, len(attrs))
+	for _, kv := range attrs {
+		m[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return m
+}
+
+// attributeList converts an attribute map back into attributes sorted by key
+func attributeList(m map[string]any) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(m))
+	for _, k := range slices.Sorted(maps.Keys(m)) {
+		attrs = append(attrs, toAttribute(k, m[k]))
+	}
+	return attrs
+}
+
+// processedSpan presents a span with the attributes and events left by the
+// span processors
+type processedSpan struct {
+	sdktrace.ReadOnlySpan
+	attrs  []attribute.KeyValue
+	events []sdktrace.Event
+}
+
+// newProcessedSpan returns s with the attributes and events of span
+func newProcessedSpan(s sdktrace.ReadOnlySpan, span *FinishedSpan) processedSpan {
+	events := make([]sdktrace.Event, len(span.Events))
+	for i, e := range span.Events {
+		events[i] = sdktrace.Event{Name: e.Name, Time: e.Time, Attributes: attributeList(e.Attributes)}
+	}
+	return processedSpan{ReadOnlySpan: s, attrs: attributeList(span.Attributes), events: events}
+}
+
+func (s processedSpan) Attributes() []attribute.KeyValue { return s.attrs }
+func (s processedSpan) Events() []sdktrace.Event         { return s.events }
@@ -0,0 +1,213 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestStagedProcessor(t *testing.T) {
+	var order []string
+	track := func(name string, stage ProcessorStage, priority int, process func(*FinishedSpan) bool) SpanProcessor {
+		return SpanProcessor{Name: name, Stage: stage, Priority: priority, Process: func(s *FinishedSpan) bool {
+			order = append(order, name)
+			return process(s)
+		}}
+	}
+	redact := track("redact", StageRedact, 0, func(s *FinishedSpan) bool {
+		if _, ok := s.Attributes["card.number"]; ok {
+			s.Attributes["card.number"] = "[REDACTED]"
+		}
+		for _, e := range s.Events {
+			delete(e.Attributes, "card.number")
+		}
+		return true
+	})
+	enrich := track("enrich", StageEnrich, 10, func(s *FinishedSpan) bool {
+		s.Attributes["card.number"] = "4242424242424242"
+		return true
+	})
+	enrichFirst := track("enrich-first", StageEnrich, -1, func(s *FinishedSpan) bool {
+		s.Attributes["team"] = "payments"
+		return true
+	})
+	filter := track("filter", StageFilter, 0, func(s *FinishedSpan) bool {
+		return s.Operation != "health"
+	})
+
+	processors, err := sortSpanProcessors([]SpanProcessor{filter, redact, enrich, enrichFirst})
+	require.NoError(t, err)
+
+	first, second := tracetest.NewSpanRecorder(), tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(
+		newStagedProcessor(processors, []sdktrace.SpanProcessor{first, second}, nil, &exportStats{}),
+	))
+	defer tp.Shutdown(context.Background())
+	tracer := tp.Tracer("test")
+
+	_, span := tracer.Start(context.Background(), "charge", trace.WithAttributes(attribute.String("amount", "10")))
+	span.AddEvent("log", trace.WithAttributes(attribute.String("card.number", "4242424242424242"), attribute.String("step", "authorize")))
+	span.End()
+	assert.Equal(t, []string{"enrich-first", "enrich", "redact", "filter"}, order)
+
+	_, health := tracer.Start(context.Background(), "health")
+	health.End()
+
+	assert.Len(t, first.Started(), 2)
+	for _, recorder := range []*tracetest.SpanRecorder{first, second} {
+		ended := recorder.Ended()
+		require.Len(t, ended, 1)
+		assert.Equal(t, "charge", ended[0].Name())
+		assert.Equal(t, []attribute.KeyValue{
+			attribute.String("amount", "10"),
+			attribute.String("card.number", "[REDACTED]"),
+			attribute.String("team", "payments"),
+		}, ended[0].Attributes())
+		require.Len(t, ended[0].Events(), 1)
+		assert.Equal(t, []attribute.KeyValue{attribute.String("step", "authorize")}, ended[0].Events()[0].Attributes)
+	}
+}
+
+func TestSortSpanProcessors(t *testing.T) {
+	keep := func(*FinishedSpan) bool { return true }
+
+	_, err := sortSpanProcessors([]SpanProcessor{{Name: "redactor"}})
+	assert.ErrorContains(t, err, `invalid span processor "redactor"`)
+
+	_, err = sortSpanProcessors([]SpanProcessor{{Name: "export", Stage: ProcessorStage(7), Process: keep}})
+	assert.ErrorContains(t, err, "unknown stage ProcessorStage(7)")
+
+	sorted, err := sortSpanProcessors([]SpanProcessor{
+		{Name: "b", Stage: StageRedact, Process: keep},
+		{Name: "a", Stage: StageRedact, Process: keep},
+		{Name: "c", Stage: StageEnrich, Priority: 5, Process: keep},
+	})
+	require.NoError(t, err)
+	var names []string
+	for _, p := range sorted {
+		names = append(names, p.Name)
+	}
+	assert.Equal(t, []string{"c", "b", "a"}, names)
+}
+
+func TestSpanProcessorsRunBeforeExport(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "test-service",
+		SampleRate:  1.0,
+		SpanLog:     SpanLogConfig{Enabled: true, Level: "info"},
+	}, logx.ProvideAdapter(zap.New(core)), withSpanProcessors(SpanProcessor{
+		Name:  "drop-internal",
+		Stage: StageFilter,
+		Process: func(s *FinishedSpan) bool {
+			return s.Kind != "internal"
+		},
+	}))
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	_, internal := provider.Start(context.Background(), "work")
+	internal.End()
+	_, server := provider.Start(context.Background(), "checkout", WithSpanKind(SpanKindServer))
+	server.End()
+
+	entries := logs.FilterMessage("span finished").All()
+	require.Len(t, entries, 1)
+	assert.Equal(t, "checkout", entries[0].ContextMap()["span"])
+
+	_, err = newOTLPProvider(Config{ServiceName: "test-service"}, getTestLogger(),
+		withSpanProcessors(SpanProcessor{Name: "broken"}),
+	)
+	assert.Error(t, err)
+}
+
+func TestSpanProcessorsDoNotFilterAudit(t *testing.T) {
+	sink := &memoryAuditSink{}
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "billing",
+		SampleRate:  1.0,
+		Audit:       AuditConfig{Enabled: true, Operations: []string{"payment.refund"}},
+		Pipeline:    []string{"redact", "min_duration"},
+		Processors: ProcessorsConfig{
+			Redact:      RedactConfig{Keys: []string{"card.number"}},
+			MinDuration: MinDurationConfig{Threshold: time.Hour},
+		},
+	}, getTestLogger(), withAuditSink(sink), withSpanProcessors(SpanProcessor{
+		Name:    "drop-all",
+		Stage:   StageFilter,
+		Process: func(*FinishedSpan) bool { return false },
+	}))
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	ctx, root := provider.Start(context.Background(), "checkout")
+	_, span := provider.Start(ctx, "payment.refund", WithAttributes(map[string]any{"card.number": "4111"}))
+	span.End()
+	root.End()
+
+	require.Len(t, sink.records, 1, "filters do not drop audit records")
+	assert.Equal(t, "[redacted]", sink.records[0].Attributes["card.number"], "redaction applies to audit records")
+}
+
+func TestSpanProcessorsRunOnceForAudit(t *testing.T) {
+	calls := map[string]int{}
+	count := func(name string, stage ProcessorStage, keep bool) SpanProcessor {
+		return SpanProcessor{Name: name, Stage: stage, Process: func(*FinishedSpan) bool {
+			calls[name]++
+			return keep
+		}}
+	}
+
+	for _, keep := range []bool{true, false} {
+		clear(calls)
+		sink := &memoryAuditSink{}
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "billing",
+			SampleRate:  1.0,
+			Audit:       AuditConfig{Enabled: true, Operations: []string{"payment.refund"}},
+		}, getTestLogger(), withAuditSink(sink), withSpanProcessors(
+			count("enrich", StageEnrich, true),
+			count("redact", StageRedact, true),
+			count("filter", StageFilter, keep),
+		))
+		require.NoError(t, err)
+
+		_, span := provider.Start(context.Background(), "payment.refund")
+		span.End()
+		require.NoError(t, provider.Shutdown(context.Background()))
+
+		assert.Len(t, sink.records, 1)
+		assert.Equal(t, map[string]int{"enrich": 1, "redact": 1, "filter": 1}, calls, "keep=%v", keep)
+	}
+}
+
+func TestProvideSpanProcessor(t *testing.T) {
+	var got []SpanProcessor
+	app := fx.New(
+		fx.NopLogger,
+		ProvideSpanProcessor(func() SpanProcessor {
+			return SpanProcessor{Name: "redactor", Stage: StageRedact, Process: func(*FinishedSpan) bool { return true }}
+		}),
+		fx.Invoke(func(p struct {
+			fx.In
+			SpanProcessors []SpanProcessor `group:"tracingx.processors"`
+		}) {
+			got = p.SpanProcessors
+		}),
+	)
+	require.NoError(t, app.Err())
+	require.Len(t, got, 1)
+	assert.Equal(t, "redactor", got[0].Name)
+	assert.Equal(t, "redact", got[0].Stage.String())
+}
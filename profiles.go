@@ -0,0 +1,120 @@
+package tracingx
+
+import "fmt"
+
+// AttributeProfile is a bundle of default attributes applied to every span
+// of a kind when it starts. Attributes set by instrumentation take precedence.
+//
+//	attributes:
+//	  profiles:
+//	    - kind: client
+//	      attributes:
+//	        deployment.region: eu-west-1
+//	      lookups:
+//	        - key: db.namespace
+//	          from: server.address
+//	          values:
+//	            10.0.4.20: orders
+type AttributeProfile struct {
+	// Kind is the span kind the profile applies to: internal, server,
+	// client, producer or consumer
+	Kind string `mapstructure:"kind"`
+
+	// Attributes are set on every span of the kind
+	Attributes map[string]string `mapstructure:"attributes"`
+
+	// Lookups derive attributes from the start attributes of the span
+	Lookups []AttributeLookup `mapstructure:"lookups"`
+}
+
+// AttributeLookup sets Key to the entry of Values matching the value of the
+// From attribute; spans without a match are left alone. Lookups of
+// peer.service from server.address on client spans extend
+// PeerServiceConfig.Services instead, so peer.service has one source.
+type AttributeLookup struct {
+	Key    string            `mapstructure:"key"`
+	From   string            `mapstructure:"from"`
+	Values map[string]string `mapstructure:"values"`
+}
+
+// attributeProfiles are the configured profiles by span kind
+type attributeProfiles map[SpanKind][]AttributeProfile
+
+// newAttributeProfiles groups profiles by span kind, validating them
+func newAttributeProfiles(profiles []AttributeProfile) (attributeProfiles, error) {
+	if len(profiles) == 0 {
+		return nil, nil
+	}
+	byKind := make(attributeProfiles, len(profiles))
+	for i, profile := range profiles {
+		kind, err := ParseSpanKind(profile.Kind)
+		if err != nil {
+			return nil, fmt.Errorf("invalid attribute profile %d: %w", i, err)
+		}
+		for _, lookup := range profile.Lookups {
+			if lookup.Key == "" || lookup.From == "" {
+				return nil, fmt.Errorf("invalid attribute profile %d: lookups need a key and a from attribute", i)
+			}
+		}
+		byKind[kind] = append(byKind[kind], profile)
+	}
+	return byKind, nil
+}
+
+// takePeerServices removes the peer.service lookups from server.address of
+// the client profiles and returns their values, which feed peer.service
+// resolution
+func (p attributeProfiles) takePeerServices() map[string]string {
+	var services map[string]string
+	for i, profile := range p[SpanKindClient] {
+		var kept []AttributeLookup
+		for _, lookup := range profile.Lookups {
+			if lookup.Key != peerServiceKey || lookup.From != serverAddressKey {
+				kept = append(kept, lookup)
+				continue
+			}
+			if services == nil {
+				services = make(map[string]string, len(lookup.Values))
+			}
+			for address, name := range lookup.Values {
+				if _, set := services[address]; !set {
+					services[address] = name
+				}
+			}
+		}
+		p[SpanKindClient][i].Lookups = kept
+	}
+	return services
+}
+
+// apply adds the profile attributes of kind missing from attrs, allocating
+// attrs when needed, and returns it
+func (p attributeProfiles) apply(kind SpanKind, attrs map[string]any) map[string]any {
+	profiles := p[kind]
+	if len(profiles) == 0 {
+		return attrs
+	}
+	if attrs == nil {
+		attrs = make(map[string]any)
+	}
+	for _, profile := range profiles {
+		for _, lookup := range profile.Lookups {
+			if _, set := attrs[lookup.Key]; set {
+				continue
+			}
+			source, ok := attrs[lookup.From]
+			if !ok {
+				continue
+			}
+			if value, ok := lookup.Values[fmt.Sprint(source)]; ok {
+				attrs[lookup.Key] = value
+			}
+		}
+		for key, value := range profile.Attributes {
+			if _, set := attrs[key]; !set {
+				attrs[key] = value
+			}
+		}
+	}
+	return attrs
+}
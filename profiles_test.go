@@ -0,0 +1,115 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttributeProfiles(t *testing.T) {
+	profiles := []AttributeProfile{
+		{
+			Kind:       "client",
+			Attributes: map[string]string{"deployment.region": "eu-west-1"},
+			Lookups: []AttributeLookup{{
+				Key:    "peer.service",
+				From:   "server.address",
+				Values: map[string]string{"10.0.4.12": "payments-api"},
+			}},
+		},
+		{Kind: "Server", Attributes: map[string]string{"service.tier": "edge"}},
+	}
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "test-service",
+		SampleRate:  1.0,
+		Attributes:  AttributesConfig{Profiles: profiles},
+	}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("applies the profile of the span kind", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "charge",
+			WithSpanKind(SpanKindClient),
+			WithAttributes(map[string]any{"server.address": "10.0.4.12"}),
+		)
+		defer span.End()
+
+		attrs := attributesOf(t, span)
+		assert.Equal(t, "eu-west-1", attrs["deployment.region"])
+		assert.Equal(t, "payments-api", attrs["peer.service"])
+		assert.NotContains(t, attrs, "service.tier")
+	})
+
+	t.Run("keeps attributes set by instrumentation", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "charge",
+			WithSpanKind(SpanKindClient),
+			WithAttributes(map[string]any{
+				"server.address":    "10.0.4.12",
+				"peer.service":      "stripe",
+				"deployment.region": "us-east-1",
+			}),
+		)
+		defer span.End()
+
+		attrs := attributesOf(t, span)
+		assert.Equal(t, "stripe", attrs["peer.service"])
+		assert.Equal(t, "us-east-1", attrs["deployment.region"])
+	})
+
+	t.Run("skips lookups without a match", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "fetch",
+			WithSpanKind(SpanKindClient),
+			WithAttributes(map[string]any{"server.address": "10.9.9.9"}),
+		)
+		defer span.End()
+		assert.NotContains(t, attributesOf(t, span), "peer.service")
+	})
+
+	t.Run("leaves other kinds alone", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "work")
+		defer span.End()
+		assert.Empty(t, attributesOf(t, span))
+
+		_, server := provider.Start(context.Background(), "GET /", WithSpanKind(SpanKindServer))
+		defer server.End()
+		assert.Equal(t, "edge", attributesOf(t, server)["service.tier"])
+	})
+
+	t.Run("does not modify the caller's attributes", func(t *testing.T) {
+		attrs := map[string]any{"server.address": "10.0.4.12"}
+		_, span := provider.Start(context.Background(), "charge", WithSpanKind(SpanKindClient), WithAttributes(attrs))
+		defer span.End()
+		assert.Len(t, attrs, 1)
+	})
+}
+
+func TestNewAttributeProfiles(t *testing.T) {
+	t.Run("rejects unknown kinds", func(t *testing.T) {
+		_, err := newAttributeProfiles([]AttributeProfile{{Kind: "database"}})
+		assert.ErrorContains(t, err, "invalid attribute profile 0")
+	})
+
+	t.Run("rejects incomplete lookups", func(t *testing.T) {
+		_, err := newAttributeProfiles([]AttributeProfile{{Kind: "client", Lookups: []AttributeLookup{{Key: "peer.service"}}}})
+		assert.Error(t, err)
+	})
+
+	t.Run("fails provider creation", func(t *testing.T) {
+		_, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			Attributes:  AttributesConfig{Profiles: []AttributeProfile{{Kind: "database"}}},
+		}, getTestLogger())
+		assert.Error(t, err)
+	})
+
+	t.Run("merges profiles of the same kind in order", func(t *testing.T) {
+		profiles, err := newAttributeProfiles([]AttributeProfile{
+			{Kind: "client", Attributes: map[string]string{"a": "1", "b": "1"}},
+			{Kind: "client", Attributes: map[string]string{"b": "2", "c": "2"}},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"a": "1", "b": "1", "c": "2"}, profiles.apply(SpanKindClient, nil))
+	})
+}
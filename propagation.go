@@ -0,0 +1,87 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// StartFromCarrier extracts the remote trace context from carrier and starts a
+// span parented to it. Spans default to SpanKindServer; pass
+// WithSpanKind(SpanKindConsumer) for message handlers. If extraction fails the
+// span is started without a remote parent and the failure is logged on the span.
+func StartFromCarrier(ctx context.Context, tracer Tracer, carrier Carrier, name string, opts ...SpanOption) (context.Context, Span) {
+	extracted, err := tracer.Extract(ctx, carrier)
+	if err != nil {
+		extracted = ctx
+	}
+
+	opts = append([]SpanOption{WithSpanKind(SpanKindServer)}, opts...)
+	ctx, span := tracer.Start(extracted, name, opts...)
+	if err != nil {
+		span.LogFields(
+			String("event", "extract_failed"),
+			Err(err),
+		)
+	}
+	return ctx, span
+}
+
+// HasTraceContext reports whether carrier contains a valid W3C trace context
+func HasTraceContext(carrier Carrier) bool {
+	if carrier == nil {
+		return false
+	}
+	ctx := propagation.TraceContext{}.Extract(context.Background(), carrier)
+	return trace.SpanContextFromContext(ctx).IsValid()
+}
+
+// SuppressPropagation returns a context for which Inject writes nothing, so
+// calls to third-party APIs receive neither traceparent nor baggage. Spans
+// started from the context are still recorded locally.
+func SuppressPropagation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, suppressPropagationKey{}, true)
+}
+
+// IsPropagationSuppressed reports whether SuppressPropagation applies to ctx
+func IsPropagationSuppressed(ctx context.Context) bool {
+	suppressed, _ := ctx.Value(suppressPropagationKey{}).(bool)
+	return suppressed
+}
+
+type suppressPropagationKey struct{}
+
+// errNilCarrier is returned when extracting from or injecting into a nil
+// carrier
+var errNilCarrier = errors.New("nil carrier")
+
+// alreadyInTrace reports whether ctx already continues the remote span found
+// in extracted, either from an earlier Extract or as its current span, in
+// which case re-extracting would re-parent the spans started since
+func alreadyInTrace(ctx, extracted context.Context) bool {
+	remote := trace.SpanContextFromContext(extracted)
+	if !remote.IsValid() {
+		return false
+	}
+	same := func(sc trace.SpanContext) bool {
+		return sc.TraceID() == remote.TraceID() && sc.SpanID() == remote.SpanID()
+	}
+	if prior, ok := ctx.Value(extractedRemoteKey{}).(trace.SpanContext); ok && same(prior) {
+		return true
+	}
+	return same(trace.SpanContextFromContext(ctx))
+}
+
+// withExtractedRemote records the remote span context of extracted, which
+// outlives the spans started from it, for alreadyInTrace
+func withExtractedRemote(extracted context.Context) context.Context {
+	remote := trace.SpanContextFromContext(extracted)
+	if !remote.IsValid() || !remote.IsRemote() {
+		return extracted
+	}
+	return context.WithValue(extracted, extractedRemoteKey{}, remote)
+}
+
+type extractedRemoteKey struct{}
@@ -0,0 +1,85 @@
+package tracingx
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// PropagationStats counts Extract calls and the ones that found no usable
+// trace context, by reason
+type PropagationStats struct {
+	// Extracts counts Extract calls
+	Extracts uint64 `json:"extracts"`
+
+	// Missing counts carriers without a traceparent
+	Missing uint64 `json:"missing"`
+
+	// Malformed counts carriers whose traceparent could not be parsed
+	Malformed uint64 `json:"malformed"`
+
+	// UnsupportedCarrier counts carriers of a type Extract cannot read
+	UnsupportedCarrier uint64 `json:"unsupported_carrier"`
+}
+
+// extractFailure is the reason an Extract found no usable trace context
+type extractFailure string
+
+const (
+	extractOK                 extractFailure = ""
+	extractMissing            extractFailure = "missing"
+	extractMalformed          extractFailure = "malformed"
+	extractUnsupportedCarrier extractFailure = "unsupported_carrier"
+)
+
+// classifyExtract reports why carrier holds no usable W3C trace context
+func classifyExtract(carrier propagation.TextMapCarrier) extractFailure {
+	if carrier.Get("traceparent") == "" {
+		return extractMissing
+	}
+	ctx := propagation.TraceContext{}.Extract(context.Background(), carrier)
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return extractMalformed
+	}
+	return extractOK
+}
+
+// extractedRemote reports whether extracting into ctx produced a new remote
+// span context
+func extractedRemote(ctx, extracted context.Context) bool {
+	sc := trace.SpanContextFromContext(extracted)
+	return sc.IsValid() && sc.IsRemote() && !sc.Equal(trace.SpanContextFromContext(ctx))
+}
+
+// propagationStats counts Extract outcomes
+type propagationStats struct {
+	extracts    atomic.Uint64
+	missing     atomic.Uint64
+	malformed   atomic.Uint64
+	unsupported atomic.Uint64
+}
+
+// record counts an Extract call with the given outcome
+func (s *propagationStats) record(failure extractFailure) {
+	s.extracts.Add(1)
+	switch failure {
+	case extractMissing:
+		s.missing.Add(1)
+	case extractMalformed:
+		s.malformed.Add(1)
+	case extractUnsupportedCarrier:
+		s.unsupported.Add(1)
+	}
+}
+
+// snapshot returns the current counts
+func (s *propagationStats) snapshot() PropagationStats {
+	return PropagationStats{
+		Extracts:           s.extracts.Load(),
+		Missing:            s.missing.Load(),
+		Malformed:          s.malformed.Load(),
+		UnsupportedCarrier: s.unsupported.Load(),
+	}
+}
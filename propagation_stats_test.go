@@ -0,0 +1,51 @@
+package tracingx
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+func TestClassifyExtract(t *testing.T) {
+	tests := []struct {
+		name    string
+		carrier map[string]string
+		want    extractFailure
+	}{
+		{"valid", map[string]string{"traceparent": validTraceParent}, extractOK},
+		{"missing", map[string]string{"x-other": "1"}, extractMissing},
+		{"malformed", map[string]string{"traceparent": "00-zz-00f067aa0ba902b7-01"}, extractMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, classifyExtract(propagation.MapCarrier(tt.carrier)))
+		})
+	}
+}
+
+func TestPropagationStats(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	ctx := context.Background()
+	_, err = provider.Extract(ctx, HeaderCarrier(http.Header{"Traceparent": {validTraceParent}}))
+	require.NoError(t, err)
+	_, err = provider.Extract(ctx, HeaderCarrier(http.Header{}))
+	require.NoError(t, err)
+	_, err = provider.Extract(ctx, MapCarrier(map[string]string{"traceparent": "garbage"}))
+	require.NoError(t, err)
+	_, err = provider.Extract(ctx, nil)
+	require.Error(t, err)
+
+	assert.Equal(t, PropagationStats{
+		Extracts:           4,
+		Missing:            1,
+		Malformed:          1,
+		UnsupportedCarrier: 1,
+	}, provider.Diagnostics().Propagation)
+}
@@ -0,0 +1,153 @@
+package tracingx
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartFromCarrier(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("continues the remote trace", func(t *testing.T) {
+		parentCtx, parent := provider.Start(context.Background(), "producer")
+		defer parent.End()
+
+		carrier := make(map[string]string)
+		require.NoError(t, provider.Inject(parentCtx, MapCarrier(carrier)))
+
+		ctx, span := StartFromCarrier(context.Background(), provider, MapCarrier(carrier), "consume",
+			WithSpanKind(SpanKindConsumer),
+		)
+		defer span.End()
+
+		assert.Equal(t, parent.TraceID(), span.TraceID())
+		assert.NotEqual(t, parent.SpanID(), span.SpanID())
+		assert.Equal(t, span, SpanFromContext(ctx))
+	})
+
+	t.Run("starts a new trace for nil carriers", func(t *testing.T) {
+		_, span := StartFromCarrier(context.Background(), provider, nil, "orphan")
+		defer span.End()
+
+		assert.NotEmpty(t, span.TraceID())
+	})
+
+	t.Run("works with the noop provider", func(t *testing.T) {
+		ctx, span := StartFromCarrier(context.Background(), newNoopProvider(), MapCarrier(map[string]string{}), "noop")
+		defer span.End()
+
+		assert.NotNil(t, ctx)
+		assert.NotNil(t, span)
+	})
+}
+
+func TestHasTraceContext(t *testing.T) {
+	t.Run("detects valid traceparent", func(t *testing.T) {
+		carrier := map[string]string{
+			"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		}
+		assert.True(t, HasTraceContext(MapCarrier(carrier)))
+	})
+
+	t.Run("accepts http.Header", func(t *testing.T) {
+		headers := http.Header{}
+		headers.Set("Traceparent", validTraceParent)
+		assert.True(t, HasTraceContext(HeaderCarrier(headers)))
+	})
+
+	t.Run("rejects missing or malformed traceparent", func(t *testing.T) {
+		assert.False(t, HasTraceContext(MapCarrier(map[string]string{})))
+		assert.False(t, HasTraceContext(MapCarrier(map[string]string{"traceparent": "garbage"})))
+	})
+
+	t.Run("rejects nil carriers", func(t *testing.T) {
+		assert.False(t, HasTraceContext(nil))
+	})
+}
+
+func TestExtractIsIdempotent(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	upstreamCtx, upstream := provider.Start(context.Background(), "upstream")
+	defer upstream.End()
+	carrier := make(map[string]string)
+	require.NoError(t, provider.Inject(upstreamCtx, MapCarrier(carrier)))
+
+	t.Run("does not re-parent a span of the same trace", func(t *testing.T) {
+		ctx, err := provider.Extract(context.Background(), MapCarrier(carrier))
+		require.NoError(t, err)
+		ctx, server := provider.Start(ctx, "server")
+		defer server.End()
+
+		again, err := provider.Extract(ctx, MapCarrier(carrier))
+		require.NoError(t, err)
+		assert.Equal(t, ctx, again)
+
+		childCtx, child := provider.Start(ctx, "child")
+		defer child.End()
+		again, err = provider.Extract(childCtx, MapCarrier(carrier))
+		require.NoError(t, err)
+		assert.Equal(t, childCtx, again, "nor a span started below it")
+	})
+
+	t.Run("re-parents to a different span of the same trace", func(t *testing.T) {
+		ctx, err := provider.Extract(context.Background(), MapCarrier(carrier))
+		require.NoError(t, err)
+		ctx, server := provider.Start(ctx, "server")
+		defer server.End()
+
+		sibling := map[string]string{
+			"traceparent": "00-" + upstream.TraceID() + "-b7ad6b7169203331-01",
+		}
+		again, err := provider.Extract(ctx, MapCarrier(sibling))
+		require.NoError(t, err)
+		assert.NotEqual(t, ctx, again)
+		_, child := provider.Start(again, "child")
+		defer child.End()
+		assert.Equal(t, upstream.TraceID(), child.TraceID())
+		assert.Equal(t, "b7ad6b7169203331", child.ParentSpanID())
+	})
+
+	t.Run("replaces context from a different trace", func(t *testing.T) {
+		otherCtx, other := provider.Start(context.Background(), "other")
+		defer other.End()
+
+		ctx, err := provider.Extract(otherCtx, MapCarrier(carrier))
+		require.NoError(t, err)
+		assert.NotEqual(t, otherCtx, ctx)
+	})
+}
+
+func TestSuppressPropagation(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	ctx, span := provider.Start(context.Background(), "call-third-party")
+	defer span.End()
+	assert.False(t, IsPropagationSuppressed(ctx))
+
+	t.Run("injects nothing when suppressed", func(t *testing.T) {
+		carrier := make(map[string]string)
+		require.NoError(t, provider.Inject(SuppressPropagation(ctx), MapCarrier(carrier)))
+		assert.Empty(t, carrier)
+	})
+
+	t.Run("still validates the carrier", func(t *testing.T) {
+		assert.Error(t, provider.Inject(SuppressPropagation(ctx), nil))
+	})
+
+	t.Run("injects normally otherwise", func(t *testing.T) {
+		carrier := make(map[string]string)
+		require.NoError(t, provider.Inject(ctx, MapCarrier(carrier)))
+		assert.Contains(t, carrier, "traceparent")
+	})
+}
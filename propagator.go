@@ -0,0 +1,93 @@
+package tracingx
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+	"go.uber.org/fx"
+)
+
+// PropagatorsGroup is the fx value group collecting custom propagators
+const PropagatorsGroup = "tracingx.propagators"
+
+// Propagator reads and writes trace context in a custom header scheme, e.g.
+// a legacy X-Request-Trace header. Custom propagators are composed with the
+// standard W3C trace context and baggage propagators: all of them inject,
+// and on extraction a valid traceparent takes precedence, so a custom scheme
+// acts as a fallback for callers that do not send one.
+type Propagator interface {
+	// Inject writes the trace context of ctx to carrier
+	Inject(ctx context.Context, carrier Carrier)
+
+	// Extract returns ctx with the trace context read from carrier, or ctx
+	// unchanged when carrier holds none
+	Extract(ctx context.Context, carrier Carrier) context.Context
+
+	// Fields lists the carrier keys the propagator reads and writes
+	Fields() []string
+}
+
+// ProvidePropagator registers the Propagator returned by constructor with
+// the tracing module
+func ProvidePropagator(constructor any) fx.Option {
+	return fx.Provide(fx.Annotate(
+		constructor,
+		fx.As(new(Propagator)),
+		fx.ResultTags(`group:"`+PropagatorsGroup+`"`),
+	))
+}
+
+// propagatorAdapter adapts a Propagator to propagation.TextMapPropagator
+type propagatorAdapter struct {
+	p Propagator
+}
+
+func (a propagatorAdapter) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	a.p.Inject(ctx, carrier)
+}
+
+func (a propagatorAdapter) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return a.p.Extract(ctx, carrier)
+}
+
+func (a propagatorAdapter) Fields() []string {
+	return a.p.Fields()
+}
+
+// DefaultPropagators are the propagators used when Config.Propagators is empty
+var DefaultPropagators = []string{"tracecontext", "baggage"}
+
+// builtinPropagators maps the names accepted in Config.Propagators to
+// their propagators
+var builtinPropagators = map[string]func() propagation.TextMapPropagator{
+	"tracecontext": func() propagation.TextMapPropagator { return propagation.TraceContext{} },
+	"baggage":      func() propagation.TextMapPropagator { return propagation.Baggage{} },
+	"jaeger":       func() propagation.TextMapPropagator { return jaeger.Jaeger{} },
+	"datadog":      func() propagation.TextMapPropagator { return datadogPropagator{} },
+	"xray":         func() propagation.TextMapPropagator { return xrayPropagator{} },
+}
+
+// newTextMapPropagator composes custom propagators with the builtin ones
+// named in names (DefaultPropagators if empty). Extraction runs in order
+// with later propagators overriding earlier ones, so custom propagators come
+// first.
+func newTextMapPropagator(names []string, custom []Propagator) (propagation.TextMapPropagator, error) {
+	if len(names) == 0 {
+		names = DefaultPropagators
+	}
+	propagators := make([]propagation.TextMapPropagator, 0, len(custom)+len(names))
+	for _, p := range custom {
+		propagators = append(propagators, propagatorAdapter{p})
+	}
+	for _, name := range names {
+		builtin, ok := builtinPropagators[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("unknown propagator %q", name)
+		}
+		propagators = append(propagators, builtin())
+	}
+	return propagation.NewCompositeTextMapPropagator(propagators...), nil
+}
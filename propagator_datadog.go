@@ -0,0 +1,115 @@
+package tracingx
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Datadog propagation headers
+const (
+	datadogTraceIDHeader          = "x-datadog-trace-id"
+	datadogParentIDHeader         = "x-datadog-parent-id"
+	datadogSamplingPriorityHeader = "x-datadog-sampling-priority"
+	datadogTagsHeader             = "x-datadog-tags"
+
+	// datadogTraceIDHighTag carries the high 64 bits of 128-bit trace IDs
+	datadogTraceIDHighTag = "_dd.p.tid"
+)
+
+// datadogPropagator propagates trace context in dd-trace's headers. Datadog
+// IDs are unsigned 64-bit decimals: the trace ID header holds the low 64
+// bits of the trace ID, and the high 64 bits travel in the _dd.p.tid tag.
+type datadogPropagator struct{}
+
+var _ propagation.TextMapPropagator = datadogPropagator{}
+
+func (datadogPropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	traceID, spanID := sc.TraceID(), sc.SpanID()
+	carrier.Set(datadogTraceIDHeader, strconv.FormatUint(binary.BigEndian.Uint64(traceID[8:]), 10))
+	carrier.Set(datadogParentIDHeader, strconv.FormatUint(binary.BigEndian.Uint64(spanID[:]), 10))
+	if sc.IsSampled() {
+		carrier.Set(datadogSamplingPriorityHeader, "1")
+	} else {
+		carrier.Set(datadogSamplingPriorityHeader, "0")
+	}
+	if tags := datadogInjectTags(carrier.Get(datadogTagsHeader), traceID[:8]); tags != "" {
+		carrier.Set(datadogTagsHeader, tags)
+	}
+}
+
+func (datadogPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	low, err := strconv.ParseUint(carrier.Get(datadogTraceIDHeader), 10, 64)
+	if err != nil || low == 0 {
+		return ctx
+	}
+	parent, err := strconv.ParseUint(carrier.Get(datadogParentIDHeader), 10, 64)
+	if err != nil || parent == 0 {
+		return ctx
+	}
+
+	var traceID trace.TraceID
+	var spanID trace.SpanID
+	if high, ok := datadogTraceIDHigh(carrier.Get(datadogTagsHeader)); ok {
+		copy(traceID[:8], high)
+	}
+	binary.BigEndian.PutUint64(traceID[8:], low)
+	binary.BigEndian.PutUint64(spanID[:], parent)
+
+	var flags trace.TraceFlags
+	if priority, err := strconv.Atoi(carrier.Get(datadogSamplingPriorityHeader)); err == nil && priority > 0 {
+		flags = trace.FlagsSampled
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flags,
+		Remote:     true,
+	}))
+}
+
+func (datadogPropagator) Fields() []string {
+	return []string{datadogTraceIDHeader, datadogParentIDHeader, datadogSamplingPriorityHeader, datadogTagsHeader}
+}
+
+// datadogInjectTags merges the _dd.p.tid tag of the trace ID's high 64 bits
+// into the tags already in x-datadog-tags, e.g. set by dd-trace, replacing a
+// stale _dd.p.tid. The tag is left out of 64-bit trace IDs.
+func datadogInjectTags(existing string, high []byte) string {
+	var tags []string
+	for _, tag := range strings.Split(existing, ",") {
+		tag = strings.TrimSpace(tag)
+		if key, _, _ := strings.Cut(tag, "="); tag != "" && key != datadogTraceIDHighTag {
+			tags = append(tags, tag)
+		}
+	}
+	if binary.BigEndian.Uint64(high) != 0 {
+		tags = append(tags, datadogTraceIDHighTag+"="+hex.EncodeToString(high))
+	}
+	return strings.Join(tags, ",")
+}
+
+// datadogTraceIDHigh reads the high 64 bits of the trace ID from x-datadog-tags
+func datadogTraceIDHigh(tags string) ([]byte, bool) {
+	for _, tag := range strings.Split(tags, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(tag), "=")
+		if key != datadogTraceIDHighTag {
+			continue
+		}
+		high, err := hex.DecodeString(value)
+		if err != nil || len(high) != 8 {
+			return nil, false
+		}
+		return high, true
+	}
+	return nil, false
+}
@@ -0,0 +1,102 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestDatadogPropagator(t *testing.T) {
+	p := datadogPropagator{}
+
+	t.Run("extracts 64-bit ids", func(t *testing.T) {
+		ctx := p.Extract(context.Background(), propagation.MapCarrier{
+			datadogTraceIDHeader:          "1234567890123456789",
+			datadogParentIDHeader:         "987654321",
+			datadogSamplingPriorityHeader: "2",
+		})
+		sc := trace.SpanContextFromContext(ctx)
+		assert.True(t, sc.IsRemote())
+		assert.True(t, sc.IsSampled())
+		assert.Equal(t, "0000000000000000112210f47de98115", sc.TraceID().String())
+		assert.Equal(t, "000000003ade68b1", sc.SpanID().String())
+	})
+
+	t.Run("extracts 128-bit ids from tags", func(t *testing.T) {
+		ctx := p.Extract(context.Background(), propagation.MapCarrier{
+			datadogTraceIDHeader:          "1234567890123456789",
+			datadogParentIDHeader:         "987654321",
+			datadogSamplingPriorityHeader: "0",
+			datadogTagsHeader:             "_dd.p.dm=-1,_dd.p.tid=640cfd8d00000000",
+		})
+		sc := trace.SpanContextFromContext(ctx)
+		assert.False(t, sc.IsSampled())
+		assert.Equal(t, "640cfd8d00000000112210f47de98115", sc.TraceID().String())
+	})
+
+	t.Run("ignores invalid headers", func(t *testing.T) {
+		for _, carrier := range []propagation.MapCarrier{
+			{},
+			{datadogTraceIDHeader: "abc", datadogParentIDHeader: "1"},
+			{datadogTraceIDHeader: "0", datadogParentIDHeader: "1"},
+			{datadogTraceIDHeader: "1", datadogParentIDHeader: "-5"},
+		} {
+			ctx := p.Extract(context.Background(), carrier)
+			assert.False(t, trace.SpanContextFromContext(ctx).IsValid(), carrier)
+		}
+	})
+
+	t.Run("injects round trip", func(t *testing.T) {
+		traceID, _ := trace.TraceIDFromHex("640cfd8d00000000112210f47de98115")
+		spanID, _ := trace.SpanIDFromHex("000000003ade68b1")
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: trace.FlagsSampled,
+		}))
+
+		carrier := propagation.MapCarrier{}
+		p.Inject(ctx, carrier)
+		assert.Equal(t, propagation.MapCarrier{
+			datadogTraceIDHeader:          "1234567890123456789",
+			datadogParentIDHeader:         "987654321",
+			datadogSamplingPriorityHeader: "1",
+			datadogTagsHeader:             "_dd.p.tid=640cfd8d00000000",
+		}, carrier)
+
+		sc := trace.SpanContextFromContext(p.Extract(context.Background(), carrier))
+		assert.Equal(t, traceID, sc.TraceID())
+		assert.Equal(t, spanID, sc.SpanID())
+	})
+
+	t.Run("merges existing tags", func(t *testing.T) {
+		traceID, _ := trace.TraceIDFromHex("640cfd8d00000000112210f47de98115")
+		spanID, _ := trace.SpanIDFromHex("000000003ade68b1")
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID,
+			SpanID:  spanID,
+		}))
+
+		carrier := propagation.MapCarrier{datadogTagsHeader: "_dd.p.dm=-1,_dd.p.tid=0000000000000001"}
+		p.Inject(ctx, carrier)
+		assert.Equal(t, "_dd.p.dm=-1,_dd.p.tid=640cfd8d00000000", carrier[datadogTagsHeader])
+
+		traceID, _ = trace.TraceIDFromHex("0000000000000000112210f47de98115")
+		ctx = trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID,
+			SpanID:  spanID,
+		}))
+		carrier = propagation.MapCarrier{datadogTagsHeader: "_dd.p.dm=-1,_dd.p.tid=640cfd8d00000000"}
+		p.Inject(ctx, carrier)
+		assert.Equal(t, "_dd.p.dm=-1", carrier[datadogTagsHeader])
+	})
+
+	t.Run("selectable by name", func(t *testing.T) {
+		propagator, err := newTextMapPropagator([]string{"datadog", "tracecontext"}, nil)
+		assert.NoError(t, err)
+		assert.Contains(t, propagator.Fields(), datadogTraceIDHeader)
+	})
+}
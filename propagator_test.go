@@ -0,0 +1,149 @@
+package tracingx
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/fx"
+)
+
+// legacyPropagator implements an X-Request-Trace: <trace-id>:<span-id> scheme
+type legacyPropagator struct{}
+
+func newLegacyPropagator() *legacyPropagator { return &legacyPropagator{} }
+
+func (legacyPropagator) Inject(ctx context.Context, carrier Carrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if sc.IsValid() {
+		carrier.Set("X-Request-Trace", sc.TraceID().String()+":"+sc.SpanID().String())
+	}
+}
+
+func (legacyPropagator) Extract(ctx context.Context, carrier Carrier) context.Context {
+	traceHex, spanHex, ok := strings.Cut(carrier.Get("X-Request-Trace"), ":")
+	if !ok {
+		return ctx
+	}
+	traceID, err := trace.TraceIDFromHex(traceHex)
+	if err != nil {
+		return ctx
+	}
+	spanID, err := trace.SpanIDFromHex(spanHex)
+	if err != nil {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	}))
+}
+
+func (legacyPropagator) Fields() []string { return []string{"X-Request-Trace"} }
+
+func TestCustomPropagator(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0},
+		getTestLogger(), withPropagators(newLegacyPropagator()))
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	const legacy = "11111111111111111111111111111111:2222222222222222"
+
+	t.Run("extracts the custom scheme as a fallback", func(t *testing.T) {
+		ctx, err := provider.Extract(context.Background(), HeaderCarrier(http.Header{"X-Request-Trace": {legacy}}))
+		require.NoError(t, err)
+		_, span := provider.Start(ctx, "handler")
+		defer span.End()
+		assert.Equal(t, "11111111111111111111111111111111", span.TraceID())
+		assert.Equal(t, "2222222222222222", span.ParentSpanID())
+	})
+
+	t.Run("traceparent takes precedence", func(t *testing.T) {
+		ctx, err := provider.Extract(context.Background(), HeaderCarrier(http.Header{
+			"X-Request-Trace": {legacy},
+			"Traceparent":     {validTraceParent},
+		}))
+		require.NoError(t, err)
+		_, span := provider.Start(ctx, "handler")
+		defer span.End()
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.TraceID())
+	})
+
+	t.Run("injects all schemes", func(t *testing.T) {
+		ctx, span := provider.Start(context.Background(), "client")
+		defer span.End()
+
+		header := http.Header{}
+		require.NoError(t, provider.Inject(ctx, HeaderCarrier(header)))
+		assert.Equal(t, FormatTraceParent(span), header.Get("Traceparent"))
+		assert.Equal(t, span.TraceID()+":"+span.SpanID(), header.Get("X-Request-Trace"))
+	})
+
+	t.Run("custom extraction is not counted as missing", func(t *testing.T) {
+		before := provider.Diagnostics().Propagation
+		_, err := provider.Extract(context.Background(), HeaderCarrier(http.Header{"X-Request-Trace": {legacy}}))
+		require.NoError(t, err)
+		after := provider.Diagnostics().Propagation
+		assert.Equal(t, before.Extracts+1, after.Extracts)
+		assert.Equal(t, before.Missing, after.Missing)
+	})
+}
+
+func TestProvidePropagator(t *testing.T) {
+	var got []Propagator
+	app := fx.New(
+		fx.NopLogger,
+		ProvidePropagator(newLegacyPropagator),
+		fx.Invoke(func(p struct {
+			fx.In
+			Propagators []Propagator `group:"tracingx.propagators"`
+		}) {
+			got = p.Propagators
+		}),
+	)
+	require.NoError(t, app.Err())
+	require.Len(t, got, 1)
+	assert.Equal(t, []string{"X-Request-Trace"}, got[0].Fields())
+}
+
+func TestConfiguredPropagators(t *testing.T) {
+	t.Run("jaeger", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			Propagators: []string{"jaeger", "tracecontext", "baggage"},
+		}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		ctx, err := provider.Extract(context.Background(), HeaderCarrier(http.Header{
+			"Uber-Trace-Id": {"4bf92f3577b34da6a3ce929d0e0e4736:00f067aa0ba902b7:0:1"},
+		}))
+		require.NoError(t, err)
+		ctx, span := provider.Start(ctx, "handler")
+		defer span.End()
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.TraceID())
+		assert.Equal(t, "00f067aa0ba902b7", span.ParentSpanID())
+
+		header := http.Header{}
+		require.NoError(t, provider.Inject(ctx, HeaderCarrier(header)))
+		assert.Equal(t, span.TraceID()+":"+span.SpanID()+":0:1", header.Get("Uber-Trace-Id"))
+		assert.Equal(t, FormatTraceParent(span), header.Get("Traceparent"))
+	})
+
+	t.Run("rejects unknown propagators", func(t *testing.T) {
+		_, err := newOTLPProvider(Config{ServiceName: "test-service", Propagators: []string{"bogus"}}, getTestLogger())
+		assert.ErrorContains(t, err, `unknown propagator "bogus"`)
+
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service"}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+		assert.ErrorContains(t, provider.Reconfigure(Config{ServiceName: "test-service", Propagators: []string{"bogus"}}), "unknown propagator")
+	})
+}
@@ -0,0 +1,97 @@
+package tracingx
+
+import (
+	"context"
+	"encoding/hex"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// xrayTraceHeader is the AWS X-Ray trace header, e.g.
+// Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1
+const xrayTraceHeader = "x-amzn-trace-id"
+
+// xrayPropagator propagates trace context in the X-Amzn-Trace-Id header read
+// and written by AWS load balancers, API Gateway, Lambda and the X-Ray SDKs
+type xrayPropagator struct{}
+
+var _ propagation.TextMapPropagator = xrayPropagator{}
+
+func (xrayPropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	sampled := "0"
+	if sc.IsSampled() {
+		sampled = "1"
+	}
+	carrier.Set(xrayTraceHeader, "Root="+formatXRayTraceID(sc.TraceID())+";Parent="+sc.SpanID().String()+";Sampled="+sampled)
+}
+
+func (xrayPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	var traceID trace.TraceID
+	var spanID trace.SpanID
+	var flags trace.TraceFlags
+	for _, part := range strings.Split(carrier.Get(xrayTraceHeader), ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "Root":
+			id, ok := parseXRayTraceID(value)
+			if !ok {
+				return ctx
+			}
+			traceID = id
+		case "Parent":
+			id, err := trace.SpanIDFromHex(value)
+			if err != nil {
+				return ctx
+			}
+			spanID = id
+		case "Sampled":
+			if value == "1" {
+				flags = trace.FlagsSampled
+			}
+		}
+	}
+	if !traceID.IsValid() || !spanID.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: flags,
+		Remote:     true,
+	}))
+}
+
+func (xrayPropagator) Fields() []string {
+	return []string{xrayTraceHeader}
+}
+
+// formatXRayTraceID returns traceID in X-Ray's form: version 1, then the
+// high 32 bits (the trace's start time) and the remaining 96 bits
+func formatXRayTraceID(traceID trace.TraceID) string {
+	id := traceID.String()
+	return "1-" + id[:8] + "-" + id[8:]
+}
+
+// parseXRayTraceID parses an X-Ray trace ID such as
+// 1-5759e988-bd862e3fe1be46a994272793
+func parseXRayTraceID(id string) (trace.TraceID, bool) {
+	version, rest, ok := strings.Cut(id, "-")
+	if !ok || version != "1" {
+		return trace.TraceID{}, false
+	}
+	epoch, random, ok := strings.Cut(rest, "-")
+	if !ok || len(epoch) != 8 || len(random) != 24 {
+		return trace.TraceID{}, false
+	}
+	var traceID trace.TraceID
+	if _, err := hex.Decode(traceID[:], []byte(epoch+random)); err != nil {
+		return trace.TraceID{}, false
+	}
+	return traceID, traceID.IsValid()
+}
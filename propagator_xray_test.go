@@ -0,0 +1,71 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestXRayPropagator(t *testing.T) {
+	p := xrayPropagator{}
+
+	t.Run("extracts the trace header", func(t *testing.T) {
+		ctx := p.Extract(context.Background(), propagation.MapCarrier{
+			xrayTraceHeader: "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1;Lineage=a87bd80c:1|68fd508a:5",
+		})
+		sc := trace.SpanContextFromContext(ctx)
+		assert.True(t, sc.IsRemote())
+		assert.True(t, sc.IsSampled())
+		assert.Equal(t, "5759e988bd862e3fe1be46a994272793", sc.TraceID().String())
+		assert.Equal(t, "53995c3f42cd8ad8", sc.SpanID().String())
+	})
+
+	t.Run("leaves deferred decisions unsampled", func(t *testing.T) {
+		ctx := p.Extract(context.Background(), propagation.MapCarrier{
+			xrayTraceHeader: "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=?",
+		})
+		sc := trace.SpanContextFromContext(ctx)
+		assert.True(t, sc.IsValid())
+		assert.False(t, sc.IsSampled())
+	})
+
+	t.Run("ignores invalid headers", func(t *testing.T) {
+		for _, header := range []string{
+			"",
+			"Root=1-5759e988-bd862e3fe1be46a994272793",
+			"Root=2-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8",
+			"Root=1-5759e98-bd862e3fe1be46a9942727930;Parent=53995c3f42cd8ad8",
+			"Root=1-5759e988-bd862e3fe1be46a99427279z;Parent=53995c3f42cd8ad8",
+			"Root=1-5759e988-bd862e3fe1be46a994272793;Parent=xyz",
+		} {
+			ctx := p.Extract(context.Background(), propagation.MapCarrier{xrayTraceHeader: header})
+			assert.False(t, trace.SpanContextFromContext(ctx).IsValid(), header)
+		}
+	})
+
+	t.Run("injects round trip", func(t *testing.T) {
+		traceID, _ := trace.TraceIDFromHex("5759e988bd862e3fe1be46a994272793")
+		spanID, _ := trace.SpanIDFromHex("53995c3f42cd8ad8")
+		ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: traceID,
+			SpanID:  spanID,
+		}))
+		carrier := propagation.MapCarrier{}
+		p.Inject(ctx, carrier)
+		assert.Equal(t, "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=0", carrier[xrayTraceHeader])
+
+		sc := trace.SpanContextFromContext(p.Extract(context.Background(), carrier))
+		assert.Equal(t, traceID, sc.TraceID())
+		assert.Equal(t, spanID, sc.SpanID())
+	})
+
+	t.Run("is registered as xray", func(t *testing.T) {
+		propagator, err := newTextMapPropagator([]string{"xray"}, nil)
+		require.NoError(t, err)
+		assert.Contains(t, propagator.Fields(), xrayTraceHeader)
+	})
+}
@@ -0,0 +1,108 @@
+package tracingx
+
+import (
+	"net"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/gostratum/core/logx"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// DatadogPropagators are the propagators used by the datadog provider when
+// Config.Propagators is empty: x-datadog-* headers for Datadog tracing
+// libraries, and W3C trace context, which wins when both are present
+var DatadogPropagators = []string{"datadog", "tracecontext", "baggage"}
+
+// DatadogConfig contains Datadog APM configuration. Spans are sent over OTLP
+// to the Datadog Agent's OTLP gRPC intake (otlp_config.receiver in the Agent
+// configuration), so no intermediate collector is needed.
+type DatadogConfig struct {
+	// AgentHost is the Datadog Agent's host (defaults to DD_AGENT_HOST, then
+	// localhost)
+	AgentHost string `mapstructure:"agent_host"`
+
+	// OTLPPort is the port of the Agent's OTLP gRPC intake
+	OTLPPort int `mapstructure:"otlp_port" default:"4317"`
+
+	// Env is the environment of unified service tagging, recorded as
+	// deployment.environment.name (defaults to DD_ENV)
+	Env string `mapstructure:"env"`
+
+	// Version is the service version of unified service tagging, recorded as
+	// service.version (defaults to DD_VERSION)
+	Version string `mapstructure:"version"`
+}
+
+// endpoint returns the address of the Agent's OTLP intake
+func (c DatadogConfig) endpoint() string {
+	host := firstNonEmpty(c.AgentHost, os.Getenv("DD_AGENT_HOST"), "localhost")
+	port := c.OTLPPort
+	if port == 0 {
+		port = 4317
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// otlpConfig returns base, the OTLP queue and batch settings, pointed at the
+// Agent. The Agent's intake is plaintext, like the Agent's other local ports.
+func (c DatadogConfig) otlpConfig(base OTLPConfig) OTLPConfig {
+	base.Endpoint = c.endpoint()
+	base.Insecure = true
+	return base
+}
+
+// resourceAttributes returns the unified service tagging attributes
+func (c DatadogConfig) resourceAttributes() []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if env := firstNonEmpty(c.Env, os.Getenv("DD_ENV")); env != "" {
+		attrs = append(attrs, deploymentEnvironmentAttribute(env))
+	}
+	if version := firstNonEmpty(c.Version, os.Getenv("DD_VERSION")); version != "" {
+		attrs = append(attrs, serviceVersionAttribute(version))
+	}
+	return attrs
+}
+
+// propagators returns the propagators to compose: Config.Propagators, or
+// when it is empty the defaults of every exporter, in order
+func (c Config) propagators() []string {
+	if len(c.Propagators) > 0 {
+		return c.Propagators
+	}
+	var propagators []string
+	for _, exporter := range c.exporterConfigs() {
+		var defaults []string
+		switch exporter.Provider {
+		case "datadog":
+			defaults = DatadogPropagators
+		case "xray":
+			defaults = XRayPropagators
+		}
+		for _, name := range defaults {
+			if !slices.Contains(propagators, name) {
+				propagators = append(propagators, name)
+			}
+		}
+	}
+	return propagators
+}
+
+// newDatadogProvider creates a tracing provider exporting to the Datadog
+// Agent. Like the Zipkin provider it shares the OTLP provider's pipeline;
+// only the exporter settings and default propagators differ.
+func newDatadogProvider(config Config, logger logx.Logger, providerOpts ...providerOption) (Provider, error) {
+	config.Provider = "datadog"
+	return newOTLPProvider(config, logger, providerOpts...)
+}
+
+// firstNonEmpty returns the first non-empty value, or an empty string
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
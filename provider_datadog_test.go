@@ -0,0 +1,77 @@
+package tracingx
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/gostratum/core/logx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatadogProvider(t *testing.T) {
+	collector := newFakeCollector(t)
+	host, port, err := net.SplitHostPort(collector.endpoint)
+	require.NoError(t, err)
+	agentPort, err := strconv.Atoi(port)
+	require.NoError(t, err)
+	t.Setenv("DD_ENV", "staging")
+
+	result, err := NewTracer(Params{
+		Config: Config{
+			Enabled:     true,
+			Provider:    "datadog",
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			Datadog:     DatadogConfig{AgentHost: host, OTLPPort: agentPort, Version: "1.4.2"},
+		},
+		Logger: logx.NewNoopLogger(),
+	})
+	require.NoError(t, err)
+	provider := result.Provider
+	defer provider.Shutdown(context.Background())
+
+	ctx, span := provider.Start(context.Background(), "charge")
+	headers := map[string]string{}
+	require.NoError(t, provider.Inject(ctx, MapCarrier(headers)))
+	assert.NotEmpty(t, headers[datadogTraceIDHeader])
+	assert.NotEmpty(t, headers["traceparent"])
+	span.End()
+	require.NoError(t, provider.ForceFlush(context.Background()))
+	assert.Equal(t, []string{"charge"}, spanNames(collector))
+
+	res := map[string]string{}
+	for _, kv := range provider.(*otlpProvider).current().resource.Attributes() {
+		res[string(kv.Key)] = kv.Value.Emit()
+	}
+	assert.Equal(t, "staging", res["deployment.environment.name"])
+	assert.Equal(t, "1.4.2", res["service.version"])
+	assert.Equal(t, "datadog", provider.Diagnostics().Provider)
+
+	// Datadog tracing libraries propagate over x-datadog-* headers only
+	extracted, err := provider.Extract(context.Background(), MapCarrier(map[string]string{
+		datadogTraceIDHeader:  "1234567890123456789",
+		datadogParentIDHeader: "987654321",
+	}))
+	require.NoError(t, err)
+	_, child := provider.Start(extracted, "refund")
+	defer child.End()
+	assert.True(t, child.IsRemoteParent())
+}
+
+func TestDatadogConfig(t *testing.T) {
+	t.Setenv("DD_AGENT_HOST", "10.0.0.7")
+	t.Setenv("DD_VERSION", "")
+	assert.Equal(t, "10.0.0.7:4317", DatadogConfig{}.endpoint())
+	assert.Equal(t, "datadog-agent:4319", DatadogConfig{AgentHost: "datadog-agent", OTLPPort: 4319}.endpoint())
+	assert.Empty(t, DatadogConfig{}.resourceAttributes())
+
+	otlp := DatadogConfig{}.otlpConfig(OTLPConfig{Endpoint: "collector:4317", QueueSize: 8192})
+	assert.Equal(t, OTLPConfig{Endpoint: "10.0.0.7:4317", Insecure: true, QueueSize: 8192}, otlp)
+
+	assert.Equal(t, DatadogPropagators, Config{Provider: "datadog"}.propagators())
+	assert.Equal(t, []string{"tracecontext"}, Config{Provider: "datadog", Propagators: []string{"tracecontext"}}.propagators())
+	assert.Empty(t, Config{Provider: "otlp"}.propagators())
+}
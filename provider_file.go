@@ -0,0 +1,157 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/gostratum/core/logx"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// FileConfig contains file exporter configuration
+type FileConfig struct {
+	// Path is the file spans are written to as OTLP/JSON, one batch per line
+	Path string `mapstructure:"path" default:"traces.jsonl"`
+
+	// MaxSizeMB rotates the file before it grows past this size (0 disables
+	// rotation)
+	MaxSizeMB int `mapstructure:"max_size_mb" default:"100"`
+
+	// MaxBackups is the number of rotated files kept as Path.1 (newest) to
+	// Path.N; older ones are removed
+	MaxBackups int `mapstructure:"max_backups" default:"5"`
+}
+
+// maxSize returns the rotation size in bytes, 0 when rotation is disabled
+func (c FileConfig) maxSize() int64 {
+	return int64(c.MaxSizeMB) << 20
+}
+
+// newFileProvider creates a tracing provider writing spans to a file. Like
+// the Zipkin provider it shares the OTLP provider's pipeline; only the
+// exporter differs.
+func newFileProvider(config Config, logger logx.Logger, providerOpts ...providerOption) (Provider, error) {
+	config.Provider = "file"
+	return newOTLPProvider(config, logger, providerOpts...)
+}
+
+// newFileWorkers creates the export worker writing spans to the configured file
+func newFileWorkers(ctx context.Context, config FileConfig, clock Clock, total *exportStats) ([]exportWorker, error) {
+	if config.Path == "" {
+		return nil, errors.New("failed to create file exporter: path is required")
+	}
+	exporter, err := otlptrace.New(ctx, &fileClient{config: config})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file exporter: %w", err)
+	}
+	return []exportWorker{newExportWorker(exporter, nil, OTLPConfig{}, clock, total)}, nil
+}
+
+// fileClient is an OTLP client appending each uploaded batch to a rotating
+// file as one line of OTLP/JSON, the format read by the collector's file
+// receiver
+type fileClient struct {
+	config FileConfig
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// Start opens the file for appending, creating it and its directory as needed
+func (c *fileClient) Start(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(c.config.Path), 0o755); err != nil {
+		return err
+	}
+	return c.open()
+}
+
+// Stop closes the file
+func (c *fileClient) Stop(context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file == nil {
+		return nil
+	}
+	err := c.file.Close()
+	c.file = nil
+	return err
+}
+
+// UploadTraces writes spans as one line, rotating the file first when the
+// line would grow it past the configured size
+func (c *fileClient) UploadTraces(_ context.Context, spans []*tracepb.ResourceSpans) error {
+	line, err := protojson.Marshal(&tracepb.TracesData{ResourceSpans: spans})
+	if err != nil {
+		return fmt.Errorf("failed to encode spans: %w", err)
+	}
+	line = append(line, '\n')
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file == nil {
+		return errors.New("file exporter is stopped")
+	}
+	if limit := c.config.maxSize(); limit > 0 && c.size > 0 && c.size+int64(len(line)) > limit {
+		if err := c.rotate(); err != nil {
+			return fmt.Errorf("failed to rotate %s: %w", c.config.Path, err)
+		}
+	}
+	n, err := c.file.Write(line)
+	c.size += int64(n)
+	return err
+}
+
+// open opens the file for appending and records its current size
+func (c *fileClient) open() error {
+	file, err := os.OpenFile(c.config.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	c.file, c.size = file, info.Size()
+	return nil
+}
+
+// rotate shifts the backups up by one, dropping the oldest, moves the file
+// to Path.1 and reopens an empty one
+func (c *fileClient) rotate() error {
+	if err := c.file.Close(); err != nil {
+		return err
+	}
+	c.file = nil
+
+	path := c.config.Path
+	backup := func(i int) string { return path + "." + strconv.Itoa(i) }
+	if c.config.MaxBackups <= 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return c.open()
+	}
+	if err := os.Remove(backup(c.config.MaxBackups)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for i := c.config.MaxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(backup(i), backup(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if err := os.Rename(path, backup(1)); err != nil {
+		return err
+	}
+	return c.open()
+}
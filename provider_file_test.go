@@ -0,0 +1,108 @@
+package tracingx
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gostratum/core/logx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// readTraceFile decodes the OTLP/JSON batches written to path
+func readTraceFile(t *testing.T, path string) []*tracepb.TracesData {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	var batches []*tracepb.TracesData
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 1<<20)
+	for scanner.Scan() {
+		batch := &tracepb.TracesData{}
+		require.NoError(t, protojson.Unmarshal(scanner.Bytes(), batch))
+		batches = append(batches, batch)
+	}
+	require.NoError(t, scanner.Err())
+	return batches
+}
+
+func TestFileProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spans", "traces.jsonl")
+	result, err := NewTracer(Params{
+		Config: Config{
+			Enabled:     true,
+			Provider:    "file",
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			File:        FileConfig{Path: path},
+		},
+		Logger: logx.NewNoopLogger(),
+	})
+	require.NoError(t, err)
+	provider := result.Provider
+
+	_, charge := provider.Start(context.Background(), "charge", WithAttributes(map[string]any{"payment.id": "pay_1"}))
+	charge.End()
+	require.NoError(t, provider.ForceFlush(context.Background()))
+	_, refund := provider.Start(context.Background(), "refund")
+	refund.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	batches := readTraceFile(t, path)
+	require.Len(t, batches, 2, "one line per exported batch")
+	spans := batches[0].ResourceSpans[0].ScopeSpans[0].Spans
+	require.Len(t, spans, 1)
+	assert.Equal(t, "charge", spans[0].Name)
+	assert.Equal(t, charge.TraceID(), hex.EncodeToString(spans[0].TraceId))
+	assert.Equal(t, "payment.id", spans[0].Attributes[0].Key)
+	assert.Equal(t, "refund", batches[1].ResourceSpans[0].ScopeSpans[0].Spans[0].Name)
+
+	diag := provider.Diagnostics()
+	assert.Equal(t, "file", diag.Provider)
+	assert.Equal(t, uint64(2), diag.SpansExported)
+}
+
+func TestFileProviderRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+	client := &fileClient{config: FileConfig{Path: path, MaxBackups: 2}}
+	require.NoError(t, client.Start(context.Background()))
+	defer client.Stop(context.Background())
+
+	// Rotate after every line
+	client.config.MaxSizeMB = 1
+	for _, name := range []string{"first", "second", "third", "fourth"} {
+		client.size = client.config.maxSize()
+		require.NoError(t, client.UploadTraces(context.Background(), []*tracepb.ResourceSpans{{
+			ScopeSpans: []*tracepb.ScopeSpans{{Spans: []*tracepb.Span{{Name: name}}}},
+		}}))
+	}
+
+	for file, name := range map[string]string{path: "fourth", path + ".1": "third", path + ".2": "second"} {
+		batches := readTraceFile(t, file)
+		require.Len(t, batches, 1, file)
+		assert.Equal(t, name, batches[0].ResourceSpans[0].ScopeSpans[0].Spans[0].Name)
+	}
+	_, err := os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err), "backups beyond MaxBackups are removed")
+}
+
+func TestFileProviderErrors(t *testing.T) {
+	_, err := newFileProvider(Config{ServiceName: "test-service"}, getTestLogger())
+	assert.ErrorContains(t, err, "path is required")
+
+	blocker := filepath.Join(t.TempDir(), "blocker")
+	require.NoError(t, os.WriteFile(blocker, nil, 0o644))
+	_, err = newFileProvider(Config{ServiceName: "test-service", File: FileConfig{Path: filepath.Join(blocker, "traces.jsonl")}}, getTestLogger())
+	assert.ErrorContains(t, err, "failed to create file exporter")
+
+	client := &fileClient{config: FileConfig{Path: filepath.Join(t.TempDir(), "traces.jsonl")}}
+	assert.ErrorContains(t, client.UploadTraces(context.Background(), nil), "stopped")
+}
@@ -2,6 +2,7 @@ package tracingx
 
 import (
 	"context"
+	"time"
 )
 
 // noopProvider implements a no-op tracing provider for testing
@@ -17,11 +18,11 @@ func (p *noopProvider) Start(ctx context.Context, operationName string, opts ...
 	return ContextWithSpan(ctx, span), span
 }
 
-func (p *noopProvider) Extract(ctx context.Context, carrier any) (context.Context, error) {
+func (p *noopProvider) Extract(ctx context.Context, carrier Carrier) (context.Context, error) {
 	return ctx, nil
 }
 
-func (p *noopProvider) Inject(ctx context.Context, carrier any) error {
+func (p *noopProvider) Inject(ctx context.Context, carrier Carrier) error {
 	return nil
 }
 
@@ -29,15 +30,66 @@ func (p *noopProvider) Shutdown(ctx context.Context) error {
 	return nil
 }
 
+func (p *noopProvider) SelfTest(ctx context.Context) error {
+	return nil
+}
+
+func (p *noopProvider) EmitSynthetic(ctx context.Context, profile SyntheticProfile) (string, error) {
+	return "", nil
+}
+
+func (p *noopProvider) Diagnostics() Diagnostics {
+	return Diagnostics{Provider: "noop", ExporterHealthy: true}
+}
+
+func (p *noopProvider) Stats() ExportStats {
+	return ExportStats{}
+}
+
+// closedReady is the readiness channel of a provider with nothing to connect
+var closedReady = func() chan struct{} {
+	ready := make(chan struct{})
+	close(ready)
+	return ready
+}()
+
+func (p *noopProvider) Ready() <-chan struct{} {
+	return closedReady
+}
+
+func (p *noopProvider) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+func (p *noopProvider) SetSampleRate(rate float64) error {
+	return nil
+}
+
+func (p *noopProvider) Reconfigure(config Config) error {
+	return nil
+}
+
 // noopSpan implements the Span interface
 type noopSpan struct {
 	ctx context.Context
 }
 
-func (s *noopSpan) End()                         {}
-func (s *noopSpan) SetTag(key string, value any) {}
-func (s *noopSpan) SetError(err error)           {}
-func (s *noopSpan) LogFields(fields ...Field)    {}
-func (s *noopSpan) Context() context.Context     { return s.ctx }
-func (s *noopSpan) TraceID() string              { return "" }
-func (s *noopSpan) SpanID() string               { return "" }
+func (s *noopSpan) End()                                       {}
+func (s *noopSpan) SetTag(key string, value any)               {}
+func (s *noopSpan) SetTagIfAbsent(key string, value any) bool  { return false }
+func (s *noopSpan) SetTagString(key, value string)             {}
+func (s *noopSpan) SetTagInt(key string, value int)            {}
+func (s *noopSpan) SetTagBool(key string, value bool)          {}
+func (s *noopSpan) SetTagFloat(key string, value float64)      {}
+func (s *noopSpan) SetError(err error)                         {}
+func (s *noopSpan) RecordError(err error, opts ...ErrorOption) {}
+func (s *noopSpan) SetErrors(errs ...error)                    {}
+func (s *noopSpan) LogFields(fields ...Field)                  {}
+func (s *noopSpan) Context() context.Context                   { return s.ctx }
+func (s *noopSpan) TraceID() string                            { return "" }
+func (s *noopSpan) SpanID() string                             { return "" }
+func (s *noopSpan) Duration() time.Duration                    { return 0 }
+func (s *noopSpan) ParentSpanID() string                       { return "" }
+func (s *noopSpan) IsRoot() bool                               { return false }
+func (s *noopSpan) IsSampled() bool                            { return false }
+func (s *noopSpan) IsRemoteParent() bool                       { return false }
@@ -44,7 +44,7 @@ func TestNoopProvider(t *testing.T) {
 			"traceparent": "00-12345-67890-01",
 		}
 
-		resultCtx, err := provider.Extract(ctx, carrier)
+		resultCtx, err := provider.Extract(ctx, MapCarrier(carrier))
 		assert.NoError(t, err)
 		assert.Equal(t, ctx, resultCtx)
 	})
@@ -53,7 +53,7 @@ func TestNoopProvider(t *testing.T) {
 		ctx := context.Background()
 		carrier := make(map[string]string)
 
-		err := provider.Inject(ctx, carrier)
+		err := provider.Inject(ctx, MapCarrier(carrier))
 		assert.NoError(t, err)
 		assert.Empty(t, carrier)
 	})
@@ -152,10 +152,10 @@ func TestNoopSpanCompleteWorkflow(t *testing.T) {
 
 		// Extract/inject for distributed tracing
 		carrier := make(map[string]string)
-		err := provider.Inject(childCtx, carrier)
+		err := provider.Inject(childCtx, MapCarrier(carrier))
 		assert.NoError(t, err)
 
-		newCtx, err := provider.Extract(ctx, carrier)
+		newCtx, err := provider.Extract(ctx, MapCarrier(carrier))
 		assert.NoError(t, err)
 		assert.NotNil(t, newCtx)
 
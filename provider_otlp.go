@@ -2,121 +2,428 @@ package tracingx
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gostratum/core/logx"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
-	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-// otlpProvider implements the Provider interface using OpenTelemetry
+// ErrProviderShutdown is returned by provider operations that cannot
+// complete once the provider has been shut down
+var ErrProviderShutdown = errors.New("tracing provider is shut down")
+
+// otlpProvider implements the Provider interface using OpenTelemetry. The
+// config-dependent parts live in an otlpPipeline swapped by Reconfigure.
 type otlpProvider struct {
+	logger    logx.Logger
+	options   providerOptions
+	requestID RequestIDFunc
+	clock     Clock
+
+	propagation propagationStats
+	strict      strictReporter
+
+	// configTraceID is the trace recording the provider's sampling changes
+	configTraceID trace.TraceID
+
+	mu       sync.Mutex // serializes Reconfigure and Shutdown
+	pipeline atomic.Pointer[otlpPipeline]
+	closed   atomic.Bool
+}
+
+// otlpPipeline is the exporter, sampler and tracer provider built from one Config
+type otlpPipeline struct {
 	config         Config
-	logger         logx.Logger
 	tracer         trace.Tracer
 	tracerProvider *sdktrace.TracerProvider
+	exporter       sdktrace.SpanExporter
+	batcher        sdktrace.SpanProcessor
+	workers        []exportWorker
+	stats          *exportStats
+	ratio          *ratioSampler
+	sampler        sdktrace.Sampler
+	resource       *resource.Resource
+	budget         *traceBudget
+	profiles       attributeProfiles
+	deployment     []attribute.KeyValue
+	peerServices   *peerServices
+	inbound        *inboundTrust
+	// strict is the validated Config.Strict mode
+	strict string
+	// dryRun counts the effect of the candidate rules, nil without a dry run
+	dryRun *dryRunStats
+	// ready is closed once the pipeline's exporter connections are established
+	ready <-chan struct{}
+	// active counts spans started on this pipeline that have not ended yet
+	active atomic.Int64
 }
 
 // newOTLPProvider creates a new OTLP tracing provider
-func newOTLPProvider(config Config, logger logx.Logger) (Provider, error) {
-	ctx := context.Background()
+func newOTLPProvider(config Config, logger logx.Logger, providerOpts ...providerOption) (Provider, error) {
+	options := applyProviderOptions(providerOpts...)
+	propagator, err := newTextMapPropagator(config.propagators(), options.propagators)
+	if err != nil {
+		return nil, err
+	}
+	pipeline, err := newOTLPPipeline(context.Background(), config, logger, options, &exportStats{})
+	if err != nil {
+		return nil, err
+	}
 
-	// Create OTLP exporter
-	opts := []otlptracegrpc.Option{
-		otlptracegrpc.WithEndpoint(config.OTLP.Endpoint),
+	// Set global tracer provider
+	otel.SetTracerProvider(pipeline.tracerProvider)
+
+	// Set global propagator for distributed tracing
+	otel.SetTextMapPropagator(propagator)
+
+	provider := &otlpProvider{
+		logger:    logger,
+		options:   options,
+		requestID: options.requestID,
+		clock:     options.clock,
+
+		configTraceID: newTraceID(),
 	}
+	provider.pipeline.Store(pipeline)
 
-	if config.OTLP.Insecure {
-		opts = append(opts, otlptracegrpc.WithTLSCredentials(insecure.NewCredentials()))
+	diag := startupDiagnostics(config, pipeline.sampler, pipeline.resource)
+	logger.Info("OTLP tracing provider initialized", diagnosticFields(diag)...)
+	if config.StartupSpan {
+		// Bypasses sampling so the effective configuration is always visible in the backend
+		pipeline.batcher.OnEnd(provider.syntheticSpan(StartupSpanName, diagnosticAttributes(diag)...))
 	}
 
-	if len(config.OTLP.Headers) > 0 {
-		opts = append(opts, otlptracegrpc.WithHeaders(config.OTLP.Headers))
+	return provider, nil
+}
+
+// newOTLPPipeline creates the exporter, processors, sampler and tracer
+// provider described by config. Export counters accumulate in stats, which
+// outlives replaced pipelines.
+func newOTLPPipeline(ctx context.Context, config Config, logger logx.Logger, options providerOptions, stats *exportStats) (*otlpPipeline, error) {
+	// Validate the configuration before creating any exporter, so a rejected
+	// config leaves nothing to shut down
+	res, err := newServiceResource(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	exportFilter, err := newExportFilter(config.Export)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := config.ShadowTraffic.route(); err != nil {
+		return nil, err
+	}
+	strict, err := config.strictMode()
+	if err != nil {
+		return nil, err
+	}
+	profiles, err := newAttributeProfiles(config.Attributes.Profiles)
+	if err != nil {
+		return nil, err
+	}
+	inbound, err := newInboundTrust(ctx, config.InboundSampling, logger)
+	if err != nil {
+		return nil, err
+	}
+	ratio := newRatioSampler(config.SampleRate)
+	sampler := newSampler(config, ratio)
+	idGenerator, err := newIDGenerator(config.idGenerator(), options.clock)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := newPipelineSteps(config.Pipeline, config.Processors)
+	if err != nil {
+		return nil, err
+	}
+	// Config steps run in their stages, after the code processors of the
+	// same priority
+	processors, err := sortSpanProcessors(append(slices.Clip(options.processors), steps...))
+	if err != nil {
+		return nil, err
+	}
+	for _, pipeline := range config.Pipelines {
+		if _, err := pipeline.matcher(); err != nil {
+			return nil, err
+		}
+	}
+	var dryRun *dryRunStats
+	var dryRunProcessor sdktrace.SpanProcessor
+	if config.DryRun.Enabled {
+		dryRun = &dryRunStats{}
+		if dryRunProcessor, err = newDryRunProcessor(config.DryRun, dryRun); err != nil {
+			return nil, fmt.Errorf("invalid dry run: %w", err)
+		}
 	}
 
-	exporter, err := otlptracegrpc.New(ctx, opts...)
+	// Create the export workers. A failure shuts down the workers already
+	// created, with their batchers and connections.
+	var built []exportWorker
+	fail := func(err error) (*otlpPipeline, error) {
+		closeWorkers(built)
+		return nil, err
+	}
+	var workers []exportWorker
+	var teeGroups [][]exportWorker
+	switch {
+	case options.exporter != nil:
+		// Owned by the provider, which shuts it down on Shutdown rather than
+		// with each replaced pipeline
+		workers = []exportWorker{newExportWorker(sharedExporter{options.exporter}, nil, config.OTLP, options.clock, stats)}
+	case config.exporter() == "tee":
+		teeGroups, err = newTeeWorkers(ctx, config.Exporters, config.Warmup.Enabled, options.clock, stats)
+		for _, group := range teeGroups {
+			workers = append(workers, group...)
+		}
+	default:
+		workers, err = newWorkers(ctx, config.exporterConfig(), config.Warmup.Enabled, options.clock, stats)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+		return nil, err
+	}
+	built = append(built, workers...)
+	batcher := newWorkerProcessor(workers)
+	exporter := workers[0].exporter
+	if teeGroups != nil {
+		batcher = newTeeProcessor(teeGroups, stats)
+		exporter = newTeeExporter(teeGroups)
 	}
 
-	// Create resource with service name
-	res, err := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceNameKey.String(config.ServiceName),
-		),
-	)
+	exportFilter, shadowProcessor, shadowWorkers, err := newShadowProcessor(ctx, config.ShadowTraffic, exportFilter, config.Warmup.Enabled, options.clock)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create resource: %w", err)
+		return fail(err)
+	}
+	built = append(built, shadowWorkers...)
+	exportProcessors := []sdktrace.SpanProcessor{
+		newFilterProcessor(
+			newCompressionProcessor(batcher, config.Export.Compression),
+			stats.filter(exportFilter),
+		),
+	}
+	if config.SpanLog.Enabled {
+		exportProcessors = append(exportProcessors, newSpanLogProcessor(config.SpanLog, logger))
+	}
+	conns := workerConns(workers)
+	if shadowProcessor != nil {
+		exportProcessors = append(exportProcessors, shadowProcessor)
+		conns = append(conns, workerConns(shadowWorkers)...)
+	}
+	for _, pipeline := range config.Pipelines {
+		processor, pipelineWorkers, err := newPipelineProcessor(ctx, pipeline, exportFilter, config.Export.Compression, config.Warmup.Enabled)
+		if err != nil {
+			return fail(err)
+		}
+		built = append(built, pipelineWorkers...)
+		exportProcessors = append(exportProcessors, processor)
+		conns = append(conns, workerConns(pipelineWorkers)...)
+	}
+	var audit *auditProcessor
+	if config.Audit.Enabled {
+		var sink AuditSink
+		if options.auditSink != nil {
+			// Owned by the provider, which closes it on Shutdown rather than
+			// with each replaced pipeline
+			sink = sharedAuditSink{options.auditSink}
+		} else if sink, err = NewFileAuditSink(config.Audit.Path); err != nil {
+			return fail(err)
+		}
+		audit = newAuditProcessor(config, sink, logger)
 	}
 
-	// Create tracer provider
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
+	tpOpts := []sdktrace.TracerProviderOption{
 		sdktrace.WithResource(res),
-		sdktrace.WithSampler(sdktrace.TraceIDRatioBased(config.SampleRate)),
-	)
-
-	// Set global tracer provider
-	otel.SetTracerProvider(tp)
-
-	// Set global propagator for distributed tracing
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
-		propagation.TraceContext{},
-		propagation.Baggage{},
-	))
-
-	tracer := tp.Tracer("gostratum")
+		sdktrace.WithSampler(sampler),
+	}
+	if idGenerator != nil {
+		tpOpts = append(tpOpts, sdktrace.WithIDGenerator(idGenerator))
+	}
+	if len(processors) > 0 {
+		// A single SDK processor, so every export path sees processed spans
+		exportProcessors = []sdktrace.SpanProcessor{newStagedProcessor(processors, exportProcessors, audit, stats)}
+	} else if audit != nil {
+		exportProcessors = append(exportProcessors, audit)
+	}
+	for _, processor := range exportProcessors {
+		tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(processor))
+	}
+	if dryRunProcessor != nil {
+		tpOpts = append(tpOpts, sdktrace.WithSpanProcessor(dryRunProcessor))
+	}
+	tp := sdktrace.NewTracerProvider(tpOpts...)
 
-	logger.Info("OTLP tracing provider initialized",
-		logx.String("endpoint", config.OTLP.Endpoint),
-		logx.String("service", config.ServiceName),
+	tracer := tp.Tracer(config.Instrumentation.name(),
+		trace.WithInstrumentationVersion(config.Instrumentation.version()),
+		trace.WithSchemaURL(config.schemaURL()),
 	)
 
-	return &otlpProvider{
+	return &otlpPipeline{
 		config:         config,
-		logger:         logger,
 		tracer:         tracer,
 		tracerProvider: tp,
+		exporter:       exporter,
+		batcher:        batcher,
+		workers:        workers,
+		stats:          stats,
+		ratio:          ratio,
+		sampler:        sampler,
+		resource:       res,
+		budget:         newTraceBudget(config.MaxSpansPerTrace),
+		profiles:       profiles,
+		deployment:     config.Deployment.attributes(),
+		peerServices:   newPeerServices(config.PeerService, profiles.takePeerServices(), options.peerService),
+		inbound:        inbound,
+		strict:         strict,
+		dryRun:         dryRun,
+		ready:          warmConns(conns),
 	}, nil
 }
 
-// Start creates a new span
-func (p *otlpProvider) Start(ctx context.Context, operationName string, opts ...SpanOption) (context.Context, Span) {
-	config := applySpanOptions(opts...)
+// current returns the pipeline spans are currently started on
+func (p *otlpProvider) current() *otlpPipeline {
+	return p.pipeline.Load()
+}
 
-	// Convert span kind
-	var otelKind trace.SpanKind
-	switch config.Kind {
-	case SpanKindInternal:
-		otelKind = trace.SpanKindInternal
-	case SpanKindServer:
-		otelKind = trace.SpanKindServer
-	case SpanKindClient:
-		otelKind = trace.SpanKindClient
-	case SpanKindProducer:
-		otelKind = trace.SpanKindProducer
-	case SpanKindConsumer:
-		otelKind = trace.SpanKindConsumer
-	default:
-		otelKind = trace.SpanKindInternal
+// acquire returns the current pipeline with the span about to start counted
+// as active, so a concurrent Reconfigure does not shut it down before the
+// span ends
+func (p *otlpProvider) acquire() *otlpPipeline {
+	for {
+		pipeline := p.current()
+		pipeline.active.Add(1)
+		if p.current() == pipeline {
+			return pipeline
+		}
+		// Replaced meanwhile, so its drain may not have seen the span
+		pipeline.active.Add(-1)
 	}
+}
 
-	// Convert attributes
+// sharedExporter is an injected exporter whose lifetime is managed by the
+// provider, so shutting down a replaced pipeline leaves it open
+type sharedExporter struct {
+	sdktrace.SpanExporter
+}
+
+func (e sharedExporter) Shutdown(ctx context.Context) error { return nil }
+
+// newServiceResource creates the resource identifying the service
+func newServiceResource(ctx context.Context, config Config) (*resource.Resource, error) {
+	attrs := []attribute.KeyValue{serviceNameAttribute(config.ServiceName)}
+	for _, exporter := range config.exporterConfigs() {
+		switch exporter.Provider {
+		case "datadog":
+			attrs = append(attrs, exporter.Datadog.resourceAttributes()...)
+		case "xray":
+			attrs = append(attrs, exporter.XRay.resourceAttributes()...)
+		}
+	}
+	res, err := resource.New(ctx,
+		resource.WithSchemaURL(config.schemaURL()),
+		resource.WithAttributes(attrs...),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create resource: %w", err)
+	}
+	return res, nil
+}
+
+// newOTLPExporter creates an OTLP gRPC exporter from configuration. When
+// warm is set the exporter uses a connection dialed by tracingx, returned so
+// it can be connected ahead of the first export.
+func newOTLPExporter(ctx context.Context, config OTLPConfig, warm bool) (sdktrace.SpanExporter, *grpc.ClientConn, error) {
+	opts := []otlptracegrpc.Option{
+		otlptracegrpc.WithEndpoint(config.Endpoint),
+	}
+
+	creds := credentials.NewTLS(nil)
+	if config.Insecure {
+		creds = insecure.NewCredentials()
+		opts = append(opts, otlptracegrpc.WithTLSCredentials(creds))
+	}
+
+	if len(config.Headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(config.Headers))
+	}
+
+	var conn *grpc.ClientConn
+	if warm {
+		var err error
+		if conn, err = grpc.NewClient(config.Endpoint, grpc.WithTransportCredentials(creds)); err != nil {
+			return nil, nil, fmt.Errorf("failed to create OTLP connection: %w", err)
+		}
+		opts = append(opts, otlptracegrpc.WithGRPCConn(conn))
+	}
+
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		if conn != nil {
+			_ = conn.Close()
+		}
+		return nil, nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	if conn != nil {
+		return &connExporter{SpanExporter: exporter, conn: conn}, conn, nil
+	}
+	return exporter, nil, nil
+}
+
+// Start creates a new span. After Shutdown, or when instrumentation is
+// suppressed for ctx, it returns a no-op span.
+func (p *otlpProvider) Start(ctx context.Context, operationName string, opts ...SpanOption) (context.Context, Span) {
+	if p.closed.Load() || IsInstrumentationSuppressed(ctx) {
+		span := &noopSpan{ctx: ctx}
+		return ContextWithSpan(ctx, span), span
+	}
+
+	config := applySpanOptionsWithClock(p.clock, opts...)
+	pipeline := p.acquire()
+
+	// Convert attributes, with the defaults of the span kind
 	var attrs []attribute.KeyValue
+	if config.Kind == SpanKindClient {
+		pipeline.peerServices.apply(config.Attributes)
+	}
+	config.Attributes = pipeline.profiles.apply(config.Kind, config.Attributes)
 	for k, v := range config.Attributes {
-		attrs = append(attrs, toAttribute(k, v))
+		if key, ok := pipeline.config.Attributes.userKey(k); ok {
+			attrs = append(attrs, toAttribute(key, v))
+		}
+	}
+
+	// Stitch orphan roots to their upstream via the correlation ID
+	if id := CorrelationIDFromContext(ctx); id != "" && !trace.SpanContextFromContext(ctx).IsValid() {
+		attrs = append(attrs, attribute.String(CorrelationIDAttribute, id))
+	}
+
+	if p.requestID != nil {
+		if id := p.requestID(ctx); id != "" {
+			attrs = append(attrs, attribute.String(RequestIDAttribute, id))
+		}
+	}
+	attrs = append(attrs, pipeline.deployment...)
+	if config.allowlist != nil {
+		attrs = allowlistAttributes(attrs, config.allowlist)
+	}
+	if IsShadowTraffic(ctx) {
+		// Kept by allowlists: shadow spans are routed by it
+		attrs = append(attrs, attribute.Bool(ShadowTrafficAttribute, true))
 	}
 
 	// Start span
 	spanOpts := []trace.SpanStartOption{
-		trace.WithSpanKind(otelKind),
+		trace.WithSpanKind(toOTelSpanKind(config.Kind)),
 		trace.WithAttributes(attrs...),
 	}
 
@@ -124,90 +431,286 @@ func (p *otlpProvider) Start(ctx context.Context, operationName string, opts ...
 		spanOpts = append(spanOpts, trace.WithTimestamp(config.Timestamp))
 	}
 
-	ctx, otelSpan := p.tracer.Start(ctx, operationName, spanOpts...)
+	if links := toOTelLinks(config.Links); len(links) > 0 {
+		spanOpts = append(spanOpts, trace.WithLinks(links...))
+	}
+
+	parent := trace.SpanContextFromContext(ctx)
+	var otelSpan trace.Span
+	localRoot := false
+	if pipeline.budget != nil && !pipeline.budget.admit(ctx, parent, operationName, p.clock.Now()) {
+		// Over the trace's span budget: a non-recording span in the parent's trace
+		otelSpan = trace.SpanFromContext(trace.ContextWithSpanContext(context.Background(), parent))
+	} else {
+		ctx, otelSpan = pipeline.tracer.Start(ctx, operationName, spanOpts...)
+		localRoot = pipeline.budget != nil && (!parent.IsValid() || parent.IsRemote())
+	}
+	pipeline.stats.started.Add(1)
+	if otelSpan.SpanContext().IsSampled() {
+		pipeline.stats.sampled.Add(1)
+	}
 
 	span := &otlpSpan{
-		span: otelSpan,
-		ctx:  ctx,
+		span:      otelSpan,
+		ctx:       ctx,
+		clock:     p.clock,
+		start:     config.Timestamp,
+		parent:    parent,
+		name:      operationName,
+		pipeline:  pipeline,
+		localRoot: localRoot,
+	}
+	for _, attr := range attrs {
+		span.system |= pipeline.config.Attributes.systemBit(string(attr.Key))
+	}
+	if localRoot {
+		pipeline.budget.track(span)
+	}
+	if pipeline.config.Debug {
+		span.debug = p.logger
+	}
+	if len(config.LazyAttributes) > 0 && otelSpan.IsRecording() {
+		for _, fn := range config.LazyAttributes {
+			for k, v := range fn() {
+				if config.allowlist == nil || config.allowlist[k] {
+					span.setTag(k, toValue(v), false)
+				}
+			}
+		}
 	}
 
 	return ContextWithSpan(ctx, span), span
 }
 
-// Extract extracts trace context from a carrier
-func (p *otlpProvider) Extract(ctx context.Context, carrier any) (context.Context, error) {
+// Extract extracts trace context from a carrier. If ctx already belongs to
+// the extracted trace (e.g. a retry loop or a second middleware extracting
+// the same headers), ctx is returned unchanged so the current span is not
+// re-parented.
+func (p *otlpProvider) Extract(ctx context.Context, carrier Carrier) (context.Context, error) {
 	propagator := otel.GetTextMapPropagator()
 
-	// Handle different carrier types
-	var textMapCarrier propagation.TextMapCarrier
-	switch c := carrier.(type) {
-	case propagation.TextMapCarrier:
-		textMapCarrier = c
-	case map[string]string:
-		textMapCarrier = propagation.MapCarrier(c)
-	case map[string][]string:
-		textMapCarrier = &headerCarrier{headers: c}
-	default:
-		return ctx, fmt.Errorf("unsupported carrier type: %T", carrier)
+	if carrier == nil {
+		p.propagation.record(extractUnsupportedCarrier)
+		p.reportStrict("extract", extractUnsupportedCarrier, carrier)
+		return ctx, errNilCarrier
 	}
+	extracted := propagator.Extract(ctx, carrier)
 
-	return propagator.Extract(ctx, textMapCarrier), nil
+	failure := classifyExtract(carrier)
+	if failure == extractMissing && extractedRemote(ctx, extracted) {
+		// Found by a custom propagator
+		failure = extractOK
+	}
+	p.propagation.record(failure)
+	if failure == extractMalformed {
+		p.reportStrict("extract", failure, carrier)
+	}
+	if alreadyInTrace(ctx, extracted) {
+		return ctx, nil
+	}
+	pipeline := p.current()
+	extracted = pipeline.inbound.apply(ctx, extracted)
+	shadow := extractShadowTraffic(extracted, carrier)
+	extracted = withReceivedBudget(pipeline.config.Baggage.sanitizeInbound(ctx, extracted))
+	extracted = withExtractedRemote(extracted)
+	if shadow {
+		extracted = ContextWithShadowTraffic(extracted)
+	}
+	return pipeline.config.Correlation.extractCorrelationID(extracted, carrier), nil
 }
 
-// Inject injects trace context into a carrier
-func (p *otlpProvider) Inject(ctx context.Context, carrier any) error {
+// Inject injects trace context into a carrier, with the remaining timeout
+// budget of ctx in the baggage. Nothing is injected when propagation is
+// suppressed for ctx.
+func (p *otlpProvider) Inject(ctx context.Context, carrier Carrier) error {
 	propagator := otel.GetTextMapPropagator()
 
-	// Handle different carrier types
-	var textMapCarrier propagation.TextMapCarrier
-	switch c := carrier.(type) {
-	case propagation.TextMapCarrier:
-		textMapCarrier = c
-	case map[string]string:
-		textMapCarrier = propagation.MapCarrier(c)
-	case map[string][]string:
-		textMapCarrier = &headerCarrier{headers: c}
-	default:
-		return fmt.Errorf("unsupported carrier type: %T", carrier)
+	if carrier == nil {
+		p.reportStrict("inject", extractUnsupportedCarrier, carrier)
+		return errNilCarrier
+	}
+
+	if IsPropagationSuppressed(ctx) {
+		return nil
 	}
 
-	propagator.Inject(ctx, textMapCarrier)
+	ctx = p.current().config.Baggage.limitOutbound(withBudgetBaggage(ctx))
+
+	propagator.Inject(ctx, carrier)
 	return nil
 }
 
-// Shutdown shuts down the tracer provider
-func (p *otlpProvider) Shutdown(ctx context.Context) error {
-	if p.tracerProvider != nil {
-		return p.tracerProvider.Shutdown(ctx)
+// reportStrict surfaces a propagation problem according to Config.Strict
+func (p *otlpProvider) reportStrict(operation string, failure extractFailure, carrier Carrier) {
+	p.strict.report(p.current().strict, p.logger, operation, failure, carrier)
+}
+
+// ForceFlush exports all ended spans that have not been exported yet
+func (p *otlpProvider) ForceFlush(ctx context.Context) error {
+	return p.current().tracerProvider.ForceFlush(ctx)
+}
+
+// SetSampleRate changes the head sampling rate for traces started from now on
+func (p *otlpProvider) SetSampleRate(rate float64) error {
+	pipeline := p.current()
+	previous := pipeline.samplingState()
+	if err := pipeline.ratio.setRate(rate); err != nil {
+		return err
 	}
+	p.recordSamplingChange(samplingSourceSetSampleRate, previous, pipeline.samplingState())
 	return nil
 }
 
-// otlpSpan implements the Span interface
+// Shutdown shuts down the tracer provider, exporting queued spans. It is
+// safe to call more than once and concurrently with Start and End; only the
+// first call does any work. Spans started afterwards are no-ops, and spans
+// still open at shutdown are dropped when they end.
+func (p *otlpProvider) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	pipeline := p.current()
+	err := pipeline.tracerProvider.Shutdown(ctx)
+	if p.options.exporter != nil {
+		if shutdownErr := p.options.exporter.Shutdown(ctx); shutdownErr != nil && err == nil {
+			err = fmt.Errorf("failed to shut down exporter: %w", shutdownErr)
+		}
+	}
+	if p.options.auditSink != nil && pipeline.config.Audit.Enabled {
+		if closeErr := p.options.auditSink.Close(ctx); closeErr != nil && err == nil {
+			err = fmt.Errorf("failed to close audit sink: %w", closeErr)
+		}
+	}
+	return err
+}
+
+// otlpSpan implements the Span interface. The SDK span synchronizes its own
+// state; mu guards the fields tracked by otlpSpan itself.
 type otlpSpan struct {
-	span trace.Span
-	ctx  context.Context
+	span   trace.Span
+	ctx    context.Context
+	clock  Clock
+	start  time.Time
+	parent trace.SpanContext
+	name   string
+	// pipeline is the pipeline the span was started on
+	pipeline *otlpPipeline
+	// localRoot is set when the span's trace is counted against the
+	// pipeline's span budget until the span ends
+	localRoot bool
+	// debug receives misuse diagnostics; nil unless debug mode is enabled
+	debug logx.Logger
+
+	mu      sync.Mutex
+	end     time.Time
+	endSite string
+	// system holds the bits of the first-wins system attributes set on the
+	// span
+	system uint8
 }
 
+// End completes the span. Calling End more than once is a no-op so the
+// recorded duration of the first End is preserved.
 func (s *otlpSpan) End() {
-	s.span.End()
+	if !s.finish(s.clock.Now()) {
+		s.misuse("End called on an already ended span")
+	}
+}
+
+// endAt completes the span with an explicit end timestamp
+func (s *otlpSpan) endAt(end time.Time) {
+	if !s.finish(end) {
+		s.misuse("End called on an already ended span")
+	}
+}
+
+// finish ends the underlying span at end, reporting false if the span had
+// already ended
+func (s *otlpSpan) finish(end time.Time) bool {
+	s.mu.Lock()
+	if !s.end.IsZero() {
+		s.mu.Unlock()
+		return false
+	}
+	s.end = end
+	if s.debug != nil {
+		s.endSite = callSite(3)
+	}
+	s.mu.Unlock()
+	s.span.End(trace.WithTimestamp(end))
+	s.pipeline.active.Add(-1)
+	s.pipeline.stats.ended.Add(1)
+	if s.localRoot {
+		s.pipeline.budget.release(s.span.SpanContext().TraceID())
+	}
+	return true
 }
 
 func (s *otlpSpan) SetTag(key string, value any) {
-	s.span.SetAttributes(toAttribute(key, value))
+	if s.ended() {
+		s.misuse("SetTag called after End", logx.String("key", key))
+		return
+	}
+	s.setTag(key, toValue(value), false)
+}
+
+func (s *otlpSpan) SetTagString(key, value string) {
+	s.setTypedTag("SetTagString", key, attribute.StringValue(value))
+}
+
+func (s *otlpSpan) SetTagInt(key string, value int) {
+	s.setTypedTag("SetTagInt", key, attribute.IntValue(value))
+}
+
+func (s *otlpSpan) SetTagBool(key string, value bool) {
+	s.setTypedTag("SetTagBool", key, attribute.BoolValue(value))
+}
+
+func (s *otlpSpan) SetTagFloat(key string, value float64) {
+	s.setTypedTag("SetTagFloat", key, attribute.Float64Value(value))
+}
+
+// setTypedTag implements the typed SetTag variants named by method
+func (s *otlpSpan) setTypedTag(method, key string, value attribute.Value) {
+	if s.ended() {
+		s.misuse(method+" called after End", logx.String("key", key))
+		return
+	}
+	s.setTag(key, value, false)
+}
+
+func (s *otlpSpan) SetTagIfAbsent(key string, value any) bool {
+	if s.ended() {
+		s.misuse("SetTagIfAbsent called after End", logx.String("key", key))
+		return false
+	}
+	return s.setTag(key, toValue(value), true)
 }
 
 func (s *otlpSpan) SetError(err error) {
-	s.span.RecordError(err)
+	if s.ended() {
+		s.misuse("SetError called after End")
+		return
+	}
+	s.recordException(err, trace.WithTimestamp(s.clock.Now()))
 	s.span.SetAttributes(attribute.Bool("error", true))
 }
 
 func (s *otlpSpan) LogFields(fields ...Field) {
-	attrs := make([]attribute.KeyValue, len(fields))
-	for i, f := range fields {
-		attrs[i] = toAttribute(f.Key, f.Value)
+	if s.ended() {
+		s.misuse("LogFields called after End")
+		return
+	}
+	attrs := make([]attribute.KeyValue, 0, len(fields))
+	for _, f := range fields {
+		if f.Key != "" {
+			attrs = append(attrs, f.attribute())
+		}
 	}
-	s.span.AddEvent("log", trace.WithAttributes(attrs...))
+	s.span.AddEvent("log", trace.WithAttributes(attrs...), trace.WithTimestamp(s.clock.Now()))
 }
 
 func (s *otlpSpan) Context() context.Context {
@@ -222,54 +725,83 @@ func (s *otlpSpan) SpanID() string {
 	return s.span.SpanContext().SpanID().String()
 }
 
+func (s *otlpSpan) Duration() time.Duration {
+	s.mu.Lock()
+	end := s.end
+	s.mu.Unlock()
+	if end.IsZero() {
+		end = s.clock.Now()
+	}
+	return end.Sub(s.start)
+}
+
+func (s *otlpSpan) ParentSpanID() string {
+	if !s.parent.IsValid() {
+		return ""
+	}
+	return s.parent.SpanID().String()
+}
+
+func (s *otlpSpan) IsRoot() bool {
+	return !s.parent.IsValid()
+}
+
+func (s *otlpSpan) IsSampled() bool {
+	return s.span.SpanContext().IsSampled()
+}
+
+func (s *otlpSpan) IsRemoteParent() bool {
+	return s.parent.IsValid() && s.parent.IsRemote()
+}
+
+// toOTelSpanKind converts a span kind to its OpenTelemetry equivalent
+func toOTelSpanKind(kind SpanKind) trace.SpanKind {
+	switch kind {
+	case SpanKindInternal:
+		return trace.SpanKindInternal
+	case SpanKindServer:
+		return trace.SpanKindServer
+	case SpanKindClient:
+		return trace.SpanKindClient
+	case SpanKindProducer:
+		return trace.SpanKindProducer
+	case SpanKindConsumer:
+		return trace.SpanKindConsumer
+	default:
+		return trace.SpanKindInternal
+	}
+}
+
 // toAttribute converts a value to an OpenTelemetry attribute
 func toAttribute(key string, value any) attribute.KeyValue {
+	return attribute.KeyValue{Key: attribute.Key(key), Value: toValue(value)}
+}
+
+// toValue converts a value to an OpenTelemetry attribute value, formatting
+// unsupported types as strings
+func toValue(value any) attribute.Value {
 	switch v := value.(type) {
 	case string:
-		return attribute.String(key, v)
+		return attribute.StringValue(v)
 	case int:
-		return attribute.Int(key, v)
+		return attribute.IntValue(v)
 	case int64:
-		return attribute.Int64(key, v)
+		return attribute.Int64Value(v)
 	case float64:
-		return attribute.Float64(key, v)
+		return attribute.Float64Value(v)
 	case bool:
-		return attribute.Bool(key, v)
+		return attribute.BoolValue(v)
 	case []string:
-		return attribute.StringSlice(key, v)
+		return attribute.StringSliceValue(v)
 	case []int:
-		return attribute.IntSlice(key, v)
+		return attribute.IntSliceValue(v)
 	case []int64:
-		return attribute.Int64Slice(key, v)
+		return attribute.Int64SliceValue(v)
 	case []float64:
-		return attribute.Float64Slice(key, v)
+		return attribute.Float64SliceValue(v)
 	case []bool:
-		return attribute.BoolSlice(key, v)
+		return attribute.BoolSliceValue(v)
 	default:
-		return attribute.String(key, fmt.Sprintf("%v", v))
-	}
-}
-
-// headerCarrier adapts map[string][]string to propagation.TextMapCarrier
-type headerCarrier struct {
-	headers map[string][]string
-}
-
-func (c *headerCarrier) Get(key string) string {
-	if vals, ok := c.headers[key]; ok && len(vals) > 0 {
-		return vals[0]
-	}
-	return ""
-}
-
-func (c *headerCarrier) Set(key, value string) {
-	c.headers[key] = []string{value}
-}
-
-func (c *headerCarrier) Keys() []string {
-	keys := make([]string, 0, len(c.headers))
-	for k := range c.headers {
-		keys = append(keys, k)
+		return attribute.StringValue(fmt.Sprintf("%v", v))
 	}
-	return keys
 }
@@ -3,10 +3,16 @@ package tracingx
 import (
 	"context"
 	"errors"
+	"net/http"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/gostratum/core/logx"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 // Helper function to create a test logger
@@ -74,44 +80,6 @@ func TestToAttribute(t *testing.T) {
 	})
 }
 
-func TestHeaderCarrier(t *testing.T) {
-	t.Run("Get retrieves first value", func(t *testing.T) {
-		headers := map[string][]string{
-			"traceparent": {"00-12345-67890-01", "ignored"},
-		}
-		carrier := &headerCarrier{headers: headers}
-
-		value := carrier.Get("traceparent")
-		assert.Equal(t, "00-12345-67890-01", value)
-	})
-
-	t.Run("Get returns empty for missing key", func(t *testing.T) {
-		carrier := &headerCarrier{headers: make(map[string][]string)}
-		value := carrier.Get("missing")
-		assert.Empty(t, value)
-	})
-
-	t.Run("Set adds value", func(t *testing.T) {
-		carrier := &headerCarrier{headers: make(map[string][]string)}
-		carrier.Set("tracestate", "vendor=value")
-
-		assert.Equal(t, "vendor=value", carrier.headers["tracestate"][0])
-	})
-
-	t.Run("Keys returns all keys", func(t *testing.T) {
-		headers := map[string][]string{
-			"traceparent": {"value1"},
-			"tracestate":  {"value2"},
-		}
-		carrier := &headerCarrier{headers: headers}
-
-		keys := carrier.Keys()
-		assert.Len(t, keys, 2)
-		assert.Contains(t, keys, "traceparent")
-		assert.Contains(t, keys, "tracestate")
-	})
-}
-
 func TestOTLPProviderCreationFailure(t *testing.T) {
 	t.Run("fails with invalid endpoint", func(t *testing.T) {
 		cfg := Config{
@@ -143,24 +111,20 @@ func TestOTLPProviderCreationFailure(t *testing.T) {
 }
 
 func TestOTLPSpanOperations(t *testing.T) {
-	// Create a real OTLP provider with in-memory exporter for testing
+	// Create a real OTLP provider exporting to an in-process collector
+	collector := newFakeCollector(t)
 	cfg := Config{
 		ServiceName: "test-service",
 		SampleRate:  1.0,
 		OTLP: OTLPConfig{
-			Endpoint: "localhost:4317",
+			Endpoint: collector.endpoint,
 			Insecure: true,
 		},
 	}
 
 	logger := getTestLogger()
 	provider, err := newOTLPProvider(cfg, logger)
-
-	// If OTLP provider creation fails (no endpoint available), skip these tests
-	if err != nil {
-		t.Skip("OTLP endpoint not available, skipping real span tests")
-		return
-	}
+	require.NoError(t, err)
 	defer provider.Shutdown(context.Background())
 
 	t.Run("creates and ends span", func(t *testing.T) {
@@ -260,11 +224,11 @@ func TestOTLPSpanOperations(t *testing.T) {
 
 		// Inject into carrier
 		carrier := make(map[string]string)
-		err := provider.Inject(spanCtx, carrier)
+		err := provider.Inject(spanCtx, MapCarrier(carrier))
 		assert.NoError(t, err)
 
 		// Extract from carrier
-		extractedCtx, err := provider.Extract(ctx, carrier)
+		extractedCtx, err := provider.Extract(ctx, MapCarrier(carrier))
 		assert.NoError(t, err)
 		assert.NotNil(t, extractedCtx)
 	})
@@ -276,17 +240,29 @@ func TestOTLPSpanOperations(t *testing.T) {
 		spanCtx, span := provider.Start(ctx, "http-headers-test")
 		defer span.End()
 
-		// Inject into HTTP headers format
-		headers := make(map[string][]string)
-		err := provider.Inject(spanCtx, headers)
+		// Inject into HTTP headers
+		headers := http.Header{}
+		err := provider.Inject(spanCtx, HeaderCarrier(headers))
 		assert.NoError(t, err)
 
 		// Extract from HTTP headers
-		extractedCtx, err := provider.Extract(ctx, headers)
+		extractedCtx, err := provider.Extract(ctx, HeaderCarrier(headers))
 		assert.NoError(t, err)
 		assert.NotNil(t, extractedCtx)
 	})
+
+	t.Run("exports ended spans", func(t *testing.T) {
+		require.NoError(t, provider.ForceFlush(context.Background()))
+		names := map[string]bool{}
+		for _, span := range collector.received() {
+			names[span.Name] = true
+		}
+		for _, name := range []string{"test-operation", "tag-test", "log-test", "error-test", "parent-operation", "child-operation", "grandchild-operation"} {
+			assert.True(t, names[name], "span %q not exported", name)
+		}
+	})
 }
+
 func TestOTLPInjectionExtraction(t *testing.T) {
 	provider := newNoopProvider()
 	ctx := context.Background()
@@ -295,17 +271,17 @@ func TestOTLPInjectionExtraction(t *testing.T) {
 		carrier := make(map[string]string)
 
 		// Inject
-		err := provider.Inject(ctx, carrier)
+		err := provider.Inject(ctx, MapCarrier(carrier))
 		assert.NoError(t, err)
 
 		// Extract
-		extractedCtx, err := provider.Extract(ctx, carrier)
+		extractedCtx, err := provider.Extract(ctx, MapCarrier(carrier))
 		assert.NoError(t, err)
 		assert.NotNil(t, extractedCtx)
 	})
 
-	t.Run("inject and extract with map[string][]string", func(t *testing.T) {
-		carrier := make(map[string][]string)
+	t.Run("inject and extract with http.Header", func(t *testing.T) {
+		carrier := HeaderCarrier(http.Header{})
 
 		// Inject
 		err := provider.Inject(ctx, carrier)
@@ -345,3 +321,265 @@ func TestSpanKindConversion(t *testing.T) {
 		})
 	}
 }
+
+// attributesOf returns the attributes recorded on an OTLP span
+func attributesOf(t *testing.T, span Span) map[string]any {
+	t.Helper()
+	s, ok := span.(*otlpSpan)
+	if !ok {
+		t.Fatalf("expected *otlpSpan, got %T", span)
+	}
+	ro, ok := s.span.(sdktrace.ReadOnlySpan)
+	if !ok {
+		t.Fatalf("expected recording SDK span, got %T", s.span)
+	}
+	attrs := make(map[string]any)
+	for _, kv := range ro.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return attrs
+}
+
+func TestOTLPSpanParent(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	defer provider.Shutdown(context.Background())
+
+	t.Run("root span has no parent", func(t *testing.T) {
+		_, root := provider.Start(context.Background(), "root")
+		defer root.End()
+		assert.True(t, root.IsRoot())
+		assert.Empty(t, root.ParentSpanID())
+	})
+
+	t.Run("child span reports its parent", func(t *testing.T) {
+		ctx, root := provider.Start(context.Background(), "root")
+		defer root.End()
+		_, child := provider.Start(ctx, "child")
+		defer child.End()
+		assert.False(t, child.IsRoot())
+		assert.Equal(t, root.SpanID(), child.ParentSpanID())
+	})
+
+	t.Run("span with remote parent is not a root", func(t *testing.T) {
+		ctx, err := provider.Extract(context.Background(), MapCarrier(map[string]string{
+			"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		}))
+		assert.NoError(t, err)
+		_, span := provider.Start(ctx, "server")
+		defer span.End()
+		assert.False(t, span.IsRoot())
+		assert.Equal(t, "00f067aa0ba902b7", span.ParentSpanID())
+		assert.True(t, span.IsRemoteParent())
+	})
+
+	t.Run("local parent is not remote", func(t *testing.T) {
+		ctx, root := provider.Start(context.Background(), "root")
+		defer root.End()
+		_, child := provider.Start(ctx, "child")
+		defer child.End()
+		assert.False(t, root.IsRemoteParent())
+		assert.False(t, child.IsRemoteParent())
+	})
+}
+
+func TestOTLPSpanSampled(t *testing.T) {
+	t.Run("sampled when the sampler keeps the trace", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+		assert.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		_, span := provider.Start(context.Background(), "kept")
+		defer span.End()
+		assert.True(t, span.IsSampled())
+	})
+
+	t.Run("not sampled when the sampler drops the trace", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 0}, getTestLogger())
+		assert.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		_, span := provider.Start(context.Background(), "dropped")
+		defer span.End()
+		assert.False(t, span.IsSampled())
+	})
+}
+
+func TestOTLPTypedTags(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	_, span := provider.Start(context.Background(), "typed")
+	defer span.End()
+	span.SetTagString("user", "alice")
+	span.SetTagInt("attempt", 2)
+	span.SetTagBool("cache.hit", true)
+	span.SetTagFloat("ratio", 0.5)
+	span.SetTagString("service.name", "spoofed")
+
+	attrs := attributesOf(t, span)
+	assert.Equal(t, "alice", attrs["user"])
+	assert.Equal(t, int64(2), attrs["attempt"])
+	assert.Equal(t, true, attrs["cache.hit"])
+	assert.Equal(t, 0.5, attrs["ratio"])
+	assert.Equal(t, "spoofed", attrs[ReservedAttributePrefix+"service.name"])
+
+	span.LogFields(String("event", "retry"), Int("attempt", 3), Err(nil), Field{Key: "legacy", Value: 1.5})
+	events := span.(*otlpSpan).span.(sdktrace.ReadOnlySpan).Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("event", "retry"),
+		attribute.Int("attempt", 3),
+		attribute.Float64("legacy", 1.5),
+	}, events[0].Attributes)
+}
+
+// BenchmarkSetTag compares SetTag with its typed variant for a string built
+// per request
+func BenchmarkSetTag(b *testing.B) {
+	provider, err := newOTLPProvider(Config{ServiceName: "bench", SampleRate: 1.0}, getTestLogger())
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer provider.Shutdown(context.Background())
+	_, span := provider.Start(context.Background(), "bench")
+	defer span.End()
+	value := strings.Repeat("v", 16)
+
+	b.Run("any", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			span.SetTag("user.id", value)
+		}
+	})
+	b.Run("typed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			span.SetTagString("user.id", value)
+		}
+	})
+}
+
+func TestOTLPLazyAttributes(t *testing.T) {
+	start := func(t *testing.T, rate float64) (Span, *int) {
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: rate}, getTestLogger())
+		require.NoError(t, err)
+		t.Cleanup(func() { provider.Shutdown(context.Background()) })
+
+		calls := 0
+		_, span := provider.Start(context.Background(), "upload",
+			WithAttributes(map[string]any{"payload.size": 10}),
+			WithLazyAttributes(func() map[string]any {
+				calls++
+				return map[string]any{"payload.sha256": "abc", "payload.size": 12, "service.name": "spoofed"}
+			}),
+		)
+		t.Cleanup(span.End)
+		return span, &calls
+	}
+
+	t.Run("evaluated for recording spans", func(t *testing.T) {
+		span, calls := start(t, 1.0)
+		assert.Equal(t, 1, *calls)
+		attrs := attributesOf(t, span)
+		assert.Equal(t, "abc", attrs["payload.sha256"])
+		assert.Equal(t, int64(12), attrs["payload.size"])
+		assert.Equal(t, "spoofed", attrs[ReservedAttributePrefix+"service.name"])
+	})
+
+	t.Run("skipped for unsampled spans", func(t *testing.T) {
+		_, calls := start(t, 0)
+		assert.Zero(t, *calls)
+	})
+}
+
+func TestOTLPProviderShutdown(t *testing.T) {
+	newProvider := func(t *testing.T) (Provider, *fakeCollector) {
+		collector := newFakeCollector(t)
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+		}, getTestLogger())
+		require.NoError(t, err)
+		return provider, collector
+	}
+
+	t.Run("is idempotent", func(t *testing.T) {
+		provider, collector := newProvider(t)
+		_, span := provider.Start(context.Background(), "work")
+		span.End()
+
+		require.NoError(t, provider.Shutdown(context.Background()))
+		require.NoError(t, provider.Shutdown(context.Background()))
+		assert.Len(t, collector.received(), 1)
+	})
+
+	t.Run("degrades to noop after shutdown", func(t *testing.T) {
+		provider, collector := newProvider(t)
+		require.NoError(t, provider.Shutdown(context.Background()))
+
+		ctx, span := provider.Start(context.Background(), "late")
+		assert.NotPanics(t, func() {
+			span.SetTag("key", "value")
+			span.SetError(errors.New("boom"))
+			span.End()
+		})
+		assert.Empty(t, span.TraceID())
+		assert.False(t, span.IsSampled())
+		assert.Equal(t, span, SpanFromContext(ctx))
+		assert.Empty(t, collector.received())
+
+		assert.ErrorIs(t, provider.SelfTest(context.Background()), ErrProviderShutdown)
+		assert.ErrorIs(t, provider.Reconfigure(Config{ServiceName: "test-service"}), ErrProviderShutdown)
+		assert.NoError(t, provider.ForceFlush(context.Background()))
+	})
+
+	t.Run("ends spans open at shutdown safely", func(t *testing.T) {
+		provider, _ := newProvider(t)
+		_, span := provider.Start(context.Background(), "open")
+		require.NoError(t, provider.Shutdown(context.Background()))
+
+		assert.NotPanics(t, func() {
+			span.SetTag("key", "value")
+			span.End()
+		})
+	})
+
+	t.Run("races with start and end", func(t *testing.T) {
+		provider, _ := newProvider(t)
+		const workers = 16
+
+		var wg sync.WaitGroup
+		start := make(chan struct{})
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				for j := 0; j < 50; j++ {
+					ctx, span := provider.Start(context.Background(), "concurrent")
+					_, child := provider.Start(ctx, "child")
+					child.End()
+					span.End()
+				}
+			}()
+		}
+		for i := 0; i < 4; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				<-start
+				assert.NoError(t, provider.Shutdown(context.Background()))
+			}()
+		}
+		close(start)
+		wg.Wait()
+
+		_, span := provider.Start(context.Background(), "after")
+		assert.IsType(t, &noopSpan{}, span)
+	})
+}
@@ -0,0 +1,27 @@
+package tracingx
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/gostratum/core/logx"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+)
+
+// newStdoutProvider creates a tracing provider writing spans to standard
+// output as JSON, for local development. Like the Zipkin provider it shares
+// the OTLP provider's pipeline; only the exporter differs.
+func newStdoutProvider(config Config, logger logx.Logger, providerOpts ...providerOption) (Provider, error) {
+	config.Provider = "stdout"
+	return newOTLPProvider(config, logger, providerOpts...)
+}
+
+// newStdoutWorkers creates the export worker writing spans to w, one JSON
+// object per span
+func newStdoutWorkers(w io.Writer, clock Clock, total *exportStats) ([]exportWorker, error) {
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+	}
+	return []exportWorker{newExportWorker(exporter, nil, OTLPConfig{}, clock, total)}, nil
+}
@@ -0,0 +1,247 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ExporterConfig is one exporter of the tee provider, configured like the
+// top-level provider settings
+type ExporterConfig struct {
+	// Provider is the exporter: otlp, zipkin, datadog, xray, file or stdout
+	Provider string `mapstructure:"provider" default:"otlp"`
+
+	// OTLP configures the otlp exporter
+	OTLP OTLPConfig `mapstructure:"otlp"`
+
+	// Zipkin configures the zipkin exporter
+	Zipkin ZipkinConfig `mapstructure:"zipkin"`
+
+	// Datadog configures the datadog exporter
+	Datadog DatadogConfig `mapstructure:"datadog"`
+
+	// XRay configures the xray exporter
+	XRay XRayConfig `mapstructure:"xray"`
+
+	// File configures the file exporter
+	File FileConfig `mapstructure:"file"`
+}
+
+// usesOTLP reports whether the exporter sends spans over OTLP, with the
+// headers of its otlp settings
+func (c ExporterConfig) usesOTLP() bool {
+	switch c.Provider {
+	case "", "otlp", "datadog":
+		return true
+	case "xray":
+		return !c.XRay.Direct
+	default:
+		return false
+	}
+}
+
+// sanitize returns a copy of c with secret-like header values redacted
+func (c ExporterConfig) sanitize() ExporterConfig {
+	c.OTLP = c.OTLP.sanitize()
+	return c
+}
+
+// exporterConfig returns the top-level exporter settings
+func (c Config) exporterConfig() ExporterConfig {
+	return ExporterConfig{Provider: c.exporter(), OTLP: c.OTLP, Zipkin: c.Zipkin, Datadog: c.Datadog, XRay: c.XRay, File: c.File}
+}
+
+// exporterConfigs returns the settings of each exporter spans are sent to:
+// the tee's exporters, or the top-level exporter
+func (c Config) exporterConfigs() []ExporterConfig {
+	if c.exporter() == "tee" {
+		return c.Exporters
+	}
+	return []ExporterConfig{c.exporterConfig()}
+}
+
+// newTeeProvider creates a tracing provider sending every span to each of
+// config.Exporters. Sampling, processors and export filters run once, in the
+// shared OTLP provider pipeline, so all exporters receive the same spans.
+func newTeeProvider(config Config, logger logx.Logger, providerOpts ...providerOption) (Provider, error) {
+	config.Provider = "tee"
+	return newOTLPProvider(config, logger, providerOpts...)
+}
+
+// newWorkers creates the export workers of one exporter
+func newWorkers(ctx context.Context, config ExporterConfig, warm bool, clock Clock, total *exportStats) ([]exportWorker, error) {
+	switch config.Provider {
+	case "", "otlp":
+		return newExportWorkers(ctx, config.OTLP, warm, clock, total)
+	case "zipkin":
+		return newZipkinWorkers(config.Zipkin, clock, total)
+	case "datadog":
+		return newExportWorkers(ctx, config.Datadog.otlpConfig(config.OTLP), warm, clock, total)
+	case "xray":
+		return newXRayWorkers(ctx, config.XRay, config.OTLP, warm, clock, total)
+	case "file":
+		return newFileWorkers(ctx, config.File, clock, total)
+	case "stdout":
+		return newStdoutWorkers(os.Stdout, clock, total)
+	default:
+		return nil, fmt.Errorf("unknown exporter %q", config.Provider)
+	}
+}
+
+// newTeeWorkers creates the export workers of each exporter, grouped by
+// exporter. Each exporter records its exports in its own stats, folded into
+// total by teeStats so every span is counted once.
+func newTeeWorkers(ctx context.Context, exporters []ExporterConfig, warm bool, clock Clock, total *exportStats) ([][]exportWorker, error) {
+	if len(exporters) == 0 {
+		return nil, errors.New("failed to create tee exporter: no exporters configured")
+	}
+	tee := newTeeStats(total, len(exporters))
+	groups := make([][]exportWorker, 0, len(exporters))
+	for i, exporter := range exporters {
+		workers, err := newWorkers(ctx, exporter, warm, clock, tee.exporters[i])
+		if err != nil {
+			for _, group := range groups {
+				closeWorkers(group)
+			}
+			return nil, fmt.Errorf("failed to create tee exporter %d: %w", i, err)
+		}
+		groups = append(groups, workers)
+	}
+	return groups, nil
+}
+
+// teeStats folds the export counters of the tee's exporters into the
+// provider totals, counting each span once: as exported when an exporter
+// delivered it, as dropped only when every exporter failed it or dropped it
+// on a full queue. Spans are not tracked individually, so the totals follow
+// the exporter that delivered the most spans and the one that dropped the
+// fewest, dropped spans counting as overflowed as far as every exporter
+// overflowed.
+type teeStats struct {
+	total     *exportStats
+	exporters []*exportStats
+
+	mu         sync.Mutex
+	exported   uint64
+	failed     uint64
+	overflowed uint64
+}
+
+// newTeeStats returns the stats of n exporters folded into total
+func newTeeStats(total *exportStats, n int) *teeStats {
+	t := &teeStats{total: total, exporters: make([]*exportStats, n)}
+	for i := range t.exporters {
+		t.exporters[i] = &exportStats{onExport: t.update}
+	}
+	return t
+}
+
+// update adds the exporters' new exports to the totals
+func (t *teeStats) update() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var exported uint64
+	dropped, overflowed := uint64(math.MaxUint64), uint64(math.MaxUint64)
+	var lastExport time.Time
+	var errs []error
+	for _, s := range t.exporters {
+		exported = max(exported, s.exported.Load())
+		dropped = min(dropped, s.failed.Load()+s.overflowed.Load())
+		overflowed = min(overflowed, s.overflowed.Load())
+		s.mu.Lock()
+		if s.lastExport.After(lastExport) {
+			lastExport = s.lastExport
+		}
+		if s.lastErr != nil {
+			errs = append(errs, s.lastErr)
+		}
+		s.mu.Unlock()
+	}
+	if exported > t.exported {
+		t.total.exported.Add(exported - t.exported)
+		t.exported = exported
+	}
+	if overflowed > t.overflowed {
+		t.total.overflowed.Add(overflowed - t.overflowed)
+		t.overflowed = overflowed
+	}
+	if failed := dropped - overflowed; failed > t.failed {
+		t.total.failed.Add(failed - t.failed)
+		t.failed = failed
+	}
+	t.total.mu.Lock()
+	t.total.lastExport = lastExport
+	t.total.lastErr = errors.Join(errs...)
+	t.total.mu.Unlock()
+}
+
+// closeWorkers shuts down workers created before a failure: their batchers,
+// which shut down the exporters in turn
+func closeWorkers(workers []exportWorker) {
+	for _, w := range workers {
+		_ = w.batcher.Shutdown(context.Background())
+	}
+}
+
+// teeProcessor hands every finished span to the processor feeding each
+// exporter's workers; flushes and shutdowns run concurrently, as for
+// parallel workers
+type teeProcessor struct {
+	*parallelProcessor
+}
+
+// newTeeProcessor returns the processor feeding every exporter of groups,
+// counting each span once in total
+func newTeeProcessor(groups [][]exportWorker, total *exportStats) sdktrace.SpanProcessor {
+	p := &parallelProcessor{workers: make([]sdktrace.SpanProcessor, len(groups))}
+	for i, workers := range groups {
+		p.workers[i] = newWorkerProcessor(workers)
+	}
+	return &countingProcessor{next: teeProcessor{p}, stats: total}
+}
+
+func (p teeProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	for _, w := range p.workers {
+		w.OnEnd(s)
+	}
+}
+
+// teeExporter exports directly to the first worker of every exporter, for
+// SelfTest
+type teeExporter struct {
+	exporters []sdktrace.SpanExporter
+}
+
+// newTeeExporter returns the exporter writing to every exporter of groups
+func newTeeExporter(groups [][]exportWorker) sdktrace.SpanExporter {
+	e := &teeExporter{exporters: make([]sdktrace.SpanExporter, len(groups))}
+	for i, workers := range groups {
+		e.exporters[i] = workers[0].exporter
+	}
+	return e
+}
+
+func (e *teeExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	var errs []error
+	for _, exporter := range e.exporters {
+		errs = append(errs, exporter.ExportSpans(ctx, spans))
+	}
+	return errors.Join(errs...)
+}
+
+func (e *teeExporter) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, exporter := range e.exporters {
+		errs = append(errs, exporter.Shutdown(ctx))
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,203 @@
+package tracingx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTeeProvider(t *testing.T) {
+	collector := newFakeCollector(t)
+	zipkinCollector, zipkinSpans := newZipkinCollector(t)
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+
+	result, err := NewTracer(Params{
+		Config: Config{
+			Enabled:     true,
+			Provider:    "tee",
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			Export:      ExportConfig{ExcludeOperations: []string{"health"}},
+			Exporters: []ExporterConfig{
+				{Provider: "otlp", OTLP: OTLPConfig{Endpoint: collector.endpoint, Insecure: true, Workers: 2}},
+				{Provider: "zipkin", Zipkin: ZipkinConfig{Endpoint: zipkinCollector.URL, Timeout: time.Second}},
+				{Provider: "file", File: FileConfig{Path: path}},
+			},
+		},
+		Logger: logx.NewNoopLogger(),
+	})
+	require.NoError(t, err)
+	provider := result.Provider
+	defer provider.Shutdown(context.Background())
+
+	_, span := provider.Start(context.Background(), "charge")
+	span.End()
+	_, health := provider.Start(context.Background(), "health")
+	health.End()
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	assert.Equal(t, []string{"charge"}, spanNames(collector), "each span is sent once per exporter")
+	require.Len(t, zipkinSpans(), 1)
+	assert.Equal(t, "charge", zipkinSpans()[0].Name)
+	batches := readTraceFile(t, path)
+	require.Len(t, batches, 1)
+	assert.Equal(t, "charge", batches[0].ResourceSpans[0].ScopeSpans[0].Spans[0].Name)
+
+	diag := provider.Diagnostics()
+	assert.Equal(t, "tee", diag.Provider)
+	assert.Equal(t, uint64(1), diag.SpansExported, "counted once for all exporters")
+	require.Len(t, diag.Workers, 4)
+	var workerExports uint64
+	for _, w := range diag.Workers {
+		workerExports += w.SpansExported
+	}
+	assert.Equal(t, uint64(3), workerExports, "counted by each exporter's workers")
+	assert.Zero(t, provider.Stats().QueueLength)
+
+	require.NoError(t, provider.SelfTest(context.Background()))
+	assert.Len(t, collector.received(), 2)
+	assert.Len(t, zipkinSpans(), 2)
+}
+
+func TestTeeProviderErrors(t *testing.T) {
+	_, err := newTeeProvider(Config{ServiceName: "test-service"}, getTestLogger())
+	assert.ErrorContains(t, err, "no exporters configured")
+
+	_, err = newTeeProvider(Config{ServiceName: "test-service", Exporters: []ExporterConfig{
+		{Provider: "file", File: FileConfig{Path: filepath.Join(t.TempDir(), "traces.jsonl")}},
+		{Provider: "tee"},
+	}}, getTestLogger())
+	assert.ErrorContains(t, err, `tee exporter 1: unknown exporter "tee"`)
+
+	t.Run("self-test reports every exporter", func(t *testing.T) {
+		down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer down.Close()
+		provider, err := newTeeProvider(Config{ServiceName: "test-service", Exporters: []ExporterConfig{
+			{Provider: "file", File: FileConfig{Path: filepath.Join(t.TempDir(), "traces.jsonl")}},
+			{Provider: "zipkin", Zipkin: ZipkinConfig{Endpoint: down.URL, Timeout: time.Second}},
+		}}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+		assert.Error(t, provider.SelfTest(context.Background()))
+	})
+}
+
+func TestTeeStats(t *testing.T) {
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+	path := filepath.Join(t.TempDir(), "traces.jsonl")
+
+	newProvider := func(t *testing.T, exporters ...ExporterConfig) Provider {
+		provider, err := newTeeProvider(Config{ServiceName: "test-service", SampleRate: 1.0, Exporters: exporters}, getTestLogger())
+		require.NoError(t, err)
+		t.Cleanup(func() { provider.Shutdown(context.Background()) })
+		for range 2 {
+			_, span := provider.Start(context.Background(), "charge")
+			span.End()
+		}
+		_ = provider.ForceFlush(context.Background()) // fails with the failing exporter
+		return provider
+	}
+	file := ExporterConfig{Provider: "file", File: FileConfig{Path: path}}
+	failing := ExporterConfig{Provider: "zipkin", Zipkin: ZipkinConfig{Endpoint: down.URL, Timeout: time.Second}}
+
+	t.Run("a failed exporter does not drop spans", func(t *testing.T) {
+		stats := newProvider(t, file, failing).Stats()
+		assert.Equal(t, uint64(2), stats.SpansExported)
+		assert.Zero(t, stats.SpansDropped)
+		assert.Zero(t, stats.QueueLength)
+		assert.NotEmpty(t, stats.LastExportError)
+	})
+
+	t.Run("spans failed by every exporter are dropped once", func(t *testing.T) {
+		stats := newProvider(t, failing, failing).Stats()
+		assert.Zero(t, stats.SpansExported)
+		assert.Equal(t, uint64(2), stats.SpansDropped)
+		assert.Zero(t, stats.QueueLength)
+	})
+}
+
+func TestTeeExporterDefaults(t *testing.T) {
+	config := Config{
+		Provider:    "tee",
+		ServiceName: "test-service",
+		Exporters: []ExporterConfig{
+			{Provider: "otlp"},
+			{Provider: "datadog", Datadog: DatadogConfig{Env: "prod", Version: "1.2.3"}},
+			{Provider: "xray", XRay: XRayConfig{Region: "eu-west-1"}},
+		},
+	}
+
+	assert.Equal(t, []string{"datadog", "tracecontext", "baggage", "xray"}, config.propagators())
+	res, err := newServiceResource(context.Background(), config)
+	require.NoError(t, err)
+	attrs := map[string]string{}
+	for _, kv := range res.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	assert.Equal(t, "prod", attrs["deployment.environment.name"])
+	assert.Equal(t, "1.2.3", attrs["service.version"])
+	assert.Equal(t, "aws", attrs["cloud.provider"])
+	assert.Equal(t, "eu-west-1", attrs["cloud.region"])
+}
+
+// shutdownExporter records spans and whether it was shut down
+type shutdownExporter struct {
+	tracetest.InMemoryExporter
+	shutdowns atomic.Int32
+}
+
+func (e *shutdownExporter) Shutdown(context.Context) error {
+	e.shutdowns.Add(1)
+	return nil
+}
+
+func TestCloseWorkers(t *testing.T) {
+	exporter := &shutdownExporter{}
+	workers := []exportWorker{newExportWorker(exporter, nil, OTLPConfig{}, systemClock{}, nil)}
+	workers[0].batcher.OnEnd(tracetest.SpanStub{
+		Name:        "queued",
+		SpanContext: trace.NewSpanContext(trace.SpanContextConfig{TraceFlags: trace.FlagsSampled}),
+	}.Snapshot())
+
+	closeWorkers(workers)
+	assert.Equal(t, int32(1), exporter.shutdowns.Load())
+	assert.Len(t, exporter.GetSpans(), 1, "the batcher is shut down, flushing its queue")
+}
+
+func TestStdoutExporter(t *testing.T) {
+	var out bytes.Buffer
+	workers, err := newStdoutWorkers(&out, systemClock{}, nil)
+	require.NoError(t, err)
+
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger(), withExporter(workers[0].exporter))
+	require.NoError(t, err)
+	_, span := provider.Start(context.Background(), "charge")
+	span.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	var decoded struct{ Name string }
+	require.NoError(t, json.NewDecoder(&out).Decode(&decoded))
+	assert.Equal(t, "charge", decoded.Name)
+
+	stdout, err := newStdoutProvider(Config{ServiceName: "test-service"}, getTestLogger())
+	require.NoError(t, err)
+	defer stdout.Shutdown(context.Background())
+	assert.Equal(t, "stdout", stdout.Diagnostics().Provider)
+}
@@ -0,0 +1,342 @@
+package tracingx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// XRayPropagators are the propagators used by the xray provider when
+// Config.Propagators is empty: the X-Amzn-Trace-Id header set by AWS load
+// balancers and services, and W3C trace context, which wins when both are
+// present
+var XRayPropagators = []string{"xray", "tracecontext", "baggage"}
+
+// XRayConfig contains AWS X-Ray configuration. By default spans are sent
+// over OTLP, using the otlp settings, to a collector exporting to X-Ray such
+// as the AWS Distro for OpenTelemetry; Direct sends them to the X-Ray daemon
+// instead.
+type XRayConfig struct {
+	// Direct sends spans as X-Ray segment documents to the X-Ray daemon over
+	// UDP, without a collector
+	Direct bool `mapstructure:"direct" default:"false"`
+
+	// DaemonAddress is the X-Ray daemon's UDP address (defaults to
+	// AWS_XRAY_DAEMON_ADDRESS, then 127.0.0.1:2000)
+	DaemonAddress string `mapstructure:"daemon_address"`
+
+	// Region is the AWS region, recorded as cloud.region (defaults to
+	// AWS_REGION, then AWS_DEFAULT_REGION)
+	Region string `mapstructure:"region"`
+
+	// IndexedAttributes lists the span attributes sent as X-Ray annotations,
+	// which can be searched in filter expressions, when exporting directly.
+	// Other attributes are sent as metadata.
+	IndexedAttributes []string `mapstructure:"indexed_attributes"`
+}
+
+// daemonAddress returns the daemon's UDP address. AWS_XRAY_DAEMON_ADDRESS
+// may also hold separate addresses, as in "tcp:host:2000 udp:host:2000".
+func (c XRayConfig) daemonAddress() string {
+	address := firstNonEmpty(c.DaemonAddress, os.Getenv("AWS_XRAY_DAEMON_ADDRESS"), "127.0.0.1:2000")
+	for _, field := range strings.Fields(address) {
+		if udp, ok := strings.CutPrefix(field, "udp:"); ok {
+			return udp
+		}
+	}
+	return address
+}
+
+// resourceAttributes returns the cloud attributes of the resource
+func (c XRayConfig) resourceAttributes() []attribute.KeyValue {
+	attrs := []attribute.KeyValue{cloudProviderAWSAttribute}
+	if region := firstNonEmpty(c.Region, os.Getenv("AWS_REGION"), os.Getenv("AWS_DEFAULT_REGION")); region != "" {
+		attrs = append(attrs, cloudRegionAttribute(region))
+	}
+	return attrs
+}
+
+// idGenerator returns the ID generator mode: Config.IDGenerator when set,
+// else X-Ray IDs for the xray provider, since X-Ray rejects trace IDs that
+// do not start with a recent timestamp, and random IDs for the others
+func (c Config) idGenerator() string {
+	switch {
+	case c.IDGenerator != "":
+		return c.IDGenerator
+	case c.exporter() == "xray":
+		return IDGeneratorXRay
+	default:
+		return IDGeneratorRandom
+	}
+}
+
+// newXRayProvider creates a tracing provider exporting to AWS X-Ray. Like
+// the Zipkin provider it shares the OTLP provider's pipeline; the ID
+// generator, default propagators and exporter differ.
+func newXRayProvider(config Config, logger logx.Logger, providerOpts ...providerOption) (Provider, error) {
+	config.Provider = "xray"
+	return newOTLPProvider(config, logger, providerOpts...)
+}
+
+// newXRayWorkers creates the export workers of the xray exporter
+func newXRayWorkers(ctx context.Context, config XRayConfig, otlp OTLPConfig, warm bool, clock Clock, total *exportStats) ([]exportWorker, error) {
+	if !config.Direct {
+		return newExportWorkers(ctx, otlp, warm, clock, total)
+	}
+	conn, err := net.Dial("udp", config.daemonAddress())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create X-Ray exporter: %w", err)
+	}
+	exporter := &xrayExporter{conn: conn, indexed: make(map[attribute.Key]bool, len(config.IndexedAttributes))}
+	for _, key := range config.IndexedAttributes {
+		exporter.indexed[attribute.Key(key)] = true
+	}
+	return []exportWorker{newExportWorker(exporter, nil, otlp, clock, total)}, nil
+}
+
+// xrayHeader precedes each segment document sent to the daemon
+const xrayHeader = `{"format":"json","version":1}` + "\n"
+
+// xrayMaxAnnotations is the number of annotations X-Ray accepts per segment
+const xrayMaxAnnotations = 50
+
+// xrayExporter sends each span to the X-Ray daemon as one datagram holding
+// a segment document. Entry spans (local roots, server and consumer spans)
+// become segments; other spans become subsegments sent on their own.
+type xrayExporter struct {
+	conn    net.Conn
+	indexed map[attribute.Key]bool
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (e *xrayExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	var errs []error
+	for _, s := range spans {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		doc, err := json.Marshal(e.segment(s))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to encode segment %s: %w", s.SpanContext().SpanID(), err))
+			continue
+		}
+		if err := e.send(append([]byte(xrayHeader), doc...)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to send segment %s: %w", s.SpanContext().SpanID(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (e *xrayExporter) Shutdown(context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return e.conn.Close()
+}
+
+// send writes one datagram to the daemon
+func (e *xrayExporter) send(datagram []byte) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return errors.New("X-Ray exporter is shut down")
+	}
+	_, err := e.conn.Write(datagram)
+	return err
+}
+
+// xraySegment is an X-Ray segment or subsegment document
+type xraySegment struct {
+	Name        string                    `json:"name"`
+	ID          string                    `json:"id"`
+	TraceID     string                    `json:"trace_id"`
+	ParentID    string                    `json:"parent_id,omitempty"`
+	Type        string                    `json:"type,omitempty"`
+	Namespace   string                    `json:"namespace,omitempty"`
+	StartTime   float64                   `json:"start_time"`
+	EndTime     float64                   `json:"end_time"`
+	Error       bool                      `json:"error,omitempty"`
+	Fault       bool                      `json:"fault,omitempty"`
+	Throttle    bool                      `json:"throttle,omitempty"`
+	HTTP        *xrayHTTP                 `json:"http,omitempty"`
+	Annotations map[string]any            `json:"annotations,omitempty"`
+	Metadata    map[string]map[string]any `json:"metadata,omitempty"`
+}
+
+// xrayHTTP is the http section of a segment
+type xrayHTTP struct {
+	Request  map[string]any `json:"request,omitempty"`
+	Response map[string]any `json:"response,omitempty"`
+}
+
+// segment converts s to a segment document
+func (e *xrayExporter) segment(s sdktrace.ReadOnlySpan) xraySegment {
+	sc := s.SpanContext()
+	segment := xraySegment{
+		Name:      s.Name(),
+		ID:        sc.SpanID().String(),
+		TraceID:   formatXRayTraceID(sc.TraceID()),
+		StartTime: xrayTime(s.StartTime()),
+		EndTime:   xrayTime(s.EndTime()),
+	}
+	if parent := s.Parent(); parent.IsValid() {
+		segment.ParentID = parent.SpanID().String()
+	}
+	if isXRaySegment(s) {
+		segment.Name = xrayServiceName(s)
+	} else {
+		segment.Type = "subsegment"
+	}
+
+	var status int64
+	request := map[string]any{}
+	metadata := map[string]any{}
+	for _, kv := range s.Attributes() {
+		switch string(kv.Key) {
+		case httpRequestMethodKey:
+			request["method"] = kv.Value.AsString()
+		case urlFullKey:
+			request["url"] = kv.Value.AsString()
+		case httpResponseStatusCodeKey:
+			status = kv.Value.AsInt64()
+		}
+		if e.indexed[kv.Key] && len(segment.Annotations) < xrayMaxAnnotations && kv.Value.Type() != attribute.INVALID {
+			if segment.Annotations == nil {
+				segment.Annotations = map[string]any{}
+			}
+			segment.Annotations[xrayAnnotationKey(string(kv.Key))] = xrayAnnotationValue(kv.Value)
+			continue
+		}
+		metadata[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	if len(metadata) > 0 {
+		segment.Metadata = map[string]map[string]any{"default": metadata}
+	}
+
+	switch s.SpanKind() {
+	case trace.SpanKindClient, trace.SpanKindProducer:
+		segment.Namespace = "remote"
+		if name := remoteName(s); name != "" {
+			segment.Name = name
+		}
+	}
+	if len(request) > 0 || status != 0 {
+		segment.HTTP = &xrayHTTP{}
+		if len(request) > 0 {
+			segment.HTTP.Request = request
+		}
+		if status != 0 {
+			segment.HTTP.Response = map[string]any{"status": status}
+		}
+	}
+	switch {
+	case status == 429:
+		segment.Error, segment.Throttle = true, true
+	case status >= 400 && status < 500:
+		segment.Error = true
+	case status >= 500, s.Status().Code == codes.Error:
+		segment.Fault = true
+	}
+	segment.Name = xraySegmentName(segment.Name)
+	return segment
+}
+
+// isXRaySegment reports whether s is the entry of a service's part of the
+// trace, sent as a segment rather than a subsegment
+func isXRaySegment(s sdktrace.ReadOnlySpan) bool {
+	parent := s.Parent()
+	if !parent.IsValid() || parent.IsRemote() {
+		return true
+	}
+	kind := s.SpanKind()
+	return kind == trace.SpanKindServer || kind == trace.SpanKindConsumer
+}
+
+// xrayServiceName returns the service.name of the span's resource
+func xrayServiceName(s sdktrace.ReadOnlySpan) string {
+	if s.Resource() != nil {
+		if name, ok := s.Resource().Set().Value(serviceNameKey); ok && name.AsString() != "" {
+			return name.AsString()
+		}
+	}
+	return s.Name()
+}
+
+// remoteName returns the name of the service a client span calls:
+// peer.service, else server.address
+func remoteName(s sdktrace.ReadOnlySpan) string {
+	var address string
+	for _, kv := range s.Attributes() {
+		switch string(kv.Key) {
+		case peerServiceKey:
+			return kv.Value.AsString()
+		case serverAddressKey:
+			address = kv.Value.AsString()
+		}
+	}
+	return address
+}
+
+// xrayTime converts t to X-Ray's seconds since the epoch
+func xrayTime(t time.Time) float64 {
+	return float64(t.UnixMicro()) / 1e6
+}
+
+// xraySegmentName replaces characters X-Ray does not accept in segment
+// names and truncates them to X-Ray's 200 characters
+func xraySegmentName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r == ' ', r == '\t':
+			return r
+		case strings.ContainsRune(`_.:/%&#=+\-@`, r):
+			return r
+		case r < 0x80 && !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9'):
+			return '_'
+		}
+		return r
+	}, name)
+	if runes := []rune(name); len(runes) > 200 {
+		name = string(runes[:200])
+	}
+	return name
+}
+
+// xrayAnnotationKey replaces characters X-Ray does not accept in annotation
+// keys, which are limited to letters, digits and underscores
+func xrayAnnotationKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '_' {
+			return r
+		}
+		return '_'
+	}, key)
+}
+
+// xrayAnnotationValue converts v to an annotation value: a string, number or
+// boolean. Slices are joined into a string.
+func xrayAnnotationValue(v attribute.Value) any {
+	switch v.Type() {
+	case attribute.BOOL, attribute.INT64, attribute.FLOAT64, attribute.STRING:
+		return v.AsInterface()
+	default:
+		return v.Emit()
+	}
+}
@@ -0,0 +1,178 @@
+package tracingx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// newXRayDaemon returns a UDP listener standing in for the X-Ray daemon
+func newXRayDaemon(t *testing.T) net.PacketConn {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// readSegments reads n segment documents sent to daemon
+func readSegments(t *testing.T, daemon net.PacketConn, n int) []map[string]any {
+	t.Helper()
+	require.NoError(t, daemon.SetReadDeadline(time.Now().Add(5*time.Second)))
+	segments := make([]map[string]any, 0, n)
+	buf := make([]byte, 64<<10)
+	for len(segments) < n {
+		size, _, err := daemon.ReadFrom(buf)
+		require.NoError(t, err)
+		header, doc, ok := bytes.Cut(buf[:size], []byte("\n"))
+		require.True(t, ok)
+		assert.JSONEq(t, `{"format":"json","version":1}`, string(header))
+		var segment map[string]any
+		require.NoError(t, json.Unmarshal(doc, &segment))
+		segments = append(segments, segment)
+	}
+	return segments
+}
+
+func TestXRayProvider(t *testing.T) {
+	t.Run("exports over OTLP by default", func(t *testing.T) {
+		collector := newFakeCollector(t)
+		t.Setenv("AWS_REGION", "eu-west-1")
+
+		result, err := NewTracer(Params{
+			Config: Config{
+				Enabled:     true,
+				Provider:    "xray",
+				ServiceName: "test-service",
+				SampleRate:  1.0,
+				OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+			},
+			Logger: logx.NewNoopLogger(),
+		})
+		require.NoError(t, err)
+		provider := result.Provider
+		defer provider.Shutdown(context.Background())
+
+		ctx, span := provider.Start(context.Background(), "charge")
+		headers := map[string]string{}
+		require.NoError(t, provider.Inject(ctx, MapCarrier(headers)))
+		assert.True(t, strings.HasPrefix(headers[xrayTraceHeader], "Root=1-"+span.TraceID()[:8]+"-"))
+		assert.NotEmpty(t, headers["traceparent"])
+		span.End()
+		require.NoError(t, provider.ForceFlush(context.Background()))
+		assert.Equal(t, []string{"charge"}, spanNames(collector))
+
+		epoch, err := strconv.ParseInt(span.TraceID()[:8], 16, 64)
+		require.NoError(t, err)
+		assert.InDelta(t, time.Now().Unix(), epoch, 60, "xray IDs by default")
+
+		res := map[string]string{}
+		for _, kv := range provider.(*otlpProvider).current().resource.Attributes() {
+			res[string(kv.Key)] = kv.Value.Emit()
+		}
+		assert.Equal(t, "aws", res["cloud.provider"])
+		assert.Equal(t, "eu-west-1", res["cloud.region"])
+
+		assert.Equal(t, "xray", provider.Diagnostics().Provider)
+		diag := startupDiagnostics(Config{Provider: "xray"}, sdktrace.AlwaysSample(), resource.Empty())
+		assert.Equal(t, IDGeneratorXRay, diag["id_generator"])
+	})
+
+	t.Run("exports segments to the daemon", func(t *testing.T) {
+		daemon := newXRayDaemon(t)
+		provider, err := newXRayProvider(Config{
+			ServiceName: "checkout",
+			SampleRate:  1.0,
+			XRay: XRayConfig{
+				Direct:            true,
+				DaemonAddress:     daemon.LocalAddr().String(),
+				IndexedAttributes: []string{"order.id"},
+			},
+		}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		ctx, root := provider.Start(context.Background(), "POST /orders",
+			WithSpanKind(SpanKindServer),
+			WithAttributes(map[string]any{
+				"order.id":                "ord_1",
+				httpRequestMethodKey:      "POST",
+				urlFullKey:                "https://shop.example/orders",
+				httpResponseStatusCodeKey: 503,
+			}),
+		)
+		_, child := provider.Start(ctx, "charge",
+			WithSpanKind(SpanKindClient),
+			WithAttributes(map[string]any{peerServiceKey: "payments", "payment.id": "pay_1"}),
+		)
+		child.End()
+		root.End()
+		require.NoError(t, provider.ForceFlush(context.Background()))
+
+		segments := readSegments(t, daemon, 2)
+		sub, segment := segments[0], segments[1]
+		xrayTraceID := "1-" + root.TraceID()[:8] + "-" + root.TraceID()[8:]
+
+		assert.Equal(t, "checkout", segment["name"])
+		assert.Equal(t, root.SpanID(), segment["id"])
+		assert.Equal(t, xrayTraceID, segment["trace_id"])
+		assert.NotContains(t, segment, "parent_id")
+		assert.NotContains(t, segment, "type")
+		assert.Equal(t, true, segment["fault"])
+		assert.Equal(t, map[string]any{"order_id": "ord_1"}, segment["annotations"])
+		assert.Equal(t, map[string]any{
+			"request":  map[string]any{"method": "POST", "url": "https://shop.example/orders"},
+			"response": map[string]any{"status": float64(503)},
+		}, segment["http"])
+		assert.Less(t, segment["start_time"], segment["end_time"])
+
+		assert.Equal(t, "payments", sub["name"])
+		assert.Equal(t, "subsegment", sub["type"])
+		assert.Equal(t, "remote", sub["namespace"])
+		assert.Equal(t, root.SpanID(), sub["parent_id"])
+		assert.Equal(t, xrayTraceID, sub["trace_id"])
+		assert.Equal(t, "pay_1", sub["metadata"].(map[string]any)["default"].(map[string]any)["payment.id"])
+		assert.NotContains(t, sub, "fault")
+
+		diag := startupDiagnostics(provider.(*otlpProvider).current().config, sdktrace.AlwaysSample(), resource.Empty())
+		assert.Equal(t, daemon.LocalAddr().String(), diag["exporter.endpoint"])
+		assert.NotContains(t, diag, "exporter.headers")
+		assert.NoError(t, provider.SelfTest(context.Background()))
+	})
+}
+
+func TestXRayConfig(t *testing.T) {
+	t.Run("reads the daemon address from the environment", func(t *testing.T) {
+		t.Setenv("AWS_XRAY_DAEMON_ADDRESS", "")
+		assert.Equal(t, "127.0.0.1:2000", XRayConfig{}.daemonAddress())
+
+		t.Setenv("AWS_XRAY_DAEMON_ADDRESS", "xray:3000")
+		assert.Equal(t, "xray:3000", XRayConfig{}.daemonAddress())
+
+		t.Setenv("AWS_XRAY_DAEMON_ADDRESS", "tcp:xray:2000 udp:xray-udp:2000")
+		assert.Equal(t, "xray-udp:2000", XRayConfig{}.daemonAddress())
+		assert.Equal(t, "daemon:2000", XRayConfig{DaemonAddress: "daemon:2000"}.daemonAddress())
+	})
+
+	t.Run("keeps an explicit ID generator", func(t *testing.T) {
+		assert.Equal(t, IDGenerator64Bit, Config{Provider: "xray", IDGenerator: IDGenerator64Bit}.idGenerator())
+		assert.Equal(t, IDGeneratorRandom, Config{Provider: "xray", IDGenerator: IDGeneratorRandom}.idGenerator())
+		assert.Equal(t, IDGeneratorRandom, Config{Provider: "otlp"}.idGenerator())
+	})
+
+	t.Run("sanitizes names", func(t *testing.T) {
+		assert.Equal(t, "GET /orders/_id_", xraySegmentName("GET /orders/{id}"))
+		assert.Equal(t, "http_server_duration", xrayAnnotationKey("http.server-duration"))
+		assert.Len(t, xraySegmentName(strings.Repeat("a", 300)), 200)
+	})
+}
@@ -0,0 +1,40 @@
+package tracingx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	"go.opentelemetry.io/otel/exporters/zipkin"
+)
+
+// ZipkinConfig contains Zipkin configuration
+type ZipkinConfig struct {
+	// Endpoint is the Zipkin collector's span endpoint
+	Endpoint string `mapstructure:"endpoint" default:"http://localhost:9411/api/v2/spans"`
+
+	// Timeout bounds each export request
+	Timeout time.Duration `mapstructure:"timeout" default:"10s"`
+}
+
+// newZipkinProvider creates a tracing provider exporting to Zipkin. It shares
+// the OTLP provider's pipeline, so sampling, processors, diagnostics and
+// Reconfigure behave the same; only the exporter differs.
+func newZipkinProvider(config Config, logger logx.Logger, providerOpts ...providerOption) (Provider, error) {
+	config.Provider = "zipkin"
+	return newOTLPProvider(config, logger, providerOpts...)
+}
+
+// newZipkinWorkers creates the export worker sending spans to Zipkin
+func newZipkinWorkers(config ZipkinConfig, clock Clock, total *exportStats) ([]exportWorker, error) {
+	if config.Endpoint == "" {
+		return nil, errors.New("failed to create Zipkin exporter: endpoint is required")
+	}
+	exporter, err := zipkin.New(config.Endpoint, zipkin.WithClient(&http.Client{Timeout: config.Timeout}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Zipkin exporter: %w", err)
+	}
+	return []exportWorker{newExportWorker(exporter, nil, OTLPConfig{}, clock, total)}, nil
+}
@@ -0,0 +1,120 @@
+package tracingx
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// zipkinSpan is the part of a Zipkin v2 span checked by the tests
+type zipkinSpan struct {
+	TraceID string            `json:"traceId"`
+	ID      string            `json:"id"`
+	Name    string            `json:"name"`
+	Kind    string            `json:"kind"`
+	Tags    map[string]string `json:"tags"`
+	Local   struct {
+		ServiceName string `json:"serviceName"`
+	} `json:"localEndpoint"`
+}
+
+// newZipkinCollector returns a server recording the spans posted to it
+func newZipkinCollector(t *testing.T) (*httptest.Server, func() []zipkinSpan) {
+	var mu sync.Mutex
+	var spans []zipkinSpan
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []zipkinSpan
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mu.Lock()
+		spans = append(spans, batch...)
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []zipkinSpan {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]zipkinSpan(nil), spans...)
+	}
+}
+
+func TestZipkinProvider(t *testing.T) {
+	collector, received := newZipkinCollector(t)
+
+	result, err := NewTracer(Params{
+		Config: Config{
+			Enabled:     true,
+			Provider:    "zipkin",
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			Zipkin:      ZipkinConfig{Endpoint: collector.URL, Timeout: time.Second},
+		},
+		Logger: logx.NewNoopLogger(),
+	})
+	require.NoError(t, err)
+	provider := result.Provider
+	defer provider.Shutdown(context.Background())
+
+	_, span := provider.Start(context.Background(), "charge",
+		WithSpanKind(SpanKindClient),
+		WithAttributes(map[string]any{"payment.id": "pay_1"}),
+	)
+	span.End()
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	spans := received()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "charge", spans[0].Name)
+	assert.Equal(t, "CLIENT", spans[0].Kind)
+	assert.Equal(t, span.TraceID(), spans[0].TraceID)
+	assert.Equal(t, span.SpanID(), spans[0].ID)
+	assert.Equal(t, "pay_1", spans[0].Tags["payment.id"])
+	assert.Equal(t, "test-service", spans[0].Local.ServiceName)
+
+	diag := provider.Diagnostics()
+	assert.Equal(t, "zipkin", diag.Provider)
+	assert.True(t, diag.ExporterHealthy)
+	assert.Equal(t, uint64(1), diag.SpansExported)
+	assert.NoError(t, provider.SelfTest(context.Background()))
+}
+
+func TestZipkinProviderErrors(t *testing.T) {
+	t.Run("requires an endpoint", func(t *testing.T) {
+		_, err := newZipkinProvider(Config{ServiceName: "test-service"}, getTestLogger())
+		assert.ErrorContains(t, err, "endpoint is required")
+	})
+
+	t.Run("rejects invalid endpoints", func(t *testing.T) {
+		_, err := newZipkinProvider(Config{ServiceName: "test-service", Zipkin: ZipkinConfig{Endpoint: "localhost:9411"}}, getTestLogger())
+		assert.ErrorContains(t, err, "failed to create Zipkin exporter")
+	})
+
+	t.Run("reports export failures", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		provider, err := newZipkinProvider(Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			Zipkin:      ZipkinConfig{Endpoint: server.URL, Timeout: time.Second},
+		}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		assert.Error(t, provider.SelfTest(context.Background()))
+		assert.False(t, provider.Diagnostics().ExporterHealthy)
+	})
+}
@@ -0,0 +1,63 @@
+package tracingx
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+// reconfigureDrainTimeout bounds how long Reconfigure waits for spans started
+// on the replaced pipeline to end and be exported
+const reconfigureDrainTimeout = 10 * time.Second
+
+// Reconfigure replaces the exporter, sampler, processors and propagators
+// with ones built from config. Spans started from now on use the new
+// pipeline; the old one is drained, waiting for its in-flight spans to end
+// and flushing them to the old exporter before it is shut down. The provider
+// type (config.Provider, config.Enabled) cannot be changed. When config is
+// invalid the current pipeline is kept and the error returned.
+func (p *otlpProvider) Reconfigure(config Config) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed.Load() {
+		return ErrProviderShutdown
+	}
+
+	propagator, err := newTextMapPropagator(config.propagators(), p.options.propagators)
+	if err != nil {
+		return fmt.Errorf("failed to reconfigure tracing provider: %w", err)
+	}
+	old := p.current()
+	next, err := newOTLPPipeline(context.Background(), config, p.logger, p.options, old.stats)
+	if err != nil {
+		return fmt.Errorf("failed to reconfigure tracing provider: %w", err)
+	}
+	p.pipeline.Store(next)
+	otel.SetTracerProvider(next.tracerProvider)
+	otel.SetTextMapPropagator(propagator)
+
+	p.logger.Info("OTLP tracing provider reconfigured",
+		diagnosticFields(startupDiagnostics(config, next.sampler, next.resource))...)
+	p.recordSamplingChange(samplingSourceReconfigure, old.samplingState(), next.samplingState())
+
+	if err := old.drain(reconfigureDrainTimeout); err != nil {
+		return fmt.Errorf("failed to drain replaced tracing pipeline: %w", err)
+	}
+	return nil
+}
+
+// drain waits up to timeout for the spans still active on the pipeline to
+// end, then shuts it down, exporting everything queued. Spans still open
+// after timeout are dropped.
+func (p *otlpPipeline) drain(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for p.active.Load() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return p.tracerProvider.Shutdown(ctx)
+}
@@ -0,0 +1,189 @@
+package tracingx
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func spanNames(collector *fakeCollector) []string {
+	var names []string
+	for _, span := range collector.received() {
+		names = append(names, span.Name)
+	}
+	return names
+}
+
+func TestReconfigure(t *testing.T) {
+	t.Run("swaps exporter and drains the old pipeline", func(t *testing.T) {
+		before, after := newFakeCollector(t), newFakeCollector(t)
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			OTLP:        OTLPConfig{Endpoint: before.endpoint, Insecure: true},
+		}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		_, inFlight := provider.Start(context.Background(), "in-flight")
+		go func() {
+			time.Sleep(50 * time.Millisecond)
+			inFlight.End()
+		}()
+
+		require.NoError(t, provider.Reconfigure(Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			OTLP:        OTLPConfig{Endpoint: after.endpoint, Insecure: true},
+		}))
+		assert.Equal(t, []string{"in-flight"}, spanNames(before))
+
+		_, span := provider.Start(context.Background(), "reconfigured")
+		span.End()
+		require.NoError(t, provider.ForceFlush(context.Background()))
+		assert.Equal(t, []string{"reconfigured"}, spanNames(after))
+		assert.Equal(t, uint64(2), provider.Diagnostics().SpansExported)
+	})
+
+	t.Run("swaps sampler", func(t *testing.T) {
+		collector := newFakeCollector(t)
+		config := Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+		}
+		provider, err := newOTLPProvider(config, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		config.SampleRate = 0
+		require.NoError(t, provider.Reconfigure(config))
+		assert.Equal(t, 0.0, provider.Diagnostics().SampleRate)
+
+		_, span := provider.Start(context.Background(), "unsampled")
+		assert.False(t, span.IsSampled())
+		span.End()
+	})
+
+	t.Run("spans started concurrently are not dropped", func(t *testing.T) {
+		collector := newFakeCollector(t)
+		config := Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+		}
+		provider, err := newOTLPProvider(config, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		var wg sync.WaitGroup
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for range 200 {
+					_, span := provider.Start(context.Background(), "concurrent")
+					span.End()
+				}
+			}()
+		}
+		for range 5 {
+			require.NoError(t, provider.Reconfigure(config))
+		}
+		wg.Wait()
+		require.NoError(t, provider.ForceFlush(context.Background()))
+		assert.Len(t, collector.received(), 800)
+	})
+
+	t.Run("keeps the current pipeline on invalid config", func(t *testing.T) {
+		collector := newFakeCollector(t)
+		config := Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+		}
+		provider, err := newOTLPProvider(config, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		invalid := config
+		invalid.Export.ExcludeKinds = []string{"bogus"}
+		require.Error(t, provider.Reconfigure(invalid))
+
+		_, span := provider.Start(context.Background(), "still-exported")
+		span.End()
+		require.NoError(t, provider.ForceFlush(context.Background()))
+		assert.Equal(t, []string{"still-exported"}, spanNames(collector))
+	})
+
+	t.Run("failed builds shut down what they created", func(t *testing.T) {
+		collector := newFakeCollector(t)
+		config := Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true, Workers: 2},
+		}
+		goroutines := settledGoroutines()
+		provider, err := newOTLPProvider(config, getTestLogger())
+		require.NoError(t, err)
+
+		invalid := config
+		invalid.Pipelines = []PipelineConfig{{Name: "errors", OTLP: OTLPConfig{Endpoint: collector.endpoint, Insecure: true}}}
+		invalid.Audit = AuditConfig{Enabled: true, Path: filepath.Join(t.TempDir(), "missing", "audit.log")}
+		for range 20 {
+			require.Error(t, provider.Reconfigure(invalid))
+		}
+		require.NoError(t, provider.Shutdown(context.Background()))
+		assert.Eventually(t, func() bool {
+			return runtime.NumGoroutine() <= goroutines+2
+		}, 5*time.Second, 10*time.Millisecond, "workers of failed builds are shut down")
+	})
+
+	t.Run("keeps injected audit sink open", func(t *testing.T) {
+		sink, collector := &memoryAuditSink{}, newFakeCollector(t)
+		config := Config{
+			ServiceName: "billing",
+			Audit:       AuditConfig{Enabled: true, Operations: []string{"payment.refund"}},
+			OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+		}
+		provider, err := newOTLPProvider(config, getTestLogger(), withAuditSink(sink))
+		require.NoError(t, err)
+
+		require.NoError(t, provider.Reconfigure(config))
+		assert.False(t, sink.closed)
+
+		_, span := provider.Start(context.Background(), "payment.refund")
+		span.End()
+		require.NoError(t, provider.Shutdown(context.Background()))
+		assert.True(t, sink.closed)
+		assert.Len(t, sink.records, 1)
+	})
+
+	t.Run("keeps injected exporter running", func(t *testing.T) {
+		exporter := &shutdownExporter{}
+		config := Config{ServiceName: "test-service", SampleRate: 1.0}
+		provider, err := newOTLPProvider(config, getTestLogger(), withExporter(exporter))
+		require.NoError(t, err)
+
+		require.NoError(t, provider.Reconfigure(config))
+		assert.Zero(t, exporter.shutdowns.Load())
+
+		_, span := provider.Start(context.Background(), "reconfigured")
+		span.End()
+		require.NoError(t, provider.ForceFlush(context.Background()))
+		assert.Len(t, exporter.GetSpans(), 1)
+
+		require.NoError(t, provider.Shutdown(context.Background()))
+		assert.Equal(t, int32(1), exporter.shutdowns.Load())
+	})
+
+	t.Run("noop provider", func(t *testing.T) {
+		assert.NoError(t, newNoopProvider().Reconfigure(Config{}))
+	})
+}
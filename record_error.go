@@ -0,0 +1,147 @@
+package tracingx
+
+import (
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrorOption configures an error recorded with Span.RecordError
+type ErrorOption func(*ErrorConfig)
+
+// ErrorConfig holds the options of a recorded error
+type ErrorConfig struct {
+	// Timestamp is when the error occurred; defaults to now
+	Timestamp time.Time
+	// Fields are added to the exception event
+	Fields []Field
+	// Escaped marks an error that escaped the span's scope, e.g. returned
+	// or panicked out of the operation
+	Escaped bool
+	// StackTrace records the stack of the caller on the event
+	StackTrace bool
+}
+
+// WithErrorTimestamp records the error at t instead of now
+func WithErrorTimestamp(t time.Time) ErrorOption {
+	return func(c *ErrorConfig) {
+		c.Timestamp = t
+	}
+}
+
+// WithErrorFields adds fields to the exception event
+func WithErrorFields(fields ...Field) ErrorOption {
+	return func(c *ErrorConfig) {
+		c.Fields = append(c.Fields, fields...)
+	}
+}
+
+// WithEscaped marks the error as having escaped the span's scope
+func WithEscaped() ErrorOption {
+	return func(c *ErrorConfig) {
+		c.Escaped = true
+	}
+}
+
+// WithStackTrace records the caller's stack trace on the exception event
+func WithStackTrace() ErrorOption {
+	return func(c *ErrorConfig) {
+		c.StackTrace = true
+	}
+}
+
+// exceptionEscapedKey marks exception events for errors that left the
+// span's scope; deprecated in later semantic conventions, but still read by
+// most backends
+const exceptionEscapedKey = "exception.escaped"
+
+// RecordError records err as an exception event without marking the span
+// as failed: unlike SetError it neither sets the error tag nor changes the
+// span status, since a handled error, e.g. a retried call, does not fail the
+// operation. A nil err is ignored.
+func (s *otlpSpan) RecordError(err error, opts ...ErrorOption) {
+	if err == nil {
+		return
+	}
+	if s.ended() {
+		s.misuse("RecordError called after End")
+		return
+	}
+	config := ErrorConfig{Timestamp: s.clock.Now()}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	attrs := make([]attribute.KeyValue, 0, len(config.Fields)+1)
+	if config.Escaped {
+		attrs = append(attrs, attribute.Bool(exceptionEscapedKey, true))
+	}
+	for _, f := range config.Fields {
+		if f.Key != "" {
+			attrs = append(attrs, f.attribute())
+		}
+	}
+	eventOpts := []trace.EventOption{trace.WithTimestamp(config.Timestamp), trace.WithAttributes(attrs...)}
+	if config.StackTrace {
+		eventOpts = append(eventOpts, trace.WithStackTrace(true))
+	}
+	s.recordException(err, eventOpts...)
+}
+
+// recordException records err as an exception event. Errors redacted by an
+// attribute allowlist are recorded with their type only.
+func (s *otlpSpan) recordException(err error, opts ...trace.EventOption) {
+	if redacted, ok := err.(redactedError); ok {
+		opts = append(opts, trace.WithAttributes(attribute.String(exceptionTypeKey, redacted.typ)))
+		s.span.AddEvent(exceptionEventName, opts...)
+		return
+	}
+	s.span.RecordError(err, opts...)
+}
+
+// Attributes recorded by SetErrors
+const (
+	// ExceptionIndexAttribute is the position of an error among those
+	// recorded together by SetErrors
+	ExceptionIndexAttribute = "exception.index"
+
+	// ErrorCountAttribute is the number of errors recorded by SetErrors
+	ErrorCountAttribute = "error.count"
+)
+
+// SetErrors marks the span as errored like SetError, recording each error as
+// its own exception event with ExceptionIndexAttribute, e.g. for a batch
+// operation where several items failed. Errors combined with errors.Join
+// are split into their parts; nil errors are skipped.
+func (s *otlpSpan) SetErrors(errs ...error) {
+	if s.ended() {
+		s.misuse("SetErrors called after End")
+		return
+	}
+	flat := flattenErrors(nil, errs)
+	if len(flat) == 0 {
+		return
+	}
+	now := s.clock.Now()
+	for i, err := range flat {
+		s.recordException(err, trace.WithTimestamp(now), trace.WithAttributes(attribute.Int(ExceptionIndexAttribute, i)))
+	}
+	s.span.SetAttributes(attribute.Bool("error", true), attribute.Int(ErrorCountAttribute, len(flat)))
+}
+
+// flattenErrors appends the non-nil errors in errs to dst, replacing errors
+// created by errors.Join with the errors they join
+func flattenErrors(dst []error, errs []error) []error {
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			dst = flattenErrors(dst, joined.Unwrap())
+			continue
+		}
+		dst = append(dst, err)
+	}
+	return dst
+}
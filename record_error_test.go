@@ -0,0 +1,109 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestRecordError(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("records an exception event without failing the span", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "charge")
+		defer span.End()
+		at := time.Now().Add(-time.Second)
+		span.RecordError(errors.New("card declined"),
+			WithErrorTimestamp(at),
+			WithErrorFields(String("payment.attempt", "2")),
+			WithEscaped(),
+		)
+
+		ro := span.(*otlpSpan).span.(sdktrace.ReadOnlySpan)
+		require.Len(t, ro.Events(), 1)
+		event := ro.Events()[0]
+		assert.Equal(t, "exception", event.Name)
+		assert.Equal(t, at.UnixNano(), event.Time.UnixNano())
+		attrs := map[string]any{}
+		for _, kv := range event.Attributes {
+			attrs[string(kv.Key)] = kv.Value.AsInterface()
+		}
+		assert.Equal(t, "card declined", attrs["exception.message"])
+		assert.Equal(t, true, attrs[exceptionEscapedKey])
+		assert.Equal(t, "2", attrs["payment.attempt"])
+		assert.NotContains(t, attrs, "exception.stacktrace")
+
+		assert.Equal(t, codes.Unset, ro.Status().Code)
+		assert.NotContains(t, attributesOf(t, span), "error")
+	})
+
+	t.Run("records the stack trace on request", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "charge")
+		defer span.End()
+		span.RecordError(errors.New("timeout"), WithStackTrace())
+
+		event := span.(*otlpSpan).span.(sdktrace.ReadOnlySpan).Events()[0]
+		var stack string
+		for _, kv := range event.Attributes {
+			if kv.Key == "exception.stacktrace" {
+				stack = kv.Value.AsString()
+			}
+		}
+		assert.True(t, strings.Contains(stack, "TestRecordError"), stack)
+	})
+
+	t.Run("ignores nil errors", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "charge")
+		defer span.End()
+		span.RecordError(nil)
+		assert.Empty(t, span.(*otlpSpan).span.(sdktrace.ReadOnlySpan).Events())
+	})
+
+	t.Run("noop span", func(t *testing.T) {
+		_, span := newNoopProvider().Start(context.Background(), "charge")
+		span.RecordError(errors.New("ignored"), WithEscaped())
+	})
+}
+
+func TestSetErrors(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("records each error with its index", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "bulk")
+		defer span.End()
+		first, second, third := errors.New("item 1"), errors.New("item 4"), errors.New("item 7")
+		span.SetErrors(first, nil, errors.Join(second, errors.Join(third)))
+
+		events := span.(*otlpSpan).span.(sdktrace.ReadOnlySpan).Events()
+		require.Len(t, events, 3)
+		for i, want := range []string{"item 1", "item 4", "item 7"} {
+			attrs := map[string]any{}
+			for _, kv := range events[i].Attributes {
+				attrs[string(kv.Key)] = kv.Value.AsInterface()
+			}
+			assert.Equal(t, want, attrs["exception.message"])
+			assert.Equal(t, int64(i), attrs[ExceptionIndexAttribute])
+		}
+		attrs := attributesOf(t, span)
+		assert.Equal(t, true, attrs["error"])
+		assert.Equal(t, int64(3), attrs[ErrorCountAttribute])
+	})
+
+	t.Run("ignores nil errors", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "bulk")
+		defer span.End()
+		span.SetErrors(nil, errors.Join(nil))
+		assert.NotContains(t, attributesOf(t, span), "error")
+	})
+}
@@ -0,0 +1,210 @@
+package tracingx
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+)
+
+// httpConnectionHijackedKey marks server spans whose handler hijacked the
+// connection before writing a response
+const httpConnectionHijackedKey = "http.connection.hijacked"
+
+// statusRecorder captures the status code and body size written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	size        int64
+	wroteHeader bool
+	hijacked    bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.Write(b)
+	r.size += int64(n)
+	return n, err
+}
+
+// Unwrap exposes the underlying ResponseWriter to http.ResponseController
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *statusRecorder) flush() {
+	// Flushing commits the headers with the current status
+	r.wroteHeader = true
+	r.ResponseWriter.(http.Flusher).Flush()
+}
+
+func (r *statusRecorder) hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := r.ResponseWriter.(http.Hijacker).Hijack()
+	if err == nil {
+		r.hijacked = true
+	}
+	return conn, rw, err
+}
+
+func (r *statusRecorder) readFrom(src io.Reader) (int64, error) {
+	r.wroteHeader = true
+	n, err := r.ResponseWriter.(io.ReaderFrom).ReadFrom(src)
+	r.size += n
+	return n, err
+}
+
+func (r *statusRecorder) push(target string, opts *http.PushOptions) error {
+	return r.ResponseWriter.(http.Pusher).Push(target, opts)
+}
+
+// The adapters below expose one optional interface of the underlying
+// ResponseWriter each, so wrap can combine exactly the supported ones
+
+type recorderFlusher struct{ r *statusRecorder }
+
+func (f recorderFlusher) Flush() { f.r.flush() }
+
+type recorderHijacker struct{ r *statusRecorder }
+
+func (h recorderHijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) { return h.r.hijack() }
+
+type recorderReaderFrom struct{ r *statusRecorder }
+
+func (rf recorderReaderFrom) ReadFrom(src io.Reader) (int64, error) { return rf.r.readFrom(src) }
+
+type recorderPusher struct{ r *statusRecorder }
+
+func (p recorderPusher) Push(target string, opts *http.PushOptions) error {
+	return p.r.push(target, opts)
+}
+
+// wrap returns the recorder as a ResponseWriter implementing the same
+// optional interfaces as the one it records, no more and no fewer, so type
+// assertions by handlers (SSE, websockets, sendfile) behave as without it
+func (r *statusRecorder) wrap() http.ResponseWriter {
+	const (
+		flusher = 1 << iota
+		hijacker
+		readerFrom
+		pusher
+	)
+	var caps int
+	if _, ok := r.ResponseWriter.(http.Flusher); ok {
+		caps |= flusher
+	}
+	if _, ok := r.ResponseWriter.(http.Hijacker); ok {
+		caps |= hijacker
+	}
+	if _, ok := r.ResponseWriter.(io.ReaderFrom); ok {
+		caps |= readerFrom
+	}
+	if _, ok := r.ResponseWriter.(http.Pusher); ok {
+		caps |= pusher
+	}
+
+	f, h, rf, p := recorderFlusher{r}, recorderHijacker{r}, recorderReaderFrom{r}, recorderPusher{r}
+	switch caps {
+	case flusher:
+		return struct {
+			*statusRecorder
+			http.Flusher
+		}{r, f}
+	case hijacker:
+		return struct {
+			*statusRecorder
+			http.Hijacker
+		}{r, h}
+	case readerFrom:
+		return struct {
+			*statusRecorder
+			io.ReaderFrom
+		}{r, rf}
+	case pusher:
+		return struct {
+			*statusRecorder
+			http.Pusher
+		}{r, p}
+	case flusher | hijacker:
+		return struct {
+			*statusRecorder
+			http.Flusher
+			http.Hijacker
+		}{r, f, h}
+	case flusher | readerFrom:
+		return struct {
+			*statusRecorder
+			http.Flusher
+			io.ReaderFrom
+		}{r, f, rf}
+	case flusher | pusher:
+		return struct {
+			*statusRecorder
+			http.Flusher
+			http.Pusher
+		}{r, f, p}
+	case hijacker | readerFrom:
+		return struct {
+			*statusRecorder
+			http.Hijacker
+			io.ReaderFrom
+		}{r, h, rf}
+	case hijacker | pusher:
+		return struct {
+			*statusRecorder
+			http.Hijacker
+			http.Pusher
+		}{r, h, p}
+	case readerFrom | pusher:
+		return struct {
+			*statusRecorder
+			io.ReaderFrom
+			http.Pusher
+		}{r, rf, p}
+	case flusher | hijacker | readerFrom:
+		return struct {
+			*statusRecorder
+			http.Flusher
+			http.Hijacker
+			io.ReaderFrom
+		}{r, f, h, rf}
+	case flusher | hijacker | pusher:
+		return struct {
+			*statusRecorder
+			http.Flusher
+			http.Hijacker
+			http.Pusher
+		}{r, f, h, p}
+	case flusher | readerFrom | pusher:
+		return struct {
+			*statusRecorder
+			http.Flusher
+			io.ReaderFrom
+			http.Pusher
+		}{r, f, rf, p}
+	case hijacker | readerFrom | pusher:
+		return struct {
+			*statusRecorder
+			http.Hijacker
+			io.ReaderFrom
+			http.Pusher
+		}{r, h, rf, p}
+	case flusher | hijacker | readerFrom | pusher:
+		return struct {
+			*statusRecorder
+			http.Flusher
+			http.Hijacker
+			io.ReaderFrom
+			http.Pusher
+		}{r, f, h, rf, p}
+	default:
+		return r
+	}
+}
@@ -0,0 +1,184 @@
+package tracingx
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// plainWriter implements only http.ResponseWriter
+type plainWriter struct {
+	header http.Header
+	status int
+	body   strings.Builder
+}
+
+func newPlainWriter() *plainWriter { return &plainWriter{header: http.Header{}} }
+
+func (w *plainWriter) Header() http.Header         { return w.header }
+func (w *plainWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+func (w *plainWriter) WriteHeader(status int)      { w.status = status }
+
+// hijackWriter additionally implements http.Hijacker
+type hijackWriter struct {
+	*plainWriter
+	hijacked bool
+}
+
+func (w *hijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	w.hijacked = true
+	return nil, nil, nil
+}
+
+// fullWriter implements every optional interface
+type fullWriter struct {
+	*hijackWriter
+	flushed bool
+	pushed  string
+}
+
+func (w *fullWriter) Flush() { w.flushed = true }
+
+func (w *fullWriter) ReadFrom(src io.Reader) (int64, error) { return io.Copy(&w.body, src) }
+
+func (w *fullWriter) Push(target string, opts *http.PushOptions) error {
+	w.pushed = target
+	return nil
+}
+
+// interfacesOf lists the optional interfaces implemented by w
+func interfacesOf(w http.ResponseWriter) []string {
+	var names []string
+	if _, ok := w.(http.Flusher); ok {
+		names = append(names, "Flusher")
+	}
+	if _, ok := w.(http.Hijacker); ok {
+		names = append(names, "Hijacker")
+	}
+	if _, ok := w.(io.ReaderFrom); ok {
+		names = append(names, "ReaderFrom")
+	}
+	if _, ok := w.(http.Pusher); ok {
+		names = append(names, "Pusher")
+	}
+	return names
+}
+
+func TestStatusRecorderInterfaces(t *testing.T) {
+	writers := map[string]http.ResponseWriter{
+		"plain":    newPlainWriter(),
+		"recorder": httptest.NewRecorder(),
+		"hijacker": &hijackWriter{plainWriter: newPlainWriter()},
+		"full":     &fullWriter{hijackWriter: &hijackWriter{plainWriter: newPlainWriter()}},
+	}
+	for name, w := range writers {
+		t.Run(name, func(t *testing.T) {
+			wrapped := (&statusRecorder{ResponseWriter: w, status: http.StatusOK}).wrap()
+			assert.Equal(t, interfacesOf(w), interfacesOf(wrapped))
+		})
+	}
+
+	t.Run("passes calls through and records size", func(t *testing.T) {
+		w := &fullWriter{hijackWriter: &hijackWriter{plainWriter: newPlainWriter()}}
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		wrapped := rec.wrap()
+
+		wrapped.(http.Flusher).Flush()
+		assert.True(t, w.flushed)
+		assert.True(t, rec.wroteHeader)
+
+		require.NoError(t, wrapped.(http.Pusher).Push("/app.js", nil))
+		assert.Equal(t, "/app.js", w.pushed)
+
+		n, err := wrapped.(io.ReaderFrom).ReadFrom(strings.NewReader("hello"))
+		require.NoError(t, err)
+		assert.Equal(t, int64(5), n)
+		_, err = wrapped.Write([]byte(" world"))
+		require.NoError(t, err)
+		assert.Equal(t, int64(11), rec.size)
+		assert.Equal(t, "hello world", w.body.String())
+
+		_, _, err = wrapped.(http.Hijacker).Hijack()
+		require.NoError(t, err)
+		assert.True(t, w.hijacked)
+		assert.True(t, rec.hijacked)
+	})
+
+	t.Run("works with http.ResponseController", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		wrapped := (&statusRecorder{ResponseWriter: w, status: http.StatusOK}).wrap()
+		require.NoError(t, http.NewResponseController(wrapped).Flush())
+		assert.True(t, w.Flushed)
+	})
+}
+
+func TestMiddlewareStreaming(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	spans := make(chan Span, 1)
+	handler := NewMiddleware(provider)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		spans <- SpanFromContext(r.Context())
+		switch r.URL.Path {
+		case "/events":
+			flusher, ok := w.(http.Flusher)
+			require.True(t, ok)
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = w.Write([]byte("data: 1\n\n"))
+			flusher.Flush()
+		case "/upgrade":
+			conn, buf, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			_, _ = buf.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: close\r\n\r\n")
+			_ = buf.Flush()
+			conn.Close()
+		}
+	}))
+	done := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler.ServeHTTP(w, r)
+		done <- struct{}{}
+	}))
+	defer server.Close()
+
+	t.Run("server-sent events", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/events")
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, "data: 1\n\n", string(body))
+
+		<-done
+		span := <-spans
+		attrs := attributesOf(t, span)
+		assert.Equal(t, int64(http.StatusOK), attrs[httpResponseStatusCodeKey])
+		assert.Equal(t, int64(len(body)), attrs[httpResponseBodySizeKey])
+	})
+
+	t.Run("hijacked connections", func(t *testing.T) {
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		require.NoError(t, err)
+		defer conn.Close()
+		_, err = conn.Write([]byte("GET /upgrade HTTP/1.1\r\nHost: test\r\nConnection: Upgrade\r\nUpgrade: websocket\r\n\r\n"))
+		require.NoError(t, err)
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+		<-done
+		span := <-spans
+		attrs := attributesOf(t, span)
+		assert.Equal(t, true, attrs[httpConnectionHijackedKey])
+		assert.NotContains(t, attrs, httpResponseStatusCodeKey)
+	})
+}
@@ -0,0 +1,85 @@
+package tracingx
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Retry attribute keys recorded on attempt spans
+const (
+	RetryAttemptAttribute   = "retry.attempt"
+	RetryMaxAttribute       = "retry.max"
+	RetryBackoffMsAttribute = "retry.backoff_ms"
+)
+
+// retryState tracks the attempts of one retried operation
+type retryState struct {
+	max int
+
+	mu       sync.Mutex
+	attempts int
+	backoff  time.Duration
+	original SpanContext
+}
+
+type retryStateKey struct{}
+
+// ContextWithRetry marks ctx as the context of an operation retried up to
+// max attempts. Spans started with StartRetryAttempt, and requests sent
+// through NewTransport, are annotated with retry.attempt (starting at 1),
+// retry.max and the preceding backoff delay, and every retry is linked to the
+// span of the first attempt. Retry loops that reuse the request context, as
+// most HTTP retry clients do, need no further changes.
+func ContextWithRetry(ctx context.Context, max int) context.Context {
+	return context.WithValue(ctx, retryStateKey{}, &retryState{max: max})
+}
+
+// RecordRetryBackoff records the delay waited before the next attempt of the
+// operation in ctx. It is a no-op if ctx was not created by ContextWithRetry.
+func RecordRetryBackoff(ctx context.Context, delay time.Duration) {
+	if state, _ := ctx.Value(retryStateKey{}).(*retryState); state != nil {
+		state.mu.Lock()
+		state.backoff = delay
+		state.mu.Unlock()
+	}
+}
+
+// StartRetryAttempt starts the span of the next attempt of the operation in
+// ctx. Without ContextWithRetry it behaves like tracer.Start.
+func StartRetryAttempt(ctx context.Context, tracer Tracer, name string, opts ...SpanOption) (context.Context, Span) {
+	state, _ := ctx.Value(retryStateKey{}).(*retryState)
+	if state == nil {
+		return tracer.Start(ctx, name, opts...)
+	}
+	return state.start(ctx, tracer, name, opts...)
+}
+
+// start starts an attempt span, remembering the first attempt so retries can
+// link to it. Work nested in the attempt is not part of the retried operation,
+// so the returned context no longer carries the retry state.
+func (s *retryState) start(ctx context.Context, tracer Tracer, name string, opts ...SpanOption) (context.Context, Span) {
+	s.mu.Lock()
+	s.attempts++
+	attempt, backoff, original := s.attempts, s.backoff, s.original
+	s.backoff = 0
+	s.mu.Unlock()
+
+	attrs := map[string]any{
+		RetryAttemptAttribute: attempt,
+		RetryMaxAttribute:     s.max,
+	}
+	opts = append(opts, WithAttributes(attrs))
+	if attempt > 1 {
+		attrs[RetryBackoffMsAttribute] = backoff.Milliseconds()
+		opts = append(opts, WithLinks(original))
+	}
+
+	ctx, span := tracer.Start(context.WithValue(ctx, retryStateKey{}, (*retryState)(nil)), name, opts...)
+	if attempt == 1 {
+		s.mu.Lock()
+		s.original = SpanContext{TraceID: span.TraceID(), SpanID: span.SpanID(), Sampled: span.IsSampled()}
+		s.mu.Unlock()
+	}
+	return ctx, span
+}
@@ -0,0 +1,90 @@
+package tracingx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestStartRetryAttempt(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("annotates attempts and links retries to the first attempt", func(t *testing.T) {
+		ctx := ContextWithRetry(context.Background(), 3)
+
+		_, first := StartRetryAttempt(ctx, provider, "call")
+		first.End()
+		RecordRetryBackoff(ctx, 200*time.Millisecond)
+		_, second := StartRetryAttempt(ctx, provider, "call")
+		second.End()
+		_, third := StartRetryAttempt(ctx, provider, "call")
+		third.End()
+
+		attrs := attributesOf(t, first)
+		assert.Equal(t, int64(1), attrs[RetryAttemptAttribute])
+		assert.Equal(t, int64(3), attrs[RetryMaxAttribute])
+		assert.NotContains(t, attrs, RetryBackoffMsAttribute)
+		assert.Empty(t, first.(*otlpSpan).span.(sdktrace.ReadOnlySpan).Links())
+
+		attrs = attributesOf(t, second)
+		assert.Equal(t, int64(2), attrs[RetryAttemptAttribute])
+		assert.Equal(t, int64(200), attrs[RetryBackoffMsAttribute])
+		links := second.(*otlpSpan).span.(sdktrace.ReadOnlySpan).Links()
+		require.Len(t, links, 1)
+		assert.Equal(t, first.SpanID(), links[0].SpanContext.SpanID().String())
+
+		attrs = attributesOf(t, third)
+		assert.Equal(t, int64(3), attrs[RetryAttemptAttribute])
+		assert.Equal(t, int64(0), attrs[RetryBackoffMsAttribute])
+		links = third.(*otlpSpan).span.(sdktrace.ReadOnlySpan).Links()
+		require.Len(t, links, 1)
+		assert.Equal(t, first.SpanID(), links[0].SpanContext.SpanID().String())
+	})
+
+	t.Run("nested work is not counted as an attempt", func(t *testing.T) {
+		ctx := ContextWithRetry(context.Background(), 3)
+		attemptCtx, attempt := StartRetryAttempt(ctx, provider, "call")
+		_, nested := StartRetryAttempt(attemptCtx, provider, "nested")
+		nested.End()
+		attempt.End()
+
+		assert.NotContains(t, attributesOf(t, nested), RetryAttemptAttribute)
+		assert.Equal(t, attempt.SpanID(), nested.ParentSpanID())
+	})
+
+	t.Run("behaves like Start without retry context", func(t *testing.T) {
+		RecordRetryBackoff(context.Background(), time.Second)
+		_, span := StartRetryAttempt(context.Background(), provider, "call")
+		span.End()
+		assert.NotContains(t, attributesOf(t, span), RetryAttemptAttribute)
+	})
+
+	t.Run("transport annotates retried requests", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		spy := &spyTracer{Tracer: provider}
+		client := &http.Client{Transport: NewTransport(spy, nil)}
+		ctx := ContextWithRetry(context.Background(), 2)
+		for i := 0; i < 2; i++ {
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+			require.NoError(t, err)
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+		}
+
+		spans := spy.started()
+		require.Len(t, spans, 2)
+		assert.Equal(t, int64(1), attributesOf(t, spans[0])[RetryAttemptAttribute])
+		assert.Equal(t, int64(2), attributesOf(t, spans[1])[RetryAttemptAttribute])
+	})
+}
@@ -0,0 +1,88 @@
+package tracingx
+
+import (
+	"context"
+	"path"
+	"strings"
+)
+
+// RouteSamplingHint overrides the head sampling decision for requests whose
+// path matches Pattern, a path.Match pattern where a trailing /* also
+// matches deeper paths (/assets/* matches /assets/css/site.css). Malformed
+// patterns match nothing.
+type RouteSamplingHint struct {
+	Pattern string
+	// Priority is the sampling priority given to matching requests: positive
+	// samples them, negative drops them
+	Priority int
+}
+
+// AlwaysSampleRoute samples every request matching pattern
+func AlwaysSampleRoute(pattern string) RouteSamplingHint {
+	return RouteSamplingHint{Pattern: pattern, Priority: 1}
+}
+
+// NeverSampleRoute drops every request matching pattern
+func NeverSampleRoute(pattern string) RouteSamplingHint {
+	return RouteSamplingHint{Pattern: pattern, Priority: -1}
+}
+
+// WithRouteSampling applies the first hint matching the request path. The
+// priority is set as SamplingPriorityAttribute on the server span and
+// applies to the spans started from the request context too, so the
+// request's whole local trace is kept or dropped; requests matching no hint
+// are left to the sampler.
+func WithRouteSampling(hints ...RouteSamplingHint) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.routeSampling = append(c.routeSampling, hints...)
+	}
+}
+
+// routePriority returns the priority of the first hint matching urlPath, 0
+// when none matches
+func (c middlewareConfig) routePriority(urlPath string) int {
+	for _, hint := range c.routeSampling {
+		if matchRoute(hint.Pattern, urlPath) {
+			return hint.Priority
+		}
+	}
+	return 0
+}
+
+// matchRoute reports whether urlPath matches pattern
+func matchRoute(pattern, urlPath string) bool {
+	if matched, _ := path.Match(pattern, urlPath); matched {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(pattern, "/*"); ok {
+		// Deeper paths match when one of their leading segments matches prefix
+		for i := 1; i < len(urlPath); i++ {
+			if urlPath[i] != '/' {
+				continue
+			}
+			if matched, _ := path.Match(prefix, urlPath[:i]); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// samplingPriorityKey carries the sampling priority applied to spans started
+// from a context
+type samplingPriorityKey struct{}
+
+// contextWithSamplingPriority returns a context whose spans are sampled
+// (priority > 0) or dropped (priority < 0) whatever the sample rate
+func contextWithSamplingPriority(ctx context.Context, priority int) context.Context {
+	return context.WithValue(ctx, samplingPriorityKey{}, priority)
+}
+
+// samplingPriorityFromContext returns the sampling priority of ctx, 0 if unset
+func samplingPriorityFromContext(ctx context.Context) int {
+	if ctx == nil {
+		return 0
+	}
+	priority, _ := ctx.Value(samplingPriorityKey{}).(int)
+	return priority
+}
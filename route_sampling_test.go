@@ -0,0 +1,77 @@
+package tracingx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchRoute(t *testing.T) {
+	for _, tc := range []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"/checkout", "/checkout", true},
+		{"/checkout", "/checkout/confirm", false},
+		{"/assets/*", "/assets/site.css", true},
+		{"/assets/*", "/assets/css/site.css", true},
+		{"/assets/*", "/assets", false},
+		{"/assets/*", "/assetsx/site.css", false},
+		{"/api/*/orders", "/api/v1/orders", true},
+		{"/*/static/*", "/shop/static/img/logo.png", true},
+		{"/[", "/[", false},
+	} {
+		assert.Equal(t, tc.want, matchRoute(tc.pattern, tc.path), "%s ~ %s", tc.pattern, tc.path)
+	}
+}
+
+func TestMiddlewareRouteSampling(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	var serverSampled, childSampled bool
+	handler := NewMiddleware(provider, WithRouteSampling(
+		NeverSampleRoute("/checkout/health"),
+		AlwaysSampleRoute("/checkout/*"),
+		AlwaysSampleRoute("/checkout"),
+	))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serverSampled = SpanFromContext(r.Context()).IsSampled()
+		_, child := provider.Start(r.Context(), "db.query")
+		defer child.End()
+		childSampled = child.IsSampled()
+	}))
+
+	for _, tc := range []struct {
+		path    string
+		sampled bool
+	}{
+		{path: "/checkout", sampled: true},
+		{path: "/checkout/payment/confirm", sampled: true},
+		{path: "/checkout/health"},
+		{path: "/cart"},
+	} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tc.path, nil))
+		assert.Equal(t, tc.sampled, serverSampled, tc.path)
+		assert.Equal(t, tc.sampled, childSampled, "children follow the route hint: %s", tc.path)
+	}
+
+	t.Run("never overrides the sample rate", func(t *testing.T) {
+		always, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+		require.NoError(t, err)
+		defer always.Shutdown(context.Background())
+
+		var span Span
+		handler := NewMiddleware(always, WithRouteSampling(NeverSampleRoute("/assets/*")))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			span = SpanFromContext(r.Context())
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/assets/js/app.js", nil))
+		assert.False(t, span.IsSampled())
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.True(t, span.IsSampled())
+	})
+}
@@ -0,0 +1,83 @@
+package tracingx
+
+import (
+	"context"
+	"html/template"
+	"net/http"
+	"strings"
+)
+
+// This file links frontend real user monitoring (RUM) sessions to backend
+// traces: browsers may send traceparent on fetch requests, and server-rendered
+// pages can embed the trace context for the RUM agent to pick up.
+
+// browserTraceHeaders are the request headers browsers need permission to send
+const browserTraceHeaders = "traceparent, tracestate"
+
+// WithBrowserOrigins allows browser fetch requests from the given origins
+// ("*" for any) to participate in traces. For matching origins, CORS preflight
+// responses permit the traceparent and tracestate request headers, and
+// responses expose traceresponse and Server-Timing to frontend code.
+func WithBrowserOrigins(origins ...string) MiddlewareOption {
+	return func(c *middlewareConfig) {
+		c.browserOrigins = append(c.browserOrigins, origins...)
+	}
+}
+
+// allowsBrowserOrigin reports whether origin is configured for browser tracing
+func (c middlewareConfig) allowsBrowserOrigin(origin string) bool {
+	if origin == "" {
+		return false
+	}
+	for _, allowed := range c.browserOrigins {
+		if allowed == "*" || strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeBrowserHeaders adds the CORS and timing headers browsers need to send
+// and read trace context
+func (c middlewareConfig) writeBrowserHeaders(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if !c.allowsBrowserOrigin(origin) {
+		return
+	}
+	header := w.Header()
+	if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+		header.Add("Access-Control-Allow-Headers", browserTraceHeaders)
+	}
+	header.Add("Access-Control-Expose-Headers", TraceResponseHeader+", "+ServerTimingHeader)
+	header.Set("Timing-Allow-Origin", origin)
+}
+
+// TraceParentMeta renders the trace context of ctx as an HTML meta tag,
+// `<meta name="traceparent" content="...">`, for RUM agents to continue the
+// trace in the browser. It renders nothing if ctx has no span.
+func TraceParentMeta(ctx context.Context) template.HTML {
+	traceParent := traceParentFromContext(ctx)
+	if traceParent == "" {
+		return ""
+	}
+	return template.HTML(`<meta name="traceparent" content="` + template.HTMLEscapeString(traceParent) + `">`)
+}
+
+// TemplateFuncs returns html/template functions rendering the trace context:
+// traceID and traceparent return strings, traceparentMeta returns the meta
+// tag from TraceParentMeta. Each takes the request context.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"traceID":         TraceIDFromContext,
+		"traceparent":     traceParentFromContext,
+		"traceparentMeta": TraceParentMeta,
+	}
+}
+
+// traceParentFromContext formats the span in ctx as a traceparent value
+func traceParentFromContext(ctx context.Context) string {
+	if span := SpanFromContext(ctx); span != nil {
+		return FormatTraceParent(span)
+	}
+	return ""
+}
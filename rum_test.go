@@ -0,0 +1,91 @@
+package tracingx
+
+import (
+	"bytes"
+	"context"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrowserOrigins(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	handler := NewMiddleware(provider, WithTraceResponse(), WithBrowserOrigins("https://app.example.com"))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}),
+	)
+
+	t.Run("allows trace headers on preflight", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodOptions, "/api", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, browserTraceHeaders, rec.Header().Get("Access-Control-Allow-Headers"))
+	})
+
+	t.Run("exposes trace headers to allowed origin", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api", nil)
+		req.Header.Set("Origin", "https://app.example.com")
+		req.Header.Set("traceparent", validTraceParent)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Empty(t, rec.Header().Get("Access-Control-Allow-Headers"))
+		assert.Contains(t, rec.Header().Get("Access-Control-Expose-Headers"), TraceResponseHeader)
+		assert.Equal(t, "https://app.example.com", rec.Header().Get("Timing-Allow-Origin"))
+		assert.Contains(t, rec.Header().Get(TraceResponseHeader), "4bf92f3577b34da6a3ce929d0e0e4736")
+	})
+
+	t.Run("ignores other origins", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api", nil)
+		req.Header.Set("Origin", "https://evil.example.com")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Empty(t, rec.Header().Get("Access-Control-Expose-Headers"))
+		assert.Empty(t, rec.Header().Get("Timing-Allow-Origin"))
+	})
+
+	t.Run("wildcard allows any origin", func(t *testing.T) {
+		config := middlewareConfig{browserOrigins: []string{"*"}}
+		assert.True(t, config.allowsBrowserOrigin("https://any.example.com"))
+		assert.False(t, config.allowsBrowserOrigin(""))
+	})
+}
+
+func TestTraceParentMeta(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	ctx, span := provider.Start(context.Background(), "render")
+	defer span.End()
+
+	t.Run("renders meta tag", func(t *testing.T) {
+		assert.Equal(t, template.HTML(`<meta name="traceparent" content="`+FormatTraceParent(span)+`">`), TraceParentMeta(ctx))
+	})
+
+	t.Run("renders nothing without span", func(t *testing.T) {
+		assert.Empty(t, TraceParentMeta(context.Background()))
+	})
+
+	t.Run("template functions", func(t *testing.T) {
+		tmpl := template.Must(template.New("page").Funcs(TemplateFuncs()).Parse(
+			`<head>{{traceparentMeta .}}</head><body data-trace-id="{{traceID .}}" data-traceparent="{{traceparent .}}"></body>`,
+		))
+		var buf bytes.Buffer
+		require.NoError(t, tmpl.Execute(&buf, ctx))
+
+		assert.Contains(t, buf.String(), `<head><meta name="traceparent" content="`+FormatTraceParent(span)+`"></head>`)
+		assert.Contains(t, buf.String(), `data-trace-id="`+span.TraceID()+`"`)
+		assert.Contains(t, buf.String(), `data-traceparent="`+FormatTraceParent(span)+`"`)
+	})
+}
@@ -0,0 +1,161 @@
+package tracingx
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SamplingPriorityAttribute forces a span to be sampled, whatever the sample
+// rate, when set to a positive value as the span starts, and to be dropped
+// when set to a negative one
+const SamplingPriorityAttribute = "sampling.priority"
+
+// WithForceSample samples the span whatever the sample rate, for operations
+// that must always be traced, such as calls to a payment provider. The span
+// is propagated as sampled, so downstream services record it too.
+func WithForceSample() SpanOption {
+	return func(c *SpanConfig) {
+		if c.Attributes == nil {
+			c.Attributes = make(map[string]any)
+		}
+		c.Attributes[SamplingPriorityAttribute] = 1
+	}
+}
+
+// newSampler builds the sampler from configuration around the head sampler base
+func newSampler(config Config, base sdktrace.Sampler) sdktrace.Sampler {
+	sampler := sdktrace.Sampler(&prioritySampler{base: base})
+	if len(config.Pipelines) > 0 {
+		// Pipelines apply their own sampling to every recorded span
+		return newRecordAllSampler(sampler)
+	}
+	if config.Audit.Enabled && len(config.Audit.Operations) > 0 {
+		sampler = newRecordOperationsSampler(sampler, config.Audit.Operations)
+	}
+	return sampler
+}
+
+// recordingSampler guarantees that matching spans are recorded (so
+// processors see them) even when the base sampler drops them. Dropped spans
+// are upgraded to RecordOnly and are therefore not exported by the main
+// pipeline, and downstream services still see them as unsampled.
+type recordingSampler struct {
+	base   sdktrace.Sampler
+	record func(p sdktrace.SamplingParameters) bool
+	name   string
+}
+
+// newRecordOperationsSampler wraps base so the named operations are always recorded
+func newRecordOperationsSampler(base sdktrace.Sampler, operations []string) sdktrace.Sampler {
+	ops := make(map[string]struct{}, len(operations))
+	for _, op := range operations {
+		ops[op] = struct{}{}
+	}
+	return &recordingSampler{
+		base: base,
+		record: func(p sdktrace.SamplingParameters) bool {
+			_, ok := ops[p.Name]
+			return ok
+		},
+		name: "RecordOperations",
+	}
+}
+
+// newRecordAllSampler wraps base so every span is recorded
+func newRecordAllSampler(base sdktrace.Sampler) sdktrace.Sampler {
+	return &recordingSampler{
+		base:   base,
+		record: func(sdktrace.SamplingParameters) bool { return true },
+		name:   "RecordAll",
+	}
+}
+
+func (s *recordingSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	result := s.base.ShouldSample(p)
+	if result.Decision == sdktrace.Drop && s.record(p) {
+		result.Decision = sdktrace.RecordOnly
+	}
+	return result
+}
+
+func (s *recordingSampler) Description() string {
+	return fmt.Sprintf("%s{%s}", s.name, s.base.Description())
+}
+
+// prioritySampler samples spans started with a positive
+// SamplingPriorityAttribute, or from a context with a positive sampling
+// priority, drops those with a negative one and defers to base for the others
+type prioritySampler struct {
+	base sdktrace.Sampler
+}
+
+func (s *prioritySampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	priority := int64(samplingPriorityFromContext(p.ParentContext))
+	for _, attr := range p.Attributes {
+		if attr.Key == SamplingPriorityAttribute {
+			priority = attr.Value.AsInt64()
+		}
+	}
+	var decision sdktrace.SamplingDecision
+	switch {
+	case priority > 0:
+		decision = sdktrace.RecordAndSample
+	case priority < 0:
+		decision = sdktrace.Drop
+	default:
+		return s.base.ShouldSample(p)
+	}
+	return sdktrace.SamplingResult{
+		Decision:   decision,
+		Tracestate: trace.SpanContextFromContext(p.ParentContext).TraceState(),
+	}
+}
+
+func (s *prioritySampler) Description() string {
+	return fmt.Sprintf("Priority{%s}", s.base.Description())
+}
+
+// ratioSampler is a trace ID ratio sampler whose rate can be changed at runtime
+type ratioSampler struct {
+	rateBits atomic.Uint64
+	sampler  atomic.Pointer[sdktrace.Sampler]
+}
+
+// newRatioSampler creates a ratio sampler with the given initial rate
+func newRatioSampler(rate float64) *ratioSampler {
+	s := &ratioSampler{}
+	s.store(rate)
+	return s
+}
+
+// setRate changes the sampling rate for traces started from now on
+func (s *ratioSampler) setRate(rate float64) error {
+	if math.IsNaN(rate) || rate < 0 || rate > 1 {
+		return fmt.Errorf("invalid sample rate %v: must be between 0 and 1", rate)
+	}
+	s.store(rate)
+	return nil
+}
+
+func (s *ratioSampler) store(rate float64) {
+	sampler := sdktrace.TraceIDRatioBased(rate)
+	s.sampler.Store(&sampler)
+	s.rateBits.Store(math.Float64bits(rate))
+}
+
+// rate returns the current sampling rate
+func (s *ratioSampler) rate() float64 {
+	return math.Float64frombits(s.rateBits.Load())
+}
+
+func (s *ratioSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return (*s.sampler.Load()).ShouldSample(p)
+}
+
+func (s *ratioSampler) Description() string {
+	return (*s.sampler.Load()).Description()
+}
@@ -0,0 +1,122 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestRecordOperationsSampler(t *testing.T) {
+	sampler := newRecordOperationsSampler(sdktrace.NeverSample(), []string{"user.delete"})
+
+	t.Run("records listed operations", func(t *testing.T) {
+		result := sampler.ShouldSample(sdktrace.SamplingParameters{Name: "user.delete"})
+		assert.Equal(t, sdktrace.RecordOnly, result.Decision)
+	})
+
+	t.Run("defers to the base sampler otherwise", func(t *testing.T) {
+		result := sampler.ShouldSample(sdktrace.SamplingParameters{Name: "user.get"})
+		assert.Equal(t, sdktrace.Drop, result.Decision)
+	})
+
+	t.Run("keeps sampled decisions", func(t *testing.T) {
+		always := newRecordOperationsSampler(sdktrace.AlwaysSample(), []string{"user.delete"})
+		result := always.ShouldSample(sdktrace.SamplingParameters{Name: "user.delete"})
+		assert.Equal(t, sdktrace.RecordAndSample, result.Decision)
+	})
+
+	t.Run("describes the base sampler", func(t *testing.T) {
+		assert.Contains(t, sampler.Description(), "AlwaysOffSampler")
+	})
+}
+
+func TestPrioritySampler(t *testing.T) {
+	sampler := &prioritySampler{base: sdktrace.NeverSample()}
+
+	t.Run("samples prioritized spans", func(t *testing.T) {
+		result := sampler.ShouldSample(sdktrace.SamplingParameters{
+			Name:       "charge",
+			Attributes: []attribute.KeyValue{attribute.Int(SamplingPriorityAttribute, 1)},
+		})
+		assert.Equal(t, sdktrace.RecordAndSample, result.Decision)
+	})
+
+	t.Run("defers to the base sampler otherwise", func(t *testing.T) {
+		result := sampler.ShouldSample(sdktrace.SamplingParameters{
+			Name:       "charge",
+			Attributes: []attribute.KeyValue{attribute.Int(SamplingPriorityAttribute, 0)},
+		})
+		assert.Equal(t, sdktrace.Drop, result.Decision)
+	})
+
+	t.Run("drops deprioritized spans", func(t *testing.T) {
+		always := &prioritySampler{base: sdktrace.AlwaysSample()}
+		result := always.ShouldSample(sdktrace.SamplingParameters{
+			Name:       "asset",
+			Attributes: []attribute.KeyValue{attribute.Int(SamplingPriorityAttribute, -1)},
+		})
+		assert.Equal(t, sdktrace.Drop, result.Decision)
+	})
+
+	t.Run("applies the context priority", func(t *testing.T) {
+		result := sampler.ShouldSample(sdktrace.SamplingParameters{
+			ParentContext: contextWithSamplingPriority(context.Background(), 1),
+			Name:          "charge",
+		})
+		assert.Equal(t, sdktrace.RecordAndSample, result.Decision)
+	})
+
+	t.Run("forces sampling under a zero sample rate", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 0}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		_, dropped := provider.Start(context.Background(), "regular")
+		defer dropped.End()
+		assert.False(t, dropped.IsSampled())
+
+		_, forced := provider.Start(context.Background(), "charge", WithForceSample())
+		defer forced.End()
+		assert.True(t, forced.IsSampled())
+	})
+}
+
+func TestNewSampler(t *testing.T) {
+	t.Run("uses ratio sampling by default", func(t *testing.T) {
+		sampler := newSampler(Config{SampleRate: 0.5}, newRatioSampler(0.5))
+		assert.Contains(t, sampler.Description(), "TraceIDRatioBased")
+	})
+
+	t.Run("wraps the sampler for audited operations", func(t *testing.T) {
+		sampler := newSampler(Config{SampleRate: 0.5, Audit: AuditConfig{Enabled: true, Operations: []string{"op"}}}, newRatioSampler(0.5))
+		assert.Contains(t, sampler.Description(), "RecordOperations")
+	})
+}
+
+func TestRecordAllSampler(t *testing.T) {
+	sampler := newRecordAllSampler(sdktrace.NeverSample())
+	result := sampler.ShouldSample(sdktrace.SamplingParameters{Name: "anything"})
+	assert.Equal(t, sdktrace.RecordOnly, result.Decision)
+	assert.Contains(t, sampler.Description(), "RecordAll")
+
+	assert.Contains(t, newSampler(Config{Pipelines: []PipelineConfig{{Name: "errors"}}}, newRatioSampler(0)).Description(), "RecordAll")
+}
+
+func TestRatioSampler(t *testing.T) {
+	sampler := newRatioSampler(0)
+	params := sdktrace.SamplingParameters{Name: "op"}
+	assert.Equal(t, sdktrace.Drop, sampler.ShouldSample(params).Decision)
+
+	assert.NoError(t, sampler.setRate(1))
+	assert.Equal(t, 1.0, sampler.rate())
+	assert.Equal(t, sdktrace.RecordAndSample, sampler.ShouldSample(params).Decision)
+	assert.Contains(t, sampler.Description(), "AlwaysOnSampler")
+
+	assert.Error(t, sampler.setRate(1.5))
+	assert.Error(t, sampler.setRate(-0.1))
+	assert.Equal(t, 1.0, sampler.rate())
+}
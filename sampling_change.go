@@ -0,0 +1,72 @@
+package tracingx
+
+import (
+	"github.com/gostratum/core/logx"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ConfigSpanName is the name of the synthetic spans recording sampling
+// changes. They share one trace per provider, so the trace is the audit
+// trail of the provider's sampling changes.
+const ConfigSpanName = "tracing.config"
+
+// SamplingChangedEvent is the event of a ConfigSpanName span describing a
+// sampling change
+const SamplingChangedEvent = "sampling.changed"
+
+// Attributes of SamplingChangedEvent
+const (
+	SamplingChangeSourceAttribute    = "sampling.change.source"
+	SamplingPreviousRateAttribute    = "sampling.previous.rate"
+	SamplingRateAttribute            = "sampling.rate"
+	SamplingPreviousSamplerAttribute = "sampling.previous.sampler"
+	SamplingSamplerAttribute         = "sampling.sampler"
+)
+
+// Sources of sampling changes
+const (
+	samplingSourceSetSampleRate = "set_sample_rate"
+	samplingSourceReconfigure   = "reconfigure"
+)
+
+// samplingState is the sampling configuration of a pipeline
+type samplingState struct {
+	rate    float64
+	sampler string
+}
+
+// samplingState returns the pipeline's current sampling configuration
+func (p *otlpPipeline) samplingState() samplingState {
+	return samplingState{rate: p.ratio.rate(), sampler: p.sampler.Description()}
+}
+
+// recordSamplingChange logs a change of the sampling configuration and
+// exports it as an event on a synthetic span of the provider's config trace,
+// bypassing sampling. Unchanged configurations are not recorded.
+func (p *otlpProvider) recordSamplingChange(source string, previous, current samplingState) {
+	if previous == current {
+		return
+	}
+	p.logger.Info("tracing sampling changed",
+		logx.String("source", source),
+		logx.Any("previous_rate", previous.rate),
+		logx.Any("rate", current.rate),
+		logx.String("previous_sampler", previous.sampler),
+		logx.String("sampler", current.sampler),
+	)
+
+	stub := p.syntheticStub(p.configTraceID, ConfigSpanName)
+	stub.Events = []sdktrace.Event{{
+		Name: SamplingChangedEvent,
+		Time: stub.StartTime,
+		Attributes: []attribute.KeyValue{
+			attribute.String(SamplingChangeSourceAttribute, source),
+			attribute.Float64(SamplingPreviousRateAttribute, previous.rate),
+			attribute.Float64(SamplingRateAttribute, current.rate),
+			attribute.String(SamplingPreviousSamplerAttribute, previous.sampler),
+			attribute.String(SamplingSamplerAttribute, current.sampler),
+		},
+	}}
+	p.current().batcher.OnEnd(stub.Snapshot())
+}
@@ -0,0 +1,59 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gostratum/core/logx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSamplingChangeAudit(t *testing.T) {
+	collector := newFakeCollector(t)
+	core, logs := observer.New(zap.DebugLevel)
+	config := Config{
+		ServiceName: "test-service",
+		SampleRate:  1.0,
+		OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+	}
+	provider, err := newOTLPProvider(config, logx.ProvideAdapter(zap.New(core)))
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	require.NoError(t, provider.SetSampleRate(0.5))
+	require.NoError(t, provider.SetSampleRate(0.5))
+	assert.Error(t, provider.SetSampleRate(2))
+
+	config.SampleRate = 0.25
+	require.NoError(t, provider.Reconfigure(config))
+	require.NoError(t, provider.Reconfigure(config))
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	entries := logs.FilterMessage("tracing sampling changed").All()
+	require.Len(t, entries, 2, "unchanged and rejected rates are not recorded")
+	assert.Equal(t, "set_sample_rate", entries[0].ContextMap()["source"])
+	assert.Equal(t, 1.0, entries[0].ContextMap()["previous_rate"])
+	assert.Equal(t, 0.5, entries[0].ContextMap()["rate"])
+	assert.Equal(t, "reconfigure", entries[1].ContextMap()["source"])
+	assert.Equal(t, 0.25, entries[1].ContextMap()["rate"])
+
+	spans := collector.received()
+	require.Len(t, spans, 2)
+	assert.Equal(t, spans[0].TraceId, spans[1].TraceId, "changes share the config trace")
+	for i, source := range []string{"set_sample_rate", "reconfigure"} {
+		assert.Equal(t, ConfigSpanName, spans[i].Name)
+		require.Len(t, spans[i].Events, 1)
+		event := spans[i].Events[0]
+		assert.Equal(t, SamplingChangedEvent, event.Name)
+
+		attrs := map[string]string{}
+		for _, kv := range event.Attributes {
+			attrs[kv.Key] = kv.Value.GetStringValue()
+		}
+		assert.Equal(t, source, attrs[SamplingChangeSourceAttribute])
+		assert.NotEqual(t, attrs[SamplingPreviousSamplerAttribute], attrs[SamplingSamplerAttribute])
+	}
+}
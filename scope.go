@@ -0,0 +1,102 @@
+package tracingx
+
+import (
+	"context"
+	"sync"
+)
+
+// Scope is an activation of a span as the current span of a scope holder,
+// for frameworks that keep one context per request and cannot thread the
+// span's context through third-party callbacks. Close deactivates it.
+type Scope struct {
+	span   Span
+	holder *scopeHolder
+}
+
+// scopeHolder tracks the open scopes of a request, innermost last
+type scopeHolder struct {
+	mu     sync.Mutex
+	scopes []*Scope
+}
+
+type scopeHolderKey struct{}
+
+// ContextWithScopes returns ctx with a scope holder that Activate records
+// the current span in; frameworks call it once on their request context
+func ContextWithScopes(ctx context.Context) context.Context {
+	if scopeHolderFromContext(ctx) != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, scopeHolderKey{}, &scopeHolder{})
+}
+
+// scopeHolderFromContext returns the scope holder of ctx, or nil
+func scopeHolderFromContext(ctx context.Context) *scopeHolder {
+	holder, _ := ctx.Value(scopeHolderKey{}).(*scopeHolder)
+	return holder
+}
+
+// Activate makes span the current span of the scope holder in ctx until the
+// returned scope is closed. Without a holder (see ContextWithScopes) the
+// scope only carries the span.
+//
+//	scope := tracingx.Activate(ctx, span)
+//	defer scope.Close()
+func Activate(ctx context.Context, span Span) *Scope {
+	scope := &Scope{span: span, holder: scopeHolderFromContext(ctx)}
+	if scope.holder != nil {
+		scope.holder.mu.Lock()
+		scope.holder.scopes = append(scope.holder.scopes, scope)
+		scope.holder.mu.Unlock()
+	}
+	return scope
+}
+
+// Span returns the activated span
+func (s *Scope) Span() Span {
+	return s.span
+}
+
+// Context returns the context of the activated span, with the span attached
+func (s *Scope) Context() context.Context {
+	return ContextWithSpan(s.span.Context(), s.span)
+}
+
+// Close deactivates the scope, making the span active before it current
+// again. Scopes may be closed in any order and from any goroutine; closing
+// a scope more than once is a no-op.
+func (s *Scope) Close() {
+	if s.holder == nil {
+		return
+	}
+	s.holder.mu.Lock()
+	defer s.holder.mu.Unlock()
+	for i := len(s.holder.scopes) - 1; i >= 0; i-- {
+		if s.holder.scopes[i] == s {
+			s.holder.scopes = append(s.holder.scopes[:i], s.holder.scopes[i+1:]...)
+			return
+		}
+	}
+}
+
+// ActiveSpan returns the span of the innermost open scope in ctx, falling
+// back to the span attached to ctx itself
+func ActiveSpan(ctx context.Context) Span {
+	if holder := scopeHolderFromContext(ctx); holder != nil {
+		holder.mu.Lock()
+		defer holder.mu.Unlock()
+		if n := len(holder.scopes); n > 0 {
+			return holder.scopes[n-1].span
+		}
+	}
+	return SpanFromContext(ctx)
+}
+
+// ActiveContext returns the context of ActiveSpan, so spans started from it
+// are children of the active span, or ctx when no span is active
+func ActiveContext(ctx context.Context) context.Context {
+	if span := ActiveSpan(ctx); span != nil {
+		return ContextWithSpan(span.Context(), span)
+	}
+	return ctx
+}
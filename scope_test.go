@@ -0,0 +1,85 @@
+package tracingx
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScope(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("activates spans on the request context", func(t *testing.T) {
+		reqCtx := ContextWithScopes(context.Background())
+		assert.Nil(t, ActiveSpan(reqCtx))
+
+		_, outer := provider.Start(reqCtx, "outer")
+		defer outer.End()
+		outerScope := Activate(reqCtx, outer)
+		assert.Equal(t, outer, ActiveSpan(reqCtx))
+
+		// A callback that only has the request context parents to the active span
+		_, inner := provider.Start(ActiveContext(reqCtx), "inner")
+		defer inner.End()
+		assert.Equal(t, outer.SpanID(), inner.ParentSpanID())
+		assert.Equal(t, outer, SpanFromContext(ActiveContext(reqCtx)))
+
+		innerScope := Activate(reqCtx, inner)
+		assert.Equal(t, inner, ActiveSpan(reqCtx))
+
+		innerScope.Close()
+		innerScope.Close()
+		assert.Equal(t, outer, ActiveSpan(reqCtx))
+		outerScope.Close()
+		assert.Nil(t, ActiveSpan(reqCtx))
+	})
+
+	t.Run("scopes close in any order", func(t *testing.T) {
+		reqCtx := ContextWithScopes(context.Background())
+		_, first := provider.Start(reqCtx, "first")
+		defer first.End()
+		_, second := provider.Start(reqCtx, "second")
+		defer second.End()
+
+		firstScope := Activate(reqCtx, first)
+		secondScope := Activate(reqCtx, second)
+		firstScope.Close()
+		assert.Equal(t, second, ActiveSpan(reqCtx))
+		secondScope.Close()
+		assert.Nil(t, ActiveSpan(reqCtx))
+	})
+
+	t.Run("concurrent activations", func(t *testing.T) {
+		reqCtx := ContextWithScopes(context.Background())
+		var wg sync.WaitGroup
+		for i := 0; i < 16; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, span := provider.Start(reqCtx, "worker")
+				defer span.End()
+				scope := Activate(reqCtx, span)
+				assert.NotNil(t, ActiveSpan(reqCtx))
+				scope.Close()
+			}()
+		}
+		wg.Wait()
+		assert.Nil(t, ActiveSpan(reqCtx))
+	})
+
+	t.Run("without a holder falls back to the context", func(t *testing.T) {
+		ctx, span := provider.Start(context.Background(), "plain")
+		defer span.End()
+		scope := Activate(context.Background(), span)
+		defer scope.Close()
+		assert.Equal(t, span, scope.Span())
+		assert.Equal(t, span, SpanFromContext(scope.Context()))
+		assert.Nil(t, ActiveSpan(context.Background()))
+		assert.Equal(t, span, ActiveSpan(ctx))
+	})
+}
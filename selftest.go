@@ -0,0 +1,115 @@
+package tracingx
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/instrumentation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SelfTestSpanName is the name of the synthetic span exported by SelfTest
+const SelfTestSpanName = "tracingx.self_test"
+
+// SyntheticAttribute marks spans generated by tracingx itself rather than by
+// application code
+const SyntheticAttribute = "tracingx.synthetic"
+
+// SelfTestConfig configures the exporter connectivity probe run at startup
+type SelfTestConfig struct {
+	// Enabled runs Provider.SelfTest when the application starts
+	Enabled bool `mapstructure:"enabled" default:"false"`
+
+	// Required fails application startup when the self-test fails; otherwise
+	// the failure is logged as a warning
+	Required bool `mapstructure:"required" default:"false"`
+
+	// Timeout bounds the self-test round-trip
+	Timeout time.Duration `mapstructure:"timeout" default:"5s"`
+}
+
+// SelfTest exports a synthetic span directly to the exporter, bypassing
+// sampling and batching, and returns the exporter's error, so a misconfigured
+// endpoint is caught at deploy time instead of discovered as missing traces
+func (p *otlpProvider) SelfTest(ctx context.Context) error {
+	if p.closed.Load() {
+		return ErrProviderShutdown
+	}
+	pipeline := p.current()
+	span := p.syntheticSpan(SelfTestSpanName)
+	// Counted like a queued span so Diagnostics reflects the probe's outcome
+	pipeline.stats.enqueued.Add(1)
+	if err := pipeline.exporter.ExportSpans(ctx, []sdktrace.ReadOnlySpan{span}); err != nil {
+		return fmt.Errorf("tracing self-test failed: %w", err)
+	}
+	return nil
+}
+
+// syntheticSpan builds an ended, sampled root span attributed to the
+// provider's resource and instrumentation scope, marked as synthetic
+func (p *otlpProvider) syntheticSpan(name string, attrs ...attribute.KeyValue) sdktrace.ReadOnlySpan {
+	return p.syntheticStub(newTraceID(), name, attrs...).Snapshot()
+}
+
+// syntheticStub builds the stub of a synthetic root span in the given trace
+func (p *otlpProvider) syntheticStub(traceID trace.TraceID, name string, attrs ...attribute.KeyValue) tracetest.SpanStub {
+	var spanID trace.SpanID
+	_, _ = rand.Read(spanID[:])
+
+	pipeline := p.current()
+	now := p.clock.Now()
+	return tracetest.SpanStub{
+		Name: name,
+		SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: trace.FlagsSampled,
+		}),
+		SpanKind:   trace.SpanKindInternal,
+		StartTime:  now,
+		EndTime:    now,
+		Attributes: append([]attribute.KeyValue{attribute.Bool(SyntheticAttribute, true)}, attrs...),
+		Resource:   pipeline.resource,
+		InstrumentationScope: instrumentation.Scope{
+			Name:      pipeline.config.Instrumentation.name(),
+			Version:   pipeline.config.Instrumentation.version(),
+			SchemaURL: pipeline.config.schemaURL(),
+		},
+	}
+}
+
+// newTraceID returns a random trace ID
+func newTraceID() trace.TraceID {
+	var traceID trace.TraceID
+	_, _ = rand.Read(traceID[:])
+	return traceID
+}
+
+// runSelfTest runs the configured startup self-test
+func runSelfTest(ctx context.Context, config SelfTestConfig, provider Provider, logger logx.Logger) error {
+	if !config.Enabled {
+		return nil
+	}
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+
+	err := provider.SelfTest(ctx)
+	if err == nil {
+		logger.Info("tracing self-test succeeded")
+		return nil
+	}
+	if config.Required {
+		return err
+	}
+	logger.Warn("tracing self-test failed", logx.Err(err))
+	return nil
+}
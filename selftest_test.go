@@ -0,0 +1,130 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+)
+
+// fakeCollector is an in-process OTLP gRPC trace receiver
+type fakeCollector struct {
+	coltracepb.UnimplementedTraceServiceServer
+	endpoint string
+
+	mu    sync.Mutex
+	spans []*tracepb.Span
+}
+
+func newFakeCollector(t testing.TB) *fakeCollector {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	c := &fakeCollector{endpoint: lis.Addr().String()}
+	server := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(server, c)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+	return c
+}
+
+func (c *fakeCollector) Export(_ context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			c.spans = append(c.spans, ss.Spans...)
+		}
+	}
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func (c *fakeCollector) received() []*tracepb.Span {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*tracepb.Span(nil), c.spans...)
+}
+
+func TestSelfTest(t *testing.T) {
+	t.Run("exports synthetic span", func(t *testing.T) {
+		collector := newFakeCollector(t)
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			SampleRate:  0,
+			OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+		}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		require.NoError(t, provider.SelfTest(context.Background()))
+
+		spans := collector.received()
+		require.Len(t, spans, 1)
+		assert.Equal(t, SelfTestSpanName, spans[0].Name)
+		require.Len(t, spans[0].Attributes, 1)
+		assert.Equal(t, SyntheticAttribute, spans[0].Attributes[0].Key)
+	})
+
+	t.Run("reports unreachable exporter", func(t *testing.T) {
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		endpoint := lis.Addr().String()
+		require.NoError(t, lis.Close())
+
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			OTLP:        OTLPConfig{Endpoint: endpoint, Insecure: true},
+		}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+		err = provider.SelfTest(ctx)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "tracing self-test failed")
+	})
+
+	t.Run("noop provider always passes", func(t *testing.T) {
+		assert.NoError(t, newNoopProvider().SelfTest(context.Background()))
+	})
+}
+
+// failingProvider is a Provider whose self-test always fails and which never
+// becomes ready
+type failingProvider struct {
+	Provider
+}
+
+func (failingProvider) SelfTest(context.Context) error { return errors.New("unreachable") }
+func (failingProvider) Ready() <-chan struct{}         { return nil }
+
+func TestRunSelfTest(t *testing.T) {
+	logger := logx.NewNoopLogger()
+	provider := failingProvider{Provider: newNoopProvider()}
+
+	t.Run("skipped when disabled", func(t *testing.T) {
+		assert.NoError(t, runSelfTest(context.Background(), SelfTestConfig{}, provider, logger))
+	})
+
+	t.Run("logs failure when not required", func(t *testing.T) {
+		config := SelfTestConfig{Enabled: true, Timeout: time.Second}
+		assert.NoError(t, runSelfTest(context.Background(), config, provider, logger))
+	})
+
+	t.Run("fails startup when required", func(t *testing.T) {
+		config := SelfTestConfig{Enabled: true, Required: true, Timeout: time.Second}
+		assert.Error(t, runSelfTest(context.Background(), config, provider, logger))
+	})
+}
@@ -0,0 +1,110 @@
+package tracingx
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+)
+
+// This file is the compatibility layer between tracingx and the OpenTelemetry
+// semantic conventions. All semconv usage goes through the helpers below so
+// that moving to a newer semconv version only touches this file.
+
+// DefaultSchemaURL is the schema URL of the semantic conventions emitted by tracingx
+const DefaultSchemaURL = semconv.SchemaURL
+
+// schemaURL returns the schema URL to attach to resources and tracers
+func (c Config) schemaURL() string {
+	if c.SchemaURL != "" {
+		return c.SchemaURL
+	}
+	return DefaultSchemaURL
+}
+
+// serviceNameAttribute returns the resource attribute identifying the service
+func serviceNameAttribute(name string) attribute.KeyValue {
+	return semconv.ServiceName(name)
+}
+
+// HTTP attribute keys recorded by the HTTP instrumentation
+const (
+	httpRequestMethodKey      = string(semconv.HTTPRequestMethodKey)
+	httpResponseStatusCodeKey = string(semconv.HTTPResponseStatusCodeKey)
+	urlFullKey                = string(semconv.URLFullKey)
+	urlPathKey                = string(semconv.URLPathKey)
+	serverAddressKey          = string(semconv.ServerAddressKey)
+	serverPortKey             = string(semconv.ServerPortKey)
+	httpResponseBodySizeKey   = string(semconv.HTTPResponseBodySizeKey)
+	httpRequestResendCountKey = string(semconv.HTTPRequestResendCountKey)
+)
+
+// httpResponseHeaderKey returns the attribute key recording the response
+// header name, following the http.response.header.<key> convention
+func httpResponseHeaderKey(name string) string {
+	return responseHeaderKeys.get(name)
+}
+
+// deploymentEnvironmentAttribute returns the resource attribute naming the
+// deployment environment
+func deploymentEnvironmentAttribute(env string) attribute.KeyValue {
+	return semconv.DeploymentEnvironmentName(env)
+}
+
+// serviceNameKey is the resource attribute key identifying the service
+const serviceNameKey = semconv.ServiceNameKey
+
+// cloudProviderAWSAttribute is the resource attribute of services running on AWS
+var cloudProviderAWSAttribute = semconv.CloudProviderAWS
+
+// cloudRegionAttribute returns the resource attribute naming the cloud region
+func cloudRegionAttribute(region string) attribute.KeyValue {
+	return semconv.CloudRegion(region)
+}
+
+// serviceVersionAttribute returns the resource attribute recording the
+// service version
+func serviceVersionAttribute(version string) attribute.KeyValue {
+	return semconv.ServiceVersion(version)
+}
+
+// Network attribute keys describing the connection of a span's peer
+const (
+	networkPeerAddressKey = string(semconv.NetworkPeerAddressKey)
+	networkPeerPortKey    = string(semconv.NetworkPeerPortKey)
+	networkTransportKey   = string(semconv.NetworkTransportKey)
+	networkTypeKey        = string(semconv.NetworkTypeKey)
+)
+
+// VCS attribute keys identifying the source revision of a deployment
+const (
+	vcsRefHeadRevisionKey = string(semconv.VCSRefHeadRevisionKey)
+	vcsRefHeadNameKey     = string(semconv.VCSRefHeadNameKey)
+)
+
+// peerServiceKey records the logical name of the service a client span calls
+const peerServiceKey = string(semconv.PeerServiceKey)
+
+// messagingBatchMessageCountKey records the number of messages in a batch span
+const messagingBatchMessageCountKey = string(semconv.MessagingBatchMessageCountKey)
+
+// RPC attribute keys recorded by the RPC instrumentation
+const (
+	rpcSystemKey                  = string(semconv.RPCSystemKey)
+	rpcServiceKey                 = string(semconv.RPCServiceKey)
+	rpcMethodKey                  = string(semconv.RPCMethodKey)
+	rpcGRPCStatusCodeKey          = string(semconv.RPCGRPCStatusCodeKey)
+	rpcMessageTypeKey             = string(semconv.RPCMessageTypeKey)
+	rpcMessageIDKey               = string(semconv.RPCMessageIDKey)
+	rpcMessageUncompressedSizeKey = string(semconv.RPCMessageUncompressedSizeKey)
+	rpcMessageCompressedSizeKey   = string(semconv.RPCMessageCompressedSizeKey)
+)
+
+// rpcSystemGRPC is the rpc.system value of gRPC spans
+var rpcSystemGRPC = semconv.RPCSystemGRPC.Value.AsString()
+
+// Exception event name and attribute keys of recorded errors
+const (
+	exceptionEventName     = semconv.ExceptionEventName
+	exceptionTypeKey       = string(semconv.ExceptionTypeKey)
+	exceptionMessageKey    = string(semconv.ExceptionMessageKey)
+	exceptionStacktraceKey = string(semconv.ExceptionStacktraceKey)
+)
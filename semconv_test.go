@@ -0,0 +1,26 @@
+package tracingx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaURL(t *testing.T) {
+	t.Run("defaults to current semconv schema", func(t *testing.T) {
+		cfg := Config{}
+		assert.Equal(t, DefaultSchemaURL, cfg.schemaURL())
+		assert.Contains(t, DefaultSchemaURL, "https://opentelemetry.io/schemas/")
+	})
+
+	t.Run("honours configured schema URL", func(t *testing.T) {
+		cfg := Config{SchemaURL: "https://opentelemetry.io/schemas/1.26.0"}
+		assert.Equal(t, "https://opentelemetry.io/schemas/1.26.0", cfg.schemaURL())
+	})
+}
+
+func TestServiceNameAttribute(t *testing.T) {
+	attr := serviceNameAttribute("orders")
+	assert.Equal(t, "service.name", string(attr.Key))
+	assert.Equal(t, "orders", attr.Value.AsString())
+}
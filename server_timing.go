@@ -0,0 +1,96 @@
+package tracingx
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ServerTimingHeader is the response header carrying server timing metrics
+const ServerTimingHeader = "Server-Timing"
+
+// ServerTimingMetric is a single metric reported in a Server-Timing header,
+// e.g. `db;dur=53.2;desc="Primary DB"`
+type ServerTimingMetric struct {
+	Name        string
+	Duration    time.Duration
+	Description string
+}
+
+// ParseServerTiming parses Server-Timing header values. Malformed metrics are
+// skipped and unknown parameters are ignored.
+func ParseServerTiming(values ...string) []ServerTimingMetric {
+	var metrics []ServerTimingMetric
+	for _, value := range values {
+		for _, entry := range splitQuoted(value, ',') {
+			if metric, ok := parseServerTimingMetric(entry); ok {
+				metrics = append(metrics, metric)
+			}
+		}
+	}
+	return metrics
+}
+
+// parseServerTimingMetric parses a single `name;param=value` metric
+func parseServerTimingMetric(entry string) (ServerTimingMetric, bool) {
+	parts := splitQuoted(entry, ';')
+	name := strings.TrimSpace(parts[0])
+	if name == "" || strings.ContainsAny(name, " \t\"=") {
+		return ServerTimingMetric{}, false
+	}
+
+	metric := ServerTimingMetric{Name: name}
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		value = unquote(strings.TrimSpace(value))
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "dur":
+			if ms, err := strconv.ParseFloat(value, 64); err == nil && ms >= 0 {
+				metric.Duration = time.Duration(ms * float64(time.Millisecond))
+			}
+		case "desc":
+			metric.Description = value
+		}
+	}
+	return metric, true
+}
+
+// splitQuoted splits s on sep, ignoring separators inside double quotes
+func splitQuoted(s string, sep rune) []string {
+	var parts []string
+	var quoted, escaped bool
+	start := 0
+	for i, r := range s {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && quoted:
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case r == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unquote strips surrounding double quotes and backslash escapes from s
+func unquote(s string) string {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s
+	}
+	s = s[1 : len(s)-1]
+	var b strings.Builder
+	escaped := false
+	for _, r := range s {
+		if r == '\\' && !escaped {
+			escaped = true
+			continue
+		}
+		escaped = false
+		b.WriteRune(r)
+	}
+	return b.String()
+}
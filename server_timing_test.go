@@ -0,0 +1,65 @@
+package tracingx
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseServerTiming(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected []ServerTimingMetric
+	}{
+		{
+			name:   "single metric with duration and description",
+			values: []string{`db;dur=53.2;desc="Primary DB"`},
+			expected: []ServerTimingMetric{
+				{Name: "db", Duration: 53200 * time.Microsecond, Description: "Primary DB"},
+			},
+		},
+		{
+			name:   "multiple metrics and header values",
+			values: []string{"cache;desc=hit, app;dur=10", "total;dur=47"},
+			expected: []ServerTimingMetric{
+				{Name: "cache", Description: "hit"},
+				{Name: "app", Duration: 10 * time.Millisecond},
+				{Name: "total", Duration: 47 * time.Millisecond},
+			},
+		},
+		{
+			name:   "separators inside quoted description",
+			values: []string{`edge;desc="a, b; c";dur=1`},
+			expected: []ServerTimingMetric{
+				{Name: "edge", Duration: time.Millisecond, Description: "a, b; c"},
+			},
+		},
+		{
+			name:   "escaped quotes",
+			values: []string{`x;desc="say \"hi\""`},
+			expected: []ServerTimingMetric{
+				{Name: "x", Description: `say "hi"`},
+			},
+		},
+		{
+			name:   "invalid duration and unknown params are ignored",
+			values: []string{"db;dur=abc;foo=bar"},
+			expected: []ServerTimingMetric{
+				{Name: "db"},
+			},
+		},
+		{
+			name:     "empty and malformed entries are skipped",
+			values:   []string{"", " , ;dur=5"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ParseServerTiming(tt.values...))
+		})
+	}
+}
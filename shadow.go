@@ -0,0 +1,130 @@
+package tracingx
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// ShadowTrafficHeader marks a request as mirrored traffic when set to 1, e.g.
+// by the proxy mirroring production requests to a load test
+const ShadowTrafficHeader = "x-shadow-traffic"
+
+// ShadowTrafficBaggageKey is the baggage member, set to 1, carrying the
+// shadow traffic mark to downstream services
+const ShadowTrafficBaggageKey = "shadow.traffic"
+
+// ShadowTrafficAttribute marks the spans of shadow traffic
+const ShadowTrafficAttribute = "tracingx.shadow"
+
+// Shadow span routes accepted in ShadowTrafficConfig.Route
+const (
+	// ShadowRouteMain exports shadow spans with the others, marked with
+	// ShadowTrafficAttribute
+	ShadowRouteMain = "main"
+
+	// ShadowRouteExporter sends shadow spans to ShadowTrafficConfig.Exporter
+	// only
+	ShadowRouteExporter = "exporter"
+
+	// ShadowRouteDrop drops shadow spans
+	ShadowRouteDrop = "drop"
+)
+
+// ShadowTrafficConfig configures how the spans of shadow traffic, requests
+// marked by ShadowTrafficHeader or ShadowTrafficBaggageKey, are exported
+type ShadowTrafficConfig struct {
+	// Route is where shadow spans go: main, exporter or drop. With exporter
+	// or drop they are kept out of the main exporter and additional
+	// pipelines.
+	Route string `mapstructure:"route" default:"main"`
+
+	// Exporter receives the shadow spans with the exporter route,
+	// configured like the top-level provider settings
+	Exporter ExporterConfig `mapstructure:"exporter"`
+}
+
+// route returns the configured route, ShadowRouteMain when unset
+func (c ShadowTrafficConfig) route() (string, error) {
+	switch c.Route {
+	case "":
+		return ShadowRouteMain, nil
+	case ShadowRouteMain, ShadowRouteExporter, ShadowRouteDrop:
+		return c.Route, nil
+	default:
+		return "", fmt.Errorf("unknown shadow_traffic.route %q", c.Route)
+	}
+}
+
+// ContextWithShadowTraffic returns a new context marking its requests as
+// shadow traffic: spans started from it carry ShadowTrafficAttribute, and
+// the mark is propagated downstream in the baggage
+func ContextWithShadowTraffic(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, shadowTrafficKey{}, true)
+	member, err := baggage.NewMemberRaw(ShadowTrafficBaggageKey, "1")
+	if err != nil {
+		return ctx
+	}
+	bag, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx
+	}
+	return baggage.ContextWithBaggage(ctx, bag)
+}
+
+// IsShadowTraffic reports whether ctx belongs to shadow traffic, so handlers
+// can skip side effects such as sending emails or charging cards
+func IsShadowTraffic(ctx context.Context) bool {
+	shadow, _ := ctx.Value(shadowTrafficKey{}).(bool)
+	return shadow
+}
+
+type shadowTrafficKey struct{}
+
+// extractShadowTraffic reports whether the request whose trace context was
+// extracted from carrier into extracted is marked as shadow traffic
+func extractShadowTraffic(extracted context.Context, carrier propagation.TextMapCarrier) bool {
+	return carrier.Get(ShadowTrafficHeader) == "1" ||
+		baggage.FromContext(extracted).Member(ShadowTrafficBaggageKey).Value() == "1"
+}
+
+// isShadowSpan reports whether s belongs to shadow traffic
+func isShadowSpan(s sdktrace.ReadOnlySpan) bool {
+	for _, kv := range s.Attributes() {
+		if kv.Key == ShadowTrafficAttribute && kv.Value.Type() == attribute.BOOL {
+			return kv.Value.AsBool()
+		}
+	}
+	return false
+}
+
+// newShadowProcessor routes shadow spans for route: it returns the filter
+// for the main export paths and, with the exporter route, the processor
+// sending shadow spans accepted by exportFilter to their exporter
+func newShadowProcessor(ctx context.Context, config ShadowTrafficConfig, exportFilter func(s sdktrace.ReadOnlySpan) bool, warm bool, clock Clock) (func(s sdktrace.ReadOnlySpan) bool, sdktrace.SpanProcessor, []exportWorker, error) {
+	route, err := config.route()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if route == ShadowRouteMain {
+		return exportFilter, nil, nil, nil
+	}
+	mainFilter := func(s sdktrace.ReadOnlySpan) bool {
+		return !isShadowSpan(s) && exportFilter(s)
+	}
+	if route == ShadowRouteDrop {
+		return mainFilter, nil, nil, nil
+	}
+	workers, err := newWorkers(ctx, config.Exporter, warm, clock, nil)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to create shadow traffic exporter: %w", err)
+	}
+	processor := newFilterProcessor(newWorkerProcessor(workers), func(s sdktrace.ReadOnlySpan) bool {
+		return isShadowSpan(s) && exportFilter(s)
+	})
+	return mainFilter, processor, workers, nil
+}
@@ -0,0 +1,111 @@
+package tracingx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+func TestShadowTraffic(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	// serve handles a request with header through the middleware, returning
+	// the server and child spans and the headers of an outgoing call
+	serve := func(t *testing.T, header http.Header) (server, child Span, shadow bool, outgoing http.Header) {
+		t.Helper()
+		outgoing = http.Header{}
+		handler := NewMiddleware(provider)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			server = SpanFromContext(r.Context())
+			shadow = IsShadowTraffic(r.Context())
+			ctx, span := provider.Start(r.Context(), "db.query")
+			defer span.End()
+			child = span
+			require.NoError(t, provider.Inject(ctx, HeaderCarrier(outgoing)))
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		return server, child, shadow, outgoing
+	}
+
+	t.Run("marks requests with the header", func(t *testing.T) {
+		server, child, shadow, outgoing := serve(t, http.Header{"X-Shadow-Traffic": {"1"}})
+		assert.True(t, shadow)
+		assert.Equal(t, true, attributesOf(t, server)[ShadowTrafficAttribute])
+		assert.Equal(t, true, attributesOf(t, child)[ShadowTrafficAttribute])
+
+		bag, err := baggage.Parse(outgoing.Get("baggage"))
+		require.NoError(t, err)
+		assert.Equal(t, "1", bag.Member(ShadowTrafficBaggageKey).Value(), "the mark is propagated downstream")
+	})
+
+	t.Run("marks requests with the baggage member", func(t *testing.T) {
+		server, _, shadow, _ := serve(t, http.Header{"Baggage": {ShadowTrafficBaggageKey + "=1"}})
+		assert.True(t, shadow)
+		assert.Equal(t, true, attributesOf(t, server)[ShadowTrafficAttribute])
+	})
+
+	t.Run("leaves other requests unmarked", func(t *testing.T) {
+		server, child, shadow, outgoing := serve(t, http.Header{"X-Shadow-Traffic": {"0"}})
+		assert.False(t, shadow)
+		assert.NotContains(t, attributesOf(t, server), ShadowTrafficAttribute)
+		assert.NotContains(t, attributesOf(t, child), ShadowTrafficAttribute)
+		assert.Empty(t, outgoing.Get("baggage"))
+	})
+}
+
+func TestShadowTrafficRoutes(t *testing.T) {
+	// export starts a production and a shadow span on a provider configured
+	// with shadow, returning the span names received by the main collector
+	export := func(t *testing.T, shadow ShadowTrafficConfig) []string {
+		t.Helper()
+		collector := newFakeCollector(t)
+		provider, err := newOTLPProvider(Config{
+			ServiceName:   "test-service",
+			SampleRate:    1.0,
+			OTLP:          OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+			ShadowTraffic: shadow,
+		}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		_, span := provider.Start(context.Background(), "production")
+		span.End()
+		_, span = provider.Start(ContextWithShadowTraffic(context.Background()), "mirrored")
+		span.End()
+		require.NoError(t, provider.ForceFlush(context.Background()))
+		return spanNames(collector)
+	}
+
+	t.Run("exports shadow spans with the others by default", func(t *testing.T) {
+		assert.ElementsMatch(t, []string{"production", "mirrored"}, export(t, ShadowTrafficConfig{}))
+	})
+
+	t.Run("drops shadow spans", func(t *testing.T) {
+		assert.Equal(t, []string{"production"}, export(t, ShadowTrafficConfig{Route: ShadowRouteDrop}))
+	})
+
+	t.Run("sends shadow spans to their exporter", func(t *testing.T) {
+		shadow := newFakeCollector(t)
+		names := export(t, ShadowTrafficConfig{
+			Route:    ShadowRouteExporter,
+			Exporter: ExporterConfig{Provider: "otlp", OTLP: OTLPConfig{Endpoint: shadow.endpoint, Insecure: true}},
+		})
+		assert.Equal(t, []string{"production"}, names)
+		assert.Equal(t, []string{"mirrored"}, spanNames(shadow))
+	})
+
+	t.Run("rejects unknown routes", func(t *testing.T) {
+		_, err := newOTLPProvider(Config{ServiceName: "test-service", ShadowTraffic: ShadowTrafficConfig{Route: "elsewhere"}}, getTestLogger())
+		assert.ErrorContains(t, err, `unknown shadow_traffic.route "elsewhere"`)
+	})
+}
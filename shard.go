@@ -0,0 +1,22 @@
+package tracingx
+
+import (
+	"context"
+	"hash/fnv"
+)
+
+// ShardFromTraceID maps the trace in ctx to one of n shards, so every span
+// and request of a trace is routed to the same backend, e.g. by load tests
+// or canary routers. The shard is the FNV-1a 64-bit hash of the lowercase hex
+// trace ID modulo n, stable across processes and releases so services in
+// other languages can compute the same mapping. It returns -1 when ctx
+// carries no trace or n is less than 1.
+func ShardFromTraceID(ctx context.Context, n int) int {
+	traceID := TraceIDFromContext(ctx)
+	if traceID == "" || n < 1 {
+		return -1
+	}
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(traceID))
+	return int(h.Sum64() % uint64(n))
+}
@@ -0,0 +1,45 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestShardFromTraceID(t *testing.T) {
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{1},
+	}))
+
+	t.Run("is stable", func(t *testing.T) {
+		assert.Equal(t, 2, ShardFromTraceID(ctx, 8))
+		assert.Equal(t, 0, ShardFromTraceID(ctx, 1))
+	})
+
+	t.Run("follows the trace across spans", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		parentCtx, parent := provider.Start(context.Background(), "parent")
+		defer parent.End()
+		childCtx, child := provider.Start(parentCtx, "child")
+		defer child.End()
+		for n := 1; n <= 16; n++ {
+			shard := ShardFromTraceID(parentCtx, n)
+			assert.Equal(t, shard, ShardFromTraceID(childCtx, n))
+			assert.True(t, shard >= 0 && shard < n)
+		}
+	})
+
+	t.Run("requires a trace and shards", func(t *testing.T) {
+		assert.Equal(t, -1, ShardFromTraceID(context.Background(), 8))
+		assert.Equal(t, -1, ShardFromTraceID(ctx, 0))
+	})
+}
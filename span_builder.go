@@ -0,0 +1,79 @@
+package tracingx
+
+import (
+	"context"
+	"time"
+)
+
+// SpanBuilder assembles a span incrementally, e.g. while a request is being
+// parsed, so it can be started once its name, kind and attributes are known.
+// A SpanBuilder is not safe for concurrent use.
+type SpanBuilder struct {
+	name  string
+	kind  SpanKind
+	attrs map[string]any
+	opts  []SpanOption
+}
+
+// NewSpanBuilder creates a builder for a span with the given operation name
+func NewSpanBuilder(name string) *SpanBuilder {
+	return &SpanBuilder{
+		name:  name,
+		kind:  SpanKindInternal,
+		attrs: make(map[string]any),
+	}
+}
+
+// WithName replaces the operation name
+func (b *SpanBuilder) WithName(name string) *SpanBuilder {
+	b.name = name
+	return b
+}
+
+// WithKind sets the span kind
+func (b *SpanBuilder) WithKind(kind SpanKind) *SpanBuilder {
+	b.kind = kind
+	return b
+}
+
+// WithAttribute sets a single attribute
+func (b *SpanBuilder) WithAttribute(key string, value any) *SpanBuilder {
+	b.attrs[key] = value
+	return b
+}
+
+// WithAttributes merges attributes into the builder; later values win
+func (b *SpanBuilder) WithAttributes(attrs map[string]any) *SpanBuilder {
+	for k, v := range attrs {
+		b.attrs[k] = v
+	}
+	return b
+}
+
+// WithOptions appends span options applied after the builder's own settings
+func (b *SpanBuilder) WithOptions(opts ...SpanOption) *SpanBuilder {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
+// Start starts the span now
+func (b *SpanBuilder) Start(ctx context.Context, tracer Tracer) (context.Context, Span) {
+	return tracer.Start(ctx, b.name, b.spanOptions()...)
+}
+
+// StartAt starts the span with a backdated start timestamp, typically the
+// time the request was received
+func (b *SpanBuilder) StartAt(ctx context.Context, tracer Tracer, t time.Time) (context.Context, Span) {
+	opts := append(b.spanOptions(), WithTimestamp(t))
+	return tracer.Start(ctx, b.name, opts...)
+}
+
+// spanOptions converts the builder state into span options
+func (b *SpanBuilder) spanOptions() []SpanOption {
+	attrs := make(map[string]any, len(b.attrs))
+	for k, v := range b.attrs {
+		attrs[k] = v
+	}
+	opts := []SpanOption{WithSpanKind(b.kind), WithAttributes(attrs)}
+	return append(opts, b.opts...)
+}
@@ -0,0 +1,62 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSpanBuilder(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("assembles span incrementally", func(t *testing.T) {
+		builder := NewSpanBuilder("request").
+			WithKind(SpanKindServer).
+			WithAttributes(map[string]any{"http.method": "GET"})
+		builder.WithName("GET /orders").WithAttribute("http.route", "/orders")
+
+		_, span := builder.Start(context.Background(), provider)
+		span.End()
+
+		ro := span.(*otlpSpan).span.(sdktrace.ReadOnlySpan)
+		assert.Equal(t, "GET /orders", ro.Name())
+		assert.Equal(t, trace.SpanKindServer, ro.SpanKind())
+		attrs := attributesOf(t, span)
+		assert.Equal(t, "GET", attrs["http.method"])
+		assert.Equal(t, "/orders", attrs["http.route"])
+	})
+
+	t.Run("starts with backdated timestamp", func(t *testing.T) {
+		received := time.Now().Add(-time.Second)
+
+		_, span := NewSpanBuilder("backdated").StartAt(context.Background(), provider, received)
+		span.End()
+
+		ro := span.(*otlpSpan).span.(sdktrace.ReadOnlySpan)
+		assert.True(t, ro.StartTime().Equal(received))
+		assert.GreaterOrEqual(t, span.Duration(), time.Second)
+	})
+
+	t.Run("later changes do not affect started spans", func(t *testing.T) {
+		builder := NewSpanBuilder("reused").WithAttribute("step", 1)
+		_, first := builder.Start(context.Background(), provider)
+		builder.WithAttribute("step", 2)
+		first.End()
+
+		assert.Equal(t, int64(1), attributesOf(t, first)["step"])
+	})
+
+	t.Run("works with noop tracer", func(t *testing.T) {
+		ctx, span := NewSpanBuilder("noop").WithKind(SpanKindClient).StartAt(context.Background(), newNoopProvider(), time.Now())
+		assert.NotNil(t, ctx)
+		assert.NotNil(t, span)
+		span.End()
+	})
+}
@@ -0,0 +1,108 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exerciseSpanConcurrently calls every Span method from many goroutines;
+// run with -race to detect unsynchronized state
+func exerciseSpanConcurrently(t *testing.T, span Span) {
+	t.Helper()
+	const workers = 16
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			key := fmt.Sprintf("worker.%d", i)
+			span.SetTag(key, i)
+			span.LogFields(Field{Key: "worker", Value: i})
+			if i%4 == 0 {
+				span.SetError(errors.New("worker failed"))
+			}
+			_ = span.Duration()
+			_ = span.TraceID()
+			_ = span.SpanID()
+			_ = span.ParentSpanID()
+			_ = span.IsRoot()
+			_ = span.IsSampled()
+			_ = span.IsRemoteParent()
+			_ = span.Context()
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestSpanConcurrentUse(t *testing.T) {
+	t.Run("otlp span", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		_, span := provider.Start(context.Background(), "parallel")
+		exerciseSpanConcurrently(t, span)
+		span.End()
+
+		attrs := attributesOf(t, span)
+		for i := 0; i < 16; i++ {
+			assert.EqualValues(t, i, attrs[fmt.Sprintf("worker.%d", i)])
+		}
+	})
+
+	t.Run("end races with tagging", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		_, span := provider.Start(context.Background(), "racing")
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			exerciseSpanConcurrently(t, span)
+		}()
+		go func() {
+			defer wg.Done()
+			span.End()
+		}()
+		wg.Wait()
+		assert.GreaterOrEqual(t, span.Duration(), time.Duration(0))
+	})
+
+	t.Run("noop span", func(t *testing.T) {
+		_, span := newNoopProvider().Start(context.Background(), "parallel")
+		exerciseSpanConcurrently(t, span)
+		span.End()
+	})
+}
+
+func TestProviderConcurrentStart(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	ctx, root := provider.Start(context.Background(), "root")
+	defer root.End()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, child := provider.Start(ctx, "child")
+			child.SetTag("parallel", true)
+			child.End()
+			assert.Equal(t, root.TraceID(), child.TraceID())
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,78 @@
+package tracingx
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanContext identifies a span independently of the process that created it,
+// e.g. the producer span of a message being consumed
+type SpanContext struct {
+	TraceID string
+	SpanID  string
+	Sampled bool
+}
+
+// IsValid reports whether the span context has valid trace and span IDs
+func (sc SpanContext) IsValid() bool {
+	return IsValidTraceID(sc.TraceID) && IsValidSpanID(sc.SpanID)
+}
+
+// SpanContextFromContext returns the span context of the current span in ctx
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	if span := SpanFromContext(ctx); span != nil {
+		return SpanContext{TraceID: span.TraceID(), SpanID: span.SpanID(), Sampled: span.IsSampled()}
+	}
+	return fromOTelSpanContext(trace.SpanContextFromContext(ctx))
+}
+
+// SpanContextFromCarrier returns the W3C trace context found in carrier, such
+// as the headers of a consumed message. The result is invalid if carrier has
+// no trace context.
+func SpanContextFromCarrier(carrier Carrier) (SpanContext, error) {
+	if carrier == nil {
+		return SpanContext{}, errNilCarrier
+	}
+	ctx := propagation.TraceContext{}.Extract(context.Background(), carrier)
+	return fromOTelSpanContext(trace.SpanContextFromContext(ctx)), nil
+}
+
+// fromOTelSpanContext converts an OpenTelemetry span context
+func fromOTelSpanContext(sc trace.SpanContext) SpanContext {
+	if !sc.IsValid() {
+		return SpanContext{}
+	}
+	return SpanContext{
+		TraceID: sc.TraceID().String(),
+		SpanID:  sc.SpanID().String(),
+		Sampled: sc.IsSampled(),
+	}
+}
+
+// toOTelLinks converts span contexts to OpenTelemetry links, skipping invalid ones
+func toOTelLinks(contexts []SpanContext) []trace.Link {
+	var links []trace.Link
+	for _, sc := range contexts {
+		traceID, err := trace.TraceIDFromHex(sc.TraceID)
+		if err != nil {
+			continue
+		}
+		spanID, err := trace.SpanIDFromHex(sc.SpanID)
+		if err != nil {
+			continue
+		}
+		var flags trace.TraceFlags
+		if sc.Sampled {
+			flags = trace.FlagsSampled
+		}
+		links = append(links, trace.Link{SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     spanID,
+			TraceFlags: flags,
+			Remote:     true,
+		})})
+	}
+	return links
+}
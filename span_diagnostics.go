@@ -0,0 +1,43 @@
+package tracingx
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/gostratum/core/logx"
+)
+
+// ended reports whether End has been called on the span
+func (s *otlpSpan) ended() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.end.IsZero()
+}
+
+// misuse logs a span misuse with the offending call site when debug mode is enabled
+func (s *otlpSpan) misuse(reason string, fields ...logx.Field) {
+	if s.debug == nil {
+		return
+	}
+	s.mu.Lock()
+	endSite := s.endSite
+	s.mu.Unlock()
+
+	fields = append(fields,
+		logx.String("span", s.name),
+		logx.String("trace_id", s.TraceID()),
+		logx.String("span_id", s.SpanID()),
+		logx.String("call_site", callSite(3)),
+		logx.String("ended_at", endSite),
+	)
+	s.debug.Warn("tracing: "+reason, fields...)
+}
+
+// callSite returns the file:line of the caller skip frames above callSite
+func callSite(skip int) string {
+	_, file, line, ok := runtime.Caller(skip)
+	if !ok {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
@@ -0,0 +1,86 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestDoubleEnd(t *testing.T) {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	clock := newFakeClock(start)
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger(),
+		withClock(clock),
+	)
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	_, span := provider.Start(context.Background(), "deferred")
+	clock.Advance(time.Second)
+	span.End()
+	clock.Advance(time.Minute)
+	span.End()
+
+	assert.Equal(t, time.Second, span.Duration())
+}
+
+func TestUseAfterEndIsIgnored(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	_, span := provider.Start(context.Background(), "finished")
+	span.End()
+
+	assert.NotPanics(t, func() {
+		span.SetTag("late", true)
+		span.SetError(errors.New("late"))
+		span.LogFields(Field{Key: "late", Value: true})
+	})
+	assert.NotContains(t, attributesOf(t, span), "late")
+}
+
+func TestMisuseDiagnostics(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	logger := logx.ProvideAdapter(zap.New(core))
+
+	t.Run("logs call sites in debug mode", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0, Debug: true}, logger)
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		_, span := provider.Start(context.Background(), "misused")
+		span.End()
+		span.End()
+		span.SetTag("late", true)
+
+		entries := logs.FilterMessage("tracing: End called on an already ended span").All()
+		require.Len(t, entries, 1)
+		fields := entries[0].ContextMap()
+		assert.Equal(t, "misused", fields["span"])
+		assert.Contains(t, fields["call_site"], "span_diagnostics_test.go")
+		assert.Contains(t, fields["ended_at"], "span_diagnostics_test.go")
+
+		assert.Equal(t, 1, logs.FilterMessage("tracing: SetTag called after End").Len())
+	})
+
+	t.Run("is silent without debug mode", func(t *testing.T) {
+		logs.TakeAll()
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, logger)
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		_, span := provider.Start(context.Background(), "misused")
+		span.End()
+		span.End()
+
+		assert.Zero(t, logs.FilterMessageSnippet("tracing:").Len())
+	})
+}
@@ -0,0 +1,78 @@
+package tracingx
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// spanFallbacks maps goroutine IDs to the spans bound with
+// BindSpanFallback, innermost last
+var spanFallbacks = struct {
+	mu    sync.Mutex
+	spans map[uint64][]Span
+}{spans: make(map[uint64][]Span)}
+
+// BindSpanFallback registers span as the fallback span of the calling
+// goroutine until the returned release function is called, for legacy code
+// paths that lose the context entirely (e.g. callback APIs without one).
+//
+// Prefer passing ctx, or Activate where a request context is available. The
+// fallback has caveats:
+//   - it is only visible on the binding goroutine, not on goroutines it
+//     starts, which must bind the span themselves;
+//   - release must be called on every path, typically deferred, or the span
+//     stays registered after the request;
+//   - looking up the goroutine costs about a microsecond per call.
+func BindSpanFallback(span Span) (release func()) {
+	id := goroutineID()
+	spanFallbacks.mu.Lock()
+	spanFallbacks.spans[id] = append(spanFallbacks.spans[id], span)
+	spanFallbacks.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			spanFallbacks.mu.Lock()
+			defer spanFallbacks.mu.Unlock()
+			spans := spanFallbacks.spans[id]
+			for i := len(spans) - 1; i >= 0; i-- {
+				if spans[i] == span {
+					spans = append(spans[:i], spans[i+1:]...)
+					break
+				}
+			}
+			if len(spans) == 0 {
+				delete(spanFallbacks.spans, id)
+			} else {
+				spanFallbacks.spans[id] = spans
+			}
+		})
+	}
+}
+
+// CurrentSpanFallback returns the span most recently bound with
+// BindSpanFallback on the calling goroutine, or nil
+func CurrentSpanFallback() Span {
+	id := goroutineID()
+	spanFallbacks.mu.Lock()
+	defer spanFallbacks.mu.Unlock()
+	if spans := spanFallbacks.spans[id]; len(spans) > 0 {
+		return spans[len(spans)-1]
+	}
+	return nil
+}
+
+// goroutineID returns the ID of the calling goroutine, parsed from the
+// "goroutine N [status]:" header of its stack trace
+func goroutineID() uint64 {
+	var buf [64]byte
+	n := runtime.Stack(buf[:], false)
+	header := bytes.TrimPrefix(buf[:n], []byte("goroutine "))
+	if i := bytes.IndexByte(header, ' '); i > 0 {
+		header = header[:i]
+	}
+	id, _ := strconv.ParseUint(string(header), 10, 64)
+	return id
+}
@@ -0,0 +1,56 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpanFallback(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("binds spans to the calling goroutine", func(t *testing.T) {
+		assert.Nil(t, CurrentSpanFallback())
+
+		_, outer := provider.Start(context.Background(), "outer")
+		defer outer.End()
+		releaseOuter := BindSpanFallback(outer)
+		assert.Equal(t, outer, CurrentSpanFallback())
+
+		_, inner := provider.Start(outer.Context(), "inner")
+		defer inner.End()
+		releaseInner := BindSpanFallback(inner)
+		assert.Equal(t, inner, CurrentSpanFallback())
+
+		releaseInner()
+		releaseInner()
+		assert.Equal(t, outer, CurrentSpanFallback())
+		releaseOuter()
+		assert.Nil(t, CurrentSpanFallback())
+	})
+
+	t.Run("is not visible on other goroutines", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "request")
+		defer span.End()
+		release := BindSpanFallback(span)
+		defer release()
+
+		seen := make(chan Span)
+		go func() { seen <- CurrentSpanFallback() }()
+		assert.Nil(t, <-seen)
+	})
+}
+
+func TestGoroutineID(t *testing.T) {
+	main := goroutineID()
+	assert.NotZero(t, main)
+	assert.Equal(t, main, goroutineID())
+
+	other := make(chan uint64)
+	go func() { other <- goroutineID() }()
+	assert.NotEqual(t, main, <-other)
+}
@@ -0,0 +1,31 @@
+package tracingx
+
+import (
+	"context"
+	"time"
+)
+
+// endAtSpan is implemented by spans that can be ended at an explicit time
+type endAtSpan interface {
+	endAt(end time.Time)
+}
+
+// RecordSpan creates a span covering start to end and ends it immediately.
+// It represents work done by systems that cannot be instrumented directly,
+// such as CDN timings or server-side durations reported in a response. The
+// span is parented to the span context in ctx, which may be local or
+// extracted from a remote carrier; the returned span is already ended and is
+// only useful for reading its IDs.
+func RecordSpan(ctx context.Context, tracer Tracer, name string, start, end time.Time, opts ...SpanOption) Span {
+	if end.Before(start) {
+		end = start
+	}
+	opts = append(opts, WithTimestamp(start))
+	_, span := tracer.Start(ctx, name, opts...)
+	if s, ok := span.(endAtSpan); ok {
+		s.endAt(end)
+	} else {
+		span.End()
+	}
+	return span
+}
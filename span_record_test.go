@@ -0,0 +1,60 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRecordSpan(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	end := start.Add(120 * time.Millisecond)
+
+	t.Run("records explicit start and end", func(t *testing.T) {
+		ctx, parent := provider.Start(context.Background(), "client")
+		defer parent.End()
+
+		span := RecordSpan(ctx, provider, "cdn.fetch", start, end,
+			WithSpanKind(SpanKindServer),
+			WithAttributes(map[string]any{"cdn.cache": "hit"}),
+		)
+
+		ro := span.(*otlpSpan).span.(sdktrace.ReadOnlySpan)
+		assert.True(t, ro.StartTime().Equal(start))
+		assert.True(t, ro.EndTime().Equal(end))
+		assert.Equal(t, trace.SpanKindServer, ro.SpanKind())
+		assert.Equal(t, parent.SpanID(), span.ParentSpanID())
+		assert.Equal(t, parent.TraceID(), span.TraceID())
+		assert.Equal(t, 120*time.Millisecond, span.Duration())
+		assert.Equal(t, "hit", attributesOf(t, span)["cdn.cache"])
+	})
+
+	t.Run("parents to remote context", func(t *testing.T) {
+		carrier := map[string]string{"traceparent": validTraceParent}
+		ctx, err := provider.Extract(context.Background(), MapCarrier(carrier))
+		require.NoError(t, err)
+
+		span := RecordSpan(ctx, provider, "db.server", start, end)
+		assert.True(t, span.IsRemoteParent())
+		assert.Equal(t, trace.SpanContextFromContext(ctx).TraceID().String(), span.TraceID())
+	})
+
+	t.Run("clamps end before start", func(t *testing.T) {
+		span := RecordSpan(context.Background(), provider, "inverted", end, start)
+		assert.Equal(t, time.Duration(0), span.Duration())
+	})
+
+	t.Run("works with noop tracer", func(t *testing.T) {
+		span := RecordSpan(context.Background(), newNoopProvider(), "noop", start, end)
+		assert.NotNil(t, span)
+	})
+}
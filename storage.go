@@ -0,0 +1,93 @@
+package tracingx
+
+import (
+	"context"
+	"strings"
+)
+
+// Object storage attribute keys recorded by the storage helpers
+const (
+	StorageSystemAttribute     = "storage.system"
+	StorageOperationAttribute  = "storage.operation"
+	StorageBucketAttribute     = "storage.bucket"
+	StorageKeyAttribute        = "storage.key"
+	StorageKeyHashAttribute    = "storage.key_hash"
+	StorageBytesAttribute      = "storage.bytes"
+	StoragePartNumberAttribute = "storage.part_number"
+)
+
+// StorageOperation describes a call to an object store such as S3, GCS or
+// MinIO
+type StorageOperation struct {
+	// System is the object store, e.g. "s3", "gcs" or "minio"
+	System string
+
+	// Operation is the name of the call, e.g. "GetObject" or "UploadPart"
+	Operation string
+
+	// Bucket and Key locate the object; Key is empty for bucket-level
+	// operations such as listing
+	Bucket string
+	Key    string
+
+	// HashKey records a hash of Key instead of the key, for keys embedding
+	// identifiers such as user IDs or emails
+	HashKey bool
+
+	// PartNumber is the part of a multipart upload, zero otherwise
+	PartNumber int
+}
+
+// TraceStorage runs fn, an object storage call reporting the bytes it
+// transferred, in a client span named "<system>.<operation> <bucket>"
+// recording the bucket, key, byte count and multipart part number
+func TraceStorage(ctx context.Context, tracer Tracer, op StorageOperation, fn func(ctx context.Context) (int64, error)) (int64, error) {
+	ctx, span := tracer.Start(ctx, storageSpanName(op), WithSpanKind(SpanKindClient), WithAttributes(op.attributes()))
+	defer span.End()
+
+	n, err := fn(ctx)
+	span.SetTag(StorageBytesAttribute, n)
+	if err != nil {
+		span.SetError(err)
+	}
+	return n, err
+}
+
+// storageSpanName returns the span name of op
+func storageSpanName(op StorageOperation) string {
+	var b strings.Builder
+	if op.System != "" {
+		b.WriteString(op.System)
+		b.WriteByte('.')
+	}
+	b.WriteString(op.Operation)
+	if op.Bucket != "" {
+		b.WriteByte(' ')
+		b.WriteString(op.Bucket)
+	}
+	return b.String()
+}
+
+// attributes returns the span attributes of op
+func (op StorageOperation) attributes() map[string]any {
+	attrs := map[string]any{
+		StorageOperationAttribute: op.Operation,
+	}
+	if op.System != "" {
+		attrs[StorageSystemAttribute] = op.System
+	}
+	if op.Bucket != "" {
+		attrs[StorageBucketAttribute] = op.Bucket
+	}
+	switch {
+	case op.Key == "":
+	case op.HashKey:
+		attrs[StorageKeyHashAttribute] = hashKey(op.Key)
+	default:
+		attrs[StorageKeyAttribute] = op.Key
+	}
+	if op.PartNumber > 0 {
+		attrs[StoragePartNumberAttribute] = op.PartNumber
+	}
+	return attrs
+}
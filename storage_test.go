@@ -0,0 +1,88 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceStorage(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("records an object operation", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		n, err := TraceStorage(context.Background(), spy, StorageOperation{
+			System:    "s3",
+			Operation: "GetObject",
+			Bucket:    "exports",
+			Key:       "2026/10/report.csv",
+		}, func(ctx context.Context) (int64, error) {
+			return 4096, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(4096), n)
+
+		span := spy.started()[0]
+		ro := span.(*otlpSpan).span.(sdktrace.ReadOnlySpan)
+		assert.Equal(t, "s3.GetObject exports", ro.Name())
+		assert.Equal(t, trace.SpanKindClient, ro.SpanKind())
+
+		attrs := attributesOf(t, span)
+		assert.Equal(t, "s3", attrs[StorageSystemAttribute])
+		assert.Equal(t, "GetObject", attrs[StorageOperationAttribute])
+		assert.Equal(t, "exports", attrs[StorageBucketAttribute])
+		assert.Equal(t, "2026/10/report.csv", attrs[StorageKeyAttribute])
+		assert.Equal(t, int64(4096), attrs[StorageBytesAttribute])
+		assert.NotContains(t, attrs, StorageKeyHashAttribute)
+		assert.NotContains(t, attrs, StoragePartNumberAttribute)
+	})
+
+	t.Run("hashes keys and records part numbers", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		_, err := TraceStorage(context.Background(), spy, StorageOperation{
+			System:     "minio",
+			Operation:  "UploadPart",
+			Bucket:     "avatars",
+			Key:        "users/alice@example.com.png",
+			HashKey:    true,
+			PartNumber: 3,
+		}, func(ctx context.Context) (int64, error) {
+			return 5 << 20, nil
+		})
+		require.NoError(t, err)
+
+		attrs := attributesOf(t, spy.started()[0])
+		assert.Equal(t, hashKey("users/alice@example.com.png"), attrs[StorageKeyHashAttribute])
+		assert.NotContains(t, attrs, StorageKeyAttribute)
+		assert.Equal(t, int64(3), attrs[StoragePartNumberAttribute])
+	})
+
+	t.Run("records errors and partial transfers", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		_, err := TraceStorage(context.Background(), spy, StorageOperation{
+			System:    "gcs",
+			Operation: "PutObject",
+			Bucket:    "exports",
+			Key:       "a",
+		}, func(ctx context.Context) (int64, error) {
+			return 100, errors.New("connection reset")
+		})
+		require.Error(t, err)
+
+		attrs := attributesOf(t, spy.started()[0])
+		assert.Equal(t, true, attrs["error"])
+		assert.Equal(t, int64(100), attrs[StorageBytesAttribute])
+	})
+
+	t.Run("names bucket-level operations", func(t *testing.T) {
+		assert.Equal(t, "s3.ListObjectsV2 exports", storageSpanName(StorageOperation{System: "s3", Operation: "ListObjectsV2", Bucket: "exports"}))
+		assert.Equal(t, "ListBuckets", storageSpanName(StorageOperation{Operation: "ListBuckets"}))
+	})
+}
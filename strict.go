@@ -0,0 +1,70 @@
+package tracingx
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gostratum/core/logx"
+)
+
+// Strict modes for propagation problems callers tend to ignore
+const (
+	// StrictOff ignores problems beyond the returned errors
+	StrictOff = "off"
+
+	// StrictLog logs the first problem seen per carrier type and reason
+	StrictLog = "log"
+
+	// StrictPanic panics on problems of the caller's own making, such as a
+	// nil carrier, for tests and development. Malformed trace context
+	// received from other services is logged as with StrictLog instead.
+	StrictPanic = "panic"
+)
+
+// strictMode returns the configured strict mode, StrictOff when unset
+func (c Config) strictMode() (string, error) {
+	switch mode := strings.ToLower(c.Strict); mode {
+	case "":
+		return StrictOff, nil
+	case StrictOff, StrictLog, StrictPanic:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown strict mode %q", c.Strict)
+	}
+}
+
+// strictReporter surfaces unsupported carriers and malformed trace context
+type strictReporter struct {
+	seen sync.Map // carrier type and reason -> struct{}
+}
+
+// report handles a propagation problem of operation ("extract" or
+// "inject") for carrier according to mode
+func (r *strictReporter) report(mode string, logger logx.Logger, operation string, failure extractFailure, carrier Carrier) {
+	switch mode {
+	case StrictLog:
+		r.log(logger, operation, failure, carrier)
+	case StrictPanic:
+		// Malformed trace context is sent by other services; crashing on it
+		// would let any caller take the service down
+		if failure == extractMalformed {
+			r.log(logger, operation, failure, carrier)
+			return
+		}
+		panic(fmt.Sprintf("tracingx: %s: %s carrier %T", operation, failure, carrier))
+	}
+}
+
+// log logs the first problem seen per carrier type, operation and reason
+func (r *strictReporter) log(logger logx.Logger, operation string, failure extractFailure, carrier Carrier) {
+	carrierType := fmt.Sprintf("%T", carrier)
+	if _, loaded := r.seen.LoadOrStore(carrierType+"|"+operation+"|"+string(failure), struct{}{}); loaded {
+		return
+	}
+	logger.Warn("tracing propagation problem",
+		logx.String("operation", operation),
+		logx.String("reason", string(failure)),
+		logx.String("carrier", carrierType),
+	)
+}
@@ -0,0 +1,91 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gostratum/core/logx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestStrictMode(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("off", func(t *testing.T) {
+		core, logs := observer.New(zapcore.WarnLevel)
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service", Strict: StrictOff}, logx.ProvideAdapter(zap.New(core)))
+		require.NoError(t, err)
+		defer provider.Shutdown(ctx)
+
+		_, err = provider.Extract(ctx, nil)
+		assert.Error(t, err)
+		assert.Zero(t, logs.FilterMessage("tracing propagation problem").Len())
+	})
+
+	t.Run("log once per carrier type", func(t *testing.T) {
+		core, logs := observer.New(zapcore.WarnLevel)
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service", Strict: StrictLog}, logx.ProvideAdapter(zap.New(core)))
+		require.NoError(t, err)
+		defer provider.Shutdown(ctx)
+
+		for i := 0; i < 3; i++ {
+			_, _ = provider.Extract(ctx, nil)
+			_ = provider.Inject(ctx, nil)
+			_, _ = provider.Extract(ctx, MapCarrier(map[string]string{"traceparent": "garbage"}))
+		}
+		_, _ = provider.Extract(ctx, MapCarrier(map[string]string{"traceparent": validTraceParent}))
+
+		problems := logs.FilterMessage("tracing propagation problem").All()
+		require.Len(t, problems, 3)
+		fields := problems[0].ContextMap()
+		assert.Equal(t, "extract", fields["operation"])
+		assert.Equal(t, "unsupported_carrier", fields["reason"])
+		assert.Equal(t, "<nil>", fields["carrier"])
+		assert.Equal(t, "malformed", problems[2].ContextMap()["reason"])
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service", Strict: StrictPanic}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(ctx)
+
+		assert.PanicsWithValue(t, "tracingx: inject: unsupported_carrier carrier <nil>", func() {
+			_ = provider.Inject(ctx, nil)
+		})
+		assert.Panics(t, func() {
+			_, _ = provider.Extract(ctx, nil)
+		})
+		assert.NotPanics(t, func() {
+			_, _ = provider.Extract(ctx, MapCarrier(map[string]string{}))
+		})
+	})
+
+	t.Run("panic logs malformed inbound trace context", func(t *testing.T) {
+		core, logs := observer.New(zapcore.WarnLevel)
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service", Strict: StrictPanic}, logx.ProvideAdapter(zap.New(core)))
+		require.NoError(t, err)
+		defer provider.Shutdown(ctx)
+
+		assert.NotPanics(t, func() {
+			_, err = provider.Extract(ctx, MapCarrier(map[string]string{"traceparent": "garbage"}))
+		})
+		assert.NoError(t, err)
+		problems := logs.FilterMessage("tracing propagation problem").All()
+		require.Len(t, problems, 1)
+		assert.Equal(t, "malformed", problems[0].ContextMap()["reason"])
+	})
+
+	t.Run("rejects unknown modes", func(t *testing.T) {
+		_, err := newOTLPProvider(Config{ServiceName: "test-service", Strict: "crash"}, getTestLogger())
+		assert.ErrorContains(t, err, `unknown strict mode "crash"`)
+
+		provider, err := newOTLPProvider(Config{ServiceName: "test-service", Strict: "LOG"}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(ctx)
+		assert.ErrorContains(t, provider.Reconfigure(Config{ServiceName: "test-service", Strict: "crash"}), `unknown strict mode "crash"`)
+	})
+}
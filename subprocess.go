@@ -0,0 +1,94 @@
+package tracingx
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Environment variables carrying trace context to child processes, following
+// the OpenTelemetry environment carrier convention
+const (
+	TraceParentEnv = "TRACEPARENT"
+	TraceStateEnv  = "TRACESTATE"
+	BaggageEnv     = "BAGGAGE"
+)
+
+// EnvCarrier is a propagation carrier over environment variables; keys are
+// upper-cased, so traceparent is stored as TRACEPARENT
+type EnvCarrier map[string]string
+
+// Get returns the value of the variable for key
+func (c EnvCarrier) Get(key string) string {
+	return c[envKey(key)]
+}
+
+// Set stores value in the variable for key
+func (c EnvCarrier) Set(key, value string) {
+	c[envKey(key)] = value
+}
+
+// Keys lists the variables in the carrier
+func (c EnvCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// envKey converts a propagation key to an environment variable name
+func envKey(key string) string {
+	return strings.ToUpper(strings.ReplaceAll(key, "-", "_"))
+}
+
+// InjectEnv returns env (in os.Environ form) with the trace context of ctx
+// set in TRACEPARENT, TRACESTATE and BAGGAGE. Inherited values of these
+// variables are removed first, so a child of an untraced context does not
+// join an unrelated trace.
+func InjectEnv(ctx context.Context, tracer Tracer, env []string) ([]string, error) {
+	carrier := EnvCarrier{}
+	if err := tracer.Inject(ctx, carrier); err != nil {
+		return env, err
+	}
+
+	out := make([]string, 0, len(env)+len(carrier))
+	for _, kv := range env {
+		name, _, _ := strings.Cut(kv, "=")
+		if name != TraceParentEnv && name != TraceStateEnv && name != BaggageEnv {
+			out = append(out, kv)
+		}
+	}
+	for k, v := range carrier {
+		out = append(out, k+"="+v)
+	}
+	return out, nil
+}
+
+// InjectCommand passes the trace context of ctx to cmd through its
+// environment, starting from os.Environ when cmd.Env is unset
+func InjectCommand(ctx context.Context, tracer Tracer, cmd *exec.Cmd) error {
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	env, err := InjectEnv(ctx, tracer, env)
+	if err != nil {
+		return err
+	}
+	cmd.Env = env
+	return nil
+}
+
+// ExtractEnv extracts the trace context passed to this process by its
+// parent through TRACEPARENT, TRACESTATE and BAGGAGE
+func ExtractEnv(ctx context.Context, tracer Tracer) (context.Context, error) {
+	carrier := EnvCarrier{}
+	for _, name := range []string{TraceParentEnv, TraceStateEnv, BaggageEnv} {
+		if value, ok := os.LookupEnv(name); ok {
+			carrier[name] = value
+		}
+	}
+	return tracer.Extract(ctx, carrier)
+}
@@ -0,0 +1,81 @@
+package tracingx
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+func TestEnvCarrier(t *testing.T) {
+	carrier := EnvCarrier{}
+	carrier.Set("traceparent", validTraceParent)
+	carrier.Set("x-custom", "1")
+	assert.Equal(t, validTraceParent, carrier["TRACEPARENT"])
+	assert.Equal(t, "1", carrier["X_CUSTOM"])
+	assert.Equal(t, validTraceParent, carrier.Get("traceparent"))
+	assert.ElementsMatch(t, []string{"TRACEPARENT", "X_CUSTOM"}, carrier.Keys())
+}
+
+func TestInjectEnv(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("sets trace context and drops inherited values", func(t *testing.T) {
+		member, err := baggage.NewMember("tenant", "acme")
+		require.NoError(t, err)
+		bag, err := baggage.New(member)
+		require.NoError(t, err)
+		ctx, span := provider.Start(baggage.ContextWithBaggage(context.Background(), bag), "parent")
+		defer span.End()
+
+		env, err := InjectEnv(ctx, provider, []string{"PATH=/bin", "TRACEPARENT=stale", "TRACESTATE=stale"})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{
+			"PATH=/bin",
+			"TRACEPARENT=" + FormatTraceParent(span),
+			"BAGGAGE=tenant=acme",
+		}, env)
+	})
+
+	t.Run("untraced context clears inherited values", func(t *testing.T) {
+		env, err := InjectEnv(context.Background(), provider, []string{"TRACEPARENT=stale", "HOME=/root"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"HOME=/root"}, env)
+	})
+
+	t.Run("passes context to a child process", func(t *testing.T) {
+		if _, err := exec.LookPath("sh"); err != nil {
+			t.Skip("sh not available")
+		}
+		ctx, span := provider.Start(context.Background(), "shell out")
+		defer span.End()
+
+		cmd := exec.Command("sh", "-c", `printf %s "$TRACEPARENT"`)
+		require.NoError(t, InjectCommand(ctx, provider, cmd))
+		out, err := cmd.Output()
+		require.NoError(t, err)
+		assert.Equal(t, FormatTraceParent(span), string(out))
+	})
+}
+
+func TestExtractEnv(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Setenv(TraceParentEnv, validTraceParent)
+	t.Setenv(BaggageEnv, "tenant=acme")
+
+	ctx, err := ExtractEnv(context.Background(), provider)
+	require.NoError(t, err)
+	_, span := provider.Start(ctx, "child")
+	defer span.End()
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.TraceID())
+	assert.True(t, span.IsRemoteParent())
+	assert.Equal(t, "acme", baggage.FromContext(ctx).Member("tenant").Value())
+}
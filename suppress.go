@@ -0,0 +1,21 @@
+package tracingx
+
+import "context"
+
+// SuppressInstrumentation returns a context in which tracingx creates no
+// spans: providers return no-op spans and the tracingx middleware and
+// transports pass requests through untraced. It breaks cycles where
+// instrumentation triggers traced operations itself, e.g. an exporter whose
+// HTTP client goes through the traced transport. Exporters wrapped by
+// tracingx receive a suppressed context.
+func SuppressInstrumentation(ctx context.Context) context.Context {
+	return context.WithValue(ctx, suppressInstrumentationKey{}, true)
+}
+
+// IsInstrumentationSuppressed reports whether SuppressInstrumentation applies to ctx
+func IsInstrumentationSuppressed(ctx context.Context) bool {
+	suppressed, _ := ctx.Value(suppressInstrumentationKey{}).(bool)
+	return suppressed
+}
+
+type suppressInstrumentationKey struct{}
@@ -0,0 +1,79 @@
+package tracingx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// contextExporter records whether exports ran with instrumentation suppressed
+type contextExporter struct {
+	suppressed bool
+}
+
+func (e *contextExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.suppressed = IsInstrumentationSuppressed(ctx)
+	return nil
+}
+
+func (e *contextExporter) Shutdown(ctx context.Context) error { return nil }
+
+func TestSuppressInstrumentation(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	suppressed := SuppressInstrumentation(context.Background())
+	assert.True(t, IsInstrumentationSuppressed(suppressed))
+	assert.False(t, IsInstrumentationSuppressed(context.Background()))
+
+	t.Run("provider returns noop spans", func(t *testing.T) {
+		_, span := provider.Start(suppressed, "work")
+		assert.IsType(t, &noopSpan{}, span)
+		span.End()
+	})
+
+	t.Run("transport passes requests through", func(t *testing.T) {
+		var received http.Header
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r.Header.Clone()
+		}))
+		defer server.Close()
+
+		bg, _ := provider.Start(context.Background(), "parent")
+		spy := &spyTracer{Tracer: provider}
+		client := &http.Client{Transport: NewTransport(spy, nil)}
+		req, err := http.NewRequestWithContext(SuppressInstrumentation(bg), http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Empty(t, spy.started())
+		assert.Empty(t, received.Get("traceparent"))
+	})
+
+	t.Run("middleware passes requests through", func(t *testing.T) {
+		spy := &spyTracer{Tracer: provider}
+		handler := NewMiddleware(spy, WithTraceResponse())(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Nil(t, SpanFromContext(r.Context()))
+		}))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(suppressed))
+		assert.Empty(t, spy.started())
+		assert.Empty(t, rec.Header().Get(TraceResponseHeader))
+	})
+
+	t.Run("exporters run suppressed", func(t *testing.T) {
+		next := &contextExporter{}
+		exporter := &countingExporter{next: next, stats: &exportStats{}, clock: systemClock{}}
+		require.NoError(t, exporter.ExportSpans(context.Background(), nil))
+		assert.True(t, next.suppressed)
+	})
+}
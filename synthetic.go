@@ -0,0 +1,129 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gostratum/core/logx"
+)
+
+// SyntheticTraceName is the default root operation of synthetic traces
+const SyntheticTraceName = "tracingx.synthetic_trace"
+
+// errSyntheticFailure is recorded on the failing children of a synthetic trace
+var errSyntheticFailure = errors.New("synthetic failure")
+
+// SyntheticProfile describes the trace emitted by Provider.EmitSynthetic: a
+// server root span with sequential client children
+type SyntheticProfile struct {
+	// Name is the root span's operation (default SyntheticTraceName)
+	Name string `mapstructure:"name"`
+
+	// Children is the number of child spans under the root
+	Children int `mapstructure:"children" default:"3"`
+
+	// Latency is the simulated duration of each child; the root spans all
+	// of them. Timestamps are backdated, so emitting does not block.
+	Latency time.Duration `mapstructure:"latency" default:"25ms"`
+
+	// Errors is the number of children, from the last, marked as failed
+	Errors int `mapstructure:"errors" default:"1"`
+}
+
+// name returns the root operation
+func (p SyntheticProfile) name() string {
+	if p.Name == "" {
+		return SyntheticTraceName
+	}
+	return p.Name
+}
+
+// SyntheticConfig configures synthetic traces emitted on a timer
+type SyntheticConfig struct {
+	// Enabled emits Profile every Interval while the application runs
+	Enabled bool `mapstructure:"enabled" default:"false"`
+
+	// Interval is the time between synthetic traces
+	Interval time.Duration `mapstructure:"interval" default:"1m"`
+
+	// Profile is the shape of the emitted traces
+	Profile SyntheticProfile `mapstructure:"profile"`
+}
+
+// EmitSynthetic starts and ends a trace shaped by profile through the whole
+// pipeline: sampling is forced, but processors, filters and the exporter
+// treat its spans like any others. Every span carries SyntheticAttribute.
+// It returns the trace ID, so monitoring can check that the trace arrived
+// in the backend.
+func (p *otlpProvider) EmitSynthetic(ctx context.Context, profile SyntheticProfile) (string, error) {
+	if p.closed.Load() {
+		return "", ErrProviderShutdown
+	}
+	attrs := map[string]any{SyntheticAttribute: true}
+	start := p.clock.Now().Add(-time.Duration(profile.Children) * profile.Latency)
+
+	rootCtx, root := p.Start(ctx, profile.name(),
+		WithSpanKind(SpanKindServer),
+		WithAttributes(attrs),
+		WithTimestamp(start),
+		WithForceSample(),
+	)
+	end := start
+	for i := 0; i < profile.Children; i++ {
+		_, child := p.Start(rootCtx, profile.name()+".child."+strconv.Itoa(i+1),
+			WithSpanKind(SpanKindClient),
+			WithAttributes(attrs),
+			WithTimestamp(end),
+			WithForceSample(),
+		)
+		end = end.Add(profile.Latency)
+		if i >= profile.Children-profile.Errors {
+			// Like SetError, but timestamped within the backdated span
+			child.RecordError(errSyntheticFailure, WithErrorTimestamp(end))
+			child.SetTagBool("error", true)
+		}
+		endSpanAt(child, end)
+	}
+	endSpanAt(root, end)
+	return root.TraceID(), nil
+}
+
+// endSpanAt ends span at end when the span supports explicit end timestamps
+func endSpanAt(span Span, end time.Time) {
+	if s, ok := span.(*otlpSpan); ok {
+		s.endAt(end)
+		return
+	}
+	span.End()
+}
+
+// startSyntheticTraces emits config.Profile every config.Interval until the
+// returned function is called
+func startSyntheticTraces(config SyntheticConfig, provider Provider, logger logx.Logger) (stop func()) {
+	if !config.Enabled || config.Interval <= 0 {
+		return func() {}
+	}
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(config.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if _, err := provider.EmitSynthetic(context.Background(), config.Profile); err != nil {
+					logger.Warn("failed to emit synthetic trace", logx.Err(err))
+				}
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
@@ -0,0 +1,92 @@
+package tracingx
+
+import (
+	"context"
+	"encoding/hex"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+func TestEmitSynthetic(t *testing.T) {
+	collector := newFakeCollector(t)
+	provider, err := newOTLPProvider(Config{
+		ServiceName: "test-service",
+		SampleRate:  0,
+		OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+	}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	traceID, err := provider.EmitSynthetic(context.Background(), SyntheticProfile{
+		Children: 3,
+		Latency:  20 * time.Millisecond,
+		Errors:   1,
+	})
+	require.NoError(t, err)
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	spans := collector.received()
+	require.Len(t, spans, 4, "sampling is forced")
+	byName := map[string]*tracepb.Span{}
+	for _, s := range spans {
+		assert.Equal(t, traceID, hex.EncodeToString(s.TraceId))
+		keys := map[string]bool{}
+		for _, kv := range s.Attributes {
+			keys[kv.Key] = true
+		}
+		assert.True(t, keys[SyntheticAttribute], s.Name)
+		byName[s.Name] = s
+	}
+
+	root := byName[SyntheticTraceName]
+	require.NotNil(t, root)
+	assert.Equal(t, tracepb.Span_SPAN_KIND_SERVER, root.Kind)
+	assert.Equal(t, uint64(60*time.Millisecond), root.EndTimeUnixNano-root.StartTimeUnixNano)
+	for i, name := range []string{"child.1", "child.2", "child.3"} {
+		child := byName[SyntheticTraceName+"."+name]
+		require.NotNil(t, child, name)
+		assert.Equal(t, root.SpanId, child.ParentSpanId)
+		assert.Equal(t, uint64(20*time.Millisecond), child.EndTimeUnixNano-child.StartTimeUnixNano)
+		failed := false
+		for _, kv := range child.Attributes {
+			failed = failed || kv.Key == "error" && kv.Value.GetBoolValue()
+		}
+		assert.Equal(t, i == 2, failed, name)
+		if failed {
+			require.Len(t, child.Events, 1)
+			assert.Equal(t, child.EndTimeUnixNano, child.Events[0].TimeUnixNano)
+		}
+	}
+
+	require.NoError(t, provider.Shutdown(context.Background()))
+	_, err = provider.EmitSynthetic(context.Background(), SyntheticProfile{})
+	assert.ErrorIs(t, err, ErrProviderShutdown)
+}
+
+// countingSyntheticProvider counts EmitSynthetic calls
+type countingSyntheticProvider struct {
+	Provider
+	emitted atomic.Int32
+}
+
+func (p *countingSyntheticProvider) EmitSynthetic(context.Context, SyntheticProfile) (string, error) {
+	p.emitted.Add(1)
+	return "", nil
+}
+
+func TestStartSyntheticTraces(t *testing.T) {
+	provider := &countingSyntheticProvider{}
+	startSyntheticTraces(SyntheticConfig{Interval: time.Millisecond}, provider, getTestLogger())()
+
+	stop := startSyntheticTraces(SyntheticConfig{Enabled: true, Interval: time.Millisecond}, provider, getTestLogger())
+	assert.Eventually(t, func() bool { return provider.emitted.Load() >= 2 }, time.Second, time.Millisecond)
+	stop()
+	emitted := provider.emitted.Load()
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, emitted, provider.emitted.Load(), "no traces after stop")
+}
@@ -0,0 +1,83 @@
+package tracingx
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceTruncatedAttribute marks traces that reached Config.MaxSpansPerTrace,
+// and the events recorded in place of the spans over budget
+const TraceTruncatedAttribute = "trace.truncated"
+
+// traceBudget caps the spans started per trace in this process. Traces are
+// tracked from their local root span (a span without a parent or with a
+// remote one) until it ends.
+type traceBudget struct {
+	max int
+
+	mu     sync.Mutex
+	traces map[trace.TraceID]*traceBudgetEntry
+}
+
+// traceBudgetEntry tracks the spans of one trace
+type traceBudgetEntry struct {
+	count     int
+	root      *otlpSpan
+	truncated bool
+}
+
+// newTraceBudget creates a budget of max spans per trace; nil when max is
+// not positive, disabling the cap
+func newTraceBudget(max int) *traceBudget {
+	if max <= 0 {
+		return nil
+	}
+	return &traceBudget{max: max, traces: make(map[trace.TraceID]*traceBudgetEntry)}
+}
+
+// track starts counting the trace of a local root span
+func (b *traceBudget) track(root *otlpSpan) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.traces[root.span.SpanContext().TraceID()] = &traceBudgetEntry{count: 1, root: root}
+}
+
+// release stops counting the trace of an ended local root span
+func (b *traceBudget) release(id trace.TraceID) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.traces, id)
+}
+
+// admit reports whether a child span named name may be started under
+// parent. Over budget, the span is recorded as an event on the parent span
+// in ctx instead, and the root span is marked as truncated.
+func (b *traceBudget) admit(ctx context.Context, parent trace.SpanContext, name string, now time.Time) bool {
+	if !parent.IsValid() || parent.IsRemote() {
+		return true
+	}
+
+	b.mu.Lock()
+	entry, ok := b.traces[parent.TraceID()]
+	if !ok || entry.count < b.max {
+		if ok {
+			entry.count++
+		}
+		b.mu.Unlock()
+		return true
+	}
+	first := !entry.truncated
+	entry.truncated = true
+	root := entry.root
+	b.mu.Unlock()
+
+	if first {
+		root.setSystemTag(TraceTruncatedAttribute, attribute.BoolValue(true))
+	}
+	trace.SpanFromContext(ctx).AddEvent(name, trace.WithAttributes(attribute.Bool(TraceTruncatedAttribute, true)), trace.WithTimestamp(now))
+	return false
+}
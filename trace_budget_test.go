@@ -0,0 +1,94 @@
+package tracingx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+)
+
+func TestTraceBudget(t *testing.T) {
+	collector := newFakeCollector(t)
+	provider, err := newOTLPProvider(Config{
+		ServiceName:      "test-service",
+		SampleRate:       1.0,
+		MaxSpansPerTrace: 3,
+		OTLP:             OTLPConfig{Endpoint: collector.endpoint, Insecure: true},
+	}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	ctx, root := provider.Start(context.Background(), "root")
+	for i := 0; i < 2; i++ {
+		_, child := provider.Start(ctx, "child")
+		child.End()
+	}
+
+	childCtx, truncated := provider.Start(ctx, "recursive")
+	assert.Equal(t, root.SpanID(), truncated.SpanID())
+	assert.Equal(t, root.TraceID(), truncated.TraceID())
+	truncated.SetTag("ignored", true)
+	_, grandchild := provider.Start(childCtx, "recursive")
+	grandchild.End()
+	truncated.End()
+	root.End()
+
+	// The budget is released with the root span
+	_, next := provider.Start(context.Background(), "next")
+	next.End()
+	budget := provider.(*otlpProvider).current().budget
+	assert.Empty(t, budget.traces)
+
+	require.NoError(t, provider.ForceFlush(context.Background()))
+	spans := map[string][]*tracepb.Span{}
+	for _, span := range collector.received() {
+		spans[span.Name] = append(spans[span.Name], span)
+	}
+	require.Len(t, spans["root"], 1)
+	assert.Len(t, spans["child"], 2)
+	assert.Empty(t, spans["recursive"])
+	assert.Len(t, spans["next"], 1)
+
+	rootSpan := spans["root"][0]
+	var marked bool
+	for _, kv := range rootSpan.Attributes {
+		if kv.Key == TraceTruncatedAttribute {
+			marked = kv.Value.GetBoolValue()
+		}
+	}
+	assert.True(t, marked)
+	require.Len(t, rootSpan.Events, 2)
+	for _, event := range rootSpan.Events {
+		assert.Equal(t, "recursive", event.Name)
+		require.Len(t, event.Attributes, 1)
+		assert.Equal(t, TraceTruncatedAttribute, event.Attributes[0].Key)
+	}
+}
+
+func TestNewTraceBudget(t *testing.T) {
+	assert.Nil(t, newTraceBudget(0))
+	assert.Nil(t, newTraceBudget(-1))
+	assert.NotNil(t, newTraceBudget(5000))
+}
+
+func TestTraceBudgetFirstWins(t *testing.T) {
+	provider, err := newOTLPProvider(Config{
+		ServiceName:      "test-service",
+		SampleRate:       1.0,
+		MaxSpansPerTrace: 1,
+		Attributes:       AttributesConfig{SystemFirstWins: true},
+	}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	ctx, root := provider.Start(context.Background(), "root")
+	defer root.End()
+	root.SetTag(TraceTruncatedAttribute, false)
+	_, over := provider.Start(ctx, "child")
+	over.End()
+
+	root.SetTag(TraceTruncatedAttribute, false)
+	assert.Equal(t, true, attributesOf(t, root)[TraceTruncatedAttribute])
+}
@@ -0,0 +1,115 @@
+package tracingx
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ID generator modes accepted in Config.IDGenerator
+const (
+	// IDGeneratorRandom generates random 128-bit trace IDs
+	IDGeneratorRandom = "random"
+
+	// IDGenerator64Bit generates trace IDs whose high 64 bits are zero, so
+	// backends that keep only the low 64 bits (legacy Jaeger, Datadog) see
+	// the same IDs as 128-bit aware ones
+	IDGenerator64Bit = "64bit"
+
+	// IDGeneratorXRay generates AWS X-Ray compatible trace IDs, whose high
+	// 32 bits are the Unix time in seconds the trace started
+	IDGeneratorXRay = "xray"
+)
+
+// newIDGenerator returns the SDK ID generator for mode, or nil for the
+// SDK's default random generator. X-Ray IDs are timestamped by clock.
+func newIDGenerator(mode string, clock Clock) (sdktrace.IDGenerator, error) {
+	switch strings.ToLower(mode) {
+	case "", IDGeneratorRandom:
+		return nil, nil
+	case IDGenerator64Bit:
+		return id64Generator{}, nil
+	case IDGeneratorXRay:
+		return xrayIDGenerator{clock: clock}, nil
+	default:
+		return nil, fmt.Errorf("unknown id_generator %q", mode)
+	}
+}
+
+// id64Generator generates trace IDs with only the low 64 bits set
+type id64Generator struct{}
+
+func (id64Generator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	var traceID trace.TraceID
+	binary.BigEndian.PutUint64(traceID[8:], nonZeroUint64())
+	return traceID, id64Generator{}.NewSpanID(ctx, traceID)
+}
+
+func (id64Generator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	var spanID trace.SpanID
+	binary.BigEndian.PutUint64(spanID[:], nonZeroUint64())
+	return spanID
+}
+
+// xrayIDGenerator generates trace IDs starting with the current Unix time in
+// seconds, followed by 96 random bits, as X-Ray requires
+type xrayIDGenerator struct {
+	clock Clock
+}
+
+func (g xrayIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	var traceID trace.TraceID
+	binary.BigEndian.PutUint32(traceID[:4], uint32(g.clock.Now().Unix()))
+	binary.BigEndian.PutUint32(traceID[4:8], rand.Uint32())
+	binary.BigEndian.PutUint64(traceID[8:], nonZeroUint64())
+	return traceID, id64Generator{}.NewSpanID(ctx, traceID)
+}
+
+func (xrayIDGenerator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	return id64Generator{}.NewSpanID(ctx, traceID)
+}
+
+// nonZeroUint64 returns a random non-zero uint64
+func nonZeroUint64() uint64 {
+	for {
+		if v := rand.Uint64(); v != 0 {
+			return v
+		}
+	}
+}
+
+// TraceIDToUint64 returns the low 64 bits of a hex trace ID, the form used
+// by 64-bit backends such as Datadog
+func TraceIDToUint64(traceID string) (uint64, error) {
+	if !IsValidTraceID(traceID) {
+		return 0, fmt.Errorf("invalid trace ID %q", traceID)
+	}
+	b, _ := hex.DecodeString(traceID[16:])
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// TraceIDFromUint64 returns the 128-bit hex trace ID of a 64-bit ID, with the
+// high 64 bits zero
+func TraceIDFromUint64(id uint64) string {
+	return fmt.Sprintf("%032x", id)
+}
+
+// SpanIDToUint64 converts a hex span ID to its 64-bit integer form
+func SpanIDToUint64(spanID string) (uint64, error) {
+	if !IsValidSpanID(spanID) {
+		return 0, fmt.Errorf("invalid span ID %q", spanID)
+	}
+	b, _ := hex.DecodeString(spanID)
+	return binary.BigEndian.Uint64(b), nil
+}
+
+// SpanIDFromUint64 converts a 64-bit integer span ID to hex
+func SpanIDFromUint64(id uint64) string {
+	return fmt.Sprintf("%016x", id)
+}
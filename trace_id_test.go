@@ -0,0 +1,98 @@
+package tracingx
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceIDConversion(t *testing.T) {
+	id, err := TraceIDToUint64("640cfd8d00000000112210f47de98115")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1234567890123456789), id)
+	assert.Equal(t, "0000000000000000112210f47de98115", TraceIDFromUint64(id))
+
+	spanID, err := SpanIDToUint64("000000003ade68b1")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(987654321), spanID)
+	assert.Equal(t, "000000003ade68b1", SpanIDFromUint64(spanID))
+
+	_, err = TraceIDToUint64("not-a-trace-id")
+	assert.Error(t, err)
+	_, err = SpanIDToUint64("00000000000000000")
+	assert.Error(t, err)
+}
+
+func TestIDGenerator(t *testing.T) {
+	t.Run("64bit mode keeps the high bits zero", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			IDGenerator: IDGenerator64Bit,
+		}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		ctx, root := provider.Start(context.Background(), "root")
+		_, child := provider.Start(ctx, "child")
+		defer root.End()
+		defer child.End()
+
+		assert.True(t, strings.HasPrefix(root.TraceID(), "0000000000000000"))
+		assert.True(t, IsValidTraceID(root.TraceID()))
+		assert.Equal(t, root.TraceID(), child.TraceID())
+		assert.NotEqual(t, root.SpanID(), child.SpanID())
+
+		low, err := TraceIDToUint64(root.TraceID())
+		require.NoError(t, err)
+		assert.Equal(t, root.TraceID(), TraceIDFromUint64(low))
+	})
+
+	t.Run("xray mode starts trace IDs with the time", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			IDGenerator: IDGeneratorXRay,
+		}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		before := time.Now().Unix()
+		_, span := provider.Start(context.Background(), "root")
+		defer span.End()
+
+		require.True(t, IsValidTraceID(span.TraceID()))
+		epoch, err := strconv.ParseInt(span.TraceID()[:8], 16, 64)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, epoch, before)
+		assert.LessOrEqual(t, epoch, time.Now().Unix())
+	})
+
+	t.Run("xray mode timestamps trace IDs with the provider clock", func(t *testing.T) {
+		start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			IDGenerator: IDGeneratorXRay,
+		}, getTestLogger(), withClock(newFakeClock(start)))
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		_, span := provider.Start(context.Background(), "root")
+		defer span.End()
+
+		epoch, err := strconv.ParseInt(span.TraceID()[:8], 16, 64)
+		require.NoError(t, err)
+		assert.Equal(t, start.Unix(), epoch)
+	})
+
+	t.Run("rejects unknown modes", func(t *testing.T) {
+		_, err := newOTLPProvider(Config{ServiceName: "test-service", IDGenerator: "uuid"}, getTestLogger())
+		assert.ErrorContains(t, err, `unknown id_generator "uuid"`)
+	})
+}
@@ -0,0 +1,103 @@
+package tracingx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidTraceParent is returned (wrapped) when a traceparent header is malformed
+var ErrInvalidTraceParent = errors.New("invalid traceparent")
+
+const (
+	traceIDHexLen        = 32
+	spanIDHexLen         = 16
+	traceParentV00Length = 55 // "00-" + 32 + "-" + 16 + "-" + 2
+)
+
+// ValidateTraceParent validates a W3C traceparent header value and returns an
+// error wrapping ErrInvalidTraceParent that describes the first problem found
+func ValidateTraceParent(s string) error {
+	if len(s) < traceParentV00Length {
+		return fmt.Errorf("%w: length %d, expected at least %d", ErrInvalidTraceParent, len(s), traceParentV00Length)
+	}
+
+	version := s[0:2]
+	if !isLowerHex(version) {
+		return fmt.Errorf("%w: version %q is not lowercase hex", ErrInvalidTraceParent, version)
+	}
+	if version == "ff" {
+		return fmt.Errorf("%w: version ff is forbidden", ErrInvalidTraceParent)
+	}
+	if version == "00" && len(s) != traceParentV00Length {
+		return fmt.Errorf("%w: length %d, expected %d for version 00", ErrInvalidTraceParent, len(s), traceParentV00Length)
+	}
+	// Future versions may append fields, which must be dash-separated
+	if len(s) > traceParentV00Length && s[traceParentV00Length] != '-' {
+		return fmt.Errorf("%w: unexpected data after flags", ErrInvalidTraceParent)
+	}
+	if s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return fmt.Errorf("%w: fields must be separated by '-'", ErrInvalidTraceParent)
+	}
+
+	if traceID := s[3:35]; !IsValidTraceID(traceID) {
+		return fmt.Errorf("%w: trace-id %q must be 32 lowercase hex characters and not all zeros", ErrInvalidTraceParent, traceID)
+	}
+	if spanID := s[36:52]; !IsValidSpanID(spanID) {
+		return fmt.Errorf("%w: parent-id %q must be 16 lowercase hex characters and not all zeros", ErrInvalidTraceParent, spanID)
+	}
+	if flags := s[53:55]; !isLowerHex(flags) {
+		return fmt.Errorf("%w: trace-flags %q is not lowercase hex", ErrInvalidTraceParent, flags)
+	}
+	return nil
+}
+
+// NormalizeTraceParent repairs common formatting problems (surrounding
+// whitespace, uppercase hex) and returns the traceparent if it is then valid
+func NormalizeTraceParent(s string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(s))
+	if err := ValidateTraceParent(normalized); err != nil {
+		return "", err
+	}
+	return normalized, nil
+}
+
+// FormatTraceParent formats span's IDs as a W3C traceparent header value, or
+// returns an empty string if the span has no valid IDs (e.g. a noop span)
+func FormatTraceParent(span Span) string {
+	traceID, spanID := span.TraceID(), span.SpanID()
+	if !IsValidTraceID(traceID) || !IsValidSpanID(spanID) {
+		return ""
+	}
+	flags := "00"
+	if span.IsSampled() {
+		flags = "01"
+	}
+	return "00-" + traceID + "-" + spanID + "-" + flags
+}
+
+// IsValidTraceID reports whether s is a valid W3C trace ID
+// (32 lowercase hex characters, not all zeros)
+func IsValidTraceID(s string) bool {
+	return len(s) == traceIDHexLen && isLowerHex(s) && !isAllZeros(s)
+}
+
+// IsValidSpanID reports whether s is a valid W3C span ID
+// (16 lowercase hex characters, not all zeros)
+func IsValidSpanID(s string) bool {
+	return len(s) == spanIDHexLen && isLowerHex(s) && !isAllZeros(s)
+}
+
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isAllZeros(s string) bool {
+	return strings.Trim(s, "0") == ""
+}
@@ -0,0 +1,92 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const validTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestValidateTraceParent(t *testing.T) {
+	t.Run("accepts valid values", func(t *testing.T) {
+		assert.NoError(t, ValidateTraceParent(validTraceParent))
+		assert.NoError(t, ValidateTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00"))
+		// future versions may carry extra fields
+		assert.NoError(t, ValidateTraceParent("01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra"))
+	})
+
+	testCases := []struct {
+		name  string
+		value string
+	}{
+		{"empty", ""},
+		{"too short", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7"},
+		{"version 00 too long", validTraceParent + "-extra"},
+		{"forbidden version", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{"non-hex version", "zz-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{"wrong separator", "00_4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{"uppercase trace id", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"},
+		{"zero trace id", "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		{"zero span id", "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01"},
+		{"non-hex flags", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-0x"},
+		{"garbage after flags", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01x"},
+	}
+
+	for _, tc := range testCases {
+		t.Run("rejects "+tc.name, func(t *testing.T) {
+			err := ValidateTraceParent(tc.value)
+			assert.Error(t, err)
+			assert.True(t, errors.Is(err, ErrInvalidTraceParent))
+		})
+	}
+}
+
+func TestNormalizeTraceParent(t *testing.T) {
+	t.Run("repairs case and whitespace", func(t *testing.T) {
+		normalized, err := NormalizeTraceParent("  " + strings.ToUpper(validTraceParent) + "\n")
+		assert.NoError(t, err)
+		assert.Equal(t, validTraceParent, normalized)
+	})
+
+	t.Run("rejects unrepairable values", func(t *testing.T) {
+		_, err := NormalizeTraceParent("00-abc")
+		assert.ErrorIs(t, err, ErrInvalidTraceParent)
+	})
+}
+
+func TestIsValidIDs(t *testing.T) {
+	assert.True(t, IsValidTraceID("4bf92f3577b34da6a3ce929d0e0e4736"))
+	assert.False(t, IsValidTraceID("4bf92f3577b34da6"))
+	assert.False(t, IsValidTraceID("00000000000000000000000000000000"))
+	assert.False(t, IsValidTraceID("4bf92f3577b34da6a3ce929d0e0e473g"))
+
+	assert.True(t, IsValidSpanID("00f067aa0ba902b7"))
+	assert.False(t, IsValidSpanID("00f067aa0ba902b"))
+	assert.False(t, IsValidSpanID("0000000000000000"))
+	assert.False(t, IsValidSpanID("00F067AA0BA902B7"))
+}
+
+func TestFormatTraceParent(t *testing.T) {
+	provider, err := newOTLPProvider(Config{ServiceName: "test-service", SampleRate: 1.0}, getTestLogger())
+	require.NoError(t, err)
+	defer provider.Shutdown(context.Background())
+
+	t.Run("formats sampled span", func(t *testing.T) {
+		_, span := provider.Start(context.Background(), "op")
+		defer span.End()
+
+		traceParent := FormatTraceParent(span)
+		assert.NoError(t, ValidateTraceParent(traceParent))
+		assert.Equal(t, "00-"+span.TraceID()+"-"+span.SpanID()+"-01", traceParent)
+	})
+
+	t.Run("returns empty string for noop span", func(t *testing.T) {
+		_, span := newNoopProvider().Start(context.Background(), "op")
+		assert.Empty(t, FormatTraceParent(span))
+	})
+}
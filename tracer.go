@@ -2,7 +2,11 @@ package tracingx
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // Tracer provides distributed tracing capabilities
@@ -11,26 +15,48 @@ type Tracer interface {
 	Start(ctx context.Context, operationName string, opts ...SpanOption) (context.Context, Span)
 
 	// Extract extracts trace context from a carrier (e.g., HTTP headers)
-	Extract(ctx context.Context, carrier any) (context.Context, error)
+	Extract(ctx context.Context, carrier Carrier) (context.Context, error)
 
 	// Inject injects trace context into a carrier (e.g., HTTP headers)
-	Inject(ctx context.Context, carrier any) error
+	Inject(ctx context.Context, carrier Carrier) error
 
 	// Shutdown gracefully shuts down the tracer
 	Shutdown(ctx context.Context) error
 }
 
-// Span represents a single operation within a trace
+// Span represents a single operation within a trace.
+//
+// All Span methods are safe for concurrent use: handlers may tag, log and end
+// a span from multiple goroutines.
 type Span interface {
 	// End completes the span
 	End()
 
-	// SetTag sets a tag/attribute on the span
+	// SetTag sets a tag/attribute on the span; setting a key again replaces
+	// its value (see AttributesConfig for system attributes)
 	SetTag(key string, value any)
 
+	// SetTagIfAbsent sets a tag/attribute unless the key is already set,
+	// reporting whether it was set
+	SetTagIfAbsent(key string, value any) bool
+
+	// SetTagString, SetTagInt, SetTagBool and SetTagFloat set a typed tag
+	// like SetTag, without boxing the value in an interface on hot paths
+	SetTagString(key, value string)
+	SetTagInt(key string, value int)
+	SetTagBool(key string, value bool)
+	SetTagFloat(key string, value float64)
+
 	// SetError marks the span as errored
 	SetError(err error)
 
+	// RecordError records err as an exception event without failing the span
+	RecordError(err error, opts ...ErrorOption)
+
+	// SetErrors marks the span as errored, recording each error, including
+	// each part of an errors.Join result, as its own exception event
+	SetErrors(errs ...error)
+
 	// LogFields adds structured log fields to the span
 	LogFields(fields ...Field)
 
@@ -42,6 +68,23 @@ type Span interface {
 
 	// SpanID returns the span ID as a string
 	SpanID() string
+
+	// Duration returns the span duration once ended, or the time elapsed
+	// since the span started while it is still running
+	Duration() time.Duration
+
+	// ParentSpanID returns the parent span ID, or an empty string for root spans
+	ParentSpanID() string
+
+	// IsRoot reports whether the span has no parent, i.e. it started a new trace
+	IsRoot() bool
+
+	// IsSampled reports whether the span's trace is sampled for export
+	IsSampled() bool
+
+	// IsRemoteParent reports whether the span's parent was extracted from
+	// another process
+	IsRemoteParent() bool
 }
 
 // SpanOption configures span creation
@@ -52,6 +95,12 @@ type SpanConfig struct {
 	Kind       SpanKind
 	Attributes map[string]any
 	Timestamp  time.Time
+	Links      []SpanContext
+	// LazyAttributes are evaluated only when the span is recording
+	LazyAttributes []func() map[string]any
+	// allowlist, when set, limits the attributes recorded at start, including
+	// those the provider adds, to its keys
+	allowlist map[string]bool
 }
 
 // SpanKind represents the type of span
@@ -74,10 +123,110 @@ const (
 	SpanKindConsumer
 )
 
-// Field represents a structured log field
+// spanKindNames maps span kinds to their canonical string form
+var spanKindNames = map[SpanKind]string{
+	SpanKindInternal: "internal",
+	SpanKindServer:   "server",
+	SpanKindClient:   "client",
+	SpanKindProducer: "producer",
+	SpanKindConsumer: "consumer",
+}
+
+// String returns the lowercase name of the span kind
+func (k SpanKind) String() string {
+	if name, ok := spanKindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("SpanKind(%d)", int(k))
+}
+
+// ParseSpanKind parses a span kind name (case-insensitive, e.g. "server")
+func ParseSpanKind(s string) (SpanKind, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	for kind, n := range spanKindNames {
+		if n == name {
+			return kind, nil
+		}
+	}
+	return SpanKindInternal, fmt.Errorf("invalid span kind: %q", s)
+}
+
+// MarshalText implements encoding.TextMarshaler
+func (k SpanKind) MarshalText() ([]byte, error) {
+	if _, ok := spanKindNames[k]; !ok {
+		return nil, fmt.Errorf("invalid span kind: %d", int(k))
+	}
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler so span kinds can be
+// configured as strings
+func (k *SpanKind) UnmarshalText(text []byte) error {
+	kind, err := ParseSpanKind(string(text))
+	if err != nil {
+		return err
+	}
+	*k = kind
+	return nil
+}
+
+// Field represents a structured log field. Fields built with the typed
+// constructors such as String and Int carry their value without boxing it,
+// leaving Value nil. Fields without a key, such as Err(nil), are skipped.
 type Field struct {
 	Key   string
 	Value any
+
+	// typed is the value set by a typed constructor
+	typed attribute.Value
+}
+
+// String returns a string Field
+func String(key, value string) Field {
+	return Field{Key: key, typed: attribute.StringValue(value)}
+}
+
+// Int returns an integer Field
+func Int(key string, value int) Field {
+	return Field{Key: key, typed: attribute.IntValue(value)}
+}
+
+// Int64 returns a 64-bit integer Field
+func Int64(key string, value int64) Field {
+	return Field{Key: key, typed: attribute.Int64Value(value)}
+}
+
+// Bool returns a boolean Field
+func Bool(key string, value bool) Field {
+	return Field{Key: key, typed: attribute.BoolValue(value)}
+}
+
+// Float64 returns a floating-point Field
+func Float64(key string, value float64) Field {
+	return Field{Key: key, typed: attribute.Float64Value(value)}
+}
+
+// Err returns a Field named "error" with the error's message. Like logx.Err,
+// a nil error returns a field that is skipped.
+func Err(err error) Field {
+	if err == nil {
+		return Field{}
+	}
+	return String("error", err.Error())
+}
+
+// Duration returns a Field with the duration formatted like
+// time.Duration.String, as SetTag records durations
+func Duration(key string, value time.Duration) Field {
+	return String(key, value.String())
+}
+
+// attribute converts the field to an OpenTelemetry attribute
+func (f Field) attribute() attribute.KeyValue {
+	if f.typed.Type() != attribute.INVALID {
+		return attribute.KeyValue{Key: attribute.Key(f.Key), Value: f.typed}
+	}
+	return toAttribute(f.Key, f.Value)
 }
 
 // WithSpanKind sets the span kind
@@ -99,6 +248,19 @@ func WithAttributes(attrs map[string]any) SpanOption {
 	}
 }
 
+// WithLazyAttributes sets the attributes returned by fn on the span, calling
+// fn only when the span is recording, so attributes that are expensive to
+// compute, such as payload digests, cost nothing for unsampled traffic. They
+// are set after the span starts, so samplers do not see them, and replace
+// start attributes with the same key.
+func WithLazyAttributes(fn func() map[string]any) SpanOption {
+	return func(c *SpanConfig) {
+		if fn != nil {
+			c.LazyAttributes = append(c.LazyAttributes, fn)
+		}
+	}
+}
+
 // WithTimestamp sets the span start timestamp
 func WithTimestamp(t time.Time) SpanOption {
 	return func(c *SpanConfig) {
@@ -106,12 +268,26 @@ func WithTimestamp(t time.Time) SpanOption {
 	}
 }
 
+// WithLinks links the span to other spans, e.g. the messages processed by a
+// batch consumer. Invalid span contexts are ignored.
+func WithLinks(links ...SpanContext) SpanOption {
+	return func(c *SpanConfig) {
+		c.Links = append(c.Links, links...)
+	}
+}
+
 // applyOptions applies span options and returns the config
 func applySpanOptions(opts ...SpanOption) *SpanConfig {
+	return applySpanOptionsWithClock(systemClock{}, opts...)
+}
+
+// applySpanOptionsWithClock applies span options, defaulting the start
+// timestamp to the given clock
+func applySpanOptionsWithClock(clock Clock, opts ...SpanOption) *SpanConfig {
 	config := &SpanConfig{
 		Kind:       SpanKindInternal,
 		Attributes: make(map[string]any),
-		Timestamp:  time.Now(),
+		Timestamp:  clock.Now(),
 	}
 	for _, opt := range opts {
 		opt(config)
@@ -122,6 +298,33 @@ func applySpanOptions(opts ...SpanOption) *SpanConfig {
 // Provider is the interface that tracing providers must implement
 type Provider interface {
 	Tracer
+
+	// SelfTest sends a synthetic span and verifies the exporter round-trip
+	SelfTest(ctx context.Context) error
+
+	// EmitSynthetic emits a trace shaped by profile through the export
+	// pipeline, returning its trace ID
+	EmitSynthetic(ctx context.Context, profile SyntheticProfile) (string, error)
+
+	// Diagnostics reports live exporter health and export counters
+	Diagnostics() Diagnostics
+
+	// Stats returns a snapshot of the span and export counters
+	Stats() ExportStats
+
+	// Ready returns a channel closed once the exporters are connected
+	Ready() <-chan struct{}
+
+	// ForceFlush exports all ended spans that have not been exported yet
+	ForceFlush(ctx context.Context) error
+
+	// SetSampleRate changes the head sampling rate (0.0 to 1.0) for traces
+	// started from now on
+	SetSampleRate(rate float64) error
+
+	// Reconfigure atomically replaces the exporter, sampler and processors
+	// with ones built from config, draining the replaced pipeline
+	Reconfigure(config Config) error
 }
 
 // SpanFromContext extracts a span from context
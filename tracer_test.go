@@ -2,10 +2,12 @@ package tracingx
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 func TestSpanOptions(t *testing.T) {
@@ -37,6 +39,13 @@ func TestSpanOptions(t *testing.T) {
 		opt(config)
 		assert.Equal(t, timestamp, config.Timestamp)
 	})
+
+	t.Run("WithLazyAttributes", func(t *testing.T) {
+		config := &SpanConfig{}
+		WithLazyAttributes(func() map[string]any { return nil })(config)
+		WithLazyAttributes(nil)(config)
+		assert.Len(t, config.LazyAttributes, 1)
+	})
 }
 
 func TestApplySpanOptions(t *testing.T) {
@@ -127,4 +136,69 @@ func TestField(t *testing.T) {
 		assert.Equal(t, "user_id", field.Key)
 		assert.Equal(t, 12345, field.Value)
 	})
+
+	t.Run("typed constructors", func(t *testing.T) {
+		assert.Equal(t, attribute.String("user", "alice"), String("user", "alice").attribute())
+		assert.Equal(t, attribute.Int("attempt", 2), Int("attempt", 2).attribute())
+		assert.Equal(t, attribute.Int64("bytes", 1<<40), Int64("bytes", 1<<40).attribute())
+		assert.Equal(t, attribute.Bool("hit", true), Bool("hit", true).attribute())
+		assert.Equal(t, attribute.Float64("ratio", 0.5), Float64("ratio", 0.5).attribute())
+		assert.Nil(t, String("user", "alice").Value)
+	})
+
+	t.Run("error and duration constructors", func(t *testing.T) {
+		assert.Equal(t, attribute.String("error", "boom"), Err(errors.New("boom")).attribute())
+		assert.Equal(t, Field{}, Err(nil))
+		assert.Equal(t, attribute.String("wait", "250ms"), Duration("wait", 250*time.Millisecond).attribute())
+	})
+
+	t.Run("untyped fields convert their value", func(t *testing.T) {
+		assert.Equal(t, attribute.Int("user_id", 12345), Field{Key: "user_id", Value: 12345}.attribute())
+	})
+}
+
+func TestParseSpanKind(t *testing.T) {
+	for kind, name := range spanKindNames {
+		t.Run(name, func(t *testing.T) {
+			parsed, err := ParseSpanKind(name)
+			assert.NoError(t, err)
+			assert.Equal(t, kind, parsed)
+			assert.Equal(t, name, kind.String())
+		})
+	}
+
+	t.Run("is case-insensitive", func(t *testing.T) {
+		kind, err := ParseSpanKind(" Server ")
+		assert.NoError(t, err)
+		assert.Equal(t, SpanKindServer, kind)
+	})
+
+	t.Run("rejects unknown kinds", func(t *testing.T) {
+		_, err := ParseSpanKind("sideways")
+		assert.Error(t, err)
+	})
+
+	t.Run("formats unknown kinds", func(t *testing.T) {
+		assert.Equal(t, "SpanKind(42)", SpanKind(42).String())
+	})
+}
+
+func TestSpanKindText(t *testing.T) {
+	t.Run("round-trips through text", func(t *testing.T) {
+		text, err := SpanKindConsumer.MarshalText()
+		assert.NoError(t, err)
+		assert.Equal(t, "consumer", string(text))
+
+		var kind SpanKind
+		assert.NoError(t, kind.UnmarshalText(text))
+		assert.Equal(t, SpanKindConsumer, kind)
+	})
+
+	t.Run("rejects invalid text", func(t *testing.T) {
+		var kind SpanKind
+		assert.Error(t, kind.UnmarshalText([]byte("bogus")))
+
+		_, err := SpanKind(99).MarshalText()
+		assert.Error(t, err)
+	})
 }
@@ -0,0 +1,301 @@
+// Package tracingxbench generates span load against a tracingx provider
+// and reports how the export pipeline copes with it: throughput, spans the
+// batcher dropped, export failures and memory. Use it to size collector
+// deployments and the export queue before production traffic does.
+package tracingxbench
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gostratum/tracingx"
+)
+
+// Config describes the load to generate
+type Config struct {
+	// Duration is how long to generate load (default 10s)
+	Duration time.Duration
+
+	// Rate is the target number of spans per second across all workers;
+	// zero generates spans as fast as possible
+	Rate int
+
+	// Concurrency is the number of goroutines generating traces (default 1)
+	Concurrency int
+
+	// Depth is the number of nested spans in each trace (default 1)
+	Depth int
+
+	// Attributes is the number of attributes set on each span
+	Attributes int
+
+	// AttributeSize is the length of each string attribute value (default 16)
+	AttributeSize int
+
+	// Events is the number of events logged on each span
+	Events int
+
+	// FlushTimeout bounds the flush of the export queue at the end of the
+	// run (default 30s)
+	FlushTimeout time.Duration
+}
+
+// withDefaults returns c with unset fields defaulted
+func (c Config) withDefaults() Config {
+	if c.Duration <= 0 {
+		c.Duration = 10 * time.Second
+	}
+	if c.Concurrency <= 0 {
+		c.Concurrency = 1
+	}
+	if c.Depth <= 0 {
+		c.Depth = 1
+	}
+	if c.AttributeSize <= 0 {
+		c.AttributeSize = 16
+	}
+	if c.FlushTimeout <= 0 {
+		c.FlushTimeout = 30 * time.Second
+	}
+	return c
+}
+
+// Report is the outcome of a load run. Export counters are the change over
+// the run, so a provider can be reused across runs.
+type Report struct {
+	// Spans is the number of spans started and ended
+	Spans uint64 `json:"spans"`
+
+	// Sampled is the number of spans the sampler recorded
+	Sampled uint64 `json:"sampled"`
+
+	// Elapsed is the time spent generating spans, excluding the final flush
+	Elapsed time.Duration `json:"elapsed"`
+
+	// Throughput is Spans per second of Elapsed
+	Throughput float64 `json:"throughput"`
+
+	// Flush is the time the final flush of the export queue took
+	Flush time.Duration `json:"flush"`
+
+	// FlushError describes why the final flush failed, if it did
+	FlushError string `json:"flush_error,omitempty"`
+
+	// Exported is the number of spans the exporter accepted
+	Exported uint64 `json:"exported"`
+
+	// Failed is the number of spans the exporter failed to deliver
+	Failed uint64 `json:"failed"`
+
+	// Filtered is the number of spans dropped by the export filter
+	Filtered uint64 `json:"filtered"`
+
+	// Dropped is the number of spans dropped because the export queue was
+	// full
+	Dropped uint64 `json:"dropped"`
+
+	// PeakQueueDepth is the largest export queue depth seen during the run
+	PeakQueueDepth int `json:"peak_queue_depth"`
+
+	// AllocBytes is the bytes allocated during the run, by the whole process
+	AllocBytes uint64 `json:"alloc_bytes"`
+
+	// AllocsPerSpan is the heap allocations per span, by the whole process
+	AllocsPerSpan float64 `json:"allocs_per_span"`
+
+	// PeakHeapInuse is the largest in-use heap seen during the run
+	PeakHeapInuse uint64 `json:"peak_heap_inuse"`
+}
+
+// String formats the report for a terminal
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "spans:        %d (%d sampled) in %s\n", r.Spans, r.Sampled, r.Elapsed.Round(time.Millisecond))
+	fmt.Fprintf(&b, "throughput:   %.0f spans/s\n", r.Throughput)
+	fmt.Fprintf(&b, "exported:     %d\n", r.Exported)
+	fmt.Fprintf(&b, "failed:       %d\n", r.Failed)
+	fmt.Fprintf(&b, "filtered:     %d\n", r.Filtered)
+	fmt.Fprintf(&b, "dropped:      %d\n", r.Dropped)
+	fmt.Fprintf(&b, "queue peak:   %d\n", r.PeakQueueDepth)
+	fmt.Fprintf(&b, "flush:        %s", r.Flush.Round(time.Millisecond))
+	if r.FlushError != "" {
+		fmt.Fprintf(&b, " (%s)", r.FlushError)
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "allocated:    %d bytes, %.1f allocs/span\n", r.AllocBytes, r.AllocsPerSpan)
+	fmt.Fprintf(&b, "heap peak:    %d bytes\n", r.PeakHeapInuse)
+	return b.String()
+}
+
+// sampleInterval is how often queue depth and heap usage are sampled
+const sampleInterval = 50 * time.Millisecond
+
+// Run generates load against provider as described by config until the
+// duration elapses or ctx is done, then flushes the export queue and
+// reports the outcome. The provider is not shut down.
+func Run(ctx context.Context, provider tracingx.Provider, config Config) (Report, error) {
+	config = config.withDefaults()
+	if config.Rate < 0 {
+		return Report{}, fmt.Errorf("invalid rate %d: must not be negative", config.Rate)
+	}
+
+	before, beforeStats := provider.Diagnostics(), provider.Stats()
+	var memBefore runtime.MemStats
+	runtime.ReadMemStats(&memBefore)
+
+	runCtx, cancel := context.WithTimeout(ctx, config.Duration)
+	defer cancel()
+
+	var peakQueue atomic.Int64
+	var peakHeap atomic.Uint64
+	sampled := make(chan struct{})
+	go func() {
+		defer close(sampled)
+		sample(runCtx, provider, &peakQueue, &peakHeap)
+	}()
+
+	var spans, recorded atomic.Uint64
+	g := newGenerator(config)
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < config.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g.run(runCtx, provider, &spans, &recorded)
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	cancel()
+	<-sampled
+
+	flushCtx, cancelFlush := context.WithTimeout(ctx, config.FlushTimeout)
+	defer cancelFlush()
+	flushStart := time.Now()
+	flushErr := provider.ForceFlush(flushCtx)
+	flush := time.Since(flushStart)
+
+	var memAfter runtime.MemStats
+	runtime.ReadMemStats(&memAfter)
+	after, afterStats := provider.Diagnostics(), provider.Stats()
+
+	report := Report{
+		Spans:          spans.Load(),
+		Sampled:        recorded.Load(),
+		Elapsed:        elapsed,
+		Flush:          flush,
+		Exported:       after.SpansExported - before.SpansExported,
+		Failed:         after.SpansFailed - before.SpansFailed,
+		Filtered:       after.SpansFiltered - before.SpansFiltered,
+		Dropped:        afterStats.SpansOverflowed - beforeStats.SpansOverflowed,
+		PeakQueueDepth: int(peakQueue.Load()),
+		AllocBytes:     memAfter.TotalAlloc - memBefore.TotalAlloc,
+		PeakHeapInuse:  max(peakHeap.Load(), memAfter.HeapInuse),
+	}
+	if flushErr != nil {
+		report.FlushError = flushErr.Error()
+	}
+	if elapsed > 0 {
+		report.Throughput = float64(report.Spans) / elapsed.Seconds()
+	}
+	if report.Spans > 0 {
+		report.AllocsPerSpan = float64(memAfter.Mallocs-memBefore.Mallocs) / float64(report.Spans)
+	}
+	return report, nil
+}
+
+// sample records the peak queue depth and heap usage until ctx is done
+func sample(ctx context.Context, provider tracingx.Provider, peakQueue *atomic.Int64, peakHeap *atomic.Uint64) {
+	ticker := time.NewTicker(sampleInterval)
+	defer ticker.Stop()
+	var mem runtime.MemStats
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if depth := int64(provider.Diagnostics().QueueDepth); depth > peakQueue.Load() {
+			peakQueue.Store(depth)
+		}
+		runtime.ReadMemStats(&mem)
+		if mem.HeapInuse > peakHeap.Load() {
+			peakHeap.Store(mem.HeapInuse)
+		}
+	}
+}
+
+// generator produces the traces of a load run
+type generator struct {
+	config   Config
+	interval time.Duration
+	names    []string
+	attrs    map[string]any
+	fields   []tracingx.Field
+}
+
+// newGenerator precomputes span names, attributes and event fields so the
+// generator itself allocates as little as possible
+func newGenerator(config Config) *generator {
+	g := &generator{config: config, attrs: make(map[string]any, config.Attributes)}
+	if config.Rate > 0 {
+		// each worker paces whole traces of Depth spans
+		g.interval = time.Duration(float64(time.Second) * float64(config.Concurrency*config.Depth) / float64(config.Rate))
+	}
+	g.names = make([]string, config.Depth)
+	for i := range g.names {
+		g.names[i] = "bench.level" + strconv.Itoa(i)
+	}
+	value := strings.Repeat("x", config.AttributeSize)
+	for i := 0; i < config.Attributes; i++ {
+		g.attrs["bench.attr"+strconv.Itoa(i)] = value
+	}
+	g.fields = []tracingx.Field{{Key: "bench.event", Value: value}}
+	return g
+}
+
+// run generates traces until ctx is done
+func (g *generator) run(ctx context.Context, provider tracingx.Provider, spans, sampled *atomic.Uint64) {
+	var ticker *time.Ticker
+	if g.interval > 0 {
+		ticker = time.NewTicker(g.interval)
+		defer ticker.Stop()
+	}
+	for {
+		if ticker != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		} else if ctx.Err() != nil {
+			return
+		}
+		g.trace(ctx, provider, 0, spans, sampled)
+	}
+}
+
+// trace starts a span at the given depth and its children below it
+func (g *generator) trace(ctx context.Context, provider tracingx.Provider, depth int, spans, sampled *atomic.Uint64) {
+	// spans must end even when the run's context is done, so start them
+	// from a context that is never canceled
+	ctx, span := provider.Start(context.WithoutCancel(ctx), g.names[depth], tracingx.WithAttributes(g.attrs))
+	for i := 0; i < g.config.Events; i++ {
+		span.LogFields(g.fields...)
+	}
+	if depth+1 < g.config.Depth {
+		g.trace(ctx, provider, depth+1, spans, sampled)
+	}
+	span.End()
+	spans.Add(1)
+	if span.IsSampled() {
+		sampled.Add(1)
+	}
+}
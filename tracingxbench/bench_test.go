@@ -0,0 +1,123 @@
+package tracingxbench
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	"github.com/gostratum/tracingx"
+	"github.com/gostratum/tracingx/tracingxtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func noopProvider(t *testing.T) tracingx.Provider {
+	t.Helper()
+	result, err := tracingx.NewTracer(tracingx.Params{Logger: logx.NewNoopLogger()})
+	require.NoError(t, err)
+	return result.Provider
+}
+
+func TestRun(t *testing.T) {
+	t.Run("accounts for every span", func(t *testing.T) {
+		collector := tracingxtest.StartCollector(t)
+		provider := tracingxtest.NewProvider(t, collector)
+
+		report, err := Run(context.Background(), provider, Config{
+			Duration:    200 * time.Millisecond,
+			Concurrency: 2,
+			Depth:       3,
+			Attributes:  4,
+			Events:      1,
+		})
+		require.NoError(t, err)
+		assert.NotZero(t, report.Spans)
+		assert.Zero(t, report.Spans%3, "whole traces are generated")
+		assert.Equal(t, report.Spans, report.Sampled)
+		assert.Equal(t, report.Sampled, report.Exported+report.Failed+report.Filtered+report.Dropped)
+		assert.Empty(t, report.FlushError)
+		assert.Positive(t, report.Throughput)
+		assert.Positive(t, report.AllocsPerSpan)
+		assert.Contains(t, report.String(), "throughput:")
+
+		spans := collector.Spans()
+		assert.Len(t, spans, int(report.Exported))
+		assert.Len(t, spans[0].Span.Attributes, 4)
+		assert.Len(t, spans[0].Span.Events, 1)
+	})
+
+	t.Run("recorded-only spans are not dropped", func(t *testing.T) {
+		collector, errors := tracingxtest.StartCollector(t), tracingxtest.StartCollector(t)
+		result, err := tracingx.NewTracer(tracingx.Params{
+			Config: tracingx.Config{
+				Enabled:     true,
+				Provider:    "otlp",
+				ServiceName: "bench",
+				SampleRate:  0.5,
+				OTLP:        tracingx.OTLPConfig{Endpoint: collector.Endpoint(), Insecure: true},
+				// Pipelines record every span, unsampled ones included
+				Pipelines: []tracingx.PipelineConfig{{
+					Name:   "errors",
+					Filter: "errors",
+					OTLP:   tracingx.OTLPConfig{Endpoint: errors.Endpoint(), Insecure: true},
+				}},
+			},
+			Logger: logx.NewNoopLogger(),
+		})
+		require.NoError(t, err)
+		defer result.Provider.Shutdown(context.Background())
+
+		report, err := Run(context.Background(), result.Provider, Config{Duration: 200 * time.Millisecond, Rate: 1000})
+		require.NoError(t, err)
+		assert.Less(t, report.Sampled, report.Spans)
+		assert.Zero(t, report.Dropped)
+		assert.Equal(t, report.Sampled, report.Exported)
+	})
+
+	t.Run("counts spans dropped on a full queue", func(t *testing.T) {
+		collector := tracingxtest.StartCollector(t)
+		result, err := tracingx.NewTracer(tracingx.Params{
+			Config: tracingx.Config{
+				Enabled:     true,
+				Provider:    "otlp",
+				ServiceName: "bench",
+				SampleRate:  1.0,
+				OTLP:        tracingx.OTLPConfig{Endpoint: collector.Endpoint(), Insecure: true, QueueSize: 1, BatchSize: 1},
+			},
+			Logger: logx.NewNoopLogger(),
+		})
+		require.NoError(t, err)
+		defer result.Provider.Shutdown(context.Background())
+
+		report, err := Run(context.Background(), result.Provider, Config{Duration: 200 * time.Millisecond, Concurrency: 2})
+		require.NoError(t, err)
+		assert.Positive(t, report.Dropped)
+		assert.Equal(t, result.Provider.Stats().SpansOverflowed, report.Dropped)
+		assert.Equal(t, report.Sampled, report.Exported+report.Failed+report.Filtered+report.Dropped)
+		assert.Len(t, collector.Spans(), int(report.Exported))
+	})
+
+	t.Run("paces spans to the rate", func(t *testing.T) {
+		report, err := Run(context.Background(), noopProvider(t), Config{
+			Duration: 300 * time.Millisecond,
+			Rate:     100,
+		})
+		require.NoError(t, err)
+		assert.InDelta(t, 30, report.Spans, 10)
+		assert.Zero(t, report.Sampled)
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		report, err := Run(ctx, noopProvider(t), Config{Duration: time.Minute})
+		require.NoError(t, err)
+		assert.Less(t, report.Elapsed, time.Second)
+	})
+
+	t.Run("rejects a negative rate", func(t *testing.T) {
+		_, err := Run(context.Background(), noopProvider(t), Config{Rate: -1})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,258 @@
+// Package tracingxconnect traces Connect RPCs (connectrpc.com/connect) with
+// tracingx.
+package tracingxconnect
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"connectrpc.com/connect"
+	"github.com/gostratum/tracingx"
+)
+
+// Attribute keys and values from the OpenTelemetry RPC semantic conventions
+const (
+	rpcSystemKey           = "rpc.system"
+	rpcServiceKey          = "rpc.service"
+	rpcMethodKey           = "rpc.method"
+	rpcConnectErrorCodeKey = "rpc.connect_rpc.error_code"
+	rpcMessageTypeKey      = "rpc.message.type"
+	rpcMessageIDKey        = "rpc.message.id"
+	serverAddressKey       = "server.address"
+
+	rpcSystemConnect = "connect_rpc"
+	messageSent      = "SENT"
+	messageReceived  = "RECEIVED"
+)
+
+// Option configures the interceptor
+type Option func(*interceptor)
+
+// WithoutMessageEvents stops the interceptor from logging an event per
+// message of streaming RPCs
+func WithoutMessageEvents() Option {
+	return func(i *interceptor) {
+		i.noMessageEvents = true
+	}
+}
+
+// NewInterceptor returns a connect.Interceptor tracing both clients and
+// handlers: clients run each call in a client span and propagate its trace
+// context in the request headers, handlers run each call in a server span
+// parented to the caller. Spans are named pkg.Service/Method and carry the
+// rpc.* attributes and the Connect error code. Client spans are errored for
+// any error; server spans only for codes that indicate a server fault, such
+// as Internal and Unavailable.
+//
+//	client := examplev1connect.NewExampleServiceClient(httpClient, url,
+//		connect.WithInterceptors(tracingxconnect.NewInterceptor(tracer)))
+//	path, handler := examplev1connect.NewExampleServiceHandler(svc,
+//		connect.WithInterceptors(tracingxconnect.NewInterceptor(tracer)))
+func NewInterceptor(tracer tracingx.Tracer, opts ...Option) connect.Interceptor {
+	i := &interceptor{tracer: tracer}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
+}
+
+type interceptor struct {
+	tracer          tracingx.Tracer
+	noMessageEvents bool
+}
+
+func (i *interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if tracingx.IsInstrumentationSuppressed(ctx) {
+			return next(ctx, req)
+		}
+		ctx, span := i.start(ctx, req.Spec(), req.Peer(), req.Header())
+		defer span.End()
+		resp, err := next(ctx, req)
+		i.finish(ctx, span, req.Spec(), err)
+		return resp, err
+	}
+}
+
+func (i *interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return func(ctx context.Context, spec connect.Spec) connect.StreamingClientConn {
+		if tracingx.IsInstrumentationSuppressed(ctx) {
+			return next(ctx, spec)
+		}
+		conn := next(ctx, spec)
+		ctx, span := i.start(ctx, spec, conn.Peer(), conn.RequestHeader())
+		return &clientConn{StreamingClientConn: conn, interceptor: i, ctx: ctx, span: span}
+	}
+}
+
+func (i *interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		if tracingx.IsInstrumentationSuppressed(ctx) {
+			return next(ctx, conn)
+		}
+		ctx, span := i.start(ctx, conn.Spec(), conn.Peer(), conn.RequestHeader())
+		defer span.End()
+		err := next(ctx, &handlerConn{StreamingHandlerConn: conn, interceptor: i, span: span})
+		i.finish(ctx, span, conn.Spec(), err)
+		return err
+	}
+}
+
+// start starts the span of a call: a client span whose trace context is
+// injected into header, or a server span extracted from it
+func (i *interceptor) start(ctx context.Context, spec connect.Spec, peer connect.Peer, header http.Header) (context.Context, tracingx.Span) {
+	name, service, method := splitProcedure(spec.Procedure)
+	attrs := map[string]any{
+		rpcSystemKey:  rpcSystemConnect,
+		rpcServiceKey: service,
+		rpcMethodKey:  method,
+	}
+
+	var span tracingx.Span
+	if spec.IsClient {
+		if peer.Addr != "" {
+			attrs[serverAddressKey] = peer.Addr
+		}
+		ctx, span = i.tracer.Start(ctx, name, tracingx.WithSpanKind(tracingx.SpanKindClient), tracingx.WithAttributes(attrs))
+		if err := i.tracer.Inject(ctx, tracingx.HeaderCarrier(header)); err != nil {
+			span.LogFields(
+				tracingx.String("event", "inject_failed"),
+				tracingx.Err(err),
+			)
+		}
+	} else {
+		if peer.Addr != "" {
+			ctx = tracingx.ContextWithPeerAddress(ctx, peer.Addr)
+		}
+		ctx, span = tracingx.StartFromCarrier(ctx, i.tracer, tracingx.HeaderCarrier(header), name, tracingx.WithAttributes(attrs))
+	}
+	tracingx.RecordBudget(ctx, span)
+	return ctx, span
+}
+
+// finish records the outcome of a call on its span
+func (i *interceptor) finish(ctx context.Context, span tracingx.Span, spec connect.Spec, err error) {
+	if !spec.IsClient {
+		tracingx.RecordCancellation(ctx, span)
+	}
+	if err == nil {
+		return
+	}
+	code := connect.CodeOf(err)
+	span.SetTagString(rpcConnectErrorCodeKey, code.String())
+	if spec.IsClient || serverFault(code) {
+		span.SetError(err)
+	}
+}
+
+// logMessage logs a message event of a streaming call
+func (i *interceptor) logMessage(span tracingx.Span, direction string, id int64) {
+	if i.noMessageEvents {
+		return
+	}
+	span.LogFields(
+		tracingx.String("event", "message"),
+		tracingx.String(rpcMessageTypeKey, direction),
+		tracingx.Int64(rpcMessageIDKey, id),
+	)
+}
+
+// clientConn ends the client span of a streaming call once the response is
+// fully received or closed
+type clientConn struct {
+	connect.StreamingClientConn
+	interceptor *interceptor
+	ctx         context.Context
+	span        tracingx.Span
+
+	sent, received atomic.Int64
+	once           sync.Once
+}
+
+func (c *clientConn) Send(msg any) error {
+	err := c.StreamingClientConn.Send(msg)
+	if err == nil {
+		c.interceptor.logMessage(c.span, messageSent, c.sent.Add(1))
+	}
+	return err
+}
+
+func (c *clientConn) Receive(msg any) error {
+	err := c.StreamingClientConn.Receive(msg)
+	switch {
+	case err == nil:
+		c.interceptor.logMessage(c.span, messageReceived, c.received.Add(1))
+	case errors.Is(err, io.EOF):
+		c.end(nil)
+	default:
+		c.end(err)
+	}
+	return err
+}
+
+func (c *clientConn) CloseResponse() error {
+	err := c.StreamingClientConn.CloseResponse()
+	c.end(err)
+	return err
+}
+
+// end records err and ends the span, once
+func (c *clientConn) end(err error) {
+	c.once.Do(func() {
+		c.interceptor.finish(c.ctx, c.span, c.Spec(), err)
+		c.span.End()
+	})
+}
+
+// handlerConn logs the messages of a streaming handler
+type handlerConn struct {
+	connect.StreamingHandlerConn
+	interceptor *interceptor
+	span        tracingx.Span
+
+	sent, received atomic.Int64
+}
+
+func (c *handlerConn) Send(msg any) error {
+	err := c.StreamingHandlerConn.Send(msg)
+	if err == nil {
+		c.interceptor.logMessage(c.span, messageSent, c.sent.Add(1))
+	}
+	return err
+}
+
+func (c *handlerConn) Receive(msg any) error {
+	err := c.StreamingHandlerConn.Receive(msg)
+	if err == nil {
+		c.interceptor.logMessage(c.span, messageReceived, c.received.Add(1))
+	}
+	return err
+}
+
+// serverFault reports whether an error code returned by a handler
+// indicates a server fault rather than a caller error
+func serverFault(code connect.Code) bool {
+	switch code {
+	case connect.CodeUnknown, connect.CodeDeadlineExceeded, connect.CodeUnimplemented,
+		connect.CodeInternal, connect.CodeUnavailable, connect.CodeDataLoss:
+		return true
+	default:
+		return false
+	}
+}
+
+// splitProcedure splits a procedure such as /pkg.Service/Method into the span
+// name pkg.Service/Method and its service and method parts
+func splitProcedure(procedure string) (name, service, method string) {
+	name = strings.TrimPrefix(procedure, "/")
+	service, method, ok := strings.Cut(name, "/")
+	if !ok {
+		return name, "", name
+	}
+	return name, service, method
+}
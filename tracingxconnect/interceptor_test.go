@@ -0,0 +1,193 @@
+package tracingxconnect
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/gostratum/tracingx"
+	"github.com/gostratum/tracingx/tracingxtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const (
+	echoProcedure  = "/test.v1.EchoService/Echo"
+	countProcedure = "/test.v1.EchoService/Count"
+)
+
+// serve serves the echo service, failing Echo with echoErr when set, and
+// returns the URL it listens on
+func serve(t *testing.T, interceptor connect.Interceptor, echoErr error) string {
+	t.Helper()
+	opts := connect.WithInterceptors(interceptor)
+	mux := http.NewServeMux()
+	mux.Handle(echoProcedure, connect.NewUnaryHandler(echoProcedure,
+		func(ctx context.Context, req *connect.Request[wrapperspb.StringValue]) (*connect.Response[wrapperspb.StringValue], error) {
+			if echoErr != nil {
+				return nil, echoErr
+			}
+			return connect.NewResponse(req.Msg), nil
+		}, opts))
+	mux.Handle(countProcedure, connect.NewServerStreamHandler(countProcedure,
+		func(ctx context.Context, req *connect.Request[wrapperspb.Int32Value], stream *connect.ServerStream[wrapperspb.Int32Value]) error {
+			for i := int32(1); i <= req.Msg.Value; i++ {
+				if err := stream.Send(wrapperspb.Int32(i)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, opts))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// recorded flushes provider and returns the only trace received, whose root
+// is the client span
+func recorded(t *testing.T, provider tracingx.Provider, collector *tracingxtest.Collector) (client, server *tracingxtest.Span) {
+	t.Helper()
+	require.NoError(t, provider.ForceFlush(context.Background()))
+	collector.WaitForSpans(t, 2, time.Second)
+	traces := collector.Traces()
+	require.Len(t, traces, 1)
+	require.Len(t, traces[0].Spans, 1)
+	client = traces[0].Spans[0]
+	require.Len(t, client.Children, 1)
+	return client, client.Children[0]
+}
+
+func TestInterceptor(t *testing.T) {
+	t.Run("traces both sides of a unary call", func(t *testing.T) {
+		collector := tracingxtest.StartCollector(t)
+		provider := tracingxtest.NewProvider(t, collector)
+		interceptor := NewInterceptor(provider)
+		url := serve(t, interceptor, nil)
+
+		client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](
+			http.DefaultClient, url+echoProcedure, connect.WithInterceptors(interceptor))
+		resp, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("hi")))
+		require.NoError(t, err)
+		assert.Equal(t, "hi", resp.Msg.Value)
+
+		clientSpan, serverSpan := recorded(t, provider, collector)
+		assert.Equal(t, "test.v1.EchoService/Echo", clientSpan.Name)
+		assert.Equal(t, "client", clientSpan.Kind)
+		assert.Equal(t, "test.v1.EchoService/Echo", serverSpan.Name)
+		assert.Equal(t, "server", serverSpan.Kind)
+		for _, span := range []*tracingxtest.Span{clientSpan, serverSpan} {
+			assert.Equal(t, "connect_rpc", span.Attributes[rpcSystemKey])
+			assert.Equal(t, "test.v1.EchoService", span.Attributes[rpcServiceKey])
+			assert.Equal(t, "Echo", span.Attributes[rpcMethodKey])
+			assert.NotContains(t, span.Attributes, rpcConnectErrorCodeKey)
+			assert.NotContains(t, span.Attributes, "error")
+			assert.Empty(t, span.Events)
+		}
+		assert.Contains(t, clientSpan.Attributes, serverAddressKey)
+	})
+
+	t.Run("errors the server span only for server faults", func(t *testing.T) {
+		for _, tc := range []struct {
+			code        connect.Code
+			serverError bool
+		}{
+			{connect.CodeInvalidArgument, false},
+			{connect.CodeNotFound, false},
+			{connect.CodeInternal, true},
+			{connect.CodeUnavailable, true},
+		} {
+			t.Run(tc.code.String(), func(t *testing.T) {
+				collector := tracingxtest.StartCollector(t)
+				provider := tracingxtest.NewProvider(t, collector)
+				interceptor := NewInterceptor(provider)
+				url := serve(t, interceptor, connect.NewError(tc.code, errors.New("boom")))
+
+				client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](
+					http.DefaultClient, url+echoProcedure, connect.WithInterceptors(interceptor))
+				_, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("hi")))
+				require.Error(t, err)
+
+				clientSpan, serverSpan := recorded(t, provider, collector)
+				assert.Equal(t, tc.code.String(), clientSpan.Attributes[rpcConnectErrorCodeKey])
+				assert.Equal(t, tc.code.String(), serverSpan.Attributes[rpcConnectErrorCodeKey])
+				assert.Equal(t, true, clientSpan.Attributes["error"])
+				if tc.serverError {
+					assert.Equal(t, true, serverSpan.Attributes["error"])
+				} else {
+					assert.NotContains(t, serverSpan.Attributes, "error")
+				}
+			})
+		}
+	})
+
+	t.Run("logs the messages of a streaming call", func(t *testing.T) {
+		collector := tracingxtest.StartCollector(t)
+		provider := tracingxtest.NewProvider(t, collector)
+		interceptor := NewInterceptor(provider)
+		url := serve(t, interceptor, nil)
+
+		client := connect.NewClient[wrapperspb.Int32Value, wrapperspb.Int32Value](
+			http.DefaultClient, url+countProcedure, connect.WithInterceptors(interceptor))
+		stream, err := client.CallServerStream(context.Background(), connect.NewRequest(wrapperspb.Int32(3)))
+		require.NoError(t, err)
+		var received int
+		for stream.Receive() {
+			received++
+		}
+		require.NoError(t, stream.Err())
+		assert.Equal(t, 3, received)
+
+		clientSpan, serverSpan := recorded(t, provider, collector)
+		assert.Equal(t, "test.v1.EchoService/Count", clientSpan.Name)
+		assert.Equal(t, "client", clientSpan.Kind)
+		assert.Equal(t, "server", serverSpan.Kind)
+
+		types := func(span *tracingxtest.Span) []any {
+			var types []any
+			for _, e := range span.Events {
+				assert.Equal(t, "message", e.Attributes["event"])
+				types = append(types, e.Attributes[rpcMessageTypeKey])
+			}
+			return types
+		}
+		assert.Equal(t, []any{"SENT", "RECEIVED", "RECEIVED", "RECEIVED"}, types(clientSpan))
+		assert.Equal(t, []any{"RECEIVED", "SENT", "SENT", "SENT"}, types(serverSpan))
+		assert.Equal(t, int64(3), serverSpan.Events[3].Attributes[rpcMessageIDKey])
+	})
+
+	t.Run("skips message events when disabled", func(t *testing.T) {
+		collector := tracingxtest.StartCollector(t)
+		provider := tracingxtest.NewProvider(t, collector)
+		interceptor := NewInterceptor(provider, WithoutMessageEvents())
+		url := serve(t, interceptor, nil)
+
+		client := connect.NewClient[wrapperspb.Int32Value, wrapperspb.Int32Value](
+			http.DefaultClient, url+countProcedure, connect.WithInterceptors(interceptor))
+		stream, err := client.CallServerStream(context.Background(), connect.NewRequest(wrapperspb.Int32(2)))
+		require.NoError(t, err)
+		for stream.Receive() {
+		}
+		require.NoError(t, stream.Close())
+
+		clientSpan, serverSpan := recorded(t, provider, collector)
+		assert.Empty(t, clientSpan.Events)
+		assert.Empty(t, serverSpan.Events)
+	})
+}
+
+func TestSplitProcedure(t *testing.T) {
+	name, service, method := splitProcedure("/pkg.v1.Service/Method")
+	assert.Equal(t, "pkg.v1.Service/Method", name)
+	assert.Equal(t, "pkg.v1.Service", service)
+	assert.Equal(t, "Method", method)
+
+	name, service, method = splitProcedure("Method")
+	assert.Equal(t, "Method", name)
+	assert.Empty(t, service)
+	assert.Equal(t, "Method", method)
+}
@@ -0,0 +1,136 @@
+package tracingxtest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Fault is the failure injected into one export batch. Delay is applied
+// first; then Hang blocks until the export context ends (an export
+// timeout), or Err is returned.
+type Fault struct {
+	Delay time.Duration
+	Hang  bool
+	Err   error
+}
+
+// ErrorFault fails a batch with err
+func ErrorFault(err error) Fault { return Fault{Err: err} }
+
+// SlowFault delays a batch by delay, then exports it
+func SlowFault(delay time.Duration) Fault { return Fault{Delay: delay} }
+
+// TimeoutFault blocks a batch until its export context times out
+func TimeoutFault() Fault { return Fault{Hang: true} }
+
+// faults schedules the faults of successive export batches
+type faults struct {
+	mu       sync.Mutex
+	queue    []Fault
+	fallback Fault
+	batches  int
+	failed   int
+}
+
+// Enqueue injects faults into the next batches, one per batch, in order
+func (f *faults) Enqueue(faults ...Fault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.queue = append(f.queue, faults...)
+}
+
+// SetDefault injects fault into every batch once the enqueued faults are
+// used up; the zero Fault restores normal exports
+func (f *faults) SetDefault(fault Fault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fallback = fault
+}
+
+// Batches returns the number of export batches received
+func (f *faults) Batches() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.batches
+}
+
+// Failed returns the number of export batches failed by a fault
+func (f *faults) Failed() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.failed
+}
+
+// inject applies the fault of the next batch, returning its error
+func (f *faults) inject(ctx context.Context) error {
+	f.mu.Lock()
+	fault := f.fallback
+	if len(f.queue) > 0 {
+		fault, f.queue = f.queue[0], f.queue[1:]
+	}
+	f.batches++
+	f.mu.Unlock()
+
+	err := fault.apply(ctx)
+	if err != nil {
+		f.mu.Lock()
+		f.failed++
+		f.mu.Unlock()
+	}
+	return err
+}
+
+// apply waits out the fault's delay or hang and returns its error
+func (fault Fault) apply(ctx context.Context) error {
+	if fault.Delay > 0 {
+		timer := time.NewTimer(fault.Delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+	if fault.Hang {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return fault.Err
+}
+
+// ChaosExporter is a span exporter that injects faults (errors, delays,
+// timeouts) into export batches, for testing how an application and its
+// export pipeline behave when the telemetry backend misbehaves. To inject
+// faults into the OTLP provider, use the same methods on Collector.
+type ChaosExporter struct {
+	faults
+	next sdktrace.SpanExporter
+}
+
+// NewChaosExporter creates a chaos exporter passing batches that are not
+// failed on to next; nil discards them
+func NewChaosExporter(next sdktrace.SpanExporter) *ChaosExporter {
+	return &ChaosExporter{next: next}
+}
+
+// ExportSpans implements sdktrace.SpanExporter
+func (e *ChaosExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	if err := e.inject(ctx); err != nil {
+		return err
+	}
+	if e.next == nil {
+		return nil
+	}
+	return e.next.ExportSpans(ctx, spans)
+}
+
+// Shutdown implements sdktrace.SpanExporter
+func (e *ChaosExporter) Shutdown(ctx context.Context) error {
+	if e.next == nil {
+		return nil
+	}
+	return e.next.Shutdown(ctx)
+}
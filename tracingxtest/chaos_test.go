@@ -0,0 +1,82 @@
+package tracingxtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestChaosExporter(t *testing.T) {
+	spans := func(n int) []sdktrace.ReadOnlySpan {
+		return tracetest.SpanStubs(make([]tracetest.SpanStub, n)).Snapshots()
+	}
+
+	t.Run("injects enqueued faults in order", func(t *testing.T) {
+		next := tracetest.NewInMemoryExporter()
+		exporter := NewChaosExporter(next)
+		boom := errors.New("backend down")
+		exporter.Enqueue(ErrorFault(boom), SlowFault(20*time.Millisecond))
+
+		assert.ErrorIs(t, exporter.ExportSpans(context.Background(), spans(1)), boom)
+		assert.Empty(t, next.GetSpans())
+
+		start := time.Now()
+		require.NoError(t, exporter.ExportSpans(context.Background(), spans(2)))
+		assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+		assert.Len(t, next.GetSpans(), 2)
+
+		require.NoError(t, exporter.ExportSpans(context.Background(), spans(1)))
+		assert.Equal(t, 3, exporter.Batches())
+		assert.Equal(t, 1, exporter.Failed())
+	})
+
+	t.Run("times out hanging batches", func(t *testing.T) {
+		exporter := NewChaosExporter(nil)
+		exporter.SetDefault(TimeoutFault())
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, exporter.ExportSpans(ctx, spans(1)), context.DeadlineExceeded)
+
+		exporter.SetDefault(Fault{})
+		assert.NoError(t, exporter.ExportSpans(context.Background(), spans(1)))
+		assert.NoError(t, exporter.Shutdown(context.Background()))
+	})
+
+	t.Run("drives a batch span processor", func(t *testing.T) {
+		exporter := NewChaosExporter(nil)
+		exporter.SetDefault(ErrorFault(errors.New("backend down")))
+		provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+		defer provider.Shutdown(context.Background())
+
+		_, span := provider.Tracer("test").Start(context.Background(), "work")
+		span.End()
+		assert.Equal(t, 1, exporter.Failed())
+	})
+}
+
+func TestCollectorFaults(t *testing.T) {
+	collector := StartCollector(t)
+	provider := NewProvider(t, collector)
+
+	collector.Enqueue(ErrorFault(status.Error(codes.InvalidArgument, "rejected")))
+	_, span := provider.Start(context.Background(), "dropped")
+	span.End()
+	assert.Error(t, provider.ForceFlush(context.Background()))
+	assert.Equal(t, 1, collector.Failed())
+	assert.Empty(t, collector.Spans())
+
+	_, span = provider.Start(context.Background(), "exported")
+	span.End()
+	require.NoError(t, provider.ForceFlush(context.Background()))
+	received := collector.WaitForSpans(t, 1, 5*time.Second)
+	assert.Equal(t, "exported", received[0].Span.Name)
+	assert.Equal(t, 2, collector.Batches())
+}
@@ -0,0 +1,232 @@
+package tracingxtest
+
+import (
+	"context"
+	"encoding/hex"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	"github.com/gostratum/tracingx"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Collector is an in-process OTLP gRPC trace receiver. Point the OTLP
+// provider at Endpoint (with insecure transport) to test what a service
+// really exports, without a collector running next to the tests. Faults
+// enqueued with Enqueue or SetDefault are injected into incoming exports;
+// errors are returned as Unavailable, so the exporter retries them.
+type Collector struct {
+	coltracepb.UnimplementedTraceServiceServer
+	faults
+
+	endpoint string
+	server   *grpc.Server
+
+	mu    sync.Mutex
+	spans []ReceivedSpan
+}
+
+// ReceivedSpan is a span received by the collector with the resource and
+// instrumentation scope it was exported under
+type ReceivedSpan struct {
+	Resource map[string]any
+	Scope    string
+	Span     *tracepb.Span
+}
+
+// StartCollector starts a collector on a free local port; it is stopped
+// when the test ends
+func StartCollector(t testing.TB) *Collector {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen for OTLP: %v", err)
+	}
+	c := &Collector{endpoint: lis.Addr().String(), server: grpc.NewServer()}
+	coltracepb.RegisterTraceServiceServer(c.server, c)
+	go func() { _ = c.server.Serve(lis) }()
+	t.Cleanup(c.server.Stop)
+	return c
+}
+
+// Endpoint returns the host:port the collector listens on
+func (c *Collector) Endpoint() string {
+	return c.endpoint
+}
+
+// NewProvider creates an OTLP provider for service "tracingxtest" sampling
+// every trace and exporting to collector; it is shut down when the test ends
+func NewProvider(t testing.TB, collector *Collector) tracingx.Provider {
+	t.Helper()
+	result, err := tracingx.NewTracer(tracingx.Params{
+		Config: tracingx.Config{
+			Enabled:     true,
+			Provider:    "otlp",
+			ServiceName: "tracingxtest",
+			SampleRate:  1.0,
+			OTLP:        tracingx.OTLPConfig{Endpoint: collector.Endpoint(), Insecure: true},
+		},
+		Logger: logx.NewNoopLogger(),
+	})
+	if err != nil {
+		t.Fatalf("failed to create provider: %v", err)
+	}
+	t.Cleanup(func() { _ = result.Provider.Shutdown(context.Background()) })
+	return result.Provider
+}
+
+// Export implements the OTLP trace service
+func (c *Collector) Export(ctx context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	if err := c.inject(ctx); err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rs := range req.ResourceSpans {
+		resource := keyValues(rs.GetResource().GetAttributes())
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				c.spans = append(c.spans, ReceivedSpan{Resource: resource, Scope: ss.GetScope().GetName(), Span: span})
+			}
+		}
+	}
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+// Spans returns the spans received so far, in arrival order
+func (c *Collector) Spans() []ReceivedSpan {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]ReceivedSpan(nil), c.spans...)
+}
+
+// Traces returns the spans received so far as traces
+func (c *Collector) Traces() []Trace {
+	received := c.Spans()
+	records := make([]record, len(received))
+	for i, r := range received {
+		s := r.Span
+		records[i] = record{
+			traceID: hex.EncodeToString(s.TraceId),
+			spanID:  hex.EncodeToString(s.SpanId),
+			parent:  hex.EncodeToString(s.ParentSpanId),
+			start:   time.Unix(0, int64(s.StartTimeUnixNano)),
+			span: &Span{
+				Name:       s.Name,
+				Kind:       protoKindString(s.Kind),
+				Status:     protoStatusString(s.GetStatus().GetCode()),
+				Attributes: keyValues(s.Attributes),
+				Events:     protoEvents(s.Events),
+				Duration:   time.Duration(s.EndTimeUnixNano - s.StartTimeUnixNano),
+			},
+		}
+	}
+	return buildTraces(records)
+}
+
+// WaitForSpans waits until at least n spans have been received, failing t
+// after timeout. Call the provider's ForceFlush first to avoid waiting for
+// the batch interval.
+func (c *Collector) WaitForSpans(t testing.TB, n int, timeout time.Duration) []ReceivedSpan {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		spans := c.Spans()
+		if len(spans) >= n {
+			return spans
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("received %d spans, want at least %d", len(spans), n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// Reset discards the spans received so far
+func (c *Collector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.spans = nil
+}
+
+// protoKindString returns the lower-case name of an OTLP span kind, empty for internal spans
+func protoKindString(kind tracepb.Span_SpanKind) string {
+	switch kind {
+	case tracepb.Span_SPAN_KIND_UNSPECIFIED, tracepb.Span_SPAN_KIND_INTERNAL:
+		return ""
+	default:
+		return strings.ToLower(strings.TrimPrefix(kind.String(), "SPAN_KIND_"))
+	}
+}
+
+// protoStatusString returns the lower-case name of an OTLP status code, empty when unset
+func protoStatusString(code tracepb.Status_StatusCode) string {
+	if code == tracepb.Status_STATUS_CODE_UNSET {
+		return ""
+	}
+	return strings.ToLower(strings.TrimPrefix(code.String(), "STATUS_CODE_"))
+}
+
+// protoEvents converts OTLP span events
+func protoEvents(in []*tracepb.Span_Event) []Event {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]Event, len(in))
+	for i, e := range in {
+		out[i] = Event{Name: e.Name, Attributes: keyValues(e.Attributes)}
+	}
+	return out
+}
+
+// keyValues converts OTLP attributes to a map, nil when there are none
+func keyValues(kvs []*commonpb.KeyValue) map[string]any {
+	if len(kvs) == 0 {
+		return nil
+	}
+	m := make(map[string]any, len(kvs))
+	for _, kv := range kvs {
+		m[kv.Key] = anyValue(kv.Value)
+	}
+	return m
+}
+
+// anyValue converts an OTLP attribute value, using the types of
+// attribute.Value.AsInterface
+func anyValue(v *commonpb.AnyValue) any {
+	switch v := v.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return v.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return v.BoolValue
+	case *commonpb.AnyValue_IntValue:
+		return v.IntValue
+	case *commonpb.AnyValue_DoubleValue:
+		return v.DoubleValue
+	case *commonpb.AnyValue_BytesValue:
+		return v.BytesValue
+	case *commonpb.AnyValue_ArrayValue:
+		values := make([]any, len(v.ArrayValue.Values))
+		for i, item := range v.ArrayValue.Values {
+			values[i] = anyValue(item)
+		}
+		return values
+	case *commonpb.AnyValue_KvlistValue:
+		return keyValues(v.KvlistValue.Values)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,47 @@
+package tracingxtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gostratum/tracingx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	collector := StartCollector(t)
+	provider := NewProvider(t, collector)
+
+	ctx, root := provider.Start(context.Background(), "HTTP POST",
+		tracingx.WithSpanKind(tracingx.SpanKindServer),
+		tracingx.WithAttributes(map[string]any{"http.request.method": "POST"}),
+	)
+	_, child := provider.Start(ctx, "charge card")
+	child.SetTag("retries", []string{"a", "b"})
+	child.SetError(errors.New("declined"))
+	child.End()
+	root.End()
+	require.NoError(t, provider.ForceFlush(context.Background()))
+
+	received := collector.WaitForSpans(t, 2, 5*time.Second)
+	assert.Equal(t, "tracingxtest", received[0].Resource["service.name"])
+	assert.NotEmpty(t, received[0].Scope)
+
+	traces := collector.Traces()
+	require.Len(t, traces, 1)
+	assert.Empty(t, DiffTraces(Trace{Spans: []*Span{{
+		Name:       "HTTP POST",
+		Kind:       "server",
+		Attributes: map[string]any{"http.request.method": "POST"},
+		Children: []*Span{{
+			Name:       "charge card",
+			Attributes: map[string]any{"retries": []string{"a", "b"}, "error": true},
+		}},
+	}}}, traces[0], IgnoreDurations(), IgnoreEvents()))
+
+	collector.Reset()
+	assert.Empty(t, collector.Spans())
+}
@@ -0,0 +1,182 @@
+package tracingxtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DiffOption configures DiffTraces
+type DiffOption func(*diffConfig)
+
+// diffConfig contains the tolerances of a trace comparison
+type diffConfig struct {
+	ignoreDurations   bool
+	durationTolerance time.Duration
+	ignoreAttributes  map[string]bool
+	ignoreEvents      bool
+}
+
+// IgnoreDurations ignores span durations
+func IgnoreDurations() DiffOption {
+	return func(c *diffConfig) {
+		c.ignoreDurations = true
+	}
+}
+
+// DurationTolerance accepts span durations within tolerance of the expected ones
+func DurationTolerance(tolerance time.Duration) DiffOption {
+	return func(c *diffConfig) {
+		c.durationTolerance = tolerance
+	}
+}
+
+// IgnoreAttributes ignores the given span and event attributes, e.g. ones
+// holding generated IDs or hostnames
+func IgnoreAttributes(keys ...string) DiffOption {
+	return func(c *diffConfig) {
+		for _, key := range keys {
+			c.ignoreAttributes[key] = true
+		}
+	}
+}
+
+// IgnoreEvents ignores span events
+func IgnoreEvents() DiffOption {
+	return func(c *diffConfig) {
+		c.ignoreEvents = true
+	}
+}
+
+// DiffTraces compares two traces span by span and returns a description of
+// the differences, one per line, or an empty string when they match.
+// Attribute values are compared in their JSON form, so values loaded from
+// golden files match the recorded ones.
+func DiffTraces(expected, actual Trace, opts ...DiffOption) string {
+	config := diffConfig{ignoreAttributes: make(map[string]bool)}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	var d differ
+	d.config = config
+	d.spans("", expected.Spans, actual.Spans)
+	return strings.Join(d.diffs, "\n")
+}
+
+// differ accumulates the differences found between two traces
+type differ struct {
+	config diffConfig
+	diffs  []string
+}
+
+func (d *differ) add(path, format string, args ...any) {
+	d.diffs = append(d.diffs, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (d *differ) spans(path string, expected, actual []*Span) {
+	for i := 0; i < max(len(expected), len(actual)); i++ {
+		switch {
+		case i >= len(actual):
+			d.add(spanPath(path, expected[i], i), "missing span")
+		case i >= len(expected):
+			d.add(spanPath(path, actual[i], i), "unexpected span")
+		default:
+			d.span(spanPath(path, expected[i], i), expected[i], actual[i])
+		}
+	}
+}
+
+func (d *differ) span(path string, expected, actual *Span) {
+	if expected.Name != actual.Name {
+		d.add(path, "name: expected %q, got %q", expected.Name, actual.Name)
+	}
+	if expected.Kind != actual.Kind {
+		d.add(path, "kind: expected %q, got %q", expected.Kind, actual.Kind)
+	}
+	if expected.Status != actual.Status {
+		d.add(path, "status: expected %q, got %q", expected.Status, actual.Status)
+	}
+	if !d.config.ignoreDurations {
+		delta := actual.Duration - expected.Duration
+		if delta < -d.config.durationTolerance || delta > d.config.durationTolerance {
+			d.add(path, "duration: expected %s, got %s", expected.Duration, actual.Duration)
+		}
+	}
+	d.attributes(path, expected.Attributes, actual.Attributes)
+	if !d.config.ignoreEvents {
+		d.events(path, expected.Events, actual.Events)
+	}
+	d.spans(path, expected.Children, actual.Children)
+}
+
+func (d *differ) attributes(path string, expected, actual map[string]any) {
+	keys := make(map[string]bool)
+	for k := range expected {
+		keys[k] = true
+	}
+	for k := range actual {
+		keys[k] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for k := range keys {
+		if !d.config.ignoreAttributes[k] {
+			sorted = append(sorted, k)
+		}
+	}
+	sort.Strings(sorted)
+
+	for _, k := range sorted {
+		want, inExpected := expected[k]
+		got, inActual := actual[k]
+		switch {
+		case !inActual:
+			d.add(path, "attribute %s: missing, expected %v", k, want)
+		case !inExpected:
+			d.add(path, "attribute %s: unexpected %v", k, got)
+		case !reflect.DeepEqual(jsonValue(want), jsonValue(got)):
+			d.add(path, "attribute %s: expected %v, got %v", k, want, got)
+		}
+	}
+}
+
+func (d *differ) events(path string, expected, actual []Event) {
+	for i := 0; i < max(len(expected), len(actual)); i++ {
+		switch {
+		case i >= len(actual):
+			d.add(path, "event %d %q: missing", i, expected[i].Name)
+		case i >= len(expected):
+			d.add(path, "event %d %q: unexpected", i, actual[i].Name)
+		default:
+			eventPath := fmt.Sprintf("%s event %d %q", path, i, expected[i].Name)
+			if expected[i].Name != actual[i].Name {
+				d.add(eventPath, "name: expected %q, got %q", expected[i].Name, actual[i].Name)
+			}
+			d.attributes(eventPath, expected[i].Attributes, actual[i].Attributes)
+		}
+	}
+}
+
+// spanPath extends the path of a parent span with the i-th child span
+func spanPath(parent string, span *Span, i int) string {
+	step := fmt.Sprintf("%s[%d]", span.Name, i)
+	if parent == "" {
+		return step
+	}
+	return parent + " > " + step
+}
+
+// jsonValue returns v as decoded from its JSON encoding
+func jsonValue(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
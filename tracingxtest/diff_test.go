@@ -0,0 +1,69 @@
+package tracingxtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTraces(t *testing.T) {
+	expected := func() Trace {
+		return Trace{Spans: []*Span{{
+			Name:       "HTTP GET",
+			Kind:       "server",
+			Attributes: map[string]any{"http.response.status_code": float64(200), "host.name": "a"},
+			Duration:   10 * time.Millisecond,
+			Children: []*Span{{
+				Name:     "SELECT",
+				Events:   []Event{{Name: "log", Attributes: map[string]any{"rows": 3}}},
+				Duration: 5 * time.Millisecond,
+			}},
+		}}}
+	}
+
+	t.Run("equal traces", func(t *testing.T) {
+		actual := expected()
+		actual.Spans[0].Attributes["http.response.status_code"] = int64(200)
+		assert.Empty(t, DiffTraces(expected(), actual))
+	})
+
+	t.Run("reports differences with span paths", func(t *testing.T) {
+		actual := expected()
+		actual.Spans[0].Kind = "client"
+		actual.Spans[0].Attributes["http.response.status_code"] = 500
+		actual.Spans[0].Attributes["extra"] = true
+		delete(actual.Spans[0].Attributes, "host.name")
+		actual.Spans[0].Children[0].Status = "error"
+		actual.Spans[0].Children = append(actual.Spans[0].Children, &Span{Name: "cache"})
+
+		assert.Equal(t, `HTTP GET[0]: kind: expected "server", got "client"
+HTTP GET[0]: attribute extra: unexpected true
+HTTP GET[0]: attribute host.name: missing, expected a
+HTTP GET[0]: attribute http.response.status_code: expected 200, got 500
+HTTP GET[0] > SELECT[0]: status: expected "", got "error"
+HTTP GET[0] > cache[1]: unexpected span`, DiffTraces(expected(), actual))
+	})
+
+	t.Run("reports missing spans and events", func(t *testing.T) {
+		actual := expected()
+		actual.Spans[0].Children[0].Events = nil
+		assert.Equal(t, `HTTP GET[0] > SELECT[0]: event 0 "log": missing`, DiffTraces(expected(), actual))
+
+		actual.Spans[0].Children = nil
+		assert.Equal(t, `HTTP GET[0] > SELECT[0]: missing span`, DiffTraces(expected(), actual))
+	})
+
+	t.Run("tolerances", func(t *testing.T) {
+		actual := expected()
+		actual.Spans[0].Duration = 12 * time.Millisecond
+		actual.Spans[0].Attributes["host.name"] = "b"
+		actual.Spans[0].Children[0].Events[0].Attributes["rows"] = 4
+
+		assert.NotEmpty(t, DiffTraces(expected(), actual, IgnoreAttributes("host.name"), IgnoreEvents()))
+		assert.Empty(t, DiffTraces(expected(), actual, IgnoreAttributes("host.name"), IgnoreEvents(), IgnoreDurations()))
+		assert.Empty(t, DiffTraces(expected(), actual, IgnoreAttributes("host.name", "rows"), DurationTolerance(2*time.Millisecond)))
+		assert.Contains(t, DiffTraces(expected(), actual, IgnoreAttributes("host.name", "rows"), DurationTolerance(time.Millisecond)),
+			"HTTP GET[0]: duration: expected 10ms, got 12ms")
+	})
+}
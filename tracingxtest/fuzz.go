@@ -0,0 +1,82 @@
+package tracingxtest
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TraceContextSeed is one set of trace context header values for fuzzing
+type TraceContextSeed struct {
+	TraceParent string
+	TraceState  string
+	Baggage     string
+}
+
+// TraceContextCorpus returns seeds for fuzzing trace context extraction:
+// valid headers plus the malformed and hostile values clients send, such as
+// wrong lengths, bad versions, zero IDs, oversized or duplicated members and
+// control characters
+func TraceContextCorpus() []TraceContextSeed {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+		valid   = "00-" + traceID + "-" + spanID + "-01"
+	)
+	return []TraceContextSeed{
+		{TraceParent: valid},
+		{TraceParent: valid, TraceState: "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE", Baggage: "tenant=acme,user=42;prop=1"},
+		{TraceParent: "00-" + traceID + "-" + spanID + "-00"},
+		{TraceParent: "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		{TraceParent: "01-" + traceID + "-" + spanID + "-01-future"},
+		{TraceParent: "00-00000000000000000000000000000000-" + spanID + "-01"},
+		{TraceParent: "00-" + traceID + "-0000000000000000-01"},
+		{TraceParent: "00-" + strings.ToUpper(traceID) + "-" + spanID + "-01"},
+		{TraceParent: "00-" + traceID[:31] + "-" + spanID + "-01"},
+		{TraceParent: "00_" + traceID + "_" + spanID + "_01"},
+		{TraceParent: " " + valid + " "},
+		{TraceParent: valid + "\x00"},
+		{TraceParent: "00-" + traceID + "-" + spanID + "-zz"},
+		{TraceParent: "-"},
+		{TraceParent: ""},
+		{TraceParent: valid, TraceState: strings.Repeat("k=v,", 64)},
+		{TraceParent: valid, TraceState: "=,,=,key=\x7f"},
+		{TraceParent: valid, TraceState: "dup=1,dup=2"},
+		{TraceParent: valid, Baggage: strings.Repeat("k=v,", 512)},
+		{TraceParent: valid, Baggage: "key=" + strings.Repeat("v", 8192)},
+		{TraceParent: valid, Baggage: "=value,key=,;;;"},
+		{TraceParent: valid, Baggage: "key=%zz,other=%E2%82"},
+		{TraceParent: valid, Baggage: "timeout_budget_ms=-5"},
+		{TraceParent: valid, Baggage: "timeout_budget_ms=99999999999999999999"},
+		{Baggage: "tenant=acme"},
+	}
+}
+
+// AddTraceContextCorpus adds TraceContextCorpus to f; the fuzz target takes
+// the traceparent, tracestate and baggage values as its string arguments
+//
+//	func FuzzHandler(f *testing.F) {
+//		tracingxtest.AddTraceContextCorpus(f)
+//		f.Fuzz(func(t *testing.T, traceParent, traceState, baggage string) {
+//			req := httptest.NewRequest(http.MethodGet, "/", nil)
+//			req.Header = tracingxtest.TraceContextHeaders(traceParent, traceState, baggage)
+//			handler.ServeHTTP(httptest.NewRecorder(), req)
+//		})
+//	}
+func AddTraceContextCorpus(f *testing.F) {
+	for _, seed := range TraceContextCorpus() {
+		f.Add(seed.TraceParent, seed.TraceState, seed.Baggage)
+	}
+}
+
+// TraceContextHeaders returns HTTP headers carrying the given trace context
+// values; empty values are left out
+func TraceContextHeaders(traceParent, traceState, baggage string) http.Header {
+	h := http.Header{}
+	for name, value := range map[string]string{"Traceparent": traceParent, "Tracestate": traceState, "Baggage": baggage} {
+		if value != "" {
+			h[name] = []string{value}
+		}
+	}
+	return h
+}
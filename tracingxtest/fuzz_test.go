@@ -0,0 +1,20 @@
+package tracingxtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceContextCorpus(t *testing.T) {
+	corpus := TraceContextCorpus()
+	assert.NotEmpty(t, corpus)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", corpus[0].TraceParent)
+}
+
+func TestTraceContextHeaders(t *testing.T) {
+	h := TraceContextHeaders("00-abc", "", "k=v")
+	assert.Equal(t, "00-abc", h.Get("traceparent"))
+	assert.Equal(t, "k=v", h.Get("baggage"))
+	assert.NotContains(t, h, "Tracestate")
+}
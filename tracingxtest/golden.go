@@ -0,0 +1,64 @@
+package tracingxtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// UpdateGoldenEnv is the environment variable that makes AssertGolden
+// rewrite golden files with the actual traces instead of comparing them
+const UpdateGoldenEnv = "TRACINGX_UPDATE_GOLDEN"
+
+// SaveGolden writes trace to the golden file at path as indented JSON,
+// creating its directory if needed
+func SaveGolden(path string, trace Trace) error {
+	data, err := json.MarshalIndent(trace, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode golden trace: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create golden directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write golden trace: %w", err)
+	}
+	return nil
+}
+
+// LoadGolden reads a trace written by SaveGolden
+func LoadGolden(path string) (Trace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Trace{}, fmt.Errorf("failed to read golden trace: %w", err)
+	}
+	var trace Trace
+	if err := json.Unmarshal(data, &trace); err != nil {
+		return Trace{}, fmt.Errorf("failed to decode golden trace %s: %w", path, err)
+	}
+	return trace, nil
+}
+
+// AssertGolden compares actual with the golden file at path and fails t
+// with the differences. When UpdateGoldenEnv is set, the golden file is
+// written from actual instead:
+//
+//	TRACINGX_UPDATE_GOLDEN=1 go test ./...
+func AssertGolden(t testing.TB, path string, actual Trace, opts ...DiffOption) {
+	t.Helper()
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := SaveGolden(path, actual); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	expected, err := LoadGolden(path)
+	if err != nil {
+		t.Fatalf("%v (set %s=1 to create it)", err, UpdateGoldenEnv)
+	}
+	if diff := DiffTraces(expected, actual, opts...); diff != "" {
+		t.Errorf("trace differs from golden file %s:\n%s", path, diff)
+	}
+}
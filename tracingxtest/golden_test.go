@@ -0,0 +1,38 @@
+package tracingxtest
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGolden(t *testing.T) {
+	actual := Traces(recordCheckout(t)...)[0]
+
+	t.Run("matches the checked-in golden file", func(t *testing.T) {
+		AssertGolden(t, filepath.Join("testdata", "checkout.json"), actual, IgnoreAttributes("request.id"))
+	})
+
+	t.Run("round-trips through save and load", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "nested", "trace.json")
+		require.NoError(t, SaveGolden(path, actual))
+		loaded, err := LoadGolden(path)
+		require.NoError(t, err)
+		assert.Empty(t, DiffTraces(loaded, actual))
+	})
+
+	t.Run("updates golden files on request", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "trace.json")
+		t.Setenv(UpdateGoldenEnv, "1")
+		AssertGolden(t, path, actual)
+		_, err := LoadGolden(path)
+		assert.NoError(t, err)
+	})
+
+	t.Run("missing golden file", func(t *testing.T) {
+		_, err := LoadGolden(filepath.Join(t.TempDir(), "missing.json"))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,205 @@
+package tracingxtest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	"github.com/gostratum/tracingx"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Recorder is a tracingx provider recording spans in memory, so tests can
+// assert which spans the code under test created. Spans go through the real
+// tracingx pipeline with every trace sampled; only the exporter is replaced.
+// Pass it wherever a tracingx.Tracer or tracingx.Provider is expected.
+type Recorder struct {
+	tracingx.Provider
+	exporter *memoryExporter
+
+	mu      sync.Mutex
+	started []tracingx.Span
+}
+
+// RecordedSpan is an ended span recorded by a Recorder
+type RecordedSpan struct {
+	Name         string
+	TraceID      string
+	SpanID       string
+	ParentSpanID string
+	Kind         string
+	Status       string
+	Attributes   map[string]any
+	Events       []Event
+	StartTime    time.Time
+	EndTime      time.Time
+}
+
+// Attribute returns the value of the span attribute key
+func (s RecordedSpan) Attribute(key string) (any, bool) {
+	v, ok := s.Attributes[key]
+	return v, ok
+}
+
+// Duration returns the time between the span's start and end
+func (s RecordedSpan) Duration() time.Duration {
+	return s.EndTime.Sub(s.StartTime)
+}
+
+// IsRoot reports whether the span has no parent
+func (s RecordedSpan) IsRoot() bool {
+	return s.ParentSpanID == ""
+}
+
+// NewRecorder creates a recorder sampling every trace. Call Shutdown when
+// done with it.
+func NewRecorder() *Recorder {
+	exporter := &memoryExporter{}
+	result, err := tracingx.NewTracer(tracingx.Params{
+		Config: tracingx.Config{
+			Enabled:     true,
+			Provider:    "otlp",
+			ServiceName: "tracingxtest",
+			SampleRate:  1.0,
+		},
+		Logger:   logx.NewNoopLogger(),
+		Exporter: exporter,
+	})
+	if err != nil {
+		// Unreachable: the configuration above is valid
+		panic("tracingxtest: failed to create recorder: " + err.Error())
+	}
+	return &Recorder{Provider: result.Provider, exporter: exporter}
+}
+
+// Start starts a span, recording it as started
+func (r *Recorder) Start(ctx context.Context, operationName string, opts ...tracingx.SpanOption) (context.Context, tracingx.Span) {
+	ctx, span := r.Provider.Start(ctx, operationName, opts...)
+	r.mu.Lock()
+	r.started = append(r.started, span)
+	r.mu.Unlock()
+	return ctx, span
+}
+
+// Started returns the spans started through Start, ended or not, in start order
+func (r *Recorder) Started() []tracingx.Span {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]tracingx.Span(nil), r.started...)
+}
+
+// RecordedSpans returns the ended spans in the order they ended
+func (r *Recorder) RecordedSpans() []RecordedSpan {
+	spans := r.ended()
+	recorded := make([]RecordedSpan, len(spans))
+	for i, s := range spans {
+		recorded[i] = recordedSpan(s)
+	}
+	return recorded
+}
+
+// Span returns the first ended span named name
+func (r *Recorder) Span(name string) (RecordedSpan, bool) {
+	for _, s := range r.RecordedSpans() {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return RecordedSpan{}, false
+}
+
+// Parent returns the ended parent of span
+func (r *Recorder) Parent(span RecordedSpan) (RecordedSpan, bool) {
+	if span.IsRoot() {
+		return RecordedSpan{}, false
+	}
+	for _, s := range r.RecordedSpans() {
+		if s.TraceID == span.TraceID && s.SpanID == span.ParentSpanID {
+			return s, true
+		}
+	}
+	return RecordedSpan{}, false
+}
+
+// Children returns the ended children of span in the order they ended
+func (r *Recorder) Children(span RecordedSpan) []RecordedSpan {
+	var children []RecordedSpan
+	for _, s := range r.RecordedSpans() {
+		if s.TraceID == span.TraceID && s.ParentSpanID == span.SpanID {
+			children = append(children, s)
+		}
+	}
+	return children
+}
+
+// Traces groups the ended spans into traces, for comparison with DiffTraces
+// or AssertGolden
+func (r *Recorder) Traces() []Trace {
+	return Traces(r.ended()...)
+}
+
+// Reset forgets the spans recorded so far
+func (r *Recorder) Reset() {
+	_ = r.ForceFlush(context.Background())
+	r.mu.Lock()
+	r.started = nil
+	r.mu.Unlock()
+	r.exporter.reset()
+}
+
+// ended flushes the pipeline and returns the exported spans
+func (r *Recorder) ended() []sdktrace.ReadOnlySpan {
+	_ = r.ForceFlush(context.Background())
+	return r.exporter.snapshot()
+}
+
+// recordedSpan converts an exported span
+func recordedSpan(s sdktrace.ReadOnlySpan) RecordedSpan {
+	span := RecordedSpan{
+		Name:       s.Name(),
+		TraceID:    s.SpanContext().TraceID().String(),
+		SpanID:     s.SpanContext().SpanID().String(),
+		Kind:       s.SpanKind().String(),
+		Status:     statusString(s.Status().Code),
+		Attributes: attributeMap(s.Attributes()),
+		Events:     events(s.Events()),
+		StartTime:  s.StartTime(),
+		EndTime:    s.EndTime(),
+	}
+	if s.Parent().IsValid() {
+		span.ParentSpanID = s.Parent().SpanID().String()
+	}
+	return span
+}
+
+// memoryExporter keeps exported spans in memory. Unlike
+// tracetest.InMemoryExporter it keeps them on Shutdown, so spans stay
+// readable after the recorder is shut down.
+type memoryExporter struct {
+	mu    sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (e *memoryExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(context.Context) error { return nil }
+
+// snapshot returns the exported spans
+func (e *memoryExporter) snapshot() []sdktrace.ReadOnlySpan {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]sdktrace.ReadOnlySpan(nil), e.spans...)
+}
+
+// reset forgets the exported spans
+func (e *memoryExporter) reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
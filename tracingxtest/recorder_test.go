@@ -0,0 +1,74 @@
+package tracingxtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/gostratum/tracingx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder := NewRecorder()
+	defer recorder.Shutdown(context.Background())
+
+	// The code under test only sees a tracingx.Tracer
+	var tracer tracingx.Tracer = recorder
+	ctx, root := tracer.Start(context.Background(), "checkout", tracingx.WithSpanKind(tracingx.SpanKindServer))
+	_, charge := tracer.Start(ctx, "charge", tracingx.WithAttributes(map[string]any{"payment.id": "pay_1"}))
+	charge.SetError(errors.New("declined"))
+	charge.End()
+	_, open := tracer.Start(ctx, "notify")
+
+	assert.Len(t, recorder.Started(), 3)
+	assert.Len(t, recorder.RecordedSpans(), 1, "only ended spans are recorded")
+
+	root.End()
+	spans := recorder.RecordedSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "charge", spans[0].Name)
+	assert.Equal(t, "checkout", spans[1].Name)
+
+	got, ok := recorder.Span("charge")
+	require.True(t, ok)
+	id, ok := got.Attribute("payment.id")
+	assert.True(t, ok)
+	assert.Equal(t, "pay_1", id)
+	assert.Equal(t, "internal", got.Kind)
+	assert.Equal(t, charge.SpanID(), got.SpanID)
+	assert.Equal(t, charge.TraceID(), got.TraceID)
+	assert.False(t, got.IsRoot())
+
+	parent, ok := recorder.Parent(got)
+	require.True(t, ok)
+	assert.Equal(t, "checkout", parent.Name)
+	assert.Equal(t, "server", parent.Kind)
+	assert.True(t, parent.IsRoot())
+	_, ok = recorder.Parent(parent)
+	assert.False(t, ok)
+	assert.Equal(t, []RecordedSpan{got}, recorder.Children(parent))
+
+	open.End()
+	assert.Len(t, recorder.Children(parent), 2)
+	traces := recorder.Traces()
+	require.Len(t, traces, 1)
+	assert.NotNil(t, traces[0].Find("notify"))
+
+	_, ok = recorder.Span("refund")
+	assert.False(t, ok)
+
+	recorder.Reset()
+	assert.Empty(t, recorder.Started())
+	assert.Empty(t, recorder.RecordedSpans())
+}
+
+func TestRecorderKeepsSpansAfterShutdown(t *testing.T) {
+	recorder := NewRecorder()
+	_, span := recorder.Start(context.Background(), "work")
+	span.End()
+	require.NoError(t, recorder.Shutdown(context.Background()))
+
+	assert.Len(t, recorder.RecordedSpans(), 1)
+}
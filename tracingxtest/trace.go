@@ -0,0 +1,181 @@
+// Package tracingxtest provides helpers for testing code instrumented with
+// tracingx: a comparable model of recorded traces, structural diffs with
+// tolerances, and golden files.
+package tracingxtest
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Trace is a recorded trace: its local root spans, each with its children.
+// IDs and timestamps are left out, so traces recorded by different runs of
+// the same code compare equal.
+type Trace struct {
+	Spans []*Span `json:"spans"`
+}
+
+// Span is a recorded span
+type Span struct {
+	Name       string         `json:"name"`
+	Kind       string         `json:"kind,omitempty"`
+	Status     string         `json:"status,omitempty"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+	Events     []Event        `json:"events,omitempty"`
+	Duration   time.Duration  `json:"duration,omitempty"`
+	Children   []*Span        `json:"children,omitempty"`
+}
+
+// Event is an event recorded on a span
+type Event struct {
+	Name       string         `json:"name"`
+	Attributes map[string]any `json:"attributes,omitempty"`
+}
+
+// Traces groups spans, e.g. from a tracetest.SpanRecorder, into traces in
+// the order they started. Children are ordered by name, then start time, so
+// spans started concurrently compare deterministically.
+func Traces(spans ...sdktrace.ReadOnlySpan) []Trace {
+	records := make([]record, len(spans))
+	for i, s := range spans {
+		records[i] = record{
+			traceID: s.SpanContext().TraceID().String(),
+			spanID:  s.SpanContext().SpanID().String(),
+			parent:  s.Parent().SpanID().String(),
+			start:   s.StartTime(),
+			span: &Span{
+				Name:       s.Name(),
+				Kind:       kindString(s.SpanKind()),
+				Status:     statusString(s.Status().Code),
+				Attributes: attributeMap(s.Attributes()),
+				Events:     events(s.Events()),
+				Duration:   s.EndTime().Sub(s.StartTime()),
+			},
+		}
+	}
+	return buildTraces(records)
+}
+
+// record is a span with the identifiers its trace is rebuilt from
+type record struct {
+	traceID string
+	spanID  string
+	parent  string
+	start   time.Time
+	span    *Span
+}
+
+// buildTraces links records to their parents and groups them by trace
+func buildTraces(records []record) []Trace {
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].span.Name != records[j].span.Name {
+			return records[i].span.Name < records[j].span.Name
+		}
+		return records[i].start.Before(records[j].start)
+	})
+
+	bySpanID := make(map[string]*record, len(records))
+	for i := range records {
+		bySpanID[records[i].traceID+"/"+records[i].spanID] = &records[i]
+	}
+
+	type group struct {
+		start time.Time
+		trace Trace
+	}
+	groups := make(map[string]*group)
+	var order []*group
+	for i := range records {
+		r := &records[i]
+		if parent, ok := bySpanID[r.traceID+"/"+r.parent]; ok {
+			parent.span.Children = append(parent.span.Children, r.span)
+			continue
+		}
+		g, ok := groups[r.traceID]
+		if !ok {
+			g = &group{start: r.start}
+			groups[r.traceID] = g
+			order = append(order, g)
+		}
+		if r.start.Before(g.start) {
+			g.start = r.start
+		}
+		g.trace.Spans = append(g.trace.Spans, r.span)
+	}
+
+	sort.SliceStable(order, func(i, j int) bool { return order[i].start.Before(order[j].start) })
+	traces := make([]Trace, len(order))
+	for i, g := range order {
+		traces[i] = g.trace
+	}
+	return traces
+}
+
+// Find returns the first span named name in the trace, depth first, or nil
+func (t Trace) Find(name string) *Span {
+	for _, s := range t.Spans {
+		if found := s.Find(name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// Find returns s or the first descendant named name, depth first, or nil
+func (s *Span) Find(name string) *Span {
+	if s.Name == name {
+		return s
+	}
+	for _, child := range s.Children {
+		if found := child.Find(name); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// kindString returns the lower-case name of a span kind, empty for internal spans
+func kindString(kind trace.SpanKind) string {
+	if kind == trace.SpanKindInternal || kind == trace.SpanKindUnspecified {
+		return ""
+	}
+	return kind.String()
+}
+
+// statusString returns the lower-case name of a status code, empty when unset
+func statusString(code codes.Code) string {
+	if code == codes.Unset {
+		return ""
+	}
+	return strings.ToLower(code.String())
+}
+
+// attributeMap converts attributes to a map, nil when there are none
+func attributeMap(attrs []attribute.KeyValue) map[string]any {
+	if len(attrs) == 0 {
+		return nil
+	}
+	m := make(map[string]any, len(attrs))
+	for _, kv := range attrs {
+		m[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return m
+}
+
+// events converts span events
+func events(in []sdktrace.Event) []Event {
+	if len(in) == 0 {
+		return nil
+	}
+	out := make([]Event, len(in))
+	for i, e := range in {
+		out[i] = Event{Name: e.Name, Attributes: attributeMap(e.Attributes)}
+	}
+	return out
+}
@@ -0,0 +1,86 @@
+package tracingxtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordCheckout records a checkout request trace and returns the ended spans
+func recordCheckout(t *testing.T) []sdktrace.ReadOnlySpan {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { provider.Shutdown(context.Background()) })
+	tracer := provider.Tracer("test")
+
+	start := time.Unix(1700000000, 0)
+	ctx, root := tracer.Start(context.Background(), "HTTP POST",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(attribute.String("http.request.method", "POST"), attribute.String("request.id", "r-1")),
+	)
+	_, charge := tracer.Start(ctx, "charge card", trace.WithTimestamp(start.Add(time.Millisecond)))
+	charge.AddEvent("retry", trace.WithAttributes(attribute.Int("retry.attempt", 1)))
+	charge.SetStatus(codes.Error, "declined")
+	charge.End(trace.WithTimestamp(start.Add(21 * time.Millisecond)))
+	_, query := tracer.Start(ctx, "SELECT orders", trace.WithSpanKind(trace.SpanKindClient), trace.WithTimestamp(start.Add(time.Millisecond)))
+	query.SetAttributes(attribute.Int64("db.rows", 3))
+	query.End(trace.WithTimestamp(start.Add(6 * time.Millisecond)))
+	root.End(trace.WithTimestamp(start.Add(30 * time.Millisecond)))
+
+	return recorder.Ended()
+}
+
+func TestTraces(t *testing.T) {
+	traces := Traces(recordCheckout(t)...)
+	require.Len(t, traces, 1)
+	require.Len(t, traces[0].Spans, 1)
+
+	root := traces[0].Spans[0]
+	assert.Equal(t, "HTTP POST", root.Name)
+	assert.Equal(t, "server", root.Kind)
+	assert.Equal(t, 30*time.Millisecond, root.Duration)
+	assert.Equal(t, "POST", root.Attributes["http.request.method"])
+
+	// Children are ordered by name
+	require.Len(t, root.Children, 2)
+	assert.Equal(t, "SELECT orders", root.Children[0].Name)
+	assert.Equal(t, "client", root.Children[0].Kind)
+
+	charge := traces[0].Find("charge card")
+	require.NotNil(t, charge)
+	assert.Equal(t, "error", charge.Status)
+	assert.Empty(t, charge.Kind)
+	require.Len(t, charge.Events, 1)
+	assert.Equal(t, "retry", charge.Events[0].Name)
+	assert.Equal(t, int64(1), charge.Events[0].Attributes["retry.attempt"])
+
+	assert.Nil(t, traces[0].Find("missing"))
+}
+
+func TestTracesGroupsByTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defer provider.Shutdown(context.Background())
+	tracer := provider.Tracer("test")
+
+	start := time.Unix(1700000000, 0)
+	_, second := tracer.Start(context.Background(), "second", trace.WithTimestamp(start.Add(time.Second)))
+	second.End()
+	_, first := tracer.Start(context.Background(), "first", trace.WithTimestamp(start))
+	first.End()
+
+	traces := Traces(recorder.Ended()...)
+	require.Len(t, traces, 2)
+	assert.Equal(t, "first", traces[0].Spans[0].Name)
+	assert.Equal(t, "second", traces[1].Spans[0].Name)
+}
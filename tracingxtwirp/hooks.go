@@ -0,0 +1,194 @@
+// Package tracingxtwirp traces Twirp RPCs (github.com/twitchtv/twirp) with
+// tracingx.
+package tracingxtwirp
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gostratum/tracingx"
+	"github.com/twitchtv/twirp"
+)
+
+// Attribute keys and values from the OpenTelemetry RPC semantic conventions,
+// plus the Twirp package and error code
+const (
+	rpcSystemKey         = "rpc.system"
+	rpcServiceKey        = "rpc.service"
+	rpcMethodKey         = "rpc.method"
+	rpcTwirpPackageKey   = "rpc.twirp.package"
+	rpcTwirpErrorCodeKey = "rpc.twirp.error_code"
+	serverAddressKey     = "server.address"
+
+	rpcSystemTwirp = "twirp"
+)
+
+// call is the state of an RPC, shared by its hooks through the context
+type call struct {
+	start time.Time
+	span  tracingx.Span
+}
+
+type serverCallKey struct{}
+
+type clientCallKey struct{}
+
+// Handler extracts the trace context of incoming requests into their
+// context, so the spans of the server hooks are parented to the caller.
+// Services already behind the tracingx HTTP middleware do not need it.
+//
+//	server := example.NewHaberdasherServer(svc,
+//		twirp.WithServerHooks(tracingxtwirp.NewServerHooks(tracer)))
+//	http.Handle(server.PathPrefix(), tracingxtwirp.Handler(tracer, server))
+func Handler(tracer tracingx.Tracer, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		base := tracingx.ContextWithPeerAddress(r.Context(), r.RemoteAddr)
+		ctx, err := tracer.Extract(base, tracingx.HeaderCarrier(r.Header))
+		if err != nil {
+			ctx = base
+		}
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// NewServerHooks returns server hooks running each call in a server span
+// named pkg.Service/Method, carrying the rpc.* attributes and the Twirp
+// error code. Spans are errored only for codes that indicate a server
+// fault, such as internal and unavailable. Combine them with other hooks
+// using twirp.ChainHooks.
+func NewServerHooks(tracer tracingx.Tracer) *twirp.ServerHooks {
+	return &twirp.ServerHooks{
+		RequestReceived: func(ctx context.Context) (context.Context, error) {
+			if tracingx.IsInstrumentationSuppressed(ctx) {
+				return ctx, nil
+			}
+			return context.WithValue(ctx, serverCallKey{}, &call{start: time.Now()}), nil
+		},
+		RequestRouted: func(ctx context.Context) (context.Context, error) {
+			c, ok := ctx.Value(serverCallKey{}).(*call)
+			if !ok {
+				return ctx, nil
+			}
+			return startServerSpan(ctx, tracer, c), nil
+		},
+		Error: func(ctx context.Context, err twirp.Error) context.Context {
+			c, ok := ctx.Value(serverCallKey{}).(*call)
+			if !ok {
+				return ctx
+			}
+			if c.span == nil {
+				// the request was rejected before it was routed
+				ctx = startServerSpan(ctx, tracer, c)
+			}
+			c.span.SetTagString(rpcTwirpErrorCodeKey, string(err.Code()))
+			if serverFault(err.Code()) {
+				c.span.SetError(err)
+			}
+			return ctx
+		},
+		ResponseSent: func(ctx context.Context) {
+			c, ok := ctx.Value(serverCallKey{}).(*call)
+			if !ok || c.span == nil {
+				return
+			}
+			tracingx.RecordCancellation(ctx, c.span)
+			c.span.End()
+		},
+	}
+}
+
+// startServerSpan starts the server span of c, backdated to when the
+// request was received
+func startServerSpan(ctx context.Context, tracer tracingx.Tracer, c *call) context.Context {
+	name, attrs := describe(ctx)
+	ctx, c.span = tracer.Start(ctx, name,
+		tracingx.WithSpanKind(tracingx.SpanKindServer),
+		tracingx.WithTimestamp(c.start),
+		tracingx.WithAttributes(attrs),
+	)
+	tracingx.RecordBudget(ctx, c.span)
+	return ctx
+}
+
+// NewClientHooks returns client hooks running each call in a client span
+// and propagating its trace context in the request headers. Spans are named
+// and annotated like the server ones, and errored for any error. Combine
+// them with other hooks using twirp.ChainClientHooks.
+//
+//	client := example.NewHaberdasherProtobufClient(url, http.DefaultClient,
+//		twirp.WithClientHooks(tracingxtwirp.NewClientHooks(tracer)))
+func NewClientHooks(tracer tracingx.Tracer) *twirp.ClientHooks {
+	return &twirp.ClientHooks{
+		RequestPrepared: func(ctx context.Context, req *http.Request) (context.Context, error) {
+			if tracingx.IsInstrumentationSuppressed(ctx) {
+				return ctx, nil
+			}
+			name, attrs := describe(ctx)
+			attrs[serverAddressKey] = req.URL.Host
+
+			c := &call{}
+			ctx, c.span = tracer.Start(ctx, name,
+				tracingx.WithSpanKind(tracingx.SpanKindClient),
+				tracingx.WithAttributes(attrs),
+			)
+			if err := tracer.Inject(ctx, tracingx.HeaderCarrier(req.Header)); err != nil {
+				c.span.LogFields(
+					tracingx.String("event", "inject_failed"),
+					tracingx.Err(err),
+				)
+			}
+			return context.WithValue(ctx, clientCallKey{}, c), nil
+		},
+		ResponseReceived: func(ctx context.Context) {
+			if c, ok := ctx.Value(clientCallKey{}).(*call); ok {
+				c.span.End()
+			}
+		},
+		Error: func(ctx context.Context, err twirp.Error) {
+			c, ok := ctx.Value(clientCallKey{}).(*call)
+			if !ok {
+				// the request failed before it was prepared
+				return
+			}
+			c.span.SetTagString(rpcTwirpErrorCodeKey, string(err.Code()))
+			c.span.SetError(err)
+			c.span.End()
+		},
+	}
+}
+
+// describe returns the span name and attributes of the call in ctx
+func describe(ctx context.Context) (string, map[string]any) {
+	pkg, _ := twirp.PackageName(ctx)
+	service, _ := twirp.ServiceName(ctx)
+	method, _ := twirp.MethodName(ctx)
+	if pkg != "" {
+		service = pkg + "." + service
+	}
+
+	attrs := map[string]any{
+		rpcSystemKey:  rpcSystemTwirp,
+		rpcServiceKey: service,
+	}
+	if pkg != "" {
+		attrs[rpcTwirpPackageKey] = pkg
+	}
+	if method == "" {
+		return service, attrs
+	}
+	attrs[rpcMethodKey] = method
+	return service + "/" + method, attrs
+}
+
+// serverFault reports whether an error code returned by a handler
+// indicates a server fault rather than a caller error
+func serverFault(code twirp.ErrorCode) bool {
+	switch code {
+	case twirp.Unknown, twirp.DeadlineExceeded, twirp.Unimplemented,
+		twirp.Internal, twirp.Unavailable, twirp.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,121 @@
+package tracingxtwirp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gostratum/tracingx/tracingxtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/twitchtv/twirp"
+	"github.com/twitchtv/twirp/example"
+)
+
+// haberdasher makes hats, failing with err when set
+type haberdasher struct {
+	err error
+}
+
+func (h haberdasher) MakeHat(ctx context.Context, size *example.Size) (*example.Hat, error) {
+	if h.err != nil {
+		return nil, h.err
+	}
+	return &example.Hat{Size: size.Inches, Color: "blue", Name: "fedora"}, nil
+}
+
+// roundTrip serves svc with the server hooks and calls it through a client
+// with the client hooks, returning the ended client and server spans
+func roundTrip(t *testing.T, svc example.Haberdasher) (client, server *tracingxtest.Span, err error) {
+	t.Helper()
+	collector := tracingxtest.StartCollector(t)
+	provider := tracingxtest.NewProvider(t, collector)
+
+	handler := example.NewHaberdasherServer(svc, twirp.WithServerHooks(NewServerHooks(provider)))
+	srv := httptest.NewServer(Handler(provider, handler))
+	defer srv.Close()
+
+	c := example.NewHaberdasherProtobufClient(srv.URL, http.DefaultClient,
+		twirp.WithClientHooks(NewClientHooks(provider)))
+	_, err = c.MakeHat(context.Background(), &example.Size{Inches: 7})
+
+	require.NoError(t, provider.ForceFlush(context.Background()))
+	collector.WaitForSpans(t, 2, time.Second)
+	traces := collector.Traces()
+	require.Len(t, traces, 1)
+	require.Len(t, traces[0].Spans, 1)
+	client = traces[0].Spans[0]
+	require.Len(t, client.Children, 1)
+	return client, client.Children[0], err
+}
+
+func TestHooks(t *testing.T) {
+	t.Run("traces both sides of a call", func(t *testing.T) {
+		client, server, err := roundTrip(t, haberdasher{})
+		require.NoError(t, err)
+
+		assert.Equal(t, "twitch.twirp.example.Haberdasher/MakeHat", client.Name)
+		assert.Equal(t, "client", client.Kind)
+		assert.Equal(t, "twitch.twirp.example.Haberdasher/MakeHat", server.Name)
+		assert.Equal(t, "server", server.Kind)
+		for _, span := range []*tracingxtest.Span{client, server} {
+			assert.Equal(t, "twirp", span.Attributes[rpcSystemKey])
+			assert.Equal(t, "twitch.twirp.example.Haberdasher", span.Attributes[rpcServiceKey])
+			assert.Equal(t, "MakeHat", span.Attributes[rpcMethodKey])
+			assert.Equal(t, "twitch.twirp.example", span.Attributes[rpcTwirpPackageKey])
+			assert.NotContains(t, span.Attributes, rpcTwirpErrorCodeKey)
+			assert.NotContains(t, span.Attributes, "error")
+		}
+		assert.Contains(t, client.Attributes, serverAddressKey)
+	})
+
+	t.Run("errors the server span only for server faults", func(t *testing.T) {
+		for _, tc := range []struct {
+			code        twirp.ErrorCode
+			serverError bool
+		}{
+			{twirp.InvalidArgument, false},
+			{twirp.NotFound, false},
+			{twirp.Internal, true},
+			{twirp.Unavailable, true},
+		} {
+			t.Run(string(tc.code), func(t *testing.T) {
+				client, server, err := roundTrip(t, haberdasher{err: twirp.NewError(tc.code, "boom")})
+				require.Error(t, err)
+
+				assert.Equal(t, string(tc.code), client.Attributes[rpcTwirpErrorCodeKey])
+				assert.Equal(t, string(tc.code), server.Attributes[rpcTwirpErrorCodeKey])
+				assert.Equal(t, true, client.Attributes["error"])
+				if tc.serverError {
+					assert.Equal(t, true, server.Attributes["error"])
+				} else {
+					assert.NotContains(t, server.Attributes, "error")
+				}
+			})
+		}
+	})
+
+	t.Run("traces requests rejected before routing", func(t *testing.T) {
+		collector := tracingxtest.StartCollector(t)
+		provider := tracingxtest.NewProvider(t, collector)
+		handler := example.NewHaberdasherServer(haberdasher{}, twirp.WithServerHooks(NewServerHooks(provider)))
+		srv := httptest.NewServer(Handler(provider, handler))
+		defer srv.Close()
+
+		resp, err := http.Post(srv.URL+example.HaberdasherPathPrefix+"MakeShoe", "application/json", nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		require.NoError(t, provider.ForceFlush(context.Background()))
+		spans := collector.WaitForSpans(t, 1, time.Second)
+		require.Len(t, spans, 1)
+		traces := collector.Traces()
+		server := traces[0].Spans[0]
+		assert.Equal(t, "twitch.twirp.example.Haberdasher", server.Name)
+		assert.Equal(t, "bad_route", server.Attributes[rpcTwirpErrorCodeKey])
+		assert.NotContains(t, server.Attributes, "error")
+	})
+}
@@ -0,0 +1,242 @@
+package tracingx
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// paymentGatewayAttributes are the attributes recorded on payment provider
+// calls: none of them can carry cardholder data
+var paymentGatewayAttributes = []string{
+	httpRequestMethodKey,
+	httpResponseStatusCodeKey,
+	urlFullKey,
+	serverAddressKey,
+	serverPortKey,
+	peerServiceKey,
+	httpResponseBodySizeKey,
+	httpRequestResendCountKey,
+	RetryAttemptAttribute,
+	RetryMaxAttribute,
+	RetryBackoffMsAttribute,
+	BudgetAttribute,
+	SamplingPriorityAttribute,
+}
+
+// WithPaymentGatewayPreset configures the transport for calls to payment
+// providers under PCI DSS constraints: URLs are recorded by WithStrictURLs,
+// every call is sampled, and only the method, status code, URL, host,
+// response size, retry and budget attributes are recorded, so no header or
+// query parameter can leak cardholder data; errors are recorded with their
+// type only. Request and response bodies are never captured by the
+// transport.
+//
+//	client := &http.Client{
+//		Transport: tracingx.NewTransport(tracer, nil, tracingx.WithPaymentGatewayPreset()),
+//	}
+func WithPaymentGatewayPreset() TransportOption {
+	return func(c *transportConfig) {
+		WithStrictURLs()(c)
+		WithForceSampling()(c)
+		WithAttributeAllowlist(paymentGatewayAttributes...)(c)
+	}
+}
+
+// url returns the recorded form of u
+func (c transportConfig) url(u *url.URL) string {
+	if !c.strictURLs {
+		return u.Redacted()
+	}
+	return strictURL(u)
+}
+
+// apiVersionSegment matches path segments such as v1 kept by strictURL
+var apiVersionSegment = regexp.MustCompile(`^v[0-9]+$`)
+
+// strictURL returns u without query, fragment and credentials, with path
+// segments containing digits, other than API versions, replaced by {id}
+func strictURL(u *url.URL) string {
+	segments := strings.Split(u.EscapedPath(), "/")
+	for i, segment := range segments {
+		if strings.ContainsAny(segment, "0123456789") && !apiVersionSegment.MatchString(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	return u.Scheme + "://" + u.Host + strings.Join(segments, "/")
+}
+
+// sanitizeError strips the URL from errors returned by the base transport
+// when URLs are recorded strictly
+func (c transportConfig) sanitizeError(err error) error {
+	var urlErr *url.Error
+	if !c.strictURLs || !errors.As(err, &urlErr) {
+		return err
+	}
+	sanitized := *urlErr
+	if u, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+		sanitized.URL = strictURL(u)
+	} else {
+		sanitized.URL = ""
+	}
+	return &sanitized
+}
+
+// withAttributeAllowlist limits the attributes recorded when the span
+// starts, including those added by the provider such as attribute profiles,
+// peer.service, deployment and correlation attributes, to allowed
+func withAttributeAllowlist(allowed map[string]bool) SpanOption {
+	return func(c *SpanConfig) {
+		c.allowlist = allowed
+	}
+}
+
+// allowlistAttributes returns the attributes of attrs whose keys are allowed
+func allowlistAttributes(attrs []attribute.KeyValue, allowed map[string]bool) []attribute.KeyValue {
+	kept := attrs[:0]
+	for _, kv := range attrs {
+		if allowed[string(kv.Key)] {
+			kept = append(kept, kv)
+		}
+	}
+	return kept
+}
+
+// wrap returns span restricted to the allowlisted attributes
+func (c transportConfig) wrap(span Span) Span {
+	if c.allowlist == nil {
+		return span
+	}
+	return &allowlistSpan{Span: span, allowed: c.allowlist}
+}
+
+// allowlistSpan drops tags, event fields and error details whose keys are
+// not allowed. Errors keep their type, and the span its error marker; their
+// message and stack trace are recorded only when exception.message and
+// exception.stacktrace are allowed.
+type allowlistSpan struct {
+	Span
+	allowed map[string]bool
+}
+
+func (s *allowlistSpan) SetTag(key string, value any) {
+	if s.allowed[key] {
+		s.Span.SetTag(key, value)
+	}
+}
+
+func (s *allowlistSpan) SetTagIfAbsent(key string, value any) bool {
+	return s.allowed[key] && s.Span.SetTagIfAbsent(key, value)
+}
+
+func (s *allowlistSpan) SetTagString(key, value string) {
+	if s.allowed[key] {
+		s.Span.SetTagString(key, value)
+	}
+}
+
+func (s *allowlistSpan) SetTagInt(key string, value int) {
+	if s.allowed[key] {
+		s.Span.SetTagInt(key, value)
+	}
+}
+
+func (s *allowlistSpan) SetTagBool(key string, value bool) {
+	if s.allowed[key] {
+		s.Span.SetTagBool(key, value)
+	}
+}
+
+func (s *allowlistSpan) SetTagFloat(key string, value float64) {
+	if s.allowed[key] {
+		s.Span.SetTagFloat(key, value)
+	}
+}
+
+func (s *allowlistSpan) SetError(err error) {
+	s.Span.SetError(s.redact(err))
+}
+
+func (s *allowlistSpan) RecordError(err error, opts ...ErrorOption) {
+	var config ErrorConfig
+	for _, opt := range opts {
+		opt(&config)
+	}
+	var kept []ErrorOption
+	if !config.Timestamp.IsZero() {
+		kept = append(kept, WithErrorTimestamp(config.Timestamp))
+	}
+	if config.Escaped {
+		kept = append(kept, WithEscaped())
+	}
+	if config.StackTrace && s.allowed[exceptionStacktraceKey] {
+		kept = append(kept, WithStackTrace())
+	}
+	if fields := s.fields(config.Fields); len(fields) > 0 {
+		kept = append(kept, WithErrorFields(fields...))
+	}
+	s.Span.RecordError(s.redact(err), kept...)
+}
+
+func (s *allowlistSpan) SetErrors(errs ...error) {
+	// Split joined errors first, so each part stays its own event
+	flat := flattenErrors(nil, errs)
+	for i, err := range flat {
+		flat[i] = s.redact(err)
+	}
+	s.Span.SetErrors(flat...)
+}
+
+// LogFields keeps the event name and the allowed fields
+func (s *allowlistSpan) LogFields(fields ...Field) {
+	kept := make([]Field, 0, len(fields))
+	for _, f := range fields {
+		if f.Key == "event" || s.allowed[f.Key] {
+			kept = append(kept, f)
+		}
+	}
+	s.Span.LogFields(kept...)
+}
+
+// fields returns the allowed fields of fields
+func (s *allowlistSpan) fields(fields []Field) []Field {
+	var kept []Field
+	for _, f := range fields {
+		if s.allowed[f.Key] {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
+
+// redact returns err, or its redacted form when its message is not allowed
+func (s *allowlistSpan) redact(err error) error {
+	if err == nil || s.allowed[exceptionMessageKey] {
+		return err
+	}
+	return redactedError{err: err, typ: exceptionType(err)}
+}
+
+// redactedError stands in for an error whose message must not be recorded
+type redactedError struct {
+	err error
+	typ string
+}
+
+func (e redactedError) Error() string { return e.typ + " (message redacted)" }
+
+func (e redactedError) Unwrap() error { return e.err }
+
+// exceptionType returns the exception.type recorded for err, named like
+// OpenTelemetry names the types of recorded errors
+func exceptionType(err error) string {
+	t := reflect.TypeOf(err)
+	if t.PkgPath() == "" && t.Name() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
@@ -0,0 +1,52 @@
+package tracingx
+
+import (
+	"runtime/debug"
+	"sync"
+)
+
+// modulePath is the import path of this module
+const modulePath = "github.com/gostratum/tracingx"
+
+var (
+	moduleVersionOnce  sync.Once
+	moduleVersionValue string
+)
+
+// moduleVersion returns the tracingx module version recorded in the build info,
+// or an empty string when it is unavailable (e.g. in tests or local builds)
+func moduleVersion() string {
+	moduleVersionOnce.Do(func() {
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		if info.Main.Path == modulePath && info.Main.Version != "(devel)" {
+			moduleVersionValue = info.Main.Version
+			return
+		}
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath {
+				moduleVersionValue = dep.Version
+				return
+			}
+		}
+	})
+	return moduleVersionValue
+}
+
+// name returns the instrumentation scope name, defaulting to "gostratum"
+func (c InstrumentationConfig) name() string {
+	if c.Name != "" {
+		return c.Name
+	}
+	return "gostratum"
+}
+
+// version returns the instrumentation scope version, defaulting to the module version
+func (c InstrumentationConfig) version() string {
+	if c.Version != "" {
+		return c.Version
+	}
+	return moduleVersion()
+}
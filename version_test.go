@@ -0,0 +1,21 @@
+package tracingx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrumentationConfig(t *testing.T) {
+	t.Run("defaults name and version", func(t *testing.T) {
+		cfg := InstrumentationConfig{}
+		assert.Equal(t, "gostratum", cfg.name())
+		assert.Equal(t, moduleVersion(), cfg.version())
+	})
+
+	t.Run("uses configured name and version", func(t *testing.T) {
+		cfg := InstrumentationConfig{Name: "orders-lib", Version: "v1.2.3"}
+		assert.Equal(t, "orders-lib", cfg.name())
+		assert.Equal(t, "v1.2.3", cfg.version())
+	})
+}
@@ -0,0 +1,125 @@
+package tracingx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// WarmupConfig configures connecting the exporters when the provider is
+// created, so the first spans do not pay DNS, TLS and connection setup
+type WarmupConfig struct {
+	// Enabled dials every exporter connection when the provider is created;
+	// Provider.Ready reports when all of them are connected
+	Enabled bool `mapstructure:"enabled" default:"false"`
+
+	// Required fails application startup when the exporters are not
+	// connected within Timeout; otherwise it is logged as a warning
+	Required bool `mapstructure:"required" default:"false"`
+
+	// Timeout bounds how long application startup waits for the connections
+	Timeout time.Duration `mapstructure:"timeout" default:"5s"`
+}
+
+// connExporter is an exporter using a connection dialed by tracingx, which
+// it closes on Shutdown
+type connExporter struct {
+	sdktrace.SpanExporter
+	conn *grpc.ClientConn
+}
+
+func (e *connExporter) Shutdown(ctx context.Context) error {
+	err := e.SpanExporter.Shutdown(ctx)
+	if closeErr := e.conn.Close(); closeErr != nil && err == nil {
+		err = fmt.Errorf("failed to close exporter connection: %w", closeErr)
+	}
+	return err
+}
+
+// warmConns connects conns and returns a channel closed once all of them
+// are ready. The channel stays open if a connection is closed first.
+func warmConns(conns []*grpc.ClientConn) <-chan struct{} {
+	if len(conns) == 0 {
+		return closedReady
+	}
+	ready := make(chan struct{})
+	go func() {
+		var wg sync.WaitGroup
+		connected := make([]bool, len(conns))
+		for i, conn := range conns {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				connected[i] = waitReady(conn)
+			}()
+		}
+		wg.Wait()
+		for _, ok := range connected {
+			if !ok {
+				return
+			}
+		}
+		close(ready)
+	}()
+	return ready
+}
+
+// waitReady connects conn and waits until it is ready, reporting false when
+// it is closed first
+func waitReady(conn *grpc.ClientConn) bool {
+	for {
+		state := conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return true
+		case connectivity.Shutdown:
+			return false
+		case connectivity.Idle:
+			conn.Connect()
+		}
+		conn.WaitForStateChange(context.Background(), state)
+	}
+}
+
+// Ready returns a channel closed once the exporter connections of the
+// current pipeline are established; when warm-up is disabled it is closed
+// from the start
+func (p *otlpProvider) Ready() <-chan struct{} {
+	return p.current().ready
+}
+
+// errWarmupTimeout is returned when the exporters do not connect in time
+var errWarmupTimeout = errors.New("tracing exporters did not connect in time")
+
+// runWarmup waits for the configured warm-up to complete
+func runWarmup(ctx context.Context, config WarmupConfig, provider Provider, logger logx.Logger) error {
+	if !config.Enabled {
+		return nil
+	}
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	select {
+	case <-provider.Ready():
+		logger.Info("tracing exporters connected", logx.Duration("elapsed", time.Since(start)))
+		return nil
+	case <-ctx.Done():
+	}
+	err := fmt.Errorf("%w: %w", errWarmupTimeout, ctx.Err())
+	if config.Required {
+		return err
+	}
+	logger.Warn("tracing warm-up incomplete", logx.Err(err))
+	return nil
+}
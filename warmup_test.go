@@ -0,0 +1,132 @@
+package tracingx
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/gostratum/core/logx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarmup(t *testing.T) {
+	t.Run("connects exporters before the first span", func(t *testing.T) {
+		collector := newFakeCollector(t)
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			SampleRate:  1.0,
+			Warmup:      WarmupConfig{Enabled: true},
+			OTLP:        OTLPConfig{Endpoint: collector.endpoint, Insecure: true, Workers: 2},
+			Pipelines:   []PipelineConfig{{Name: "errors", Filter: "errors", SampleRate: 1.0, OTLP: OTLPConfig{Endpoint: collector.endpoint, Insecure: true}}},
+		}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		select {
+		case <-provider.Ready():
+		case <-time.After(5 * time.Second):
+			t.Fatal("exporters did not connect")
+		}
+
+		_, span := provider.Start(context.Background(), "first")
+		span.End()
+		require.NoError(t, provider.ForceFlush(context.Background()))
+		assert.Len(t, collector.received(), 1)
+	})
+
+	t.Run("ready from the start when disabled", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			OTLP:        OTLPConfig{Endpoint: "127.0.0.1:1", Insecure: true},
+		}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+		select {
+		case <-provider.Ready():
+		default:
+			t.Fatal("not ready")
+		}
+	})
+
+	t.Run("not ready while the collector is unreachable", func(t *testing.T) {
+		provider, err := newOTLPProvider(Config{
+			ServiceName: "test-service",
+			Warmup:      WarmupConfig{Enabled: true},
+			OTLP:        OTLPConfig{Endpoint: "127.0.0.1:1", Insecure: true},
+		}, getTestLogger())
+		require.NoError(t, err)
+		defer provider.Shutdown(context.Background())
+
+		select {
+		case <-provider.Ready():
+			t.Fatal("ready without a collector")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("failed builds close their connections", func(t *testing.T) {
+		config := Config{
+			ServiceName: "test-service",
+			Warmup:      WarmupConfig{Enabled: true},
+			OTLP:        OTLPConfig{Endpoint: "127.0.0.1:1", Insecure: true, Workers: 2},
+		}
+		goroutines := settledGoroutines()
+		provider, err := newOTLPProvider(config, getTestLogger())
+		require.NoError(t, err)
+
+		invalid := config
+		invalid.Audit = AuditConfig{Enabled: true, Path: filepath.Join(t.TempDir(), "missing", "audit.log")}
+		for range 20 {
+			require.Error(t, provider.Reconfigure(invalid))
+		}
+		require.NoError(t, provider.Shutdown(context.Background()))
+		// Eventually runs the condition on a goroutine of its own
+		assert.Eventually(t, func() bool {
+			return runtime.NumGoroutine() <= goroutines+1
+		}, 5*time.Second, 10*time.Millisecond, "warmed connections of failed builds are closed")
+	})
+}
+
+func TestRunWarmup(t *testing.T) {
+	logger := logx.NewNoopLogger()
+	unready := failingProvider{Provider: newNoopProvider()}
+
+	t.Run("skipped when disabled", func(t *testing.T) {
+		assert.NoError(t, runWarmup(context.Background(), WarmupConfig{}, unready, logger))
+	})
+
+	t.Run("returns once ready", func(t *testing.T) {
+		config := WarmupConfig{Enabled: true, Required: true, Timeout: time.Second}
+		assert.NoError(t, runWarmup(context.Background(), config, newNoopProvider(), logger))
+	})
+
+	t.Run("logs timeout when not required", func(t *testing.T) {
+		config := WarmupConfig{Enabled: true, Timeout: 10 * time.Millisecond}
+		assert.NoError(t, runWarmup(context.Background(), config, unready, logger))
+	})
+
+	t.Run("fails startup when required", func(t *testing.T) {
+		config := WarmupConfig{Enabled: true, Required: true, Timeout: 10 * time.Millisecond}
+		err := runWarmup(context.Background(), config, unready, logger)
+		assert.ErrorIs(t, err, errWarmupTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+// settledGoroutines returns the number of goroutines once those left by
+// earlier tests have exited, so leak checks start from a stable count
+func settledGoroutines() int {
+	n := runtime.NumGoroutine()
+	for range 100 {
+		time.Sleep(10 * time.Millisecond)
+		current := runtime.NumGoroutine()
+		if current == n {
+			return n
+		}
+		n = current
+	}
+	return n
+}